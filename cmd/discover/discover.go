@@ -29,6 +29,8 @@ type Options struct {
 	ForceReal        bool
 	// Cloud-specific options
 	AWSProfile       string
+	AWSPageSize      int32
+	AWSUserDataHashes bool
 	AzureSubscription string
 	GCPProject       string
 }
@@ -60,6 +62,10 @@ Supports real discovery across all major cloud platforms.`,
 	// Cloud-specific flags
 	cmd.Flags().StringVar(&opts.AWSProfile, "aws-profile", "", 
 		"AWS profile to use (overrides default)")
+	cmd.Flags().Int32Var(&opts.AWSPageSize, "aws-page-size", 100, 
+		"Results requested per page from paginated AWS API calls (5-1000)")
+	cmd.Flags().BoolVar(&opts.AWSUserDataHashes, "aws-user-data-hashes", false, 
+		"Record a hash of each EC2 instance's user data (one extra API call per instance)")
 	cmd.Flags().StringVar(&opts.AzureSubscription, "azure-subscription", "", 
 		"Azure subscription ID (required for Azure)")
 	cmd.Flags().StringVar(&opts.GCPProject, "gcp-project", "", 
@@ -124,75 +130,96 @@ func performMultiCloudDiscovery(ctx context.Context, opts *Options, providerType
 	fmt.Println("🔍 Multi-Cloud Infrastructure Discovery (Phase 2)")
 	fmt.Println("================================================")
 
-	var allResources []discovery.Resource
-	var allErrors []discovery.DiscoveryError
-	providerStats := make(map[string]int)
-	startTime := time.Now()
+	engine := discovery.NewEngine(discovery.EngineConfig{
+		MaxConcurrency: opts.MaxConcurrency,
+		Timeout:        opts.Timeout,
+	}, nil)
+
+	// Register a connector for each provider; failures are reported alongside discovery errors
+	var connectorErrors []discovery.DiscoveryError
+	var registered []discovery.CloudProvider
 
-	// Discover resources from each provider
 	for _, provider := range providerTypes {
-		fmt.Printf("\n🔍 Discovering %s resources...\n", strings.ToUpper(string(provider)))
-		
-		resources, err := discoverProviderResources(ctx, provider, opts)
+		fmt.Printf("\n🔍 Connecting to %s...\n", strings.ToUpper(string(provider)))
+
+		connector, err := newProviderConnector(ctx, provider, opts)
 		if err != nil {
-			logger.Errorf("Failed to discover %s resources: %v", provider, err)
-			allErrors = append(allErrors, discovery.DiscoveryError{
-				Provider: provider,
-				Message:  err.Error(),
+			logger.Errorf("Failed to set up %s connector: %v", provider, err)
+			connectorErrors = append(connectorErrors, discovery.DiscoveryError{
+				Provider:  provider,
+				Message:   err.Error(),
+				Error:     err,
+				Severity:  discovery.SeverityError,
+				Timestamp: time.Now(),
 			})
 			continue
 		}
 
-		providerStats[string(provider)] = len(resources)
-		allResources = append(allResources, resources...)
-		
-		fmt.Printf("✅ Found %d %s resources\n", len(resources), provider)
+		engine.RegisterConnector(connector)
+		registered = append(registered, provider)
 	}
 
-	duration := time.Since(startTime)
-
-	// Create combined result
 	result := &discovery.DiscoveryResult{
-		Resources: allResources,
-		Errors:    allErrors,
 		Metadata: discovery.DiscoveryMetadata{
-			StartTime:     startTime,
-			EndTime:       time.Now(),
-			Duration:      duration,
-			ResourceCount: len(allResources),
-			ProviderStats: providerStats,
-			ErrorCount:    len(allErrors),
+			StartTime:     time.Now(),
+			ProviderStats: make(map[string]int),
 		},
 	}
 
+	if len(registered) > 0 {
+		var err error
+		result, err = engine.Discover(ctx, discovery.DiscoveryOptions{
+			Providers:      registered,
+			Regions:        opts.Regions,
+			ResourceTypes:  opts.ResourceTypes,
+			MaxConcurrency: opts.MaxConcurrency,
+			Timeout:        opts.Timeout,
+		})
+		if err != nil {
+			return fmt.Errorf("discovery failed: %w", err)
+		}
+	} else {
+		result.Metadata.EndTime = time.Now()
+	}
+
+	result.Errors = append(connectorErrors, result.Errors...)
+	result.Metadata.ErrorCount += len(connectorErrors)
+
+	for _, provider := range registered {
+		fmt.Printf("✅ Found %d %s resources\n", result.Metadata.ProviderStats[string(provider)], provider)
+	}
+
 	// Output results
 	fmt.Printf("\n🎉 Multi-Cloud Discovery Complete!\n")
-	fmt.Printf("Total resources found: %d\n", len(allResources))
-	fmt.Printf("Discovery duration: %v\n", duration)
-	
-	if len(allErrors) > 0 {
-		fmt.Printf("⚠️  Encountered %d errors during discovery\n", len(allErrors))
+	fmt.Printf("Total resources found: %d\n", result.Metadata.ResourceCount)
+	fmt.Printf("Discovery duration: %v\n", result.Metadata.Duration)
+
+	if len(result.Errors) > 0 {
+		fmt.Printf("⚠️  Encountered %d errors during discovery\n", len(result.Errors))
+		for _, discoveryErr := range result.Errors {
+			logger.Warnf("%s: %s", discoveryErr.Provider, discoveryErr.Message)
+		}
 	}
 
 	return outputResults(result, opts)
 }
 
-// discoverProviderResources discovers resources from a specific cloud provider
-func discoverProviderResources(ctx context.Context, provider discovery.CloudProvider, opts *Options) ([]discovery.Resource, error) {
+// newProviderConnector creates and validates a connector for a specific cloud provider
+func newProviderConnector(ctx context.Context, provider discovery.CloudProvider, opts *Options) (discovery.ProviderConnector, error) {
 	switch provider {
 	case discovery.AWS:
-		return discoverAWSResources(ctx, opts)
+		return newAWSConnector(ctx, opts)
 	case discovery.Azure:
-		return discoverAzureResources(ctx, opts)
+		return newAzureConnector(ctx, opts)
 	case discovery.GCP:
-		return discoverGCPResources(ctx, opts)
+		return newGCPConnector(ctx, opts)
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
 }
 
-// discoverAWSResources discovers AWS resources
-func discoverAWSResources(ctx context.Context, opts *Options) ([]discovery.Resource, error) {
+// newAWSConnector creates an AWS connector
+func newAWSConnector(ctx context.Context, opts *Options) (discovery.ProviderConnector, error) {
 	// Use first region or default
 	region := "us-east-1"
 	if len(opts.Regions) > 0 {
@@ -209,23 +236,19 @@ func discoverAWSResources(ctx context.Context, opts *Options) ([]discovery.Resou
 	if err != nil {
 		return nil, fmt.Errorf("failed to create AWS connector: %w", err)
 	}
+	awsConnector.SetPageSize(opts.AWSPageSize)
+	awsConnector.SetUserDataHashes(opts.AWSUserDataHashes)
 
 	// Validate credentials
 	if err := awsConnector.ValidateCredentials(ctx); err != nil {
 		return nil, fmt.Errorf("AWS credential validation failed: %w", err)
 	}
 
-	// Prepare discovery options
-	providerOpts := discovery.ProviderDiscoveryOptions{
-		Regions:       opts.Regions,
-		ResourceTypes: opts.ResourceTypes,
-	}
-
-	return awsConnector.Discover(ctx, providerOpts)
+	return awsConnector, nil
 }
 
-// discoverAzureResources discovers Azure resources
-func discoverAzureResources(ctx context.Context, opts *Options) ([]discovery.Resource, error) {
+// newAzureConnector creates an Azure connector
+func newAzureConnector(ctx context.Context, opts *Options) (discovery.ProviderConnector, error) {
 	if opts.AzureSubscription == "" {
 		return nil, fmt.Errorf("Azure subscription ID is required (use --azure-subscription)")
 	}
@@ -241,17 +264,11 @@ func discoverAzureResources(ctx context.Context, opts *Options) ([]discovery.Res
 		return nil, fmt.Errorf("Azure credential validation failed: %w", err)
 	}
 
-	// Prepare discovery options
-	providerOpts := discovery.ProviderDiscoveryOptions{
-		Regions:       opts.Regions,
-		ResourceTypes: opts.ResourceTypes,
-	}
-
-	return azureConnector.Discover(ctx, providerOpts)
+	return azureConnector, nil
 }
 
-// discoverGCPResources discovers GCP resources
-func discoverGCPResources(ctx context.Context, opts *Options) ([]discovery.Resource, error) {
+// newGCPConnector creates a GCP connector
+func newGCPConnector(ctx context.Context, opts *Options) (discovery.ProviderConnector, error) {
 	if opts.GCPProject == "" {
 		return nil, fmt.Errorf("GCP project ID is required (use --gcp-project)")
 	}
@@ -267,13 +284,7 @@ func discoverGCPResources(ctx context.Context, opts *Options) ([]discovery.Resou
 		return nil, fmt.Errorf("GCP credential validation failed: %w", err)
 	}
 
-	// Prepare discovery options
-	providerOpts := discovery.ProviderDiscoveryOptions{
-		Regions:       opts.Regions,
-		ResourceTypes: opts.ResourceTypes,
-	}
-
-	return gcpConnector.Discover(ctx, providerOpts)
+	return gcpConnector, nil
 }
 
 // outputResults outputs the discovery results
@@ -14,7 +14,11 @@ import (
 
 	"github.com/BigChiefRick/chimera/pkg/discovery"
 	"github.com/BigChiefRick/chimera/pkg/generation"
+	"github.com/BigChiefRick/chimera/pkg/generation/arm"
+	"github.com/BigChiefRick/chimera/pkg/generation/cdk"
+	"github.com/BigChiefRick/chimera/pkg/generation/cloudformation"
 	"github.com/BigChiefRick/chimera/pkg/generation/mappers"
+	"github.com/BigChiefRick/chimera/pkg/generation/pulumi"
 	"github.com/BigChiefRick/chimera/pkg/generation/terraform"
 )
 
@@ -27,6 +31,8 @@ type Options struct {
 	// Output options
 	OutputPath       string
 	Format           string
+	CFNEncoding      string
+	Organize         string
 	OrganizeByType   bool
 	OrganizeByRegion bool
 	SingleFile       bool
@@ -37,6 +43,8 @@ type Options struct {
 	ProviderVersion  string
 	GenerateModules  bool
 	ModuleStructure  string
+	GenerateImports  bool
+	ImportScript     bool
 	
 	// Filtering options
 	ExcludeResources []string
@@ -78,9 +86,25 @@ Examples:
   # Generate organized by resource type
   chimera generate --input resources.json --output ./terraform/ --organize-by-type
 
+  # Generate a directory per provider and region with a file per service
+  chimera generate --input resources.json --output ./terraform/ --organize by_provider+by_region+by_service
+
   # Generate with modules
   chimera generate --input resources.json --output ./terraform/ --generate-modules
 
+  # Generate an ARM template or Bicep modules from discovered Azure resources
+  chimera generate --input azure-resources.json --output ./arm/ --format arm
+  chimera generate --input azure-resources.json --output ./bicep/ --format bicep
+
+  # Generate a CloudFormation template and import descriptor
+  chimera generate --input aws-resources.json --output ./cfn/ --format cloudformation
+
+  # Generate an AWS CDK app with a stack per account and region
+  chimera generate --input aws-resources.json --output ./cdk/ --format cdk-typescript
+
+  # Generate a Pulumi project that imports the discovered resources
+  chimera generate --input resources.json --output ./infra/ --format pulumi-go
+
   # Preview what would be generated
   chimera generate --input resources.json --dry-run`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -98,7 +122,11 @@ Examples:
 	cmd.Flags().StringVarP(&opts.OutputPath, "output", "o", "./generated", 
 		"Output directory for generated files")
 	cmd.Flags().StringVar(&opts.Format, "format", "terraform", 
-		"Output format (terraform,pulumi,cloudformation)")
+		"Output format (terraform,terraform-json,pulumi-typescript,pulumi-python,pulumi-go,cloudformation,cdk-typescript,cdk-python,arm,bicep)")
+	cmd.Flags().StringVar(&opts.CFNEncoding, "cfn-encoding", "yaml", 
+		"CloudFormation template encoding (yaml,json)")
+	cmd.Flags().StringVar(&opts.Organize, "organize", "", 
+		"File organization (by_provider,by_service,by_region,by_resource_type,flat); combine with + (e.g. by_provider+by_region+by_service)")
 	cmd.Flags().BoolVar(&opts.OrganizeByType, "organize-by-type", false, 
 		"Organize files by resource type")
 	cmd.Flags().BoolVar(&opts.OrganizeByRegion, "organize-by-region", false, 
@@ -108,7 +136,7 @@ Examples:
 
 	// Generation flags
 	cmd.Flags().BoolVar(&opts.IncludeState, "include-state", false, 
-		"Include state configuration")
+		"Generate a terraform.tfstate describing the discovered resources")
 	cmd.Flags().BoolVar(&opts.IncludeProvider, "include-provider", true, 
 		"Include provider configuration")
 	cmd.Flags().StringVar(&opts.ProviderVersion, "provider-version", "", 
@@ -117,6 +145,10 @@ Examples:
 		"Generate Terraform modules")
 	cmd.Flags().StringVar(&opts.ModuleStructure, "module-structure", "by_provider", 
 		"Module organization (by_provider,by_service,by_region,by_resource_type)")
+	cmd.Flags().BoolVar(&opts.GenerateImports, "generate-imports", false, 
+		"Generate imports.tf with Terraform 1.5+ import blocks")
+	cmd.Flags().BoolVar(&opts.ImportScript, "import-script", false, 
+		"Generate import.sh with terraform import commands for older Terraform versions")
 
 	// Filtering flags
 	cmd.Flags().StringSliceVar(&opts.ExcludeResources, "exclude", []string{}, 
@@ -205,24 +237,37 @@ func runGenerate(ctx context.Context, opts *Options) error {
 
 	// Register mappers - FIX: Remove discovery.AWS parameter
 	engine.RegisterMapper(mappers.NewAWSMapper())
-	// TODO: Add Azure and GCP mappers in Phase 4
+	engine.RegisterMapper(mappers.NewAzureMapper())
+	engine.RegisterMapper(mappers.NewGCPMapper())
 
 	// Register generators
-	engine.RegisterGenerator(generation.Terraform, terraform.NewGenerator())
-	// TODO: Add Pulumi and CloudFormation generators in Phase 4
+	engine.RegisterGenerator(generation.Terraform,
+		generation.NewTerraformFormatGenerator(terraform.NewGenerator()))
+	engine.RegisterGenerator(generation.TerraformJSON,
+		generation.NewTerraformFormatGenerator(terraform.NewJSONGenerator()))
+	engine.RegisterGenerator(generation.CloudFormation,
+		cloudformation.NewGenerator(cloudformation.Encoding(opts.CFNEncoding)))
+	engine.RegisterGenerator(generation.CDK, cdk.NewGenerator(cdk.TypeScript))
+	engine.RegisterGenerator(generation.CDKTypeScript, cdk.NewGenerator(cdk.TypeScript))
+	engine.RegisterGenerator(generation.CDKPython, cdk.NewGenerator(cdk.Python))
+	engine.RegisterGenerator(generation.ARM, arm.NewGenerator())
+	engine.RegisterGenerator(generation.Bicep, arm.NewBicepGenerator())
+	engine.RegisterGenerator(generation.Pulumi, pulumi.NewGenerator(pulumi.TypeScript))
+	engine.RegisterGenerator(generation.PulumiTypeScript, pulumi.NewGenerator(pulumi.TypeScript))
+	engine.RegisterGenerator(generation.PulumiPython, pulumi.NewGenerator(pulumi.Python))
+	engine.RegisterGenerator(generation.PulumiGo, pulumi.NewGenerator(pulumi.Go))
 
 	// Convert options to generation options
 	genOpts := convertToGenerationOptions(opts, filteredResources)
 
-	// Perform generation
+	// Perform generation; the engine writes the files under the output path
 	result, err := engine.Generate(ctx, genOpts)
 	if err != nil {
 		return fmt.Errorf("generation failed: %w", err)
 	}
 
-	// Write files to disk
-	if err := writeGeneratedFiles(result.Files, opts); err != nil {
-		return fmt.Errorf("failed to write files: %w", err)
+	if opts.Verbose {
+		listGeneratedFiles(result.Files, opts)
 	}
 
 	// Display results
@@ -354,15 +399,28 @@ func showGenerationPlan(resources []discovery.Resource, opts *Options) error {
 	}
 
 	fmt.Printf("\n📁 Files that would be generated:\n")
-	if opts.SingleFile {
-		fmt.Printf("   main.tf (all resources)\n")
-	} else if opts.OrganizeByType {
-		for resourceType := range typeCounts {
-			fileName := strings.ReplaceAll(resourceType, "aws_", "") + ".tf"
-			fmt.Printf("   %s\n", fileName)
+	pattern := generation.ResolvePattern(convertToGenerationOptions(opts, resources))
+	if pattern == "" {
+		pattern = generation.OrganizeByProvider
+	}
+	organizer := generation.NewPatternOrganizer()
+	fileCounts := make(map[string]int)
+	for _, resource := range resources {
+		filePath, err := organizer.GetFilePath(generation.TerraformResource{
+			Type:     resource.Type,
+			Provider: resource.Provider,
+			SourceInfo: generation.SourceInfo{
+				OriginalProvider: resource.Provider,
+				OriginalRegion:   resource.Region,
+			},
+		}, pattern)
+		if err != nil {
+			return err
 		}
-	} else {
-		fmt.Printf("   main.tf (all resources)\n")
+		fileCounts[filePath]++
+	}
+	for filePath, count := range fileCounts {
+		fmt.Printf("   %s (%d resources)\n", filePath, count)
 	}
 
 	if opts.IncludeProvider {
@@ -389,7 +447,7 @@ func validateOptions(opts *Options) error {
 	}
 
 	// Validate format
-	validFormats := []string{"terraform", "pulumi", "cloudformation"}
+	validFormats := []string{"terraform", "terraform-json", "pulumi", "pulumi-typescript", "pulumi-python", "pulumi-go", "cloudformation", "cdk", "cdk-typescript", "cdk-python", "arm", "bicep"}
 	validFormat := false
 	for _, format := range validFormats {
 		if opts.Format == format {
@@ -402,6 +460,11 @@ func validateOptions(opts *Options) error {
 			opts.Format, strings.Join(validFormats, ","))
 	}
 
+	// Validate CloudFormation encoding
+	if opts.CFNEncoding != string(cloudformation.YAML) && opts.CFNEncoding != string(cloudformation.JSON) {
+		return fmt.Errorf("invalid CloudFormation encoding: %s (valid: yaml,json)", opts.CFNEncoding)
+	}
+
 	// Validate module structure
 	validStructures := []string{"by_provider", "by_service", "by_region", "by_resource_type", "flat"}
 	validStructure := false
@@ -425,6 +488,16 @@ func validateOptions(opts *Options) error {
 		return fmt.Errorf("cannot use --single-file with --organize-by-region")
 	}
 
+	if opts.Organize != "" {
+		if opts.SingleFile || opts.OrganizeByType || opts.OrganizeByRegion {
+			return fmt.Errorf("cannot use --organize with --single-file, --organize-by-type or --organize-by-region")
+		}
+		pattern := generation.OrganizationPattern(opts.Organize)
+		if err := generation.NewPatternOrganizer().ValidateOrganization(pattern, nil); err != nil {
+			return fmt.Errorf("invalid organization: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -435,10 +508,28 @@ func convertToGenerationOptions(opts *Options, resources []discovery.Resource) g
 	switch opts.Format {
 	case "terraform":
 		format = generation.Terraform
+	case "terraform-json":
+		format = generation.TerraformJSON
 	case "pulumi":
 		format = generation.Pulumi
+	case "pulumi-typescript":
+		format = generation.PulumiTypeScript
+	case "pulumi-python":
+		format = generation.PulumiPython
+	case "pulumi-go":
+		format = generation.PulumiGo
 	case "cloudformation":
 		format = generation.CloudFormation
+	case "cdk":
+		format = generation.CDK
+	case "cdk-typescript":
+		format = generation.CDKTypeScript
+	case "cdk-python":
+		format = generation.CDKPython
+	case "arm":
+		format = generation.ARM
+	case "bicep":
+		format = generation.Bicep
 	default:
 		format = generation.Terraform
 	}
@@ -470,6 +561,7 @@ func convertToGenerationOptions(opts *Options, resources []discovery.Resource) g
 		Resources:         resources,
 		Format:            format,
 		OutputPath:        opts.OutputPath,
+		Organization:      generation.OrganizationPattern(opts.Organize),
 		OrganizeByType:    opts.OrganizeByType,
 		OrganizeByRegion:  opts.OrganizeByRegion,
 		SingleFile:        opts.SingleFile,
@@ -478,6 +570,8 @@ func convertToGenerationOptions(opts *Options, resources []discovery.Resource) g
 		ProviderVersion:   opts.ProviderVersion,
 		GenerateModules:   opts.GenerateModules,
 		ModuleStructure:   moduleStructure,
+		GenerateImports:   opts.GenerateImports,
+		ImportScript:      opts.ImportScript,
 		ExcludeResources:  opts.ExcludeResources,
 		IncludeResources:  opts.IncludeResources,
 		TemplateVariables: templateVars,
@@ -515,27 +609,12 @@ func prepareOutputDirectory(outputPath string, force bool) error {
 	return nil
 }
 
-// writeGeneratedFiles writes generated files to disk
-func writeGeneratedFiles(files []generation.GeneratedFile, opts *Options) error {
+// listGeneratedFiles prints the files the engine wrote under the output path
+func listGeneratedFiles(files []generation.GeneratedFile, opts *Options) {
 	for _, file := range files {
-		// Create directory if it doesn't exist
-		dir := filepath.Dir(file.Path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
-		}
-
-		// Write file
-		if err := os.WriteFile(file.Path, []byte(file.Content), 0644); err != nil {
-			return fmt.Errorf("failed to write file %s: %w", file.Path, err)
-		}
-
-		if opts.Verbose {
-			fmt.Printf("✅ Generated: %s (%d bytes, %d resources)\n", 
-				file.Path, file.Size, file.ResourceCount)
-		}
+		fmt.Printf("✅ Generated: %s (%d bytes, %d resources)\n",
+			filepath.Join(opts.OutputPath, file.Path), file.Size, file.ResourceCount)
 	}
-
-	return nil
 }
 
 // displayResults displays generation results
@@ -562,6 +641,56 @@ func displayResults(result *generation.GenerationResult, opts *Options) {
 		}
 	}
 
+	if opts.Format == "cloudformation" {
+		fmt.Printf("\n🚀 Ready to import:\n")
+		fmt.Printf("   cd %s\n", opts.OutputPath)
+		fmt.Printf("   aws cloudformation create-change-set --stack-name <stack> --change-set-name import \\\n")
+		fmt.Printf("     --change-set-type IMPORT --template-body file://template.%s \\\n", opts.CFNEncoding)
+		fmt.Printf("     --resources-to-import file://import-resources.json\n")
+		return
+	}
+
+	if strings.HasPrefix(opts.Format, "cdk") {
+		fmt.Printf("\n🚀 Ready to import:\n")
+		fmt.Printf("   cd %s\n", opts.OutputPath)
+		if opts.Format == "cdk-python" {
+			fmt.Printf("   python3 -m venv .venv && .venv/bin/pip install -r requirements.txt\n")
+		} else {
+			fmt.Printf("   npm install\n")
+		}
+		fmt.Printf("   cdk import <stack> --resource-mapping import-mappings/<stack>.json\n")
+		return
+	}
+
+	if opts.Format == "arm" || opts.Format == "bicep" {
+		template := "azuredeploy.json"
+		if opts.Format == "bicep" {
+			template = "main.bicep"
+		}
+		fmt.Printf("\n🚀 Ready to deploy:\n")
+		fmt.Printf("   cd %s\n", opts.OutputPath)
+		fmt.Printf("   az deployment group create --resource-group <group> --template-file %s\n", template)
+		fmt.Printf("   (use az deployment sub create when the template spans resource groups)\n")
+		return
+	}
+
+	if strings.HasPrefix(opts.Format, "pulumi") {
+		fmt.Printf("\n🚀 Ready to import:\n")
+		fmt.Printf("   cd %s\n", opts.OutputPath)
+		switch opts.Format {
+		case "pulumi-python":
+			fmt.Printf("   python3 -m venv venv && venv/bin/pip install -r requirements.txt\n")
+		case "pulumi-go":
+			fmt.Printf("   go mod tidy\n")
+		default:
+			fmt.Printf("   npm install\n")
+		}
+		fmt.Printf("   pulumi stack init <stack>\n")
+		fmt.Printf("   pulumi config set <key> <value>   (for each required config value in the program)\n")
+		fmt.Printf("   pulumi up\n")
+		return
+	}
+
 	fmt.Printf("\n🚀 Ready to deploy:\n")
 	fmt.Printf("   cd %s\n", opts.OutputPath)
 	fmt.Printf("   terraform init\n")
@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation/graph"
+)
+
+// Options contains the graph command options
+type Options struct {
+	InputPath  string
+	OutputPath string
+	Format     string
+	ClusterBy  string
+	Root       string
+	Provider   string
+}
+
+// NewGraphCommand creates the graph command
+func NewGraphCommand() *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Draw the dependency graph of discovered resources",
+		Long: `Draw the dependency graph of previously discovered infrastructure
+resources as a Graphviz DOT digraph, a Mermaid flowchart or JSON.
+
+Edges point from a resource to the resource it depends on. Dependencies are
+taken from the discovery output and from resource metadata such as VPC,
+subnet and network references.
+
+Examples:
+  # Render the graph with Graphviz
+  chimera graph --input resources.json | dot -Tsvg > resources.svg
+
+  # Mermaid flowchart clustered by VPC, for embedding in Markdown
+  chimera graph --input resources.json --format mermaid --cluster-by vpc
+
+  # Everything connected to one VPC, as JSON
+  chimera graph --input resources.json --format json --root vpc-0abc1234`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGraph(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.InputPath, "input", "i", "",
+		"Input file with discovered resources (required)")
+	cmd.Flags().StringVarP(&opts.OutputPath, "output", "o", "",
+		"Output file path (default: stdout)")
+	cmd.Flags().StringVarP(&opts.Format, "format", "f", "dot",
+		"Output format (dot,mermaid,json)")
+	cmd.Flags().StringVar(&opts.ClusterBy, "cluster-by", "",
+		"Group resources into clusters (provider,region,vpc)")
+	cmd.Flags().StringVar(&opts.Root, "root", "",
+		"Only draw the resources connected to this resource ID")
+	cmd.Flags().StringVar(&opts.Provider, "provider", "",
+		"Filter by cloud provider (aws,azure,gcp)")
+
+	cmd.MarkFlagRequired("input")
+
+	return cmd
+}
+
+// runGraph executes the graph command
+func runGraph(opts *Options) error {
+	logger := logrus.WithField("command", "graph")
+
+	format := graph.Format(strings.ToLower(opts.Format))
+	switch format {
+	case graph.DOT, graph.Mermaid, graph.JSON:
+	default:
+		return fmt.Errorf("invalid format %q, valid formats: dot, mermaid, json", opts.Format)
+	}
+
+	clusterBy := graph.ClusterBy(strings.ToLower(opts.ClusterBy))
+	switch clusterBy {
+	case graph.ClusterNone, graph.ClusterProvider, graph.ClusterRegion, graph.ClusterVPC:
+	default:
+		return fmt.Errorf("invalid cluster-by %q, valid values: provider, region, vpc", opts.ClusterBy)
+	}
+
+	resources, err := loadResources(opts.InputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load resources: %w", err)
+	}
+	if opts.Provider != "" {
+		var filtered []discovery.Resource
+		for _, resource := range resources {
+			if string(resource.Provider) == opts.Provider {
+				filtered = append(filtered, resource)
+			}
+		}
+		resources = filtered
+	}
+	logger.Infof("Loaded %d resources from %s", len(resources), opts.InputPath)
+
+	content, err := graph.Render(resources, graph.Options{
+		Format:    format,
+		ClusterBy: clusterBy,
+		Root:      opts.Root,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.OutputPath == "" {
+		fmt.Print(content)
+		return nil
+	}
+	if err := os.WriteFile(opts.OutputPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	logger.Infof("Dependency graph written to %s", opts.OutputPath)
+	return nil
+}
+
+// loadResources loads discovered resources from a discovery result or a
+// JSON array of resources
+func loadResources(inputPath string) ([]discovery.Resource, error) {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	var discoveryResult discovery.DiscoveryResult
+	if err := json.Unmarshal(data, &discoveryResult); err == nil && len(discoveryResult.Resources) > 0 {
+		return discoveryResult.Resources, nil
+	}
+
+	var resources []discovery.Resource
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return resources, nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/BigChiefRick/chimera/cmd/discover"
 	"github.com/BigChiefRick/chimera/cmd/generate"
+	"github.com/BigChiefRick/chimera/cmd/graph"
 	"github.com/BigChiefRick/chimera/pkg/config"
 )
 
@@ -41,7 +42,7 @@ Supported IaC Outputs:
   • Terraform (.tf)
   • Pulumi
   • AWS CloudFormation
-  • Azure ARM Templates`,
+  • Azure ARM Templates and Bicep`,
 	Version: version,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		setupLogging()
@@ -80,6 +81,7 @@ func init() {
 	// Add subcommands
 	rootCmd.AddCommand(discover.NewDiscoverCommand())
 	rootCmd.AddCommand(generate.NewGenerateCommand())
+	rootCmd.AddCommand(graph.NewGraphCommand())
 	rootCmd.AddCommand(newVersionCommand())
 	rootCmd.AddCommand(newConfigCommand())
 }
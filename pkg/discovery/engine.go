@@ -2,7 +2,9 @@ package discovery
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -56,7 +58,7 @@ func (e *Engine) RegisterConnector(connector ProviderConnector) {
 // Discover discovers resources based on the provided options
 func (e *Engine) Discover(ctx context.Context, opts DiscoveryOptions) (*DiscoveryResult, error) {
 	startTime := time.Now()
-	
+
 	// Validate options
 	if err := e.validateOptions(opts); err != nil {
 		return nil, fmt.Errorf("invalid discovery options: %w", err)
@@ -73,14 +75,161 @@ func (e *Engine) Discover(ctx context.Context, opts DiscoveryOptions) (*Discover
 		},
 	}
 
-	// For Phase 1, return framework demonstration
+	timeout := e.config.Timeout
+	if opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	maxConcurrency := e.config.MaxConcurrency
+	if opts.MaxConcurrency > 0 {
+		maxConcurrency = opts.MaxConcurrency
+	}
+
+	// Fan out to every requested provider, bounded by maxConcurrency
+	outcomes := make([]providerOutcome, len(opts.Providers))
+	semaphore := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+
+	for i, provider := range opts.Providers {
+		connector, exists := e.connectors[provider]
+		if !exists {
+			outcomes[i] = providerOutcome{
+				provider: provider,
+				err:      fmt.Errorf("no connector available for provider: %s", provider),
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, connector ProviderConnector) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				outcomes[i] = providerOutcome{provider: connector.Provider(), err: ctx.Err()}
+				return
+			}
+
+			outcomes[i] = e.discoverProvider(ctx, connector, opts)
+		}(i, connector)
+	}
+
+	wg.Wait()
+
+	// Merge results in the order the providers were requested
+	for _, outcome := range outcomes {
+		if outcome.err != nil {
+			e.logger.Warnf("Discovery failed for provider %s: %v", outcome.provider, outcome.err)
+			result.Errors = append(result.Errors, DiscoveryError{
+				Provider:  outcome.provider,
+				Message:   outcome.err.Error(),
+				Error:     outcome.err,
+				Severity:  SeverityError,
+				Timestamp: time.Now(),
+			})
+			continue
+		}
+
+		result.Resources = append(result.Resources, outcome.resources...)
+		result.Errors = append(result.Errors, outcome.errors...)
+		result.Metadata.ProviderStats[string(outcome.provider)] += len(outcome.resources)
+	}
+
+	for _, discoveryErr := range result.Errors {
+		switch discoveryErr.Severity {
+		case SeverityWarning:
+			result.Metadata.WarningCount++
+		default:
+			result.Metadata.ErrorCount++
+		}
+	}
+
 	result.Metadata.EndTime = time.Now()
 	result.Metadata.Duration = result.Metadata.EndTime.Sub(result.Metadata.StartTime)
-	result.Metadata.ResourceCount = 0
+	result.Metadata.ResourceCount = len(result.Resources)
 
 	return result, nil
 }
 
+// providerOutcome holds the result of discovering a single provider
+type providerOutcome struct {
+	provider  CloudProvider
+	resources []Resource
+	errors    []DiscoveryError
+	err       error
+}
+
+// discoverProvider runs discovery for a single connector, retrying failed attempts
+func (e *Engine) discoverProvider(ctx context.Context, connector ProviderConnector, opts DiscoveryOptions) providerOutcome {
+	provider := connector.Provider()
+	providerOpts := ProviderDiscoveryOptions{
+		Provider:        provider,
+		Regions:         opts.Regions,
+		ResourceTypes:   opts.ResourceTypes,
+		Filters:         opts.Filters,
+		Tags:            opts.Tags,
+		IncludeManaged:  opts.IncludeManaged,
+		IncludeDefaults: opts.IncludeDefaults,
+		MaxConcurrency:  opts.MaxConcurrency,
+	}
+	if providerOpts.MaxConcurrency <= 0 {
+		providerOpts.MaxConcurrency = e.config.MaxConcurrency
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= e.config.RetryAttempts; attempt++ {
+		if attempt > 1 {
+			e.logger.Debugf("Retrying %s discovery (attempt %d/%d)", provider, attempt, e.config.RetryAttempts)
+			select {
+			case <-time.After(e.config.RetryDelay):
+			case <-ctx.Done():
+				return providerOutcome{provider: provider, err: ctx.Err()}
+			}
+		}
+
+		resources, err := e.runConnector(ctx, connector, providerOpts)
+		if err == nil {
+			e.logger.Infof("Discovered %d resources from provider %s", len(resources), provider)
+			return providerOutcome{provider: provider, resources: resources}
+		}
+
+		// Partial failures keep what was discovered; retrying would repeat the successful work
+		var partialErr *PartialDiscoveryError
+		if errors.As(err, &partialErr) {
+			e.logger.Warnf("Discovered %d resources from provider %s with %d errors", len(resources), provider, len(partialErr.Errors))
+			return providerOutcome{provider: provider, resources: resources, errors: partialErr.Errors}
+		}
+
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return providerOutcome{
+		provider: provider,
+		err:      fmt.Errorf("discovery failed after %d attempts: %w", e.config.RetryAttempts, lastErr),
+	}
+}
+
+// runConnector performs a single connect/discover/disconnect cycle
+func (e *Engine) runConnector(ctx context.Context, connector ProviderConnector, opts ProviderDiscoveryOptions) ([]Resource, error) {
+	if err := connector.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() {
+		if err := connector.Disconnect(ctx); err != nil {
+			e.logger.Warnf("Failed to disconnect from provider %s: %v", connector.Provider(), err)
+		}
+	}()
+
+	return connector.DiscoverResources(ctx, opts)
+}
+
 // validateOptions validates discovery options
 func (e *Engine) validateOptions(opts DiscoveryOptions) error {
 	if len(opts.Providers) == 0 {
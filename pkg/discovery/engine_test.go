@@ -0,0 +1,269 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeConnector is a ProviderConnector whose discovery is scripted by a function
+type fakeConnector struct {
+	provider CloudProvider
+	discover func(ctx context.Context, attempt int) ([]Resource, error)
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *fakeConnector) Provider() CloudProvider                       { return c.provider }
+func (c *fakeConnector) Connect(ctx context.Context) error             { return nil }
+func (c *fakeConnector) Disconnect(ctx context.Context) error          { return nil }
+func (c *fakeConnector) ValidateCredentials(ctx context.Context) error { return nil }
+func (c *fakeConnector) GetRegions(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+func (c *fakeConnector) GetResourceTypes(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+func (c *fakeConnector) GetResourcesByType(ctx context.Context, resourceType, region string) ([]Resource, error) {
+	return nil, nil
+}
+
+func (c *fakeConnector) DiscoverResources(ctx context.Context, opts ProviderDiscoveryOptions) ([]Resource, error) {
+	c.mu.Lock()
+	c.calls++
+	attempt := c.calls
+	c.mu.Unlock()
+	return c.discover(ctx, attempt)
+}
+
+func (c *fakeConnector) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+// resources returns n resources of a provider
+func resources(provider CloudProvider, n int) []Resource {
+	result := make([]Resource, n)
+	for i := range result {
+		result[i] = Resource{ID: string(provider) + "-" + string(rune('a'+i)), Provider: provider}
+	}
+	return result
+}
+
+// newTestEngine creates an engine with the given connectors and silent logging
+func newTestEngine(config EngineConfig, connectors ...ProviderConnector) *Engine {
+	engine := NewEngine(config, nil)
+	engine.logger.SetOutput(io.Discard)
+	for _, connector := range connectors {
+		engine.RegisterConnector(connector)
+	}
+	return engine
+}
+
+func TestDiscoverMergesProviderResults(t *testing.T) {
+	aws := &fakeConnector{provider: AWS, discover: func(ctx context.Context, attempt int) ([]Resource, error) {
+		return resources(AWS, 3), nil
+	}}
+	gcp := &fakeConnector{provider: GCP, discover: func(ctx context.Context, attempt int) ([]Resource, error) {
+		return resources(GCP, 2), nil
+	}}
+	engine := newTestEngine(EngineConfig{RetryDelay: time.Millisecond}, aws, gcp)
+
+	result, err := engine.Discover(context.Background(), DiscoveryOptions{Providers: []CloudProvider{AWS, GCP}})
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+
+	if len(result.Resources) != 5 || result.Metadata.ResourceCount != 5 {
+		t.Fatalf("got %d resources (count %d), want 5", len(result.Resources), result.Metadata.ResourceCount)
+	}
+	// Results are merged in the order the providers were requested
+	for i, resource := range result.Resources {
+		want := AWS
+		if i >= 3 {
+			want = GCP
+		}
+		if resource.Provider != want {
+			t.Errorf("resource %d provider = %s, want %s", i, resource.Provider, want)
+		}
+	}
+	if got := result.Metadata.ProviderStats; got["aws"] != 3 || got["gcp"] != 2 {
+		t.Errorf("ProviderStats = %v, want aws=3 gcp=2", got)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("got errors %v, want none", result.Errors)
+	}
+}
+
+func TestDiscoverRecordsProviderError(t *testing.T) {
+	failure := errors.New("access denied")
+	aws := &fakeConnector{provider: AWS, discover: func(ctx context.Context, attempt int) ([]Resource, error) {
+		return nil, failure
+	}}
+	azure := &fakeConnector{provider: Azure, discover: func(ctx context.Context, attempt int) ([]Resource, error) {
+		return resources(Azure, 1), nil
+	}}
+	engine := newTestEngine(EngineConfig{RetryAttempts: 2, RetryDelay: time.Millisecond}, aws, azure)
+
+	result, err := engine.Discover(context.Background(), DiscoveryOptions{Providers: []CloudProvider{AWS, Azure}})
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+
+	if len(result.Resources) != 1 || result.Resources[0].Provider != Azure {
+		t.Fatalf("got resources %v, want the Azure resource only", result.Resources)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(result.Errors))
+	}
+	discoveryErr := result.Errors[0]
+	if discoveryErr.Provider != AWS || discoveryErr.Severity != SeverityError || !errors.Is(discoveryErr.Error, failure) {
+		t.Errorf("got error %+v, want an AWS error wrapping %v", discoveryErr, failure)
+	}
+	if result.Metadata.ErrorCount != 1 {
+		t.Errorf("ErrorCount = %d, want 1", result.Metadata.ErrorCount)
+	}
+	if _, exists := result.Metadata.ProviderStats["aws"]; exists {
+		t.Errorf("ProviderStats recorded the failed provider: %v", result.Metadata.ProviderStats)
+	}
+}
+
+func TestDiscoverRecordsMissingConnector(t *testing.T) {
+	engine := newTestEngine(EngineConfig{})
+
+	result, err := engine.Discover(context.Background(), DiscoveryOptions{Providers: []CloudProvider{VMware}})
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Provider != VMware {
+		t.Errorf("got errors %v, want one VMware error", result.Errors)
+	}
+}
+
+func TestDiscoverRetriesFailedAttempts(t *testing.T) {
+	aws := &fakeConnector{provider: AWS, discover: func(ctx context.Context, attempt int) ([]Resource, error) {
+		if attempt < 3 {
+			return nil, errors.New("throttled")
+		}
+		return resources(AWS, 2), nil
+	}}
+	engine := newTestEngine(EngineConfig{RetryAttempts: 3, RetryDelay: time.Millisecond}, aws)
+
+	result, err := engine.Discover(context.Background(), DiscoveryOptions{Providers: []CloudProvider{AWS}})
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+	if calls := aws.callCount(); calls != 3 {
+		t.Errorf("connector called %d times, want 3", calls)
+	}
+	if len(result.Resources) != 2 || len(result.Errors) != 0 {
+		t.Errorf("got %d resources and errors %v, want 2 resources and no errors", len(result.Resources), result.Errors)
+	}
+}
+
+func TestDiscoverStopsAfterRetryAttempts(t *testing.T) {
+	aws := &fakeConnector{provider: AWS, discover: func(ctx context.Context, attempt int) ([]Resource, error) {
+		return nil, errors.New("unreachable")
+	}}
+	engine := newTestEngine(EngineConfig{RetryAttempts: 4, RetryDelay: time.Millisecond}, aws)
+
+	result, err := engine.Discover(context.Background(), DiscoveryOptions{Providers: []CloudProvider{AWS}})
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+	if calls := aws.callCount(); calls != 4 {
+		t.Errorf("connector called %d times, want 4", calls)
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("got %d errors, want 1", len(result.Errors))
+	}
+}
+
+func TestDiscoverDoesNotRetryPartialResults(t *testing.T) {
+	aws := &fakeConnector{provider: AWS, discover: func(ctx context.Context, attempt int) ([]Resource, error) {
+		return resources(AWS, 2), &PartialDiscoveryError{Errors: []DiscoveryError{
+			{Provider: AWS, Region: "eu-west-1", Message: "denied", Severity: SeverityWarning},
+		}}
+	}}
+	engine := newTestEngine(EngineConfig{RetryAttempts: 3, RetryDelay: time.Millisecond}, aws)
+
+	result, err := engine.Discover(context.Background(), DiscoveryOptions{Providers: []CloudProvider{AWS}})
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+	if calls := aws.callCount(); calls != 1 {
+		t.Errorf("connector called %d times, want 1", calls)
+	}
+	if len(result.Resources) != 2 || len(result.Errors) != 1 || result.Metadata.WarningCount != 1 {
+		t.Errorf("got %d resources, errors %v and %d warnings, want 2 resources and 1 warning",
+			len(result.Resources), result.Errors, result.Metadata.WarningCount)
+	}
+}
+
+func TestDiscoverTimeout(t *testing.T) {
+	aws := &fakeConnector{provider: AWS, discover: func(ctx context.Context, attempt int) ([]Resource, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	engine := newTestEngine(EngineConfig{RetryAttempts: 3, RetryDelay: time.Millisecond}, aws)
+
+	start := time.Now()
+	result, err := engine.Discover(context.Background(), DiscoveryOptions{
+		Providers: []CloudProvider{AWS},
+		Timeout:   20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Discover took %s, want it to stop at the timeout", elapsed)
+	}
+	// A timed out attempt is not retried
+	if calls := aws.callCount(); calls != 1 {
+		t.Errorf("connector called %d times, want 1", calls)
+	}
+	if len(result.Errors) != 1 || !errors.Is(result.Errors[0].Error, context.DeadlineExceeded) {
+		t.Errorf("got errors %v, want a deadline exceeded error", result.Errors)
+	}
+}
+
+func TestDiscoverRespectsMaxConcurrency(t *testing.T) {
+	var running, peak int32
+	discover := func(ctx context.Context, attempt int) ([]Resource, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			seen := atomic.LoadInt32(&peak)
+			if current <= seen || atomic.CompareAndSwapInt32(&peak, seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return nil, nil
+	}
+
+	providers := []CloudProvider{AWS, Azure, GCP, VMware, KVM, OpenStack}
+	connectors := make([]ProviderConnector, 0, len(providers))
+	for _, provider := range providers {
+		connectors = append(connectors, &fakeConnector{provider: provider, discover: discover})
+	}
+	engine := newTestEngine(EngineConfig{MaxConcurrency: 10, RetryDelay: time.Millisecond}, connectors...)
+
+	if _, err := engine.Discover(context.Background(), DiscoveryOptions{Providers: providers, MaxConcurrency: 2}); err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&peak); got > 2 {
+		t.Errorf("%d providers discovered concurrently, want at most 2", got)
+	}
+	for _, connector := range connectors {
+		if calls := connector.(*fakeConnector).callCount(); calls != 1 {
+			t.Errorf("provider %s discovered %d times, want 1", connector.Provider(), calls)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package discovery
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -68,6 +70,12 @@ type DiscoveryError struct {
 	Timestamp    time.Time     `json:"timestamp"`
 }
 
+// Severity levels for discovery errors
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
 // DiscoveryMetadata contains metadata about the discovery operation
 type DiscoveryMetadata struct {
 	StartTime     time.Time              `json:"start_time"`
@@ -137,6 +145,30 @@ type ProviderDiscoveryOptions struct {
 	Tags            map[string]string      `json:"tags,omitempty"`
 	IncludeManaged  bool                   `json:"include_managed"`
 	IncludeDefaults bool                   `json:"include_defaults"`
+	MaxConcurrency  int                    `json:"max_concurrency,omitempty"`
+}
+
+// PartialDiscoveryError is returned by a connector when discovery succeeded for
+// part of the requested scope. The resources returned alongside it are valid and
+// Errors describes the regions or resource types that failed.
+type PartialDiscoveryError struct {
+	Errors []DiscoveryError
+}
+
+// Error implements the error interface
+func (e *PartialDiscoveryError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, discoveryErr := range e.Errors {
+		scope := string(discoveryErr.Provider)
+		if discoveryErr.Region != "" {
+			scope += "/" + discoveryErr.Region
+		}
+		if discoveryErr.ResourceType != "" {
+			scope += "/" + discoveryErr.ResourceType
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", scope, discoveryErr.Message))
+	}
+	return fmt.Sprintf("partial discovery failure (%d errors): %s", len(e.Errors), strings.Join(messages, "; "))
 }
 
 // SteampipeConnector defines the interface for Steampipe integration
@@ -2,7 +2,12 @@ package providers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -19,8 +24,79 @@ type AWSConnector struct {
 	config aws.Config
 	logger *logrus.Logger
 	clients map[string]interface{}
+	mu      sync.Mutex
+
+	// pageSize is the MaxResults value sent with every paginated Describe* call
+	pageSize int32
+
+	// userDataHashes enables hashing the user data of every instance
+	userDataHashes bool
+
+	// newEC2Client builds a regional EC2 client; replaced in tests with a stub
+	newEC2Client func(cfg aws.Config, region string) ec2API
+
+	// newS3Client builds a regional S3 client; replaced in tests with a stub
+	newS3Client func(cfg aws.Config, region string) s3API
+
+	// newIAMClient builds the global IAM client; replaced in tests with a stub
+	newIAMClient func(cfg aws.Config) iamAPI
+
+	// newRDSClient builds a regional RDS client; replaced in tests with a stub
+	newRDSClient func(cfg aws.Config, region string) rdsAPI
+
+	// newLambdaClient builds a regional Lambda client; replaced in tests with a stub
+	newLambdaClient func(cfg aws.Config, region string) lambdaAPI
+
+	// newAPIGatewayClient builds a regional API Gateway client; replaced in tests with a stub
+	newAPIGatewayClient func(cfg aws.Config, region string) apiGatewayAPI
+
+	// newAPIGatewayV2Client builds a regional API Gateway v2 client; replaced in tests with a stub
+	newAPIGatewayV2Client func(cfg aws.Config, region string) apiGatewayV2API
+
+	// newEventBridgeClient builds a regional EventBridge client; replaced in tests with a stub
+	newEventBridgeClient func(cfg aws.Config, region string) eventBridgeAPI
+
+	// buckets caches the account's S3 buckets, which are listed globally
+	buckets   []s3Bucket
+	bucketsMu sync.Mutex
+
+	// iamDetails caches the account's IAM roles, users, groups and policies
+	iamDetails *iamAuthorization
+	iamMu      sync.Mutex
 }
 
+// ec2API is the subset of the EC2 API used for discovery
+type ec2API interface {
+	ec2.DescribeVpcsAPIClient
+	ec2.DescribeSubnetsAPIClient
+	ec2.DescribeSecurityGroupsAPIClient
+	ec2.DescribeSecurityGroupRulesAPIClient
+	ec2.DescribeInstancesAPIClient
+	ec2.DescribeVolumesAPIClient
+	ec2.DescribeInternetGatewaysAPIClient
+	ec2.DescribeEgressOnlyInternetGatewaysAPIClient
+	ec2.DescribeNatGatewaysAPIClient
+	ec2.DescribeRouteTablesAPIClient
+	DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
+	DescribeKeyPairs(ctx context.Context, params *ec2.DescribeKeyPairsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeKeyPairsOutput, error)
+	DescribeInstanceAttribute(ctx context.Context, params *ec2.DescribeInstanceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceAttributeOutput, error)
+}
+
+// AWS Describe* page size limits
+const (
+	defaultAWSPageSize int32 = 100
+	minAWSPageSize     int32 = 5
+	maxAWSPageSize     int32 = 1000
+
+	// Lower MaxResults limits of individual Describe* calls
+	maxEgressOnlyGatewayPageSize int32 = 255
+	maxRouteTablePageSize        int32 = 100
+	maxVolumePageSize            int32 = 500
+)
+
+// Compile-time check that AWSConnector satisfies the ProviderConnector interface
+var _ discovery.ProviderConnector = (*AWSConnector)(nil)
+
 // NewAWSConnector creates a new AWS connector
 func NewAWSConnector(ctx context.Context, region string) (*AWSConnector, error) {
 	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
@@ -29,26 +105,96 @@ func NewAWSConnector(ctx context.Context, region string) (*AWSConnector, error)
 	}
 
 	connector := &AWSConnector{
-		config:  cfg,
-		logger:  logrus.New(),
-		clients: make(map[string]interface{}),
+		config:                cfg,
+		logger:                logrus.New(),
+		clients:               make(map[string]interface{}),
+		pageSize:              defaultAWSPageSize,
+		newEC2Client:          newRegionalEC2Client,
+		newS3Client:           newRegionalS3Client,
+		newIAMClient:          newGlobalIAMClient,
+		newRDSClient:          newRegionalRDSClient,
+		newLambdaClient:       newRegionalLambdaClient,
+		newAPIGatewayClient:   newRegionalAPIGatewayClient,
+		newAPIGatewayV2Client: newRegionalAPIGatewayV2Client,
+		newEventBridgeClient:  newRegionalEventBridgeClient,
 	}
 
-	// Initialize only working clients
-	connector.clients["ec2"] = ec2.NewFromConfig(cfg)
-	connector.clients["sts"] = sts.NewFromConfig(cfg)
+	connector.initializeClients()
 
 	return connector, nil
 }
 
+// initializeClients initializes AWS service clients
+func (c *AWSConnector) initializeClients() {
+	// Initialize only working clients
+	c.clients["ec2"] = ec2.NewFromConfig(c.config)
+	c.clients["ec2:"+c.config.Region] = c.clients["ec2"]
+	c.clients["sts"] = sts.NewFromConfig(c.config)
+}
+
+// SetPageSize sets the number of results requested per page from paginated
+// Describe* calls. Values are clamped to the 5-1000 range accepted by EC2.
+func (c *AWSConnector) SetPageSize(size int32) {
+	switch {
+	case size <= 0:
+		size = defaultAWSPageSize
+	case size < minAWSPageSize:
+		size = minAWSPageSize
+	case size > maxAWSPageSize:
+		size = maxAWSPageSize
+	}
+	c.pageSize = size
+}
+
+// SetUserDataHashes enables recording the SHA-256 hash of the user data of
+// every instance. User data is only returned by DescribeInstanceAttribute,
+// one call per instance, so it is off by default to avoid throttling in
+// accounts with many instances.
+func (c *AWSConnector) SetUserDataHashes(enabled bool) {
+	c.userDataHashes = enabled
+}
+
 // Provider returns the cloud provider this connector supports
 func (c *AWSConnector) Provider() discovery.CloudProvider {
 	return discovery.AWS
 }
 
+// Connect establishes the AWS service clients if they are not already initialized
+func (c *AWSConnector) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.clients) == 0 {
+		c.initializeClients()
+	}
+	return nil
+}
+
+// Disconnect releases the AWS service clients
+func (c *AWSConnector) Disconnect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clients = make(map[string]interface{})
+
+	c.bucketsMu.Lock()
+	c.buckets = nil
+	c.bucketsMu.Unlock()
+
+	c.iamMu.Lock()
+	c.iamDetails = nil
+	c.iamMu.Unlock()
+	return nil
+}
+
 // ValidateCredentials validates AWS credentials
 func (c *AWSConnector) ValidateCredentials(ctx context.Context) error {
-	stsClient := c.clients["sts"].(*sts.Client)
+	c.mu.Lock()
+	stsClient, ok := c.clients["sts"].(*sts.Client)
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("AWS connector is not connected")
+	}
 	
 	_, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 	if err != nil {
@@ -61,7 +207,12 @@ func (c *AWSConnector) ValidateCredentials(ctx context.Context) error {
 
 // GetRegions returns available AWS regions
 func (c *AWSConnector) GetRegions(ctx context.Context) ([]string, error) {
-	ec2Client := c.clients["ec2"].(*ec2.Client)
+	c.mu.Lock()
+	ec2Client, ok := c.clients["ec2"].(*ec2.Client)
+	c.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("AWS connector is not connected")
+	}
 	
 	result, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
 	if err != nil {
@@ -83,14 +234,63 @@ func (c *AWSConnector) GetResourceTypes(ctx context.Context) ([]string, error) {
 		"vpc",
 		"subnet",
 		"security_group",
+		"security_group_rule",
 		"instance",
+		"internet_gateway",
+		"egress_only_internet_gateway",
+		"nat_gateway",
+		"route_table",
+		"route_table_association",
+		"elastic_ip",
+		"ebs_volume",
+		"volume_attachment",
+		"key_pair",
+		"s3_bucket",
+		"iam_role",
+		"iam_policy",
+		"iam_user",
+		"iam_group",
+		"iam_instance_profile",
+		"db_instance",
+		"rds_cluster",
+		"db_subnet_group",
+		"db_parameter_group",
+		"rds_cluster_parameter_group",
+		"db_option_group",
+		"lambda_function",
+		"lambda_event_source_mapping",
+		"api_gateway_rest_api",
+		"apigatewayv2_api",
+		"cloudwatch_event_rule",
 	}, nil
 }
 
-// Discover discovers AWS resources
-func (c *AWSConnector) Discover(ctx context.Context, opts discovery.ProviderDiscoveryOptions) ([]discovery.Resource, error) {
-	var allResources []discovery.Resource
+// GetResourcesByType discovers AWS resources of a specific type in a single region.
+// Global resources such as IAM roles ignore the region.
+func (c *AWSConnector) GetResourcesByType(ctx context.Context, resourceType string, region string) ([]discovery.Resource, error) {
+	resourceType = strings.TrimPrefix(resourceType, "aws_")
+
+	supported, err := c.GetResourceTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(supported, resourceType) {
+		return nil, fmt.Errorf("unsupported AWS resource type: %s", resourceType)
+	}
+
+	if region == "" {
+		region = c.config.Region
+	}
 
+	return c.discoverResourceType(ctx, region, resourceType)
+}
+
+// DiscoverResources discovers AWS resources. Each (region, resource type) pair is
+// scanned by a bounded worker pool sharing the connector's AWS configuration.
+// Failures for individual pairs are returned as a *discovery.PartialDiscoveryError
+// alongside the resources that were discovered successfully. When no pair
+// succeeds there is nothing partial to return, so a plain error is returned.
+func (c *AWSConnector) DiscoverResources(ctx context.Context, opts discovery.ProviderDiscoveryOptions) ([]discovery.Resource, error) {
 	// Get regions to scan
 	regions := opts.Regions
 	if len(regions) == 0 {
@@ -102,55 +302,149 @@ func (c *AWSConnector) Discover(ctx context.Context, opts discovery.ProviderDisc
 	}
 
 	// Get resource types to discover
+	supportedTypes, err := c.GetResourceTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource types: %w", err)
+	}
+
 	resourceTypes := opts.ResourceTypes
 	if len(resourceTypes) == 0 {
-		var err error
-		resourceTypes, err = c.GetResourceTypes(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get resource types: %w", err)
-		}
+		resourceTypes = supportedTypes
 	}
 
-	// Discover resources for each region
-	for _, region := range regions {
-		c.logger.Infof("Discovering AWS resources in region: %s", region)
-		
-		// Create region-specific connector
-		regionConnector, err := NewAWSConnector(ctx, region)
-		if err != nil {
-			c.logger.Warnf("Failed to create connector for region %s: %v", region, err)
+	var discoveryErrors []discovery.DiscoveryError
+
+	// Build the (region, resource type) job list
+	var jobs []awsDiscoveryJob
+	for _, resourceType := range resourceTypes {
+		resourceType = strings.TrimPrefix(resourceType, "aws_")
+		if !containsString(supportedTypes, resourceType) {
+			c.logger.Warnf("Unsupported resource type: %s", resourceType)
+			discoveryErrors = append(discoveryErrors, discovery.DiscoveryError{
+				Provider:     discovery.AWS,
+				ResourceType: resourceType,
+				Message:      fmt.Sprintf("unsupported resource type: %s", resourceType),
+				Severity:     discovery.SeverityWarning,
+				Timestamp:    time.Now(),
+			})
 			continue
 		}
 
-		regionResources, err := regionConnector.discoverRegionResources(ctx, region, resourceTypes)
-		if err != nil {
-			c.logger.Warnf("Failed to discover resources in region %s: %v", region, err)
+		// Global resource types are scanned once rather than per region
+		if iamGlobalTypes[resourceType] {
+			jobs = append(jobs, awsDiscoveryJob{region: "", resourceType: resourceType})
 			continue
 		}
+		for _, region := range regions {
+			jobs = append(jobs, awsDiscoveryJob{region: region, resourceType: resourceType})
+		}
+	}
 
-		allResources = append(allResources, regionResources...)
+	workers := opts.MaxConcurrency
+	if workers <= 0 {
+		workers = defaultAWSConcurrency
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
 	}
 
-	return allResources, nil
-}
+	c.logger.Infof("Discovering AWS resources across %d regions and %d resource types with %d workers", len(regions), len(resourceTypes), workers)
 
-// discoverRegionResources discovers resources in a specific region
-func (c *AWSConnector) discoverRegionResources(ctx context.Context, region string, resourceTypes []string) ([]discovery.Resource, error) {
-	var resources []discovery.Resource
+	results := make([]awsDiscoveryResult, len(jobs))
+	jobIndexes := make(chan int)
+	var wg sync.WaitGroup
 
-	for _, resourceType := range resourceTypes {
-		c.logger.Debugf("Discovering %s resources in region %s", resourceType, region)
-		
-		typeResources, err := c.discoverResourceType(ctx, region, resourceType)
-		if err != nil {
-			c.logger.Warnf("Failed to discover %s resources in region %s: %v", resourceType, region, err)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobIndexes {
+				job := jobs[i]
+				c.logger.Debugf("Discovering %s resources in region %s", job.resourceType, job.region)
+
+				resources, err := c.discoverResourceType(ctx, job.region, job.resourceType)
+				results[i] = awsDiscoveryResult{resources: resources, err: err, done: true}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range jobs {
+		select {
+		case jobIndexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobIndexes)
+	wg.Wait()
+
+	// Merge results in job order so output is deterministic
+	var allResources []discovery.Resource
+	var firstErr error
+	skipped, succeeded := 0, 0
+	for i, result := range results {
+		if !result.done {
+			skipped++
 			continue
 		}
+		if result.err != nil {
+			discoveryErrors = append(discoveryErrors, discovery.DiscoveryError{
+				Provider:     discovery.AWS,
+				Region:       jobs[i].region,
+				ResourceType: jobs[i].resourceType,
+				Message:      result.err.Error(),
+				Error:        result.err,
+				Severity:     discovery.SeverityError,
+				Timestamp:    time.Now(),
+			})
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			continue
+		}
+		succeeded++
+		allResources = append(allResources, result.resources...)
+	}
 
-		resources = append(resources, typeResources...)
+	if skipped > 0 {
+		discoveryErrors = append(discoveryErrors, discovery.DiscoveryError{
+			Provider:  discovery.AWS,
+			Message:   fmt.Sprintf("discovery cancelled before %d of %d region/resource type scans ran: %v", skipped, len(jobs), ctx.Err()),
+			Error:     ctx.Err(),
+			Severity:  discovery.SeverityError,
+			Timestamp: time.Now(),
+		})
+		if firstErr == nil {
+			firstErr = ctx.Err()
+		}
 	}
 
-	return resources, nil
+	if succeeded == 0 && firstErr != nil {
+		return nil, fmt.Errorf("all %d AWS region/resource type scans failed: %w", len(jobs), firstErr)
+	}
+
+	if len(discoveryErrors) > 0 {
+		return allResources, &discovery.PartialDiscoveryError{Errors: discoveryErrors}
+	}
+
+	return allResources, nil
+}
+
+// defaultAWSConcurrency is the worker pool size used when no concurrency is requested
+const defaultAWSConcurrency = 10
+
+// awsDiscoveryJob is a single (region, resource type) scan
+type awsDiscoveryJob struct {
+	region       string
+	resourceType string
+}
+
+// awsDiscoveryResult holds the outcome of a single scan
+type awsDiscoveryResult struct {
+	resources []discovery.Resource
+	err       error
+	done      bool
 }
 
 // discoverResourceType discovers a specific type of AWS resource
@@ -162,8 +456,62 @@ func (c *AWSConnector) discoverResourceType(ctx context.Context, region, resourc
 		return c.discoverSubnets(ctx, region)
 	case "security_group":
 		return c.discoverSecurityGroups(ctx, region)
+	case "security_group_rule":
+		return c.discoverSecurityGroupRules(ctx, region)
 	case "instance":
 		return c.discoverInstances(ctx, region)
+	case "internet_gateway":
+		return c.discoverInternetGateways(ctx, region)
+	case "egress_only_internet_gateway":
+		return c.discoverEgressOnlyInternetGateways(ctx, region)
+	case "nat_gateway":
+		return c.discoverNATGateways(ctx, region)
+	case "route_table":
+		return c.discoverRouteTables(ctx, region)
+	case "route_table_association":
+		return c.discoverRouteTableAssociations(ctx, region)
+	case "elastic_ip":
+		return c.discoverElasticIPs(ctx, region)
+	case "ebs_volume":
+		return c.discoverEBSVolumes(ctx, region)
+	case "volume_attachment":
+		return c.discoverVolumeAttachments(ctx, region)
+	case "key_pair":
+		return c.discoverKeyPairs(ctx, region)
+	case "s3_bucket":
+		return c.discoverS3Buckets(ctx, region)
+	case "iam_role":
+		return c.discoverIAMRoles(ctx)
+	case "iam_policy":
+		return c.discoverIAMPolicies(ctx)
+	case "iam_user":
+		return c.discoverIAMUsers(ctx)
+	case "iam_group":
+		return c.discoverIAMGroups(ctx)
+	case "iam_instance_profile":
+		return c.discoverIAMInstanceProfiles(ctx)
+	case "db_instance":
+		return c.discoverDBInstances(ctx, region)
+	case "rds_cluster":
+		return c.discoverRDSClusters(ctx, region)
+	case "db_subnet_group":
+		return c.discoverDBSubnetGroups(ctx, region)
+	case "db_parameter_group":
+		return c.discoverDBParameterGroups(ctx, region)
+	case "rds_cluster_parameter_group":
+		return c.discoverRDSClusterParameterGroups(ctx, region)
+	case "db_option_group":
+		return c.discoverDBOptionGroups(ctx, region)
+	case "lambda_function":
+		return c.discoverLambdaFunctions(ctx, region)
+	case "lambda_event_source_mapping":
+		return c.discoverLambdaEventSourceMappings(ctx, region)
+	case "api_gateway_rest_api":
+		return c.discoverAPIGatewayRestAPIs(ctx, region)
+	case "apigatewayv2_api":
+		return c.discoverAPIGatewayHTTPAPIs(ctx, region)
+	case "cloudwatch_event_rule":
+		return c.discoverEventBridgeRules(ctx, region)
 	default:
 		c.logger.Warnf("Unsupported resource type: %s", resourceType)
 		return nil, nil
@@ -172,62 +520,553 @@ func (c *AWSConnector) discoverResourceType(ctx context.Context, region, resourc
 
 // discoverVPCs discovers VPCs
 func (c *AWSConnector) discoverVPCs(ctx context.Context, region string) ([]discovery.Resource, error) {
-	ec2Client := c.clients["ec2"].(*ec2.Client)
-	
-	result, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to describe VPCs: %w", err)
+	paginator := ec2.NewDescribeVpcsPaginator(c.ec2Client(region), &ec2.DescribeVpcsInput{
+		MaxResults: aws.Int32(c.pageSize),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe VPCs: %w", err)
+		}
+
+		for _, vpc := range page.Vpcs {
+			resource := discovery.Resource{
+				ID:       aws.ToString(vpc.VpcId),
+				Name:     c.getNameFromTags(vpc.Tags),
+				Type:     "aws_vpc",
+				Provider: discovery.AWS,
+				Region:   region,
+				Metadata: map[string]interface{}{
+					"cidr_block": aws.ToString(vpc.CidrBlock),
+					"state":      string(vpc.State),
+					"is_default": aws.ToBool(vpc.IsDefault),
+					"owner_id":   aws.ToString(vpc.OwnerId),
+				},
+				Tags: c.convertAWSTags(vpc.Tags),
+			}
+
+			resources = append(resources, resource)
+		}
 	}
 
+	return resources, nil
+}
+
+// discoverSubnets discovers subnets
+func (c *AWSConnector) discoverSubnets(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := ec2.NewDescribeSubnetsPaginator(c.ec2Client(region), &ec2.DescribeSubnetsInput{
+		MaxResults: aws.Int32(c.pageSize),
+	})
+
 	var resources []discovery.Resource
-	for _, vpc := range result.Vpcs {
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe subnets: %w", err)
+		}
+
+		for _, subnet := range page.Subnets {
+			resource := discovery.Resource{
+				ID:       aws.ToString(subnet.SubnetId),
+				Name:     c.getNameFromTags(subnet.Tags),
+				Type:     "aws_subnet",
+				Provider: discovery.AWS,
+				Region:   region,
+				Zone:     aws.ToString(subnet.AvailabilityZone),
+				Metadata: map[string]interface{}{
+					"vpc_id":                     aws.ToString(subnet.VpcId),
+					"cidr_block":                 aws.ToString(subnet.CidrBlock),
+					"state":                      string(subnet.State),
+					"map_public_ip_on_launch":    aws.ToBool(subnet.MapPublicIpOnLaunch),
+					"available_ip_address_count": aws.ToInt32(subnet.AvailableIpAddressCount),
+					"owner_id":                   aws.ToString(subnet.OwnerId),
+				},
+				Tags: c.convertAWSTags(subnet.Tags),
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverSecurityGroups discovers security groups
+func (c *AWSConnector) discoverSecurityGroups(ctx context.Context, region string) ([]discovery.Resource, error) {
+	client := c.ec2Client(region)
+	paginator := ec2.NewDescribeSecurityGroupsPaginator(client, &ec2.DescribeSecurityGroupsInput{
+		MaxResults: aws.Int32(c.pageSize),
+	})
+
+	var groups []ec2Types.SecurityGroup
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe security groups: %w", err)
+		}
+		groups = append(groups, page.SecurityGroups...)
+	}
+
+	// Groups referencing other groups are generated with standalone rules,
+	// which are imported by rule ID, so the rule IDs are looked up once per
+	// region when any group does
+	var ruleIDs map[string]string
+	if referencesOtherGroups(groups) {
+		var err error
+		if ruleIDs, err = c.describeSecurityGroupRuleIDs(ctx, client); err != nil {
+			return nil, err
+		}
+	}
+
+	resources := make([]discovery.Resource, 0, len(groups))
+	for _, sg := range groups {
+		groupID := aws.ToString(sg.GroupId)
 		resource := discovery.Resource{
-			ID:       aws.ToString(vpc.VpcId),
-			Name:     c.getNameFromTags(vpc.Tags),
-			Type:     "aws_vpc",
+			ID:       groupID,
+			Name:     aws.ToString(sg.GroupName),
+			Type:     "aws_security_group",
 			Provider: discovery.AWS,
 			Region:   region,
 			Metadata: map[string]interface{}{
-				"cidr_block": aws.ToString(vpc.CidrBlock),
-				"state":      string(vpc.State),
-				"is_default": aws.ToBool(vpc.IsDefault),
+				"vpc_id":      aws.ToString(sg.VpcId),
+				"description": aws.ToString(sg.Description),
+				"owner_id":    aws.ToString(sg.OwnerId),
 			},
-			Tags: c.convertAWSTags(vpc.Tags),
+			Tags: c.convertAWSTags(sg.Tags),
 		}
 
+		// Record every rule so the group can be reproduced exactly
+		resource.Metadata["ingress_rules"] = c.convertIPPermissions(groupID, false, sg.IpPermissions, ruleIDs)
+		resource.Metadata["egress_rules"] = c.convertIPPermissions(groupID, true, sg.IpPermissionsEgress, ruleIDs)
+
 		resources = append(resources, resource)
 	}
 
 	return resources, nil
 }
 
-// discoverSubnets discovers subnets - FIXED VERSION
-func (c *AWSConnector) discoverSubnets(ctx context.Context, region string) ([]discovery.Resource, error) {
-	ec2Client := c.clients["ec2"].(*ec2.Client)
-	
-	// FIXED: Corrected the function call syntax
-	result, err := ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{})
+// referencesOtherGroups reports whether any security group has a rule whose
+// source or destination is another security group
+func referencesOtherGroups(groups []ec2Types.SecurityGroup) bool {
+	for _, sg := range groups {
+		for _, permissions := range [][]ec2Types.IpPermission{sg.IpPermissions, sg.IpPermissionsEgress} {
+			for _, perm := range permissions {
+				for _, pair := range perm.UserIdGroupPairs {
+					if aws.ToString(pair.GroupId) != aws.ToString(sg.GroupId) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// describeSecurityGroupRuleIDs returns the IDs of every security group rule
+// in a region keyed by securityGroupRuleKey
+func (c *AWSConnector) describeSecurityGroupRuleIDs(ctx context.Context, client ec2API) (map[string]string, error) {
+	paginator := ec2.NewDescribeSecurityGroupRulesPaginator(client, &ec2.DescribeSecurityGroupRulesInput{
+		MaxResults: aws.Int32(c.pageSize),
+	})
+
+	ruleIDs := make(map[string]string)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe security group rules: %w", err)
+		}
+
+		for _, rule := range page.SecurityGroupRules {
+			var peer string
+			switch {
+			case rule.CidrIpv4 != nil:
+				peer = aws.ToString(rule.CidrIpv4)
+			case rule.CidrIpv6 != nil:
+				peer = aws.ToString(rule.CidrIpv6)
+			case rule.PrefixListId != nil:
+				peer = aws.ToString(rule.PrefixListId)
+			case rule.ReferencedGroupInfo != nil:
+				peer = aws.ToString(rule.ReferencedGroupInfo.GroupId)
+			}
+			key := securityGroupRuleKey(aws.ToString(rule.GroupId), aws.ToBool(rule.IsEgress), aws.ToString(rule.IpProtocol),
+				aws.ToInt32(rule.FromPort), aws.ToInt32(rule.ToPort), peer)
+			ruleIDs[key] = aws.ToString(rule.SecurityGroupRuleId)
+		}
+	}
+
+	return ruleIDs, nil
+}
+
+// securityGroupRuleKey identifies a security group rule by its group,
+// direction, protocol, ports and peer. Ports are ignored when all protocols
+// are permitted, as the two APIs report them differently.
+func securityGroupRuleKey(groupID string, egress bool, protocol string, fromPort, toPort int32, peer string) string {
+	if protocol == "-1" {
+		fromPort, toPort = 0, 0
+	}
+	return fmt.Sprintf("%s|%t|%s|%d|%d|%s", groupID, egress, protocol, fromPort, toPort, peer)
+}
+
+// discoverSecurityGroupRules discovers individual security group rules
+func (c *AWSConnector) discoverSecurityGroupRules(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := ec2.NewDescribeSecurityGroupRulesPaginator(c.ec2Client(region), &ec2.DescribeSecurityGroupRulesInput{
+		MaxResults: aws.Int32(c.pageSize),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe security group rules: %w", err)
+		}
+
+		for _, rule := range page.SecurityGroupRules {
+			resourceType := "aws_vpc_security_group_ingress_rule"
+			if aws.ToBool(rule.IsEgress) {
+				resourceType = "aws_vpc_security_group_egress_rule"
+			}
+
+			resource := discovery.Resource{
+				ID:       aws.ToString(rule.SecurityGroupRuleId),
+				Name:     c.getNameFromTags(rule.Tags),
+				Type:     resourceType,
+				Provider: discovery.AWS,
+				Region:   region,
+				Metadata: map[string]interface{}{
+					"security_group_id": aws.ToString(rule.GroupId),
+					"ip_protocol":       aws.ToString(rule.IpProtocol),
+					"from_port":         aws.ToInt32(rule.FromPort),
+					"to_port":           aws.ToInt32(rule.ToPort),
+					"is_egress":         aws.ToBool(rule.IsEgress),
+				},
+				Tags: c.convertAWSTags(rule.Tags),
+			}
+
+			if rule.CidrIpv4 != nil {
+				resource.Metadata["cidr_ipv4"] = aws.ToString(rule.CidrIpv4)
+			}
+			if rule.CidrIpv6 != nil {
+				resource.Metadata["cidr_ipv6"] = aws.ToString(rule.CidrIpv6)
+			}
+			if rule.PrefixListId != nil {
+				resource.Metadata["prefix_list_id"] = aws.ToString(rule.PrefixListId)
+			}
+			if rule.ReferencedGroupInfo != nil {
+				resource.Metadata["referenced_security_group_id"] = aws.ToString(rule.ReferencedGroupInfo.GroupId)
+				if userID := aws.ToString(rule.ReferencedGroupInfo.UserId); userID != "" {
+					resource.Metadata["referenced_user_id"] = userID
+				}
+			}
+			if rule.Description != nil {
+				resource.Metadata["description"] = aws.ToString(rule.Description)
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverInstances discovers EC2 instances
+func (c *AWSConnector) discoverInstances(ctx context.Context, region string) ([]discovery.Resource, error) {
+	client := c.ec2Client(region)
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{
+		MaxResults: aws.Int32(c.pageSize),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instances: %w", err)
+		}
+
+		// Volume details are looked up once per page rather than per instance
+		var volumeIDs []string
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				for _, mapping := range instance.BlockDeviceMappings {
+					if mapping.Ebs != nil && mapping.Ebs.VolumeId != nil {
+						volumeIDs = append(volumeIDs, aws.ToString(mapping.Ebs.VolumeId))
+					}
+				}
+			}
+		}
+		volumes, err := c.describeVolumesByID(ctx, client, volumeIDs)
+		if err != nil {
+			c.logger.Warnf("Failed to describe instance volumes in %s: %v", region, err)
+		}
+
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				resource := discovery.Resource{
+					ID:       aws.ToString(instance.InstanceId),
+					Name:     c.getNameFromTags(instance.Tags),
+					Type:     "aws_instance",
+					Provider: discovery.AWS,
+					Region:   region,
+					Metadata: map[string]interface{}{
+						"instance_type": string(instance.InstanceType),
+						"image_id":      aws.ToString(instance.ImageId),
+						"vpc_id":        aws.ToString(instance.VpcId),
+						"subnet_id":     aws.ToString(instance.SubnetId),
+						"private_ip":    aws.ToString(instance.PrivateIpAddress),
+						"public_ip":     aws.ToString(instance.PublicIpAddress),
+						"owner_id":      aws.ToString(reservation.OwnerId),
+					},
+					Tags: c.convertAWSTags(instance.Tags),
+				}
+
+				if instance.Placement != nil {
+					resource.Zone = aws.ToString(instance.Placement.AvailabilityZone)
+				}
+
+				if instance.State != nil {
+					resource.Metadata["state"] = string(instance.State.Name)
+				}
+
+				if instance.LaunchTime != nil {
+					resource.CreatedAt = instance.LaunchTime
+				}
+
+				if instance.KeyName != nil {
+					resource.Metadata["key_name"] = aws.ToString(instance.KeyName)
+				}
+
+				securityGroupIDs := make([]string, 0, len(instance.SecurityGroups))
+				for _, group := range instance.SecurityGroups {
+					securityGroupIDs = append(securityGroupIDs, aws.ToString(group.GroupId))
+				}
+				resource.Metadata["security_group_ids"] = securityGroupIDs
+
+				resource.Metadata["root_device_name"] = aws.ToString(instance.RootDeviceName)
+				resource.Metadata["block_devices"] = c.convertBlockDevices(instance, volumes)
+				resource.Metadata["ebs_optimized"] = aws.ToBool(instance.EbsOptimized)
+
+				if instance.Monitoring != nil {
+					resource.Metadata["monitoring"] = instance.Monitoring.State == ec2Types.MonitoringStateEnabled
+				}
+
+				if instance.IamInstanceProfile != nil {
+					arn := aws.ToString(instance.IamInstanceProfile.Arn)
+					resource.Metadata["iam_instance_profile_arn"] = arn
+					if idx := strings.LastIndex(arn, "/"); idx >= 0 {
+						resource.Metadata["iam_instance_profile"] = arn[idx+1:]
+					}
+				}
+
+				if options := instance.MetadataOptions; options != nil {
+					resource.Metadata["metadata_options"] = map[string]interface{}{
+						"http_endpoint":               string(options.HttpEndpoint),
+						"http_tokens":                 string(options.HttpTokens),
+						"http_put_response_hop_limit": aws.ToInt32(options.HttpPutResponseHopLimit),
+						"instance_metadata_tags":      string(options.InstanceMetadataTags),
+					}
+				}
+
+				// User data may contain secrets, so only its hash is recorded
+				if c.userDataHashes {
+					userDataHash, err := c.getUserDataHash(ctx, client, resource.ID)
+					if err != nil {
+						c.logger.Warnf("Failed to read user data for instance %s: %v", resource.ID, err)
+					} else if userDataHash != "" {
+						resource.Metadata["user_data_hash"] = userDataHash
+					}
+				}
+
+				resources = append(resources, resource)
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverInternetGateways discovers internet gateways
+func (c *AWSConnector) discoverInternetGateways(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := ec2.NewDescribeInternetGatewaysPaginator(c.ec2Client(region), &ec2.DescribeInternetGatewaysInput{
+		MaxResults: aws.Int32(c.pageSize),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe internet gateways: %w", err)
+		}
+
+		for _, gateway := range page.InternetGateways {
+			resource := discovery.Resource{
+				ID:       aws.ToString(gateway.InternetGatewayId),
+				Name:     c.getNameFromTags(gateway.Tags),
+				Type:     "aws_internet_gateway",
+				Provider: discovery.AWS,
+				Region:   region,
+				Metadata: map[string]interface{}{
+					"owner_id": aws.ToString(gateway.OwnerId),
+				},
+				Tags: c.convertAWSTags(gateway.Tags),
+			}
+
+			// An internet gateway is attached to at most one VPC
+			for _, attachment := range gateway.Attachments {
+				resource.Metadata["vpc_id"] = aws.ToString(attachment.VpcId)
+				resource.Metadata["state"] = string(attachment.State)
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverEgressOnlyInternetGateways discovers egress-only internet gateways
+func (c *AWSConnector) discoverEgressOnlyInternetGateways(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := ec2.NewDescribeEgressOnlyInternetGatewaysPaginator(c.ec2Client(region), &ec2.DescribeEgressOnlyInternetGatewaysInput{
+		MaxResults: c.maxResults(maxEgressOnlyGatewayPageSize),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe egress-only internet gateways: %w", err)
+		}
+
+		for _, gateway := range page.EgressOnlyInternetGateways {
+			resource := discovery.Resource{
+				ID:       aws.ToString(gateway.EgressOnlyInternetGatewayId),
+				Name:     c.getNameFromTags(gateway.Tags),
+				Type:     "aws_egress_only_internet_gateway",
+				Provider: discovery.AWS,
+				Region:   region,
+				Metadata: map[string]interface{}{},
+				Tags:     c.convertAWSTags(gateway.Tags),
+			}
+
+			for _, attachment := range gateway.Attachments {
+				resource.Metadata["vpc_id"] = aws.ToString(attachment.VpcId)
+				resource.Metadata["state"] = string(attachment.State)
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverNATGateways discovers NAT gateways, skipping deleted gateways
+func (c *AWSConnector) discoverNATGateways(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := ec2.NewDescribeNatGatewaysPaginator(c.ec2Client(region), &ec2.DescribeNatGatewaysInput{
+		MaxResults: aws.Int32(c.pageSize),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe NAT gateways: %w", err)
+		}
+
+		for _, gateway := range page.NatGateways {
+			if gateway.State == ec2Types.NatGatewayStateDeleted || gateway.State == ec2Types.NatGatewayStateDeleting {
+				continue
+			}
+
+			resource := discovery.Resource{
+				ID:        aws.ToString(gateway.NatGatewayId),
+				Name:      c.getNameFromTags(gateway.Tags),
+				Type:      "aws_nat_gateway",
+				Provider:  discovery.AWS,
+				Region:    region,
+				CreatedAt: gateway.CreateTime,
+				Metadata: map[string]interface{}{
+					"vpc_id":            aws.ToString(gateway.VpcId),
+					"subnet_id":         aws.ToString(gateway.SubnetId),
+					"connectivity_type": string(gateway.ConnectivityType),
+					"state":             string(gateway.State),
+				},
+				Tags: c.convertAWSTags(gateway.Tags),
+			}
+
+			var secondaryAllocations, secondaryPrivateIPs []string
+			for _, address := range gateway.NatGatewayAddresses {
+				if aws.ToBool(address.IsPrimary) {
+					if address.AllocationId != nil {
+						resource.Metadata["allocation_id"] = aws.ToString(address.AllocationId)
+					}
+					resource.Metadata["private_ip"] = aws.ToString(address.PrivateIp)
+					resource.Metadata["public_ip"] = aws.ToString(address.PublicIp)
+					resource.Metadata["network_interface_id"] = aws.ToString(address.NetworkInterfaceId)
+					continue
+				}
+				if address.AllocationId != nil {
+					secondaryAllocations = append(secondaryAllocations, aws.ToString(address.AllocationId))
+				} else if address.PrivateIp != nil {
+					secondaryPrivateIPs = append(secondaryPrivateIPs, aws.ToString(address.PrivateIp))
+				}
+			}
+			if len(secondaryAllocations) > 0 {
+				resource.Metadata["secondary_allocation_ids"] = secondaryAllocations
+			}
+			if len(secondaryPrivateIPs) > 0 {
+				resource.Metadata["secondary_private_ip_addresses"] = secondaryPrivateIPs
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverRouteTables discovers route tables with every route; subnet and
+// gateway associations are discovered as route_table_association resources
+func (c *AWSConnector) discoverRouteTables(ctx context.Context, region string) ([]discovery.Resource, error) {
+	routeTables, err := c.describeRouteTables(ctx, region)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe subnets: %w", err)
+		return nil, err
 	}
 
 	var resources []discovery.Resource
-	for _, subnet := range result.Subnets {
+	for _, table := range routeTables {
 		resource := discovery.Resource{
-			ID:       aws.ToString(subnet.SubnetId),
-			Name:     c.getNameFromTags(subnet.Tags),
-			Type:     "aws_subnet",
+			ID:       aws.ToString(table.RouteTableId),
+			Name:     c.getNameFromTags(table.Tags),
+			Type:     "aws_route_table",
 			Provider: discovery.AWS,
 			Region:   region,
-			Zone:     aws.ToString(subnet.AvailabilityZone),
 			Metadata: map[string]interface{}{
-				"vpc_id":                        aws.ToString(subnet.VpcId),
-				"cidr_block":                    aws.ToString(subnet.CidrBlock),
-				"state":                         string(subnet.State),
-				"map_public_ip_on_launch":       aws.ToBool(subnet.MapPublicIpOnLaunch),
-				"available_ip_address_count":    aws.ToInt32(subnet.AvailableIpAddressCount),
+				"vpc_id":   aws.ToString(table.VpcId),
+				"owner_id": aws.ToString(table.OwnerId),
+				"main":     false,
 			},
-			Tags: c.convertAWSTags(subnet.Tags),
+			Tags: c.convertAWSTags(table.Tags),
+		}
+
+		for _, association := range table.Associations {
+			if aws.ToBool(association.Main) {
+				resource.Metadata["main"] = true
+			}
+		}
+
+		routes := make([]map[string]interface{}, 0, len(table.Routes))
+		for _, route := range table.Routes {
+			routes = append(routes, c.convertRoute(route))
+		}
+		resource.Metadata["routes"] = routes
+
+		if len(table.PropagatingVgws) > 0 {
+			gateways := make([]string, 0, len(table.PropagatingVgws))
+			for _, vgw := range table.PropagatingVgws {
+				gateways = append(gateways, aws.ToString(vgw.GatewayId))
+			}
+			resource.Metadata["propagating_vgws"] = gateways
 		}
 
 		resources = append(resources, resource)
@@ -236,34 +1075,92 @@ func (c *AWSConnector) discoverSubnets(ctx context.Context, region string) ([]di
 	return resources, nil
 }
 
-// discoverSecurityGroups discovers security groups
-func (c *AWSConnector) discoverSecurityGroups(ctx context.Context, region string) ([]discovery.Resource, error) {
-	ec2Client := c.clients["ec2"].(*ec2.Client)
-	
-	result, err := ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{})
+// discoverRouteTableAssociations discovers the explicit subnet and gateway
+// associations of route tables. The implicit main route table association
+// is recorded on the route table itself.
+func (c *AWSConnector) discoverRouteTableAssociations(ctx context.Context, region string) ([]discovery.Resource, error) {
+	routeTables, err := c.describeRouteTables(ctx, region)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe security groups: %w", err)
+		return nil, err
 	}
 
 	var resources []discovery.Resource
-	for _, sg := range result.SecurityGroups {
+	for _, table := range routeTables {
+		for _, association := range table.Associations {
+			if aws.ToBool(association.Main) {
+				continue
+			}
+
+			resource := discovery.Resource{
+				ID:       aws.ToString(association.RouteTableAssociationId),
+				Type:     "aws_route_table_association",
+				Provider: discovery.AWS,
+				Region:   region,
+				Metadata: map[string]interface{}{
+					"route_table_id": aws.ToString(association.RouteTableId),
+					"vpc_id":         aws.ToString(table.VpcId),
+				},
+			}
+
+			if association.SubnetId != nil {
+				resource.Metadata["subnet_id"] = aws.ToString(association.SubnetId)
+			}
+			if association.GatewayId != nil {
+				resource.Metadata["gateway_id"] = aws.ToString(association.GatewayId)
+			}
+			if association.AssociationState != nil {
+				resource.Metadata["state"] = string(association.AssociationState.State)
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverElasticIPs discovers Elastic IP addresses
+func (c *AWSConnector) discoverElasticIPs(ctx context.Context, region string) ([]discovery.Resource, error) {
+	// DescribeAddresses is not paginated and returns every address at once
+	output, err := c.ec2Client(region).DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe addresses: %w", err)
+	}
+
+	var resources []discovery.Resource
+	for _, address := range output.Addresses {
+		id := aws.ToString(address.AllocationId)
+		if id == "" {
+			// EC2-Classic addresses have no allocation ID
+			id = aws.ToString(address.PublicIp)
+		}
+
 		resource := discovery.Resource{
-			ID:       aws.ToString(sg.GroupId),
-			Name:     aws.ToString(sg.GroupName),
-			Type:     "aws_security_group",
+			ID:       id,
+			Name:     c.getNameFromTags(address.Tags),
+			Type:     "aws_elastic_ip",
 			Provider: discovery.AWS,
 			Region:   region,
 			Metadata: map[string]interface{}{
-				"vpc_id":      aws.ToString(sg.VpcId),
-				"description": aws.ToString(sg.Description),
-				"owner_id":    aws.ToString(sg.OwnerId),
+				"public_ip": aws.ToString(address.PublicIp),
+				"domain":    string(address.Domain),
 			},
-			Tags: c.convertAWSTags(sg.Tags),
+			Tags: c.convertAWSTags(address.Tags),
 		}
 
-		// Add rule counts
-		resource.Metadata["ingress_rules"] = len(sg.IpPermissions)
-		resource.Metadata["egress_rules"] = len(sg.IpPermissionsEgress)
+		optional := map[string]*string{
+			"association_id":       address.AssociationId,
+			"instance_id":          address.InstanceId,
+			"network_interface_id": address.NetworkInterfaceId,
+			"private_ip":           address.PrivateIpAddress,
+			"public_ipv4_pool":     address.PublicIpv4Pool,
+			"network_border_group": address.NetworkBorderGroup,
+		}
+		for key, value := range optional {
+			if aws.ToString(value) != "" {
+				resource.Metadata[key] = aws.ToString(value)
+			}
+		}
 
 		resources = append(resources, resource)
 	}
@@ -271,47 +1168,148 @@ func (c *AWSConnector) discoverSecurityGroups(ctx context.Context, region string
 	return resources, nil
 }
 
-// discoverInstances discovers EC2 instances
-func (c *AWSConnector) discoverInstances(ctx context.Context, region string) ([]discovery.Resource, error) {
-	ec2Client := c.clients["ec2"].(*ec2.Client)
-	
-	result, err := ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{})
+// discoverEBSVolumes discovers EBS volumes with their attachments. Root
+// volumes are described by the root_block_device of their instance and are
+// not discovered separately.
+func (c *AWSConnector) discoverEBSVolumes(ctx context.Context, region string) ([]discovery.Resource, error) {
+	volumes, rootDevices, err := c.describeVolumes(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []discovery.Resource
+	for _, volume := range volumes {
+		if isRootVolume(volume, rootDevices) {
+			continue
+		}
+
+		resource := discovery.Resource{
+			ID:        aws.ToString(volume.VolumeId),
+			Name:      c.getNameFromTags(volume.Tags),
+			Type:      "aws_ebs_volume",
+			Provider:  discovery.AWS,
+			Region:    region,
+			Zone:      aws.ToString(volume.AvailabilityZone),
+			CreatedAt: volume.CreateTime,
+			Metadata: map[string]interface{}{
+				"size":                 aws.ToInt32(volume.Size),
+				"volume_type":          string(volume.VolumeType),
+				"encrypted":            aws.ToBool(volume.Encrypted),
+				"multi_attach_enabled": aws.ToBool(volume.MultiAttachEnabled),
+				"state":                string(volume.State),
+			},
+			Tags: c.convertAWSTags(volume.Tags),
+		}
+
+		if volume.Iops != nil {
+			resource.Metadata["iops"] = aws.ToInt32(volume.Iops)
+		}
+		if volume.Throughput != nil {
+			resource.Metadata["throughput"] = aws.ToInt32(volume.Throughput)
+		}
+		if volume.KmsKeyId != nil {
+			resource.Metadata["kms_key_id"] = aws.ToString(volume.KmsKeyId)
+		}
+		if volume.SnapshotId != nil && aws.ToString(volume.SnapshotId) != "" {
+			resource.Metadata["snapshot_id"] = aws.ToString(volume.SnapshotId)
+		}
+		if volume.OutpostArn != nil {
+			resource.Metadata["outpost_arn"] = aws.ToString(volume.OutpostArn)
+		}
+
+		attachments := make([]map[string]interface{}, 0, len(volume.Attachments))
+		for _, attachment := range volume.Attachments {
+			attachments = append(attachments, map[string]interface{}{
+				"attached_instance_id":  aws.ToString(attachment.InstanceId),
+				"device_name":           aws.ToString(attachment.Device),
+				"delete_on_termination": aws.ToBool(attachment.DeleteOnTermination),
+				"state":                 string(attachment.State),
+			})
+		}
+		resource.Metadata["attachments"] = attachments
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// discoverVolumeAttachments discovers the attachments of EBS volumes to
+// instances, except root volume attachments. Attachments are identified by
+// DEVICE_NAME:VOLUME_ID:INSTANCE_ID, the ID Terraform imports them with.
+func (c *AWSConnector) discoverVolumeAttachments(ctx context.Context, region string) ([]discovery.Resource, error) {
+	volumes, rootDevices, err := c.describeVolumes(ctx, region)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe instances: %w", err)
+		return nil, err
 	}
 
 	var resources []discovery.Resource
-	for _, reservation := range result.Reservations {
-		for _, instance := range reservation.Instances {
+	for _, volume := range volumes {
+		if isRootVolume(volume, rootDevices) {
+			continue
+		}
+
+		for _, attachment := range volume.Attachments {
+			device := aws.ToString(attachment.Device)
+			volumeID := aws.ToString(attachment.VolumeId)
+			instanceID := aws.ToString(attachment.InstanceId)
+
 			resource := discovery.Resource{
-				ID:       aws.ToString(instance.InstanceId),
-				Name:     c.getNameFromTags(instance.Tags),
-				Type:     "aws_instance",
-				Provider: discovery.AWS,
-				Region:   region,
-				Zone:     aws.ToString(instance.Placement.AvailabilityZone),
+				ID:        fmt.Sprintf("%s:%s:%s", device, volumeID, instanceID),
+				Type:      "aws_volume_attachment",
+				Provider:  discovery.AWS,
+				Region:    region,
+				Zone:      aws.ToString(volume.AvailabilityZone),
+				CreatedAt: attachment.AttachTime,
 				Metadata: map[string]interface{}{
-					"instance_type":   string(instance.InstanceType),
-					"state":           string(instance.State.Name),
-					"image_id":        aws.ToString(instance.ImageId),
-					"vpc_id":          aws.ToString(instance.VpcId),
-					"subnet_id":       aws.ToString(instance.SubnetId),
-					"private_ip":      aws.ToString(instance.PrivateIpAddress),
-					"public_ip":       aws.ToString(instance.PublicIpAddress),
+					"device_name":           device,
+					"volume_id":             volumeID,
+					"instance_id":           instanceID,
+					"delete_on_termination": aws.ToBool(attachment.DeleteOnTermination),
+					"state":                 string(attachment.State),
 				},
-				Tags: c.convertAWSTags(instance.Tags),
 			}
 
-			if instance.LaunchTime != nil {
-				resource.CreatedAt = instance.LaunchTime
-			}
+			resources = append(resources, resource)
+		}
+	}
 
-			if instance.KeyName != nil {
-				resource.Metadata["key_name"] = aws.ToString(instance.KeyName)
-			}
+	return resources, nil
+}
 
-			resources = append(resources, resource)
+// discoverKeyPairs discovers EC2 key pairs with their public keys
+func (c *AWSConnector) discoverKeyPairs(ctx context.Context, region string) ([]discovery.Resource, error) {
+	// DescribeKeyPairs is not paginated and returns every key pair at once
+	output, err := c.ec2Client(region).DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{
+		IncludePublicKey: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe key pairs: %w", err)
+	}
+
+	var resources []discovery.Resource
+	for _, keyPair := range output.KeyPairs {
+		resource := discovery.Resource{
+			ID:        aws.ToString(keyPair.KeyPairId),
+			Name:      aws.ToString(keyPair.KeyName),
+			Type:      "aws_key_pair",
+			Provider:  discovery.AWS,
+			Region:    region,
+			CreatedAt: keyPair.CreateTime,
+			Metadata: map[string]interface{}{
+				"key_name":        aws.ToString(keyPair.KeyName),
+				"key_type":        string(keyPair.KeyType),
+				"key_fingerprint": aws.ToString(keyPair.KeyFingerprint),
+			},
+			Tags: c.convertAWSTags(keyPair.Tags),
+		}
+
+		// The public key is not secret and lets the key pair be recreated
+		if keyPair.PublicKey != nil {
+			resource.Metadata["public_key"] = strings.TrimSpace(aws.ToString(keyPair.PublicKey))
 		}
+
+		resources = append(resources, resource)
 	}
 
 	return resources, nil
@@ -319,6 +1317,325 @@ func (c *AWSConnector) discoverInstances(ctx context.Context, region string) ([]
 
 // Helper functions
 
+// ec2Client returns an EC2 client for the given region. Regional clients are
+// built from the shared AWS config once and cached for reuse by all workers.
+func (c *AWSConnector) ec2Client(region string) ec2API {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if region == "" {
+		region = c.config.Region
+	}
+
+	key := "ec2:" + region
+	if client, exists := c.clients[key]; exists {
+		return client.(ec2API)
+	}
+
+	client := c.newEC2Client(c.config, region)
+	c.clients[key] = client
+	return client
+}
+
+// newRegionalEC2Client creates an EC2 client for a region from a shared config
+func newRegionalEC2Client(cfg aws.Config, region string) ec2API {
+	return ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+		o.Region = region
+	})
+}
+
+// maxResults returns the page size to request from Describe* calls whose
+// MaxResults limit is below the EC2 maximum
+func (c *AWSConnector) maxResults(limit int32) *int32 {
+	return aws.Int32(min(c.pageSize, limit))
+}
+
+// describeRouteTables returns every route table in a region
+func (c *AWSConnector) describeRouteTables(ctx context.Context, region string) ([]ec2Types.RouteTable, error) {
+	paginator := ec2.NewDescribeRouteTablesPaginator(c.ec2Client(region), &ec2.DescribeRouteTablesInput{
+		MaxResults: c.maxResults(maxRouteTablePageSize),
+	})
+
+	var routeTables []ec2Types.RouteTable
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe route tables: %w", err)
+		}
+		routeTables = append(routeTables, page.RouteTables...)
+	}
+
+	return routeTables, nil
+}
+
+// convertRoute converts a route into a metadata entry holding its
+// destination, target, origin and state
+func (c *AWSConnector) convertRoute(route ec2Types.Route) map[string]interface{} {
+	entry := map[string]interface{}{
+		"origin": string(route.Origin),
+		"state":  string(route.State),
+	}
+
+	fields := map[string]*string{
+		"destination_cidr_block":          route.DestinationCidrBlock,
+		"destination_ipv6_cidr_block":     route.DestinationIpv6CidrBlock,
+		"destination_prefix_list_id":      route.DestinationPrefixListId,
+		"gateway_id":                      route.GatewayId,
+		"nat_gateway_id":                  route.NatGatewayId,
+		"egress_only_internet_gateway_id": route.EgressOnlyInternetGatewayId,
+		"transit_gateway_id":              route.TransitGatewayId,
+		"vpc_peering_connection_id":       route.VpcPeeringConnectionId,
+		"network_interface_id":            route.NetworkInterfaceId,
+		"instance_id":                     route.InstanceId,
+		"carrier_gateway_id":              route.CarrierGatewayId,
+		"local_gateway_id":                route.LocalGatewayId,
+		"core_network_arn":                route.CoreNetworkArn,
+	}
+	for key, value := range fields {
+		if aws.ToString(value) != "" {
+			entry[key] = aws.ToString(value)
+		}
+	}
+
+	return entry
+}
+
+// describeVolumes returns every EBS volume in a region along with the root
+// device name of each instance a volume is attached to
+func (c *AWSConnector) describeVolumes(ctx context.Context, region string) ([]ec2Types.Volume, map[string]string, error) {
+	client := c.ec2Client(region)
+	paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{
+		MaxResults: c.maxResults(maxVolumePageSize),
+	})
+
+	var volumes []ec2Types.Volume
+	var instanceIDs []string
+	seen := make(map[string]bool)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to describe volumes: %w", err)
+		}
+		for _, volume := range page.Volumes {
+			for _, attachment := range volume.Attachments {
+				if instanceID := aws.ToString(attachment.InstanceId); instanceID != "" && !seen[instanceID] {
+					seen[instanceID] = true
+					instanceIDs = append(instanceIDs, instanceID)
+				}
+			}
+		}
+		volumes = append(volumes, page.Volumes...)
+	}
+
+	rootDevices, err := c.describeRootDevices(ctx, client, instanceIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return volumes, rootDevices, nil
+}
+
+// describeRootDevices returns the root device name of the given instances
+// keyed by instance ID
+func (c *AWSConnector) describeRootDevices(ctx context.Context, client ec2API, instanceIDs []string) (map[string]string, error) {
+	rootDevices := make(map[string]string, len(instanceIDs))
+
+	// MaxResults cannot be combined with InstanceIds, so IDs are sent in batches
+	for start := 0; start < len(instanceIDs); start += int(c.pageSize) {
+		end := min(start+int(c.pageSize), len(instanceIDs))
+
+		paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{
+			InstanceIds: instanceIDs[start:end],
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe attached instances: %w", err)
+			}
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					rootDevices[aws.ToString(instance.InstanceId)] = aws.ToString(instance.RootDeviceName)
+				}
+			}
+		}
+	}
+
+	return rootDevices, nil
+}
+
+// isRootVolume reports whether a volume is attached as the root device of
+// an instance
+func isRootVolume(volume ec2Types.Volume, rootDevices map[string]string) bool {
+	for _, attachment := range volume.Attachments {
+		rootDevice, exists := rootDevices[aws.ToString(attachment.InstanceId)]
+		if exists && rootDevice != "" && rootDevice == aws.ToString(attachment.Device) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeVolumesByID returns the given EBS volumes keyed by volume ID
+func (c *AWSConnector) describeVolumesByID(ctx context.Context, client ec2API, volumeIDs []string) (map[string]ec2Types.Volume, error) {
+	volumes := make(map[string]ec2Types.Volume, len(volumeIDs))
+
+	// MaxResults cannot be combined with VolumeIds, so IDs are sent in batches
+	for start := 0; start < len(volumeIDs); start += int(c.pageSize) {
+		end := start + int(c.pageSize)
+		if end > len(volumeIDs) {
+			end = len(volumeIDs)
+		}
+
+		paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{
+			VolumeIds: volumeIDs[start:end],
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return volumes, fmt.Errorf("failed to describe volumes: %w", err)
+			}
+			for _, volume := range page.Volumes {
+				volumes[aws.ToString(volume.VolumeId)] = volume
+			}
+		}
+	}
+
+	return volumes, nil
+}
+
+// convertBlockDevices converts instance block device mappings into metadata
+// entries, enriched with volume details where available
+func (c *AWSConnector) convertBlockDevices(instance ec2Types.Instance, volumes map[string]ec2Types.Volume) []map[string]interface{} {
+	rootDevice := aws.ToString(instance.RootDeviceName)
+	devices := make([]map[string]interface{}, 0, len(instance.BlockDeviceMappings))
+
+	for _, mapping := range instance.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+
+		deviceName := aws.ToString(mapping.DeviceName)
+		volumeID := aws.ToString(mapping.Ebs.VolumeId)
+		device := map[string]interface{}{
+			"device_name":           deviceName,
+			"volume_id":             volumeID,
+			"delete_on_termination": aws.ToBool(mapping.Ebs.DeleteOnTermination),
+			"is_root":               deviceName == rootDevice,
+		}
+
+		if volume, exists := volumes[volumeID]; exists {
+			device["volume_size"] = aws.ToInt32(volume.Size)
+			device["volume_type"] = string(volume.VolumeType)
+			device["encrypted"] = aws.ToBool(volume.Encrypted)
+			if volume.Iops != nil {
+				device["iops"] = aws.ToInt32(volume.Iops)
+			}
+			if volume.Throughput != nil {
+				device["throughput"] = aws.ToInt32(volume.Throughput)
+			}
+			if volume.KmsKeyId != nil {
+				device["kms_key_id"] = aws.ToString(volume.KmsKeyId)
+			}
+			if volume.SnapshotId != nil {
+				device["snapshot_id"] = aws.ToString(volume.SnapshotId)
+			}
+		}
+
+		devices = append(devices, device)
+	}
+
+	return devices
+}
+
+// getUserDataHash returns the SHA-256 hash of an instance's user data, or an
+// empty string when the instance has none
+func (c *AWSConnector) getUserDataHash(ctx context.Context, client ec2API, instanceID string) (string, error) {
+	output, err := client.DescribeInstanceAttribute(ctx, &ec2.DescribeInstanceAttributeInput{
+		InstanceId: aws.String(instanceID),
+		Attribute:  ec2Types.InstanceAttributeNameUserData,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe instance attribute: %w", err)
+	}
+
+	if output.UserData == nil || aws.ToString(output.UserData.Value) == "" {
+		return "", nil
+	}
+
+	userData, err := base64.StdEncoding.DecodeString(aws.ToString(output.UserData.Value))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode user data: %w", err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(userData)), nil
+}
+
+// convertIPPermissions converts EC2 IP permissions into metadata rule
+// entries, recording the ID of each source's rule when it is known
+func (c *AWSConnector) convertIPPermissions(groupID string, egress bool, permissions []ec2Types.IpPermission, ruleIDs map[string]string) []map[string]interface{} {
+	rules := make([]map[string]interface{}, 0, len(permissions))
+	for _, perm := range permissions {
+		rule := map[string]interface{}{
+			"protocol":  aws.ToString(perm.IpProtocol),
+			"from_port": aws.ToInt32(perm.FromPort),
+			"to_port":   aws.ToInt32(perm.ToPort),
+		}
+
+		source := func(peer, description string) map[string]interface{} {
+			entry := map[string]interface{}{"description": description}
+			key := securityGroupRuleKey(groupID, egress, aws.ToString(perm.IpProtocol), aws.ToInt32(perm.FromPort), aws.ToInt32(perm.ToPort), peer)
+			if ruleID, exists := ruleIDs[key]; exists {
+				entry["rule_id"] = ruleID
+			}
+			return entry
+		}
+
+		if len(perm.IpRanges) > 0 {
+			ranges := make([]map[string]interface{}, 0, len(perm.IpRanges))
+			for _, r := range perm.IpRanges {
+				entry := source(aws.ToString(r.CidrIp), aws.ToString(r.Description))
+				entry["cidr"] = aws.ToString(r.CidrIp)
+				ranges = append(ranges, entry)
+			}
+			rule["cidr_blocks"] = ranges
+		}
+
+		if len(perm.Ipv6Ranges) > 0 {
+			ranges := make([]map[string]interface{}, 0, len(perm.Ipv6Ranges))
+			for _, r := range perm.Ipv6Ranges {
+				entry := source(aws.ToString(r.CidrIpv6), aws.ToString(r.Description))
+				entry["cidr"] = aws.ToString(r.CidrIpv6)
+				ranges = append(ranges, entry)
+			}
+			rule["ipv6_cidr_blocks"] = ranges
+		}
+
+		if len(perm.PrefixListIds) > 0 {
+			prefixLists := make([]map[string]interface{}, 0, len(perm.PrefixListIds))
+			for _, p := range perm.PrefixListIds {
+				entry := source(aws.ToString(p.PrefixListId), aws.ToString(p.Description))
+				entry["prefix_list_id"] = aws.ToString(p.PrefixListId)
+				prefixLists = append(prefixLists, entry)
+			}
+			rule["prefix_list_ids"] = prefixLists
+		}
+
+		if len(perm.UserIdGroupPairs) > 0 {
+			groups := make([]map[string]interface{}, 0, len(perm.UserIdGroupPairs))
+			for _, pair := range perm.UserIdGroupPairs {
+				entry := source(aws.ToString(pair.GroupId), aws.ToString(pair.Description))
+				entry["group_id"] = aws.ToString(pair.GroupId)
+				entry["user_id"] = aws.ToString(pair.UserId)
+				groups = append(groups, entry)
+			}
+			rule["security_groups"] = groups
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
 // getNameFromTags extracts the Name tag from AWS tags
 func (c *AWSConnector) getNameFromTags(tags []ec2Types.Tag) string {
 	for _, tag := range tags {
@@ -336,4 +1653,13 @@ func (c *AWSConnector) convertAWSTags(tags []ec2Types.Tag) map[string]string {
 		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
 	return result
-}
\ No newline at end of file
+}
+// containsString checks if a string is in the given list
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,525 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	apiGatewayTypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	apiGatewayV2Types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+)
+
+// apiGatewayAPI is the subset of the API Gateway REST API management API
+// used for discovery
+type apiGatewayAPI interface {
+	apigateway.GetRestApisAPIClient
+	apigateway.GetResourcesAPIClient
+	GetStages(ctx context.Context, params *apigateway.GetStagesInput, optFns ...func(*apigateway.Options)) (*apigateway.GetStagesOutput, error)
+}
+
+// apiGatewayV2API is the subset of the API Gateway HTTP API management API
+// used for discovery
+type apiGatewayV2API interface {
+	GetApis(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error)
+	GetIntegrations(ctx context.Context, params *apigatewayv2.GetIntegrationsInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetIntegrationsOutput, error)
+	GetRoutes(ctx context.Context, params *apigatewayv2.GetRoutesInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetRoutesOutput, error)
+	GetStages(ctx context.Context, params *apigatewayv2.GetStagesInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetStagesOutput, error)
+}
+
+// maxAPIGatewayPageSize is the largest page the API Gateway management APIs return
+const maxAPIGatewayPageSize int32 = 500
+
+// discoverAPIGatewayRestAPIs discovers API Gateway REST APIs with their
+// resources, methods, method integrations and stages. Deployments are not
+// discovered, so stages keep the ID of the deployment they serve.
+func (c *AWSConnector) discoverAPIGatewayRestAPIs(ctx context.Context, region string) ([]discovery.Resource, error) {
+	client := c.apiGatewayClient(region)
+	paginator := apigateway.NewGetRestApisPaginator(client, &apigateway.GetRestApisInput{
+		Limit: c.apiGatewayLimit(),
+	})
+
+	var apis []apiGatewayTypes.RestApi
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get REST APIs: %w", err)
+		}
+		apis = append(apis, page.Items...)
+	}
+
+	var resources []discovery.Resource
+	for _, api := range apis {
+		apiID := aws.ToString(api.Id)
+		name := aws.ToString(api.Name)
+
+		paths, err := c.discoverRestAPIResources(ctx, region, apiID)
+		if err != nil {
+			c.logger.Warnf("Failed to get resources of REST API %s: %v", name, err)
+		}
+
+		// The root resource is created with the API, so it is not a resource of its own
+		rootID := aws.ToString(api.RootResourceId)
+		for _, path := range paths {
+			if aws.ToString(path.ParentId) == "" {
+				rootID = aws.ToString(path.Id)
+			}
+		}
+
+		var endpointTypes []string
+		if api.EndpointConfiguration != nil {
+			for _, endpointType := range api.EndpointConfiguration.Types {
+				endpointTypes = append(endpointTypes, string(endpointType))
+			}
+		}
+
+		resource := discovery.Resource{
+			ID:        apiID,
+			Name:      name,
+			Type:      "aws_api_gateway_rest_api",
+			Provider:  discovery.AWS,
+			Region:    region,
+			CreatedAt: api.CreatedDate,
+			Metadata: map[string]interface{}{
+				"description":                  aws.ToString(api.Description),
+				"endpoint_types":               endpointTypes,
+				"root_resource_id":             rootID,
+				"binary_media_types":           api.BinaryMediaTypes,
+				"disable_execute_api_endpoint": api.DisableExecuteApiEndpoint,
+			},
+			Tags: api.Tags,
+		}
+		if api.ApiKeySource != "" {
+			resource.Metadata["api_key_source"] = string(api.ApiKeySource)
+		}
+		resources = append(resources, resource)
+
+		for _, path := range paths {
+			resources = append(resources, c.convertRestAPIResource(apiID, rootID, path, region)...)
+		}
+
+		stages, err := client.GetStages(ctx, &apigateway.GetStagesInput{RestApiId: api.Id})
+		if err != nil {
+			c.logger.Warnf("Failed to get stages of REST API %s: %v", name, err)
+			continue
+		}
+		for _, stage := range stages.Item {
+			stageName := aws.ToString(stage.StageName)
+			resources = append(resources, discovery.Resource{
+				ID:       apiID + "/" + stageName,
+				Type:     "aws_api_gateway_stage",
+				Provider: discovery.AWS,
+				Region:   region,
+				Metadata: map[string]interface{}{
+					"rest_api_id":           apiID,
+					"stage_name":            stageName,
+					"deployment_id":         aws.ToString(stage.DeploymentId),
+					"description":           aws.ToString(stage.Description),
+					"cache_cluster_enabled": stage.CacheClusterEnabled,
+					"cache_cluster_size":    string(stage.CacheClusterSize),
+					"xray_tracing_enabled":  stage.TracingEnabled,
+					"variables":             stage.Variables,
+				},
+				Tags: stage.Tags,
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverRestAPIResources lists the resources of a REST API with their methods
+func (c *AWSConnector) discoverRestAPIResources(ctx context.Context, region, apiID string) ([]apiGatewayTypes.Resource, error) {
+	paginator := apigateway.NewGetResourcesPaginator(c.apiGatewayClient(region), &apigateway.GetResourcesInput{
+		RestApiId: aws.String(apiID),
+		Embed:     []string{"methods"},
+		Limit:     c.apiGatewayLimit(),
+	})
+
+	var paths []apiGatewayTypes.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, page.Items...)
+	}
+
+	sort.Slice(paths, func(i, j int) bool { return aws.ToString(paths[i].Path) < aws.ToString(paths[j].Path) })
+	return paths, nil
+}
+
+// convertRestAPIResource converts a REST API resource to a resource and its
+// methods and method integrations to resources of their own
+func (c *AWSConnector) convertRestAPIResource(apiID, rootID string, path apiGatewayTypes.Resource, region string) []discovery.Resource {
+	pathID := aws.ToString(path.Id)
+
+	var resources []discovery.Resource
+	if pathID != rootID {
+		resources = append(resources, discovery.Resource{
+			ID:       pathID,
+			Type:     "aws_api_gateway_resource",
+			Provider: discovery.AWS,
+			Region:   region,
+			Metadata: map[string]interface{}{
+				"rest_api_id":      apiID,
+				"root_resource_id": rootID,
+				"parent_id":        aws.ToString(path.ParentId),
+				"path_part":        aws.ToString(path.PathPart),
+				"path":             aws.ToString(path.Path),
+			},
+		})
+	}
+
+	methods := make([]string, 0, len(path.ResourceMethods))
+	for httpMethod := range path.ResourceMethods {
+		methods = append(methods, httpMethod)
+	}
+	sort.Strings(methods)
+
+	for _, httpMethod := range methods {
+		method := path.ResourceMethods[httpMethod]
+		methodID := apiID + "/" + pathID + "/" + httpMethod
+
+		parameters := make(map[string]interface{}, len(method.RequestParameters))
+		for name, required := range method.RequestParameters {
+			parameters[name] = required
+		}
+
+		resources = append(resources, discovery.Resource{
+			ID:       methodID,
+			Type:     "aws_api_gateway_method",
+			Provider: discovery.AWS,
+			Region:   region,
+			Metadata: map[string]interface{}{
+				"rest_api_id":        apiID,
+				"resource_id":        pathID,
+				"root_resource_id":   rootID,
+				"path":               aws.ToString(path.Path),
+				"http_method":        httpMethod,
+				"authorization":      aws.ToString(method.AuthorizationType),
+				"authorizer_id":      aws.ToString(method.AuthorizerId),
+				"api_key_required":   aws.ToBool(method.ApiKeyRequired),
+				"operation_name":     aws.ToString(method.OperationName),
+				"request_parameters": parameters,
+			},
+		})
+
+		integration := method.MethodIntegration
+		if integration == nil {
+			continue
+		}
+		uri := aws.ToString(integration.Uri)
+		resource := discovery.Resource{
+			ID:       methodID + "/integration",
+			Type:     "aws_api_gateway_integration",
+			Provider: discovery.AWS,
+			Region:   region,
+			Metadata: map[string]interface{}{
+				"rest_api_id":             apiID,
+				"resource_id":             pathID,
+				"root_resource_id":        rootID,
+				"method_id":               methodID,
+				"path":                    aws.ToString(path.Path),
+				"http_method":             httpMethod,
+				"type":                    string(integration.Type),
+				"integration_http_method": aws.ToString(integration.HttpMethod),
+				"uri":                     uri,
+				"credentials":             aws.ToString(integration.Credentials),
+				"passthrough_behavior":    aws.ToString(integration.PassthroughBehavior),
+				"content_handling":        string(integration.ContentHandling),
+				"timeout_milliseconds":    integration.TimeoutInMillis,
+				"connection_type":         string(integration.ConnectionType),
+				"connection_id":           aws.ToString(integration.ConnectionId),
+				"request_parameters":      integration.RequestParameters,
+				"request_templates":       integration.RequestTemplates,
+			},
+		}
+		if function := lambdaFunctionARN(uri); function != "" {
+			resource.Metadata["function_arn"] = function
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources
+}
+
+// discoverAPIGatewayHTTPAPIs discovers API Gateway HTTP APIs with their
+// integrations, routes and stages. WebSocket APIs are not discovered.
+func (c *AWSConnector) discoverAPIGatewayHTTPAPIs(ctx context.Context, region string) ([]discovery.Resource, error) {
+	client := c.apiGatewayV2Client(region)
+	apis, err := listHTTPAPIItems(func(token *string) ([]apiGatewayV2Types.Api, *string, error) {
+		page, err := client.GetApis(ctx, &apigatewayv2.GetApisInput{MaxResults: c.apiGatewayV2MaxResults(), NextToken: token})
+		if err != nil {
+			return nil, nil, err
+		}
+		return page.Items, page.NextToken, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HTTP APIs: %w", err)
+	}
+
+	var resources []discovery.Resource
+	for _, api := range apis {
+		if api.ProtocolType != apiGatewayV2Types.ProtocolTypeHttp {
+			continue
+		}
+
+		apiID := aws.ToString(api.ApiId)
+		resource := discovery.Resource{
+			ID:        apiID,
+			Name:      aws.ToString(api.Name),
+			Type:      "aws_apigatewayv2_api",
+			Provider:  discovery.AWS,
+			Region:    region,
+			CreatedAt: api.CreatedDate,
+			Metadata: map[string]interface{}{
+				"protocol_type":                string(api.ProtocolType),
+				"description":                  aws.ToString(api.Description),
+				"api_endpoint":                 aws.ToString(api.ApiEndpoint),
+				"disable_execute_api_endpoint": aws.ToBool(api.DisableExecuteApiEndpoint),
+			},
+			Tags: api.Tags,
+		}
+		if cors := api.CorsConfiguration; cors != nil {
+			resource.Metadata["cors_configuration"] = map[string]interface{}{
+				"allow_origins":     cors.AllowOrigins,
+				"allow_methods":     cors.AllowMethods,
+				"allow_headers":     cors.AllowHeaders,
+				"expose_headers":    cors.ExposeHeaders,
+				"max_age":           aws.ToInt32(cors.MaxAge),
+				"allow_credentials": aws.ToBool(cors.AllowCredentials),
+			}
+		}
+		resources = append(resources, resource)
+
+		children, err := c.discoverHTTPAPIChildren(ctx, region, apiID)
+		if err != nil {
+			c.logger.Warnf("Failed to discover routes of HTTP API %s: %v", aws.ToString(api.Name), err)
+		}
+		resources = append(resources, children...)
+	}
+
+	return resources, nil
+}
+
+// discoverHTTPAPIChildren discovers the integrations, routes and stages of an HTTP API
+func (c *AWSConnector) discoverHTTPAPIChildren(ctx context.Context, region, apiID string) ([]discovery.Resource, error) {
+	client := c.apiGatewayV2Client(region)
+
+	integrations, err := listHTTPAPIItems(func(token *string) ([]apiGatewayV2Types.Integration, *string, error) {
+		page, err := client.GetIntegrations(ctx, &apigatewayv2.GetIntegrationsInput{ApiId: aws.String(apiID), MaxResults: c.apiGatewayV2MaxResults(), NextToken: token})
+		if err != nil {
+			return nil, nil, err
+		}
+		return page.Items, page.NextToken, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get integrations: %w", err)
+	}
+
+	routes, err := listHTTPAPIItems(func(token *string) ([]apiGatewayV2Types.Route, *string, error) {
+		page, err := client.GetRoutes(ctx, &apigatewayv2.GetRoutesInput{ApiId: aws.String(apiID), MaxResults: c.apiGatewayV2MaxResults(), NextToken: token})
+		if err != nil {
+			return nil, nil, err
+		}
+		return page.Items, page.NextToken, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get routes: %w", err)
+	}
+
+	stages, err := listHTTPAPIItems(func(token *string) ([]apiGatewayV2Types.Stage, *string, error) {
+		page, err := client.GetStages(ctx, &apigatewayv2.GetStagesInput{ApiId: aws.String(apiID), MaxResults: c.apiGatewayV2MaxResults(), NextToken: token})
+		if err != nil {
+			return nil, nil, err
+		}
+		return page.Items, page.NextToken, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stages: %w", err)
+	}
+
+	var resources []discovery.Resource
+	for _, integration := range integrations {
+		uri := aws.ToString(integration.IntegrationUri)
+		resource := discovery.Resource{
+			ID:       aws.ToString(integration.IntegrationId),
+			Type:     "aws_apigatewayv2_integration",
+			Provider: discovery.AWS,
+			Region:   region,
+			Metadata: map[string]interface{}{
+				"api_id":                 apiID,
+				"integration_type":       string(integration.IntegrationType),
+				"integration_uri":        uri,
+				"integration_method":     aws.ToString(integration.IntegrationMethod),
+				"payload_format_version": aws.ToString(integration.PayloadFormatVersion),
+				"connection_type":        string(integration.ConnectionType),
+				"connection_id":          aws.ToString(integration.ConnectionId),
+				"credentials_arn":        aws.ToString(integration.CredentialsArn),
+				"description":            aws.ToString(integration.Description),
+				"timeout_milliseconds":   aws.ToInt32(integration.TimeoutInMillis),
+				"request_parameters":     integration.RequestParameters,
+			},
+		}
+		if function := lambdaFunctionARN(uri); function != "" {
+			resource.Metadata["function_arn"] = function
+		}
+		resources = append(resources, resource)
+	}
+
+	for _, route := range routes {
+		target := aws.ToString(route.Target)
+		resource := discovery.Resource{
+			ID:       aws.ToString(route.RouteId),
+			Type:     "aws_apigatewayv2_route",
+			Provider: discovery.AWS,
+			Region:   region,
+			Metadata: map[string]interface{}{
+				"api_id":             apiID,
+				"route_key":          aws.ToString(route.RouteKey),
+				"target":             target,
+				"authorization_type": string(route.AuthorizationType),
+				"authorizer_id":      aws.ToString(route.AuthorizerId),
+				"api_key_required":   aws.ToBool(route.ApiKeyRequired),
+				"operation_name":     aws.ToString(route.OperationName),
+			},
+		}
+		if integration := strings.TrimPrefix(target, "integrations/"); integration != target {
+			resource.Metadata["integration_id"] = integration
+		}
+		resources = append(resources, resource)
+	}
+
+	for _, stage := range stages {
+		stageName := aws.ToString(stage.StageName)
+		resources = append(resources, discovery.Resource{
+			ID:       apiID + "/" + stageName,
+			Type:     "aws_apigatewayv2_stage",
+			Provider: discovery.AWS,
+			Region:   region,
+			Metadata: map[string]interface{}{
+				"api_id":          apiID,
+				"stage_name":      stageName,
+				"auto_deploy":     aws.ToBool(stage.AutoDeploy),
+				"deployment_id":   aws.ToString(stage.DeploymentId),
+				"description":     aws.ToString(stage.Description),
+				"stage_variables": stage.StageVariables,
+			},
+			Tags: stage.Tags,
+		})
+	}
+
+	return resources, nil
+}
+
+// listHTTPAPIItems calls a paginated HTTP API management operation, which
+// has no SDK paginator, and returns the items of every page
+func listHTTPAPIItems[T any](list func(token *string) ([]T, *string, error)) ([]T, error) {
+	var items []T
+	var token *string
+	for {
+		page, next, err := list(token)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		if token = next; aws.ToString(token) == "" {
+			return items, nil
+		}
+	}
+}
+
+// apiGatewayClient returns the API Gateway client for a region, creating it on first use
+func (c *AWSConnector) apiGatewayClient(region string) apiGatewayAPI {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if region == "" {
+		region = c.config.Region
+	}
+
+	key := "apigateway:" + region
+	if client, exists := c.clients[key]; exists {
+		return client.(apiGatewayAPI)
+	}
+
+	client := c.newAPIGatewayClient(c.config, region)
+	c.clients[key] = client
+	return client
+}
+
+// apiGatewayV2Client returns the API Gateway v2 client for a region, creating it on first use
+func (c *AWSConnector) apiGatewayV2Client(region string) apiGatewayV2API {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if region == "" {
+		region = c.config.Region
+	}
+
+	key := "apigatewayv2:" + region
+	if client, exists := c.clients[key]; exists {
+		return client.(apiGatewayV2API)
+	}
+
+	client := c.newAPIGatewayV2Client(c.config, region)
+	c.clients[key] = client
+	return client
+}
+
+// newRegionalAPIGatewayClient creates an API Gateway client for a region
+// from a shared config
+func newRegionalAPIGatewayClient(cfg aws.Config, region string) apiGatewayAPI {
+	return apigateway.NewFromConfig(cfg, func(o *apigateway.Options) {
+		o.Region = region
+	})
+}
+
+// newRegionalAPIGatewayV2Client creates an API Gateway v2 client for a
+// region from a shared config
+func newRegionalAPIGatewayV2Client(cfg aws.Config, region string) apiGatewayV2API {
+	return apigatewayv2.NewFromConfig(cfg, func(o *apigatewayv2.Options) {
+		o.Region = region
+	})
+}
+
+// apiGatewayPageSize returns the connector's page size capped at the
+// largest page the API Gateway management APIs return
+func (c *AWSConnector) apiGatewayPageSize() int32 {
+	if c.pageSize > maxAPIGatewayPageSize {
+		return maxAPIGatewayPageSize
+	}
+	return c.pageSize
+}
+
+// apiGatewayLimit returns the page size of paginated REST API calls
+func (c *AWSConnector) apiGatewayLimit() *int32 {
+	return aws.Int32(c.apiGatewayPageSize())
+}
+
+// apiGatewayV2MaxResults returns the page size of paginated HTTP API calls,
+// which the v2 API takes as a string
+func (c *AWSConnector) apiGatewayV2MaxResults() *string {
+	return aws.String(strconv.Itoa(int(c.apiGatewayPageSize())))
+}
+
+// lambdaFunctionARN returns the ARN of the Lambda function an API Gateway
+// integration invokes, given either the function ARN or its invocation URI
+// arn:aws:apigateway:region:lambda:path/2015-03-31/functions/<arn>/invocations
+func lambdaFunctionARN(uri string) string {
+	if strings.HasPrefix(uri, "arn:") && strings.Contains(uri, ":lambda:") && strings.Contains(uri, ":function:") {
+		if i := strings.Index(uri, "/functions/"); i >= 0 {
+			return strings.TrimSuffix(uri[i+len("/functions/"):], "/invocations")
+		}
+		return uri
+	}
+	return ""
+}
@@ -0,0 +1,223 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventBridgeTypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+)
+
+// eventBridgeAPI is the subset of the EventBridge API used for discovery
+type eventBridgeAPI interface {
+	ListEventBuses(ctx context.Context, params *eventbridge.ListEventBusesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListEventBusesOutput, error)
+	ListRules(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error)
+	ListTargetsByRule(ctx context.Context, params *eventbridge.ListTargetsByRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error)
+	ListTagsForResource(ctx context.Context, params *eventbridge.ListTagsForResourceInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTagsForResourceOutput, error)
+}
+
+// maxEventBridgePageSize is the largest page EventBridge List* calls return
+const maxEventBridgePageSize int32 = 100
+
+// discoverEventBridgeRules discovers the EventBridge rules of every event
+// bus with their targets. Rules managed by other AWS services are skipped.
+// The List* calls have no SDK paginators, so pages are followed by NextToken.
+func (c *AWSConnector) discoverEventBridgeRules(ctx context.Context, region string) ([]discovery.Resource, error) {
+	client := c.eventBridgeClient(region)
+
+	var buses []string
+	var token *string
+	for {
+		page, err := client.ListEventBuses(ctx, &eventbridge.ListEventBusesInput{Limit: c.eventBridgeLimit(), NextToken: token})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list event buses: %w", err)
+		}
+		for _, bus := range page.EventBuses {
+			buses = append(buses, aws.ToString(bus.Name))
+		}
+
+		if token = page.NextToken; aws.ToString(token) == "" {
+			break
+		}
+	}
+
+	var resources []discovery.Resource
+	for _, bus := range buses {
+		var token *string
+		for {
+			page, err := client.ListRules(ctx, &eventbridge.ListRulesInput{
+				EventBusName: aws.String(bus),
+				Limit:        c.eventBridgeLimit(),
+				NextToken:    token,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list rules of event bus %s: %w", bus, err)
+			}
+
+			for _, rule := range page.Rules {
+				if aws.ToString(rule.ManagedBy) != "" {
+					continue
+				}
+				resources = append(resources, c.convertEventBridgeRule(ctx, region, rule))
+
+				targets, err := c.discoverEventBridgeTargets(ctx, region, rule)
+				if err != nil {
+					c.logger.Warnf("Failed to list targets of rule %s: %v", aws.ToString(rule.Name), err)
+				}
+				resources = append(resources, targets...)
+			}
+
+			if token = page.NextToken; aws.ToString(token) == "" {
+				break
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// convertEventBridgeRule converts a rule to a resource
+func (c *AWSConnector) convertEventBridgeRule(ctx context.Context, region string, rule eventBridgeTypes.Rule) discovery.Resource {
+	arn := aws.ToString(rule.Arn)
+	resource := discovery.Resource{
+		ID:       arn,
+		Name:     aws.ToString(rule.Name),
+		Type:     "aws_cloudwatch_event_rule",
+		Provider: discovery.AWS,
+		Region:   region,
+		Status:   string(rule.State),
+		Metadata: map[string]interface{}{
+			"arn":            arn,
+			"event_bus_name": aws.ToString(rule.EventBusName),
+			"description":    aws.ToString(rule.Description),
+			"state":          string(rule.State),
+		},
+		Tags: c.eventBridgeTags(ctx, region, arn),
+	}
+	if pattern := aws.ToString(rule.EventPattern); pattern != "" {
+		resource.Metadata["event_pattern"] = pattern
+	}
+	if schedule := aws.ToString(rule.ScheduleExpression); schedule != "" {
+		resource.Metadata["schedule_expression"] = schedule
+	}
+	if role := aws.ToString(rule.RoleArn); role != "" {
+		resource.Metadata["role_arn"] = role
+	}
+	return resource
+}
+
+// discoverEventBridgeTargets discovers the targets of a rule
+func (c *AWSConnector) discoverEventBridgeTargets(ctx context.Context, region string, rule eventBridgeTypes.Rule) ([]discovery.Resource, error) {
+	arn := aws.ToString(rule.Arn)
+
+	var resources []discovery.Resource
+	var token *string
+	for {
+		page, err := c.eventBridgeClient(region).ListTargetsByRule(ctx, &eventbridge.ListTargetsByRuleInput{
+			Rule:         rule.Name,
+			EventBusName: rule.EventBusName,
+			Limit:        c.eventBridgeLimit(),
+			NextToken:    token,
+		})
+		if err != nil {
+			return resources, err
+		}
+
+		for _, target := range page.Targets {
+			resource := discovery.Resource{
+				ID:       arn + "/" + aws.ToString(target.Id),
+				Type:     "aws_cloudwatch_event_target",
+				Provider: discovery.AWS,
+				Region:   region,
+				Metadata: map[string]interface{}{
+					"rule":           aws.ToString(rule.Name),
+					"rule_arn":       arn,
+					"event_bus_name": aws.ToString(rule.EventBusName),
+					"target_id":      aws.ToString(target.Id),
+					"arn":            aws.ToString(target.Arn),
+				},
+			}
+			if role := aws.ToString(target.RoleArn); role != "" {
+				resource.Metadata["role_arn"] = role
+			}
+			if input := aws.ToString(target.Input); input != "" {
+				resource.Metadata["input"] = input
+			}
+			if path := aws.ToString(target.InputPath); path != "" {
+				resource.Metadata["input_path"] = path
+			}
+			if transformer := target.InputTransformer; transformer != nil {
+				resource.Metadata["input_transformer"] = map[string]interface{}{
+					"input_paths":    transformer.InputPathsMap,
+					"input_template": aws.ToString(transformer.InputTemplate),
+				}
+			}
+			resources = append(resources, resource)
+		}
+
+		if token = page.NextToken; aws.ToString(token) == "" {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// eventBridgeTags lists the tags of a rule, logging rather than failing
+// discovery when they cannot be read
+func (c *AWSConnector) eventBridgeTags(ctx context.Context, region, arn string) map[string]string {
+	result, err := c.eventBridgeClient(region).ListTagsForResource(ctx, &eventbridge.ListTagsForResourceInput{ResourceARN: aws.String(arn)})
+	if err != nil {
+		c.logger.Warnf("Failed to list tags of %s: %v", arn, err)
+		return nil
+	}
+	if len(result.Tags) == 0 {
+		return nil
+	}
+
+	tags := make(map[string]string, len(result.Tags))
+	for _, tag := range result.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags
+}
+
+// eventBridgeClient returns the EventBridge client for a region, creating it on first use
+func (c *AWSConnector) eventBridgeClient(region string) eventBridgeAPI {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if region == "" {
+		region = c.config.Region
+	}
+
+	key := "events:" + region
+	if client, exists := c.clients[key]; exists {
+		return client.(eventBridgeAPI)
+	}
+
+	client := c.newEventBridgeClient(c.config, region)
+	c.clients[key] = client
+	return client
+}
+
+// newRegionalEventBridgeClient creates an EventBridge client for a region
+// from a shared config
+func newRegionalEventBridgeClient(cfg aws.Config, region string) eventBridgeAPI {
+	return eventbridge.NewFromConfig(cfg, func(o *eventbridge.Options) {
+		o.Region = region
+	})
+}
+
+// eventBridgeLimit returns the page size of paginated EventBridge calls,
+// with the connector's page size capped at the largest page they return
+func (c *AWSConnector) eventBridgeLimit() *int32 {
+	size := c.pageSize
+	if size > maxEventBridgePageSize {
+		size = maxEventBridgePageSize
+	}
+	return aws.Int32(size)
+}
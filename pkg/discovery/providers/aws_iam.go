@@ -0,0 +1,412 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamTypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+)
+
+// iamAPI is the subset of the IAM API used for discovery
+type iamAPI interface {
+	iam.GetAccountAuthorizationDetailsAPIClient
+	iam.ListRolesAPIClient
+	iam.ListInstanceProfilesAPIClient
+}
+
+// iamGlobalTypes are the IAM resource types; IAM is global, so they are
+// discovered once rather than in every region
+var iamGlobalTypes = map[string]bool{
+	"iam_role":             true,
+	"iam_policy":           true,
+	"iam_user":             true,
+	"iam_group":            true,
+	"iam_instance_profile": true,
+}
+
+// serviceLinkedRolePath is the path of roles created and managed by AWS services
+const serviceLinkedRolePath = "/aws-service-role/"
+
+// iamAuthorization holds the roles, users, groups and customer managed
+// policies of an account, read with GetAccountAuthorizationDetails
+type iamAuthorization struct {
+	roles    []iamTypes.RoleDetail
+	users    []iamTypes.UserDetail
+	groups   []iamTypes.GroupDetail
+	policies []iamTypes.ManagedPolicyDetail
+}
+
+// discoverIAMRoles discovers IAM roles with their trust policies, together
+// with their inline policies and managed policy attachments. The policies
+// of service-linked roles are managed by AWS and are left out.
+func (c *AWSConnector) discoverIAMRoles(ctx context.Context) ([]discovery.Resource, error) {
+	details, err := c.describeIAMAuthorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Descriptions and session durations are only returned by ListRoles
+	listed := make(map[string]iamTypes.Role)
+	paginator := iam.NewListRolesPaginator(c.iamClient(), &iam.ListRolesInput{
+		MaxItems: aws.Int32(c.pageSize),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list IAM roles: %w", err)
+		}
+		for _, role := range page.Roles {
+			listed[aws.ToString(role.Arn)] = role
+		}
+	}
+
+	var resources []discovery.Resource
+	for _, role := range details.roles {
+		arn := aws.ToString(role.Arn)
+		name := aws.ToString(role.RoleName)
+		serviceLinked := strings.HasPrefix(aws.ToString(role.Path), serviceLinkedRolePath)
+
+		resource := discovery.Resource{
+			ID:        arn,
+			Name:      name,
+			Type:      "aws_iam_role",
+			Provider:  discovery.AWS,
+			CreatedAt: role.CreateDate,
+			Metadata: map[string]interface{}{
+				"arn":                arn,
+				"role_id":            aws.ToString(role.RoleId),
+				"path":               aws.ToString(role.Path),
+				"assume_role_policy": decodePolicyDocument(role.AssumeRolePolicyDocument),
+				"service_linked":     serviceLinked,
+			},
+			Tags: c.convertIAMTags(role.Tags),
+		}
+
+		if summary, exists := listed[arn]; exists {
+			resource.Metadata["description"] = aws.ToString(summary.Description)
+			resource.Metadata["max_session_duration"] = aws.ToInt32(summary.MaxSessionDuration)
+		}
+		if boundary := role.PermissionsBoundary; boundary != nil {
+			resource.Metadata["permissions_boundary"] = aws.ToString(boundary.PermissionsBoundaryArn)
+		}
+		if lastUsed := role.RoleLastUsed; lastUsed != nil && lastUsed.LastUsedDate != nil {
+			resource.Metadata["last_used_date"] = lastUsed.LastUsedDate.UTC().Format(time.RFC3339)
+			resource.Metadata["last_used_region"] = aws.ToString(lastUsed.Region)
+		}
+
+		resources = append(resources, resource)
+		if !serviceLinked {
+			resources = append(resources, c.iamPrincipalPolicies("role", arn, name, role.RolePolicyList, role.AttachedManagedPolicies)...)
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverIAMUsers discovers IAM users with their inline policies, managed
+// policy attachments and group memberships
+func (c *AWSConnector) discoverIAMUsers(ctx context.Context) ([]discovery.Resource, error) {
+	details, err := c.describeIAMAuthorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groupARNs := make(map[string]string, len(details.groups))
+	for _, group := range details.groups {
+		groupARNs[aws.ToString(group.GroupName)] = aws.ToString(group.Arn)
+	}
+
+	var resources []discovery.Resource
+	for _, user := range details.users {
+		arn := aws.ToString(user.Arn)
+		name := aws.ToString(user.UserName)
+
+		resource := discovery.Resource{
+			ID:        arn,
+			Name:      name,
+			Type:      "aws_iam_user",
+			Provider:  discovery.AWS,
+			CreatedAt: user.CreateDate,
+			Metadata: map[string]interface{}{
+				"arn":     arn,
+				"user_id": aws.ToString(user.UserId),
+				"path":    aws.ToString(user.Path),
+			},
+			Tags: c.convertIAMTags(user.Tags),
+		}
+		if boundary := user.PermissionsBoundary; boundary != nil {
+			resource.Metadata["permissions_boundary"] = aws.ToString(boundary.PermissionsBoundaryArn)
+		}
+
+		resources = append(resources, resource)
+		resources = append(resources, c.iamPrincipalPolicies("user", arn, name, user.UserPolicyList, user.AttachedManagedPolicies)...)
+
+		if len(user.GroupList) > 0 {
+			groups := make([]string, 0, len(user.GroupList))
+			for _, group := range user.GroupList {
+				if groupARN, exists := groupARNs[group]; exists {
+					groups = append(groups, groupARN)
+				}
+			}
+			resources = append(resources, discovery.Resource{
+				ID:       arn + "/groups",
+				Type:     "aws_iam_user_group_membership",
+				Provider: discovery.AWS,
+				Metadata: map[string]interface{}{
+					"user_arn":    arn,
+					"user":        name,
+					"groups":      groups,
+					"group_names": user.GroupList,
+				},
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverIAMGroups discovers IAM groups with their inline policies and
+// managed policy attachments
+func (c *AWSConnector) discoverIAMGroups(ctx context.Context) ([]discovery.Resource, error) {
+	details, err := c.describeIAMAuthorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []discovery.Resource
+	for _, group := range details.groups {
+		arn := aws.ToString(group.Arn)
+		name := aws.ToString(group.GroupName)
+
+		resources = append(resources, discovery.Resource{
+			ID:        arn,
+			Name:      name,
+			Type:      "aws_iam_group",
+			Provider:  discovery.AWS,
+			CreatedAt: group.CreateDate,
+			Metadata: map[string]interface{}{
+				"arn":      arn,
+				"group_id": aws.ToString(group.GroupId),
+				"path":     aws.ToString(group.Path),
+			},
+		})
+		resources = append(resources, c.iamPrincipalPolicies("group", arn, name, group.GroupPolicyList, group.AttachedManagedPolicies)...)
+	}
+
+	return resources, nil
+}
+
+// discoverIAMPolicies discovers customer managed IAM policies with the
+// document of their default version
+func (c *AWSConnector) discoverIAMPolicies(ctx context.Context) ([]discovery.Resource, error) {
+	details, err := c.describeIAMAuthorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []discovery.Resource
+	for _, policy := range details.policies {
+		arn := aws.ToString(policy.Arn)
+		resource := discovery.Resource{
+			ID:        arn,
+			Name:      aws.ToString(policy.PolicyName),
+			Type:      "aws_iam_policy",
+			Provider:  discovery.AWS,
+			CreatedAt: policy.CreateDate,
+			Metadata: map[string]interface{}{
+				"arn":                arn,
+				"policy_id":          aws.ToString(policy.PolicyId),
+				"path":               aws.ToString(policy.Path),
+				"description":        aws.ToString(policy.Description),
+				"default_version_id": aws.ToString(policy.DefaultVersionId),
+				"attachment_count":   aws.ToInt32(policy.AttachmentCount),
+			},
+		}
+
+		for _, version := range policy.PolicyVersionList {
+			if version.IsDefaultVersion {
+				resource.Metadata["policy"] = decodePolicyDocument(version.Document)
+				break
+			}
+		}
+		if policy.UpdateDate != nil {
+			resource.UpdatedAt = policy.UpdateDate
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// discoverIAMInstanceProfiles discovers IAM instance profiles
+func (c *AWSConnector) discoverIAMInstanceProfiles(ctx context.Context) ([]discovery.Resource, error) {
+	paginator := iam.NewListInstanceProfilesPaginator(c.iamClient(), &iam.ListInstanceProfilesInput{
+		MaxItems: aws.Int32(c.pageSize),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list IAM instance profiles: %w", err)
+		}
+
+		for _, profile := range page.InstanceProfiles {
+			arn := aws.ToString(profile.Arn)
+			resource := discovery.Resource{
+				ID:        arn,
+				Name:      aws.ToString(profile.InstanceProfileName),
+				Type:      "aws_iam_instance_profile",
+				Provider:  discovery.AWS,
+				CreatedAt: profile.CreateDate,
+				Metadata: map[string]interface{}{
+					"arn":                 arn,
+					"instance_profile_id": aws.ToString(profile.InstanceProfileId),
+					"path":                aws.ToString(profile.Path),
+				},
+				Tags: c.convertIAMTags(profile.Tags),
+			}
+
+			// An instance profile holds at most one role
+			if len(profile.Roles) > 0 {
+				resource.Metadata["role_arn"] = aws.ToString(profile.Roles[0].Arn)
+				resource.Metadata["role"] = aws.ToString(profile.Roles[0].RoleName)
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// iamPrincipalPolicies returns the inline policies and managed policy
+// attachments of a role, user or group as separate resources
+func (c *AWSConnector) iamPrincipalPolicies(kind, arn, name string, inline []iamTypes.PolicyDetail, attached []iamTypes.AttachedPolicy) []discovery.Resource {
+	var resources []discovery.Resource
+	for _, policy := range inline {
+		policyName := aws.ToString(policy.PolicyName)
+		resources = append(resources, discovery.Resource{
+			ID:       arn + ":policy/" + policyName,
+			Type:     fmt.Sprintf("aws_iam_%s_policy", kind),
+			Provider: discovery.AWS,
+			Metadata: map[string]interface{}{
+				kind + "_arn": arn,
+				kind:          name,
+				"name":        policyName,
+				"policy":      decodePolicyDocument(policy.PolicyDocument),
+			},
+		})
+	}
+
+	for _, policy := range attached {
+		policyARN := aws.ToString(policy.PolicyArn)
+		resources = append(resources, discovery.Resource{
+			ID:       arn + "/" + policyARN,
+			Type:     fmt.Sprintf("aws_iam_%s_policy_attachment", kind),
+			Provider: discovery.AWS,
+			Metadata: map[string]interface{}{
+				kind + "_arn": arn,
+				kind:          name,
+				"policy_arn":  policyARN,
+				"policy_name": aws.ToString(policy.PolicyName),
+				"aws_managed": strings.HasPrefix(policyARN, "arn:"+arnPartition(arn)+":iam::aws:policy/"),
+			},
+		})
+	}
+
+	return resources
+}
+
+// describeIAMAuthorization returns the account's roles, users, groups and
+// customer managed policies. They are read once with a single paginated
+// call and shared by the IAM resource types.
+func (c *AWSConnector) describeIAMAuthorization(ctx context.Context) (*iamAuthorization, error) {
+	c.iamMu.Lock()
+	defer c.iamMu.Unlock()
+
+	if c.iamDetails != nil {
+		return c.iamDetails, nil
+	}
+
+	paginator := iam.NewGetAccountAuthorizationDetailsPaginator(c.iamClient(), &iam.GetAccountAuthorizationDetailsInput{
+		Filter: []iamTypes.EntityType{
+			iamTypes.EntityTypeRole,
+			iamTypes.EntityTypeUser,
+			iamTypes.EntityTypeGroup,
+			iamTypes.EntityTypeLocalManagedPolicy,
+		},
+		MaxItems: aws.Int32(c.pageSize),
+	})
+
+	details := &iamAuthorization{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get IAM authorization details: %w", err)
+		}
+		details.roles = append(details.roles, page.RoleDetailList...)
+		details.users = append(details.users, page.UserDetailList...)
+		details.groups = append(details.groups, page.GroupDetailList...)
+		details.policies = append(details.policies, page.Policies...)
+	}
+
+	c.iamDetails = details
+	return details, nil
+}
+
+// convertIAMTags converts IAM tags to a map
+func (c *AWSConnector) convertIAMTags(tags []iamTypes.Tag) map[string]string {
+	result := make(map[string]string)
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
+// iamClient returns the IAM client. IAM is a global service, so a single
+// client built from the shared config serves every region.
+func (c *AWSConnector) iamClient() iamAPI {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, exists := c.clients["iam"]; exists {
+		return client.(iamAPI)
+	}
+
+	client := c.newIAMClient(c.config)
+	c.clients["iam"] = client
+	return client
+}
+
+// newGlobalIAMClient creates an IAM client from a shared config
+func newGlobalIAMClient(cfg aws.Config) iamAPI {
+	return iam.NewFromConfig(cfg)
+}
+
+// decodePolicyDocument decodes a policy document; IAM returns documents
+// URL-encoded
+func decodePolicyDocument(document *string) string {
+	decoded, err := url.PathUnescape(aws.ToString(document))
+	if err != nil {
+		return aws.ToString(document)
+	}
+	return decoded
+}
+
+// arnPartition returns the partition of an ARN
+func arnPartition(arn string) string {
+	parts := strings.SplitN(arn, ":", 3)
+	if len(parts) < 3 {
+		return "aws"
+	}
+	return parts[1]
+}
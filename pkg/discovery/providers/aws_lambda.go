@@ -0,0 +1,209 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdaTypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+)
+
+// lambdaAPI is the subset of the Lambda API used for discovery
+type lambdaAPI interface {
+	lambda.ListFunctionsAPIClient
+	lambda.ListEventSourceMappingsAPIClient
+	lambda.GetFunctionAPIClient
+}
+
+// maxLambdaPageSize is the largest page ListFunctions returns
+const maxLambdaPageSize int32 = 50
+
+// discoverLambdaFunctions discovers Lambda functions. Only the names of
+// environment variables are recorded, as their values may be secrets, and
+// the deployment package is described by its hash rather than downloaded.
+func (c *AWSConnector) discoverLambdaFunctions(ctx context.Context, region string) ([]discovery.Resource, error) {
+	client := c.lambdaClient(region)
+	paginator := lambda.NewListFunctionsPaginator(client, &lambda.ListFunctionsInput{
+		MaxItems: c.lambdaMaxItems(maxLambdaPageSize),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Lambda functions: %w", err)
+		}
+
+		for _, function := range page.Functions {
+			resource := c.convertLambdaFunction(function, region)
+
+			// Tags and the image of container functions are only returned by GetFunction
+			details, err := client.GetFunction(ctx, &lambda.GetFunctionInput{FunctionName: function.FunctionName})
+			if err != nil {
+				c.logger.Warnf("Failed to get Lambda function %s: %v", aws.ToString(function.FunctionName), err)
+			} else {
+				resource.Tags = details.Tags
+				if details.Code != nil && aws.ToString(details.Code.ImageUri) != "" {
+					resource.Metadata["image_uri"] = aws.ToString(details.Code.ImageUri)
+				}
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// convertLambdaFunction converts a function configuration to a resource
+func (c *AWSConnector) convertLambdaFunction(function lambdaTypes.FunctionConfiguration, region string) discovery.Resource {
+	environment := []string{}
+	if function.Environment != nil {
+		for name := range function.Environment.Variables {
+			environment = append(environment, name)
+		}
+		sort.Strings(environment)
+	}
+
+	layers := make([]string, 0, len(function.Layers))
+	for _, layer := range function.Layers {
+		layers = append(layers, aws.ToString(layer.Arn))
+	}
+
+	architectures := make([]string, 0, len(function.Architectures))
+	for _, architecture := range function.Architectures {
+		architectures = append(architectures, string(architecture))
+	}
+
+	arn := aws.ToString(function.FunctionArn)
+	resource := discovery.Resource{
+		ID:       arn,
+		Name:     aws.ToString(function.FunctionName),
+		Type:     "aws_lambda_function",
+		Provider: discovery.AWS,
+		Region:   region,
+		Status:   string(function.State),
+		Metadata: map[string]interface{}{
+			"arn":                   arn,
+			"package_type":          string(function.PackageType),
+			"role":                  aws.ToString(function.Role),
+			"description":           aws.ToString(function.Description),
+			"memory_size":           aws.ToInt32(function.MemorySize),
+			"timeout":               aws.ToInt32(function.Timeout),
+			"architectures":         architectures,
+			"layers":                layers,
+			"environment_variables": environment,
+			"code_sha256":           aws.ToString(function.CodeSha256),
+			"code_size":             function.CodeSize,
+			"last_modified":         aws.ToString(function.LastModified),
+		},
+	}
+
+	// Container image functions take their runtime and handler from the image
+	if function.Runtime != "" {
+		resource.Metadata["runtime"] = string(function.Runtime)
+		resource.Metadata["handler"] = aws.ToString(function.Handler)
+	}
+	if vpc := function.VpcConfig; vpc != nil && aws.ToString(vpc.VpcId) != "" {
+		resource.Metadata["vpc_id"] = aws.ToString(vpc.VpcId)
+		resource.Metadata["subnet_ids"] = vpc.SubnetIds
+		resource.Metadata["security_group_ids"] = vpc.SecurityGroupIds
+	}
+	if key := aws.ToString(function.KMSKeyArn); key != "" {
+		resource.Metadata["kms_key_arn"] = key
+	}
+	if function.DeadLetterConfig != nil && aws.ToString(function.DeadLetterConfig.TargetArn) != "" {
+		resource.Metadata["dead_letter_target_arn"] = aws.ToString(function.DeadLetterConfig.TargetArn)
+	}
+	if function.TracingConfig != nil && function.TracingConfig.Mode != "" {
+		resource.Metadata["tracing_mode"] = string(function.TracingConfig.Mode)
+	}
+	if function.EphemeralStorage != nil && aws.ToInt32(function.EphemeralStorage.Size) > 0 {
+		resource.Metadata["ephemeral_storage_size"] = aws.ToInt32(function.EphemeralStorage.Size)
+	}
+
+	return resource
+}
+
+// discoverLambdaEventSourceMappings discovers the mappings that invoke Lambda
+// functions with records from queues and streams
+func (c *AWSConnector) discoverLambdaEventSourceMappings(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := lambda.NewListEventSourceMappingsPaginator(c.lambdaClient(region), &lambda.ListEventSourceMappingsInput{
+		MaxItems: aws.Int32(c.pageSize),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Lambda event source mappings: %w", err)
+		}
+
+		for _, mapping := range page.EventSourceMappings {
+			state := aws.ToString(mapping.State)
+			resource := discovery.Resource{
+				ID:       aws.ToString(mapping.UUID),
+				Type:     "aws_lambda_event_source_mapping",
+				Provider: discovery.AWS,
+				Region:   region,
+				Status:   state,
+				Metadata: map[string]interface{}{
+					"uuid":             aws.ToString(mapping.UUID),
+					"function_arn":     aws.ToString(mapping.FunctionArn),
+					"event_source_arn": aws.ToString(mapping.EventSourceArn),
+					"batch_size":       aws.ToInt32(mapping.BatchSize),
+					"enabled":          state == "Enabled" || state == "Enabling",
+				},
+			}
+			if window := aws.ToInt32(mapping.MaximumBatchingWindowInSeconds); window > 0 {
+				resource.Metadata["maximum_batching_window"] = window
+			}
+			if mapping.StartingPosition != "" {
+				resource.Metadata["starting_position"] = string(mapping.StartingPosition)
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// lambdaClient returns the Lambda client for a region, creating it on first use
+func (c *AWSConnector) lambdaClient(region string) lambdaAPI {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if region == "" {
+		region = c.config.Region
+	}
+
+	key := "lambda:" + region
+	if client, exists := c.clients[key]; exists {
+		return client.(lambdaAPI)
+	}
+
+	client := c.newLambdaClient(c.config, region)
+	c.clients[key] = client
+	return client
+}
+
+// newRegionalLambdaClient creates a Lambda client for a region from a shared config
+func newRegionalLambdaClient(cfg aws.Config, region string) lambdaAPI {
+	return lambda.NewFromConfig(cfg, func(o *lambda.Options) {
+		o.Region = region
+	})
+}
+
+// lambdaMaxItems returns the page size of a paginated Lambda call, with the
+// connector's page size capped at the largest page the call returns
+func (c *AWSConnector) lambdaMaxItems(maxSize int32) *int32 {
+	size := c.pageSize
+	if size > maxSize {
+		size = maxSize
+	}
+	return aws.Int32(size)
+}
@@ -0,0 +1,583 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+)
+
+// rdsAPI is the subset of the RDS API used for discovery
+type rdsAPI interface {
+	rds.DescribeDBInstancesAPIClient
+	rds.DescribeDBClustersAPIClient
+	rds.DescribeDBSubnetGroupsAPIClient
+	rds.DescribeDBParameterGroupsAPIClient
+	rds.DescribeDBClusterParameterGroupsAPIClient
+	rds.DescribeDBParametersAPIClient
+	rds.DescribeDBClusterParametersAPIClient
+	rds.DescribeOptionGroupsAPIClient
+	ListTagsForResource(ctx context.Context, params *rds.ListTagsForResourceInput, optFns ...func(*rds.Options)) (*rds.ListTagsForResourceOutput, error)
+}
+
+// RDS Describe* page size limits
+const (
+	minRDSPageSize int32 = 20
+	maxRDSPageSize int32 = 100
+)
+
+// discoverDBInstances discovers RDS DB instances. Instances that belong to
+// an Aurora cluster are discovered as cluster instances.
+func (c *AWSConnector) discoverDBInstances(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := rds.NewDescribeDBInstancesPaginator(c.rdsClient(region), &rds.DescribeDBInstancesInput{
+		MaxRecords: c.rdsMaxRecords(),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB instances: %w", err)
+		}
+
+		for _, instance := range page.DBInstances {
+			if !isRDSEngine(aws.ToString(instance.Engine)) {
+				continue
+			}
+			resources = append(resources, c.convertDBInstance(instance, region))
+		}
+	}
+
+	return resources, nil
+}
+
+// convertDBInstance converts a DB instance to a resource. Subnet, parameter
+// and option groups and clusters are recorded by ARN so that they link to the
+// discovered groups and clusters.
+func (c *AWSConnector) convertDBInstance(instance rdsTypes.DBInstance, region string) discovery.Resource {
+	arn := aws.ToString(instance.DBInstanceArn)
+	var address string
+	var port int32
+	if instance.Endpoint != nil {
+		address = aws.ToString(instance.Endpoint.Address)
+		port = aws.ToInt32(instance.Endpoint.Port)
+	}
+
+	resource := discovery.Resource{
+		ID:        arn,
+		Name:      aws.ToString(instance.DBInstanceIdentifier),
+		Type:      "aws_db_instance",
+		Provider:  discovery.AWS,
+		Region:    region,
+		Zone:      aws.ToString(instance.AvailabilityZone),
+		Status:    aws.ToString(instance.DBInstanceStatus),
+		CreatedAt: instance.InstanceCreateTime,
+		Metadata: map[string]interface{}{
+			"arn":                          arn,
+			"identifier":                   aws.ToString(instance.DBInstanceIdentifier),
+			"resource_id":                  aws.ToString(instance.DbiResourceId),
+			"instance_class":               aws.ToString(instance.DBInstanceClass),
+			"engine":                       aws.ToString(instance.Engine),
+			"engine_version":               aws.ToString(instance.EngineVersion),
+			"endpoint":                     address,
+			"port":                         port,
+			"publicly_accessible":          aws.ToBool(instance.PubliclyAccessible),
+			"availability_zone":            aws.ToString(instance.AvailabilityZone),
+			"auto_minor_version_upgrade":   aws.ToBool(instance.AutoMinorVersionUpgrade),
+			"performance_insights_enabled": aws.ToBool(instance.PerformanceInsightsEnabled),
+			"monitoring_interval":          aws.ToInt32(instance.MonitoringInterval),
+			"ca_cert_identifier":           aws.ToString(instance.CACertificateIdentifier),
+			"security_group_ids":           rdsSecurityGroupIDs(instance.VpcSecurityGroups),
+		},
+		Tags: c.convertRDSTags(instance.TagList),
+	}
+	if role := aws.ToString(instance.MonitoringRoleArn); role != "" {
+		resource.Metadata["monitoring_role_arn"] = role
+	}
+	if instance.DBSubnetGroup != nil {
+		if name := aws.ToString(instance.DBSubnetGroup.DBSubnetGroupName); name != "" {
+			resource.Metadata["db_subnet_group_name"] = name
+			resource.Metadata["db_subnet_group_arn"] = rdsARN(arn, "subgrp", name)
+		}
+	}
+	if len(instance.DBParameterGroups) > 0 {
+		name := aws.ToString(instance.DBParameterGroups[0].DBParameterGroupName)
+		resource.Metadata["parameter_group_name"] = name
+		resource.Metadata["parameter_group_arn"] = rdsARN(arn, "pg", name)
+	}
+
+	// Storage, credentials, backups and the option group are managed by the
+	// cluster for Aurora instances
+	if cluster := aws.ToString(instance.DBClusterIdentifier); cluster != "" {
+		resource.Type = "aws_rds_cluster_instance"
+		resource.Metadata["cluster_identifier"] = cluster
+		resource.Metadata["cluster_arn"] = rdsARN(arn, "cluster", cluster)
+		resource.Metadata["promotion_tier"] = aws.ToInt32(instance.PromotionTier)
+		return resource
+	}
+
+	secretARN := rdsMasterUserSecretARN(instance.MasterUserSecret)
+	resource.Metadata["db_name"] = aws.ToString(instance.DBName)
+	resource.Metadata["username"] = aws.ToString(instance.MasterUsername)
+	resource.Metadata["allocated_storage"] = aws.ToInt32(instance.AllocatedStorage)
+	resource.Metadata["storage_type"] = aws.ToString(instance.StorageType)
+	resource.Metadata["storage_encrypted"] = aws.ToBool(instance.StorageEncrypted)
+	resource.Metadata["multi_az"] = aws.ToBool(instance.MultiAZ)
+	resource.Metadata["backup_retention_period"] = aws.ToInt32(instance.BackupRetentionPeriod)
+	resource.Metadata["backup_window"] = aws.ToString(instance.PreferredBackupWindow)
+	resource.Metadata["maintenance_window"] = aws.ToString(instance.PreferredMaintenanceWindow)
+	resource.Metadata["deletion_protection"] = aws.ToBool(instance.DeletionProtection)
+	resource.Metadata["copy_tags_to_snapshot"] = aws.ToBool(instance.CopyTagsToSnapshot)
+	resource.Metadata["iam_authentication"] = aws.ToBool(instance.IAMDatabaseAuthenticationEnabled)
+	resource.Metadata["license_model"] = aws.ToString(instance.LicenseModel)
+	resource.Metadata["manage_master_password"] = secretARN != ""
+
+	if storage := aws.ToInt32(instance.MaxAllocatedStorage); storage > 0 {
+		resource.Metadata["max_allocated_storage"] = storage
+	}
+	if iops := aws.ToInt32(instance.Iops); iops > 0 {
+		resource.Metadata["iops"] = iops
+	}
+	if throughput := aws.ToInt32(instance.StorageThroughput); throughput > 0 {
+		resource.Metadata["storage_throughput"] = throughput
+	}
+	if key := aws.ToString(instance.KmsKeyId); key != "" {
+		resource.Metadata["kms_key_id"] = key
+	}
+	if secretARN != "" {
+		resource.Metadata["master_user_secret_arn"] = secretARN
+	}
+	if len(instance.OptionGroupMemberships) > 0 {
+		name := aws.ToString(instance.OptionGroupMemberships[0].OptionGroupName)
+		resource.Metadata["option_group_name"] = name
+		resource.Metadata["option_group_arn"] = rdsARN(arn, "og", name)
+	}
+	// Replicas in the same region name their source by identifier, others by ARN
+	if source := aws.ToString(instance.ReadReplicaSourceDBInstanceIdentifier); source != "" {
+		resource.Metadata["replicate_source_db_name"] = source
+		if !strings.HasPrefix(source, "arn:") {
+			source = rdsARN(arn, "db", source)
+		}
+		resource.Metadata["replicate_source_db"] = source
+	}
+
+	return resource
+}
+
+// discoverRDSClusters discovers Aurora and Multi-AZ DB clusters
+func (c *AWSConnector) discoverRDSClusters(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := rds.NewDescribeDBClustersPaginator(c.rdsClient(region), &rds.DescribeDBClustersInput{
+		MaxRecords: c.rdsMaxRecords(),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB clusters: %w", err)
+		}
+
+		for _, cluster := range page.DBClusters {
+			if !isRDSEngine(aws.ToString(cluster.Engine)) {
+				continue
+			}
+
+			arn := aws.ToString(cluster.DBClusterArn)
+			secretARN := rdsMasterUserSecretARN(cluster.MasterUserSecret)
+			members := make([]string, 0, len(cluster.DBClusterMembers))
+			for _, member := range cluster.DBClusterMembers {
+				members = append(members, aws.ToString(member.DBInstanceIdentifier))
+			}
+
+			resource := discovery.Resource{
+				ID:        arn,
+				Name:      aws.ToString(cluster.DBClusterIdentifier),
+				Type:      "aws_rds_cluster",
+				Provider:  discovery.AWS,
+				Region:    region,
+				Status:    aws.ToString(cluster.Status),
+				CreatedAt: cluster.ClusterCreateTime,
+				Metadata: map[string]interface{}{
+					"arn":                     arn,
+					"cluster_identifier":      aws.ToString(cluster.DBClusterIdentifier),
+					"cluster_resource_id":     aws.ToString(cluster.DbClusterResourceId),
+					"engine":                  aws.ToString(cluster.Engine),
+					"engine_version":          aws.ToString(cluster.EngineVersion),
+					"engine_mode":             aws.ToString(cluster.EngineMode),
+					"database_name":           aws.ToString(cluster.DatabaseName),
+					"master_username":         aws.ToString(cluster.MasterUsername),
+					"endpoint":                aws.ToString(cluster.Endpoint),
+					"reader_endpoint":         aws.ToString(cluster.ReaderEndpoint),
+					"port":                    aws.ToInt32(cluster.Port),
+					"backup_retention_period": aws.ToInt32(cluster.BackupRetentionPeriod),
+					"backup_window":           aws.ToString(cluster.PreferredBackupWindow),
+					"maintenance_window":      aws.ToString(cluster.PreferredMaintenanceWindow),
+					"storage_encrypted":       aws.ToBool(cluster.StorageEncrypted),
+					"deletion_protection":     aws.ToBool(cluster.DeletionProtection),
+					"copy_tags_to_snapshot":   aws.ToBool(cluster.CopyTagsToSnapshot),
+					"iam_authentication":      aws.ToBool(cluster.IAMDatabaseAuthenticationEnabled),
+					"manage_master_password":  secretARN != "",
+					"security_group_ids":      rdsSecurityGroupIDs(cluster.VpcSecurityGroups),
+					"members":                 members,
+				},
+				Tags: c.convertRDSTags(cluster.TagList),
+			}
+			if key := aws.ToString(cluster.KmsKeyId); key != "" {
+				resource.Metadata["kms_key_id"] = key
+			}
+			if secretARN != "" {
+				resource.Metadata["master_user_secret_arn"] = secretARN
+			}
+			if name := aws.ToString(cluster.DBSubnetGroup); name != "" {
+				resource.Metadata["db_subnet_group_name"] = name
+				resource.Metadata["db_subnet_group_arn"] = rdsARN(arn, "subgrp", name)
+			}
+			if name := aws.ToString(cluster.DBClusterParameterGroup); name != "" {
+				resource.Metadata["cluster_parameter_group_name"] = name
+				resource.Metadata["cluster_parameter_group_arn"] = rdsARN(arn, "cluster-pg", name)
+			}
+			if scaling := cluster.ServerlessV2ScalingConfiguration; scaling != nil && aws.ToFloat64(scaling.MaxCapacity) > 0 {
+				resource.Metadata["serverlessv2_scaling"] = map[string]interface{}{
+					"min_capacity": aws.ToFloat64(scaling.MinCapacity),
+					"max_capacity": aws.ToFloat64(scaling.MaxCapacity),
+				}
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverDBSubnetGroups discovers DB subnet groups, other than the default
+// group RDS creates for the default VPC
+func (c *AWSConnector) discoverDBSubnetGroups(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := rds.NewDescribeDBSubnetGroupsPaginator(c.rdsClient(region), &rds.DescribeDBSubnetGroupsInput{
+		MaxRecords: c.rdsMaxRecords(),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB subnet groups: %w", err)
+		}
+
+		for _, group := range page.DBSubnetGroups {
+			name := aws.ToString(group.DBSubnetGroupName)
+			if name == "default" {
+				continue
+			}
+
+			arn := aws.ToString(group.DBSubnetGroupArn)
+			subnets := make([]string, 0, len(group.Subnets))
+			for _, subnet := range group.Subnets {
+				subnets = append(subnets, aws.ToString(subnet.SubnetIdentifier))
+			}
+
+			resources = append(resources, discovery.Resource{
+				ID:       arn,
+				Name:     name,
+				Type:     "aws_db_subnet_group",
+				Provider: discovery.AWS,
+				Region:   region,
+				Status:   aws.ToString(group.SubnetGroupStatus),
+				Metadata: map[string]interface{}{
+					"arn":         arn,
+					"description": aws.ToString(group.DBSubnetGroupDescription),
+					"vpc_id":      aws.ToString(group.VpcId),
+					"subnet_ids":  subnets,
+				},
+				Tags: c.rdsResourceTags(ctx, region, arn),
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverDBParameterGroups discovers custom DB parameter groups with the
+// parameters that were changed from the engine defaults
+func (c *AWSConnector) discoverDBParameterGroups(ctx context.Context, region string) ([]discovery.Resource, error) {
+	client := c.rdsClient(region)
+	paginator := rds.NewDescribeDBParameterGroupsPaginator(client, &rds.DescribeDBParameterGroupsInput{
+		MaxRecords: c.rdsMaxRecords(),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe parameter groups: %w", err)
+		}
+
+		for _, group := range page.DBParameterGroups {
+			name := aws.ToString(group.DBParameterGroupName)
+			if strings.HasPrefix(name, "default.") {
+				continue
+			}
+
+			var parameters []rdsTypes.Parameter
+			parametersPaginator := rds.NewDescribeDBParametersPaginator(client, &rds.DescribeDBParametersInput{
+				DBParameterGroupName: group.DBParameterGroupName,
+				Source:               aws.String("user"),
+				MaxRecords:           c.rdsMaxRecords(),
+			})
+			for parametersPaginator.HasMorePages() {
+				parametersPage, err := parametersPaginator.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to describe parameters of %s: %w", name, err)
+				}
+				parameters = append(parameters, parametersPage.Parameters...)
+			}
+
+			resources = append(resources, c.convertRDSParameterGroup(ctx, region, "aws_db_parameter_group", name,
+				aws.ToString(group.DBParameterGroupArn), aws.ToString(group.DBParameterGroupFamily), aws.ToString(group.Description), parameters))
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverRDSClusterParameterGroups discovers custom DB cluster parameter
+// groups with the parameters that were changed from the engine defaults
+func (c *AWSConnector) discoverRDSClusterParameterGroups(ctx context.Context, region string) ([]discovery.Resource, error) {
+	client := c.rdsClient(region)
+	paginator := rds.NewDescribeDBClusterParameterGroupsPaginator(client, &rds.DescribeDBClusterParameterGroupsInput{
+		MaxRecords: c.rdsMaxRecords(),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe parameter groups: %w", err)
+		}
+
+		for _, group := range page.DBClusterParameterGroups {
+			name := aws.ToString(group.DBClusterParameterGroupName)
+			if strings.HasPrefix(name, "default.") {
+				continue
+			}
+
+			var parameters []rdsTypes.Parameter
+			parametersPaginator := rds.NewDescribeDBClusterParametersPaginator(client, &rds.DescribeDBClusterParametersInput{
+				DBClusterParameterGroupName: group.DBClusterParameterGroupName,
+				Source:                      aws.String("user"),
+				MaxRecords:                  c.rdsMaxRecords(),
+			})
+			for parametersPaginator.HasMorePages() {
+				parametersPage, err := parametersPaginator.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to describe parameters of %s: %w", name, err)
+				}
+				parameters = append(parameters, parametersPage.Parameters...)
+			}
+
+			resources = append(resources, c.convertRDSParameterGroup(ctx, region, "aws_rds_cluster_parameter_group", name,
+				aws.ToString(group.DBClusterParameterGroupArn), aws.ToString(group.DBParameterGroupFamily), aws.ToString(group.Description), parameters))
+		}
+	}
+
+	return resources, nil
+}
+
+// convertRDSParameterGroup converts a DB or DB cluster parameter group and
+// the parameters set by the user rather than left at the engine defaults to
+// a resource
+func (c *AWSConnector) convertRDSParameterGroup(ctx context.Context, region, resourceType, name, arn, family, description string, parameters []rdsTypes.Parameter) discovery.Resource {
+	converted := make([]map[string]interface{}, 0, len(parameters))
+	for _, parameter := range parameters {
+		converted = append(converted, map[string]interface{}{
+			"name":         aws.ToString(parameter.ParameterName),
+			"value":        aws.ToString(parameter.ParameterValue),
+			"apply_method": string(parameter.ApplyMethod),
+		})
+	}
+
+	return discovery.Resource{
+		ID:       arn,
+		Name:     name,
+		Type:     resourceType,
+		Provider: discovery.AWS,
+		Region:   region,
+		Metadata: map[string]interface{}{
+			"arn":         arn,
+			"family":      family,
+			"description": description,
+			"parameters":  converted,
+		},
+		Tags: c.rdsResourceTags(ctx, region, arn),
+	}
+}
+
+// discoverDBOptionGroups discovers DB option groups, leaving out the default
+// groups of each engine
+func (c *AWSConnector) discoverDBOptionGroups(ctx context.Context, region string) ([]discovery.Resource, error) {
+	paginator := rds.NewDescribeOptionGroupsPaginator(c.rdsClient(region), &rds.DescribeOptionGroupsInput{
+		MaxRecords: c.rdsMaxRecords(),
+	})
+
+	var resources []discovery.Resource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB option groups: %w", err)
+		}
+
+		for _, group := range page.OptionGroupsList {
+			name := aws.ToString(group.OptionGroupName)
+			if strings.HasPrefix(name, "default:") {
+				continue
+			}
+
+			options := make([]map[string]interface{}, 0, len(group.Options))
+			for _, option := range group.Options {
+				entry := map[string]interface{}{
+					"option_name": aws.ToString(option.OptionName),
+				}
+				if version := aws.ToString(option.OptionVersion); version != "" {
+					entry["version"] = version
+				}
+				if port := aws.ToInt32(option.Port); port > 0 {
+					entry["port"] = port
+				}
+				if groups := rdsSecurityGroupIDs(option.VpcSecurityGroupMemberships); len(groups) > 0 {
+					entry["security_group_ids"] = groups
+				}
+				if len(option.OptionSettings) > 0 {
+					settings := make(map[string]string, len(option.OptionSettings))
+					for _, setting := range option.OptionSettings {
+						settings[aws.ToString(setting.Name)] = aws.ToString(setting.Value)
+					}
+					entry["settings"] = settings
+				}
+				options = append(options, entry)
+			}
+
+			arn := aws.ToString(group.OptionGroupArn)
+			resources = append(resources, discovery.Resource{
+				ID:       arn,
+				Name:     name,
+				Type:     "aws_db_option_group",
+				Provider: discovery.AWS,
+				Region:   region,
+				Metadata: map[string]interface{}{
+					"arn":                  arn,
+					"description":          aws.ToString(group.OptionGroupDescription),
+					"engine_name":          aws.ToString(group.EngineName),
+					"major_engine_version": aws.ToString(group.MajorEngineVersion),
+					"options":              options,
+				},
+				Tags: c.rdsResourceTags(ctx, region, arn),
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// rdsResourceTags returns the tags of an RDS resource that Describe* does not
+// return them for. Tags are not essential, so failures are only logged.
+func (c *AWSConnector) rdsResourceTags(ctx context.Context, region, arn string) map[string]string {
+	result, err := c.rdsClient(region).ListTagsForResource(ctx, &rds.ListTagsForResourceInput{
+		ResourceName: aws.String(arn),
+	})
+	if err != nil {
+		c.logger.Warnf("Failed to list tags of %s: %v", arn, err)
+		return nil
+	}
+	return c.convertRDSTags(result.TagList)
+}
+
+// convertRDSTags converts RDS tags to a map
+func (c *AWSConnector) convertRDSTags(tags []rdsTypes.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
+// rdsSecurityGroupIDs returns the IDs of VPC security group memberships
+func rdsSecurityGroupIDs(memberships []rdsTypes.VpcSecurityGroupMembership) []string {
+	ids := make([]string, 0, len(memberships))
+	for _, membership := range memberships {
+		ids = append(ids, aws.ToString(membership.VpcSecurityGroupId))
+	}
+	return ids
+}
+
+// rdsMasterUserSecretARN returns the ARN of the Secrets Manager secret
+// holding a master password managed by RDS, or "" if there is none
+func rdsMasterUserSecretARN(secret *rdsTypes.MasterUserSecret) string {
+	if secret == nil {
+		return ""
+	}
+	return aws.ToString(secret.SecretArn)
+}
+
+// rdsClient returns the RDS client for a region, creating it on first use
+func (c *AWSConnector) rdsClient(region string) rdsAPI {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if region == "" {
+		region = c.config.Region
+	}
+
+	key := "rds:" + region
+	if client, exists := c.clients[key]; exists {
+		return client.(rdsAPI)
+	}
+
+	client := c.newRDSClient(c.config, region)
+	c.clients[key] = client
+	return client
+}
+
+// newRegionalRDSClient creates an RDS client for a region from a shared config
+func newRegionalRDSClient(cfg aws.Config, region string) rdsAPI {
+	return rds.NewFromConfig(cfg, func(o *rds.Options) {
+		o.Region = region
+	})
+}
+
+// rdsMaxRecords returns the page size of paginated RDS calls, with the
+// connector's page size clamped to the 20-100 records RDS accepts
+func (c *AWSConnector) rdsMaxRecords() *int32 {
+	size := c.pageSize
+	switch {
+	case size < minRDSPageSize:
+		size = minRDSPageSize
+	case size > maxRDSPageSize:
+		size = maxRDSPageSize
+	}
+	return aws.Int32(size)
+}
+
+// rdsARN builds the ARN of a related RDS resource from the ARN of a resource
+// in the same account and region, such as
+// arn:aws:rds:us-east-1:123456789012:subgrp:name for a subnet group
+func rdsARN(arn, kind, name string) string {
+	parts := strings.SplitN(arn, ":", 7)
+	if len(parts) < 5 {
+		return name
+	}
+	return strings.Join(append(parts[:5], kind, name), ":")
+}
+
+// isRDSEngine reports whether an engine is managed with the RDS resources;
+// the RDS API also returns DocumentDB and Neptune instances and clusters
+func isRDSEngine(engine string) bool {
+	return engine != "docdb" && engine != "neptune"
+}
@@ -0,0 +1,465 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+)
+
+// s3API is the subset of the S3 API used for discovery
+type s3API interface {
+	ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+	GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
+	GetBucketVersioning(ctx context.Context, params *s3.GetBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error)
+	GetBucketEncryption(ctx context.Context, params *s3.GetBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error)
+	GetBucketLifecycleConfiguration(ctx context.Context, params *s3.GetBucketLifecycleConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error)
+	GetPublicAccessBlock(ctx context.Context, params *s3.GetPublicAccessBlockInput, optFns ...func(*s3.Options)) (*s3.GetPublicAccessBlockOutput, error)
+	GetBucketPolicy(ctx context.Context, params *s3.GetBucketPolicyInput, optFns ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error)
+	GetBucketLogging(ctx context.Context, params *s3.GetBucketLoggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketLoggingOutput, error)
+	GetBucketReplication(ctx context.Context, params *s3.GetBucketReplicationInput, optFns ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error)
+}
+
+// S3 error codes returned when a bucket has no configuration of a kind
+const (
+	s3NoTagSet            = "NoSuchTagSet"
+	s3NoEncryption        = "ServerSideEncryptionConfigurationNotFoundError"
+	s3NoLifecycle         = "NoSuchLifecycleConfiguration"
+	s3NoPublicAccessBlock = "NoSuchPublicAccessBlockConfiguration"
+	s3NoBucketPolicy      = "NoSuchBucketPolicy"
+	s3NoReplication       = "ReplicationConfigurationNotFoundError"
+)
+
+// s3Bucket is a bucket returned by ListBuckets with the region it lives in
+type s3Bucket struct {
+	name      string
+	region    string
+	createdAt *time.Time
+}
+
+// discoverS3Buckets discovers the S3 buckets located in a region. Each bucket
+// is returned together with its versioning, encryption, lifecycle, public
+// access block, policy, logging and replication configuration, which the
+// AWS provider manages as separate aws_s3_bucket_* resources.
+func (c *AWSConnector) discoverS3Buckets(ctx context.Context, region string) ([]discovery.Resource, error) {
+	buckets, err := c.listS3Buckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.s3Client(region)
+	var resources []discovery.Resource
+	for _, bucket := range buckets {
+		if bucket.region != region {
+			continue
+		}
+
+		resource := discovery.Resource{
+			ID:        bucket.name,
+			Name:      bucket.name,
+			Type:      "aws_s3_bucket",
+			Provider:  discovery.AWS,
+			Region:    region,
+			CreatedAt: bucket.createdAt,
+			Metadata: map[string]interface{}{
+				"arn": fmt.Sprintf("arn:%s:s3:::%s", awsPartition(region), bucket.name),
+			},
+			Tags: make(map[string]string),
+		}
+
+		tagging, err := client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: aws.String(bucket.name)})
+		switch {
+		case err == nil:
+			for _, tag := range tagging.TagSet {
+				resource.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+		case !isS3ErrorCode(err, s3NoTagSet):
+			c.logger.Warnf("Failed to read tags of S3 bucket %s: %v", bucket.name, err)
+		}
+
+		resources = append(resources, resource)
+		resources = append(resources, c.describeS3BucketConfiguration(ctx, client, bucket)...)
+	}
+
+	return resources, nil
+}
+
+// describeS3BucketConfiguration returns the configuration resources of a
+// bucket. Configuration that cannot be read is logged and left out rather
+// than failing the whole bucket.
+func (c *AWSConnector) describeS3BucketConfiguration(ctx context.Context, client s3API, bucket s3Bucket) []discovery.Resource {
+	var resources []discovery.Resource
+	add := func(kind string, metadata map[string]interface{}) {
+		metadata["bucket"] = bucket.name
+		resources = append(resources, discovery.Resource{
+			ID:       bucket.name + "/" + kind,
+			Type:     "aws_s3_bucket_" + kind,
+			Provider: discovery.AWS,
+			Region:   bucket.region,
+			Metadata: metadata,
+		})
+	}
+	warn := func(kind string, err error) {
+		c.logger.Warnf("Failed to read %s of S3 bucket %s: %v", kind, bucket.name, err)
+	}
+	input := aws.String(bucket.name)
+
+	// Buckets that never had versioning enabled report no status
+	if versioning, err := client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: input}); err != nil {
+		warn("versioning", err)
+	} else if versioning.Status != "" {
+		add("versioning", map[string]interface{}{
+			"status":     string(versioning.Status),
+			"mfa_delete": string(versioning.MFADelete),
+		})
+	}
+
+	if encryption, err := client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: input}); err != nil {
+		if !isS3ErrorCode(err, s3NoEncryption) {
+			warn("encryption", err)
+		}
+	} else if encryption.ServerSideEncryptionConfiguration != nil {
+		rules := make([]map[string]interface{}, 0, len(encryption.ServerSideEncryptionConfiguration.Rules))
+		for _, rule := range encryption.ServerSideEncryptionConfiguration.Rules {
+			entry := map[string]interface{}{
+				"bucket_key_enabled": aws.ToBool(rule.BucketKeyEnabled),
+			}
+			if defaults := rule.ApplyServerSideEncryptionByDefault; defaults != nil {
+				entry["sse_algorithm"] = string(defaults.SSEAlgorithm)
+				if defaults.KMSMasterKeyID != nil {
+					entry["kms_master_key_id"] = aws.ToString(defaults.KMSMasterKeyID)
+				}
+			}
+			rules = append(rules, entry)
+		}
+		add("server_side_encryption_configuration", map[string]interface{}{"rules": rules})
+	}
+
+	if lifecycle, err := client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: input}); err != nil {
+		if !isS3ErrorCode(err, s3NoLifecycle) {
+			warn("lifecycle rules", err)
+		}
+	} else if len(lifecycle.Rules) > 0 {
+		rules := make([]map[string]interface{}, 0, len(lifecycle.Rules))
+		for _, rule := range lifecycle.Rules {
+			rules = append(rules, c.convertLifecycleRule(rule))
+		}
+		add("lifecycle_configuration", map[string]interface{}{"rules": rules})
+	}
+
+	if block, err := client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: input}); err != nil {
+		if !isS3ErrorCode(err, s3NoPublicAccessBlock) {
+			warn("public access block", err)
+		}
+	} else if config := block.PublicAccessBlockConfiguration; config != nil {
+		add("public_access_block", map[string]interface{}{
+			"block_public_acls":       aws.ToBool(config.BlockPublicAcls),
+			"block_public_policy":     aws.ToBool(config.BlockPublicPolicy),
+			"ignore_public_acls":      aws.ToBool(config.IgnorePublicAcls),
+			"restrict_public_buckets": aws.ToBool(config.RestrictPublicBuckets),
+		})
+	}
+
+	if policy, err := client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{Bucket: input}); err != nil {
+		if !isS3ErrorCode(err, s3NoBucketPolicy) {
+			warn("policy", err)
+		}
+	} else if aws.ToString(policy.Policy) != "" {
+		add("policy", map[string]interface{}{
+			"policy": aws.ToString(policy.Policy),
+		})
+	}
+
+	if logging, err := client.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{Bucket: input}); err != nil {
+		warn("logging", err)
+	} else if logging.LoggingEnabled != nil {
+		add("logging", map[string]interface{}{
+			"target_bucket": aws.ToString(logging.LoggingEnabled.TargetBucket),
+			"target_prefix": aws.ToString(logging.LoggingEnabled.TargetPrefix),
+		})
+	}
+
+	if replication, err := client.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{Bucket: input}); err != nil {
+		if !isS3ErrorCode(err, s3NoReplication) {
+			warn("replication", err)
+		}
+	} else if config := replication.ReplicationConfiguration; config != nil {
+		rules := make([]map[string]interface{}, 0, len(config.Rules))
+		for _, rule := range config.Rules {
+			rules = append(rules, c.convertReplicationRule(rule))
+		}
+		add("replication_configuration", map[string]interface{}{
+			"role":  aws.ToString(config.Role),
+			"rules": rules,
+		})
+	}
+
+	return resources
+}
+
+// listS3Buckets returns every bucket of the account with its region. S3
+// lists buckets globally, so the list is read once and shared by the
+// regional discovery jobs.
+func (c *AWSConnector) listS3Buckets(ctx context.Context) ([]s3Bucket, error) {
+	c.bucketsMu.Lock()
+	defer c.bucketsMu.Unlock()
+
+	if c.buckets != nil {
+		return c.buckets, nil
+	}
+
+	client := c.s3Client(c.config.Region)
+	output, err := client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list S3 buckets: %w", err)
+	}
+
+	buckets := make([]s3Bucket, 0, len(output.Buckets))
+	for _, bucket := range output.Buckets {
+		name := aws.ToString(bucket.Name)
+		location, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: aws.String(name)})
+		if err != nil {
+			c.logger.Warnf("Failed to get location of S3 bucket %s: %v", name, err)
+			continue
+		}
+
+		buckets = append(buckets, s3Bucket{
+			name:      name,
+			region:    bucketRegion(location.LocationConstraint),
+			createdAt: bucket.CreationDate,
+		})
+	}
+
+	c.buckets = buckets
+	return buckets, nil
+}
+
+// convertLifecycleRule converts an S3 lifecycle rule into a metadata entry
+func (c *AWSConnector) convertLifecycleRule(rule s3Types.LifecycleRule) map[string]interface{} {
+	entry := map[string]interface{}{
+		"id":     aws.ToString(rule.ID),
+		"status": string(rule.Status),
+	}
+	if rule.Prefix != nil {
+		entry["prefix"] = aws.ToString(rule.Prefix)
+	}
+
+	switch filter := rule.Filter.(type) {
+	case *s3Types.LifecycleRuleFilterMemberPrefix:
+		entry["filter"] = map[string]interface{}{"prefix": filter.Value}
+	case *s3Types.LifecycleRuleFilterMemberTag:
+		entry["filter"] = map[string]interface{}{"tags": c.convertS3Tags([]s3Types.Tag{filter.Value})}
+	case *s3Types.LifecycleRuleFilterMemberObjectSizeGreaterThan:
+		entry["filter"] = map[string]interface{}{"object_size_greater_than": filter.Value}
+	case *s3Types.LifecycleRuleFilterMemberObjectSizeLessThan:
+		entry["filter"] = map[string]interface{}{"object_size_less_than": filter.Value}
+	case *s3Types.LifecycleRuleFilterMemberAnd:
+		and := map[string]interface{}{}
+		if filter.Value.Prefix != nil {
+			and["prefix"] = aws.ToString(filter.Value.Prefix)
+		}
+		if len(filter.Value.Tags) > 0 {
+			and["tags"] = c.convertS3Tags(filter.Value.Tags)
+		}
+		if filter.Value.ObjectSizeGreaterThan != nil {
+			and["object_size_greater_than"] = aws.ToInt64(filter.Value.ObjectSizeGreaterThan)
+		}
+		if filter.Value.ObjectSizeLessThan != nil {
+			and["object_size_less_than"] = aws.ToInt64(filter.Value.ObjectSizeLessThan)
+		}
+		entry["filter"] = map[string]interface{}{"and": and}
+	}
+
+	if expiration := rule.Expiration; expiration != nil {
+		fields := map[string]interface{}{}
+		if expiration.Days != nil {
+			fields["days"] = aws.ToInt32(expiration.Days)
+		}
+		if expiration.Date != nil {
+			fields["date"] = expiration.Date.UTC().Format(time.RFC3339)
+		}
+		if expiration.ExpiredObjectDeleteMarker != nil {
+			fields["expired_object_delete_marker"] = aws.ToBool(expiration.ExpiredObjectDeleteMarker)
+		}
+		entry["expiration"] = fields
+	}
+
+	if len(rule.Transitions) > 0 {
+		transitions := make([]map[string]interface{}, 0, len(rule.Transitions))
+		for _, transition := range rule.Transitions {
+			fields := map[string]interface{}{
+				"storage_class": string(transition.StorageClass),
+			}
+			if transition.Days != nil {
+				fields["days"] = aws.ToInt32(transition.Days)
+			}
+			if transition.Date != nil {
+				fields["date"] = transition.Date.UTC().Format(time.RFC3339)
+			}
+			transitions = append(transitions, fields)
+		}
+		entry["transitions"] = transitions
+	}
+
+	if expiration := rule.NoncurrentVersionExpiration; expiration != nil {
+		fields := map[string]interface{}{
+			"noncurrent_days": aws.ToInt32(expiration.NoncurrentDays),
+		}
+		if expiration.NewerNoncurrentVersions != nil {
+			fields["newer_noncurrent_versions"] = aws.ToInt32(expiration.NewerNoncurrentVersions)
+		}
+		entry["noncurrent_version_expiration"] = fields
+	}
+
+	if len(rule.NoncurrentVersionTransitions) > 0 {
+		transitions := make([]map[string]interface{}, 0, len(rule.NoncurrentVersionTransitions))
+		for _, transition := range rule.NoncurrentVersionTransitions {
+			fields := map[string]interface{}{
+				"noncurrent_days": aws.ToInt32(transition.NoncurrentDays),
+				"storage_class":   string(transition.StorageClass),
+			}
+			if transition.NewerNoncurrentVersions != nil {
+				fields["newer_noncurrent_versions"] = aws.ToInt32(transition.NewerNoncurrentVersions)
+			}
+			transitions = append(transitions, fields)
+		}
+		entry["noncurrent_version_transitions"] = transitions
+	}
+
+	if abort := rule.AbortIncompleteMultipartUpload; abort != nil {
+		entry["abort_incomplete_multipart_upload_days"] = aws.ToInt32(abort.DaysAfterInitiation)
+	}
+
+	return entry
+}
+
+// convertReplicationRule converts an S3 replication rule into a metadata entry
+func (c *AWSConnector) convertReplicationRule(rule s3Types.ReplicationRule) map[string]interface{} {
+	entry := map[string]interface{}{
+		"id":     aws.ToString(rule.ID),
+		"status": string(rule.Status),
+	}
+	if rule.Priority != nil {
+		entry["priority"] = aws.ToInt32(rule.Priority)
+	}
+	if rule.Prefix != nil {
+		entry["prefix"] = aws.ToString(rule.Prefix)
+	}
+
+	switch filter := rule.Filter.(type) {
+	case *s3Types.ReplicationRuleFilterMemberPrefix:
+		entry["filter"] = map[string]interface{}{"prefix": filter.Value}
+	case *s3Types.ReplicationRuleFilterMemberTag:
+		entry["filter"] = map[string]interface{}{"tags": c.convertS3Tags([]s3Types.Tag{filter.Value})}
+	case *s3Types.ReplicationRuleFilterMemberAnd:
+		and := map[string]interface{}{}
+		if filter.Value.Prefix != nil {
+			and["prefix"] = aws.ToString(filter.Value.Prefix)
+		}
+		if len(filter.Value.Tags) > 0 {
+			and["tags"] = c.convertS3Tags(filter.Value.Tags)
+		}
+		entry["filter"] = map[string]interface{}{"and": and}
+	}
+
+	if rule.DeleteMarkerReplication != nil {
+		entry["delete_marker_replication"] = string(rule.DeleteMarkerReplication.Status)
+	}
+
+	if destination := rule.Destination; destination != nil {
+		arn := aws.ToString(destination.Bucket)
+		fields := map[string]interface{}{
+			"bucket_arn": arn,
+			// The bucket name identifies the destination if it is discovered
+			"bucket": arn[strings.LastIndex(arn, ":")+1:],
+		}
+		if destination.StorageClass != "" {
+			fields["storage_class"] = string(destination.StorageClass)
+		}
+		if destination.Account != nil {
+			fields["account"] = aws.ToString(destination.Account)
+		}
+		if destination.EncryptionConfiguration != nil && destination.EncryptionConfiguration.ReplicaKmsKeyID != nil {
+			fields["replica_kms_key_id"] = aws.ToString(destination.EncryptionConfiguration.ReplicaKmsKeyID)
+		}
+		entry["destination"] = fields
+	}
+
+	return entry
+}
+
+// convertS3Tags converts S3 tags to a metadata map
+func (c *AWSConnector) convertS3Tags(tags []s3Types.Tag) map[string]interface{} {
+	result := make(map[string]interface{}, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
+// s3Client returns an S3 client for the given region, cached like the
+// regional EC2 clients
+func (c *AWSConnector) s3Client(region string) s3API {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if region == "" {
+		region = c.config.Region
+	}
+
+	key := "s3:" + region
+	if client, exists := c.clients[key]; exists {
+		return client.(s3API)
+	}
+
+	client := c.newS3Client(c.config, region)
+	c.clients[key] = client
+	return client
+}
+
+// newRegionalS3Client creates an S3 client for a region from a shared config
+func newRegionalS3Client(cfg aws.Config, region string) s3API {
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.Region = region
+	})
+}
+
+// bucketRegion returns the region of a bucket location constraint; buckets
+// in us-east-1 have no constraint and old eu-west-1 buckets report "EU"
+func bucketRegion(constraint s3Types.BucketLocationConstraint) string {
+	switch constraint {
+	case "":
+		return "us-east-1"
+	case s3Types.BucketLocationConstraintEu:
+		return "eu-west-1"
+	default:
+		return string(constraint)
+	}
+}
+
+// awsPartition returns the ARN partition of a region
+func awsPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	default:
+		return "aws"
+	}
+}
+
+// isS3ErrorCode reports whether err is an S3 API error with the given code
+func isS3ErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
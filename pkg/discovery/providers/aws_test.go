@@ -0,0 +1,455 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventBridgeTypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+)
+
+// stubEC2 serves Describe* calls from fixed pages linked by NextToken and
+// records the MaxResults sent with each call. Calls it does not implement
+// panic through the nil embedded interface.
+type stubEC2 struct {
+	ec2API
+
+	vpcs           [][]ec2Types.Vpc
+	subnets        [][]ec2Types.Subnet
+	securityGroups [][]ec2Types.SecurityGroup
+	rules          [][]ec2Types.SecurityGroupRule
+	reservations   [][]ec2Types.Reservation
+
+	mu         sync.Mutex
+	maxResults map[string][]int32
+}
+
+// page returns the index of the page requested by a NextToken and the token
+// of the page after it
+func page(token *string, pages int) (int, *string, error) {
+	index := 0
+	if token != nil {
+		var err error
+		if index, err = strconv.Atoi(*token); err != nil || index >= pages {
+			return 0, nil, fmt.Errorf("invalid NextToken %q", *token)
+		}
+	}
+	if index+1 < pages {
+		return index, aws.String(strconv.Itoa(index + 1)), nil
+	}
+	return index, nil, nil
+}
+
+func (s *stubEC2) record(operation string, maxResults *int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxResults == nil {
+		s.maxResults = make(map[string][]int32)
+	}
+	s.maxResults[operation] = append(s.maxResults[operation], aws.ToInt32(maxResults))
+}
+
+func (s *stubEC2) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	s.record("DescribeVpcs", params.MaxResults)
+	index, next, err := page(params.NextToken, len(s.vpcs))
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeVpcsOutput{Vpcs: s.vpcs[index], NextToken: next}, nil
+}
+
+func (s *stubEC2) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	s.record("DescribeSubnets", params.MaxResults)
+	index, next, err := page(params.NextToken, len(s.subnets))
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeSubnetsOutput{Subnets: s.subnets[index], NextToken: next}, nil
+}
+
+func (s *stubEC2) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	s.record("DescribeSecurityGroups", params.MaxResults)
+	index, next, err := page(params.NextToken, len(s.securityGroups))
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: s.securityGroups[index], NextToken: next}, nil
+}
+
+func (s *stubEC2) DescribeSecurityGroupRules(ctx context.Context, params *ec2.DescribeSecurityGroupRulesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupRulesOutput, error) {
+	s.record("DescribeSecurityGroupRules", params.MaxResults)
+	index, next, err := page(params.NextToken, len(s.rules))
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeSecurityGroupRulesOutput{SecurityGroupRules: s.rules[index], NextToken: next}, nil
+}
+
+func (s *stubEC2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	s.record("DescribeInstances", params.MaxResults)
+	index, next, err := page(params.NextToken, len(s.reservations))
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: s.reservations[index], NextToken: next}, nil
+}
+
+func (s *stubEC2) DescribeInstanceAttribute(ctx context.Context, params *ec2.DescribeInstanceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceAttributeOutput, error) {
+	return &ec2.DescribeInstanceAttributeOutput{}, nil
+}
+
+// newTestAWSConnector returns a connector whose EC2 clients are the stub
+func newTestAWSConnector(stub ec2API) *AWSConnector {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &AWSConnector{
+		config:   aws.Config{Region: "us-east-1"},
+		logger:   logger,
+		clients:  make(map[string]interface{}),
+		pageSize: defaultAWSPageSize,
+		newEC2Client: func(cfg aws.Config, region string) ec2API {
+			return stub
+		},
+	}
+}
+
+// ids returns the IDs of discovered resources in order
+func ids(resources []discovery.Resource) []string {
+	result := make([]string, len(resources))
+	for i, resource := range resources {
+		result[i] = resource.ID
+	}
+	return result
+}
+
+// assertIDs fails the test unless the resources have exactly the given IDs
+func assertIDs(t *testing.T, resources []discovery.Resource, want ...string) {
+	t.Helper()
+	got := ids(resources)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got resources %v, want %v", got, want)
+	}
+}
+
+// assertMaxResults fails the test unless every call of an operation sent the
+// page size as MaxResults
+func assertMaxResults(t *testing.T, stub *stubEC2, operation string, calls int, want int32) {
+	t.Helper()
+	sent := stub.maxResults[operation]
+	if len(sent) != calls {
+		t.Fatalf("%s called %d times, want %d", operation, len(sent), calls)
+	}
+	for i, maxResults := range sent {
+		if maxResults != want {
+			t.Errorf("%s call %d sent MaxResults %d, want %d", operation, i, maxResults, want)
+		}
+	}
+}
+
+func TestDiscoverVPCsFollowsNextToken(t *testing.T) {
+	stub := &stubEC2{vpcs: [][]ec2Types.Vpc{
+		{{VpcId: aws.String("vpc-1")}, {VpcId: aws.String("vpc-2")}},
+		{{VpcId: aws.String("vpc-3")}},
+		{{VpcId: aws.String("vpc-4")}},
+	}}
+	connector := newTestAWSConnector(stub)
+	connector.SetPageSize(50)
+
+	resources, err := connector.discoverVPCs(context.Background(), "us-east-1")
+	if err != nil {
+		t.Fatalf("discoverVPCs returned error: %v", err)
+	}
+
+	assertIDs(t, resources, "vpc-1", "vpc-2", "vpc-3", "vpc-4")
+	assertMaxResults(t, stub, "DescribeVpcs", 3, 50)
+}
+
+func TestDiscoverSubnetsFollowsNextToken(t *testing.T) {
+	stub := &stubEC2{subnets: [][]ec2Types.Subnet{
+		{{SubnetId: aws.String("subnet-1"), VpcId: aws.String("vpc-1")}},
+		{{SubnetId: aws.String("subnet-2"), VpcId: aws.String("vpc-1")}, {SubnetId: aws.String("subnet-3"), VpcId: aws.String("vpc-2")}},
+	}}
+	connector := newTestAWSConnector(stub)
+
+	resources, err := connector.discoverSubnets(context.Background(), "us-east-1")
+	if err != nil {
+		t.Fatalf("discoverSubnets returned error: %v", err)
+	}
+
+	assertIDs(t, resources, "subnet-1", "subnet-2", "subnet-3")
+	assertMaxResults(t, stub, "DescribeSubnets", 2, defaultAWSPageSize)
+}
+
+func TestDiscoverSecurityGroupsFollowsNextToken(t *testing.T) {
+	stub := &stubEC2{securityGroups: [][]ec2Types.SecurityGroup{
+		{{GroupId: aws.String("sg-1"), GroupName: aws.String("web")}},
+		{{GroupId: aws.String("sg-2"), GroupName: aws.String("db")}},
+		{{GroupId: aws.String("sg-3"), GroupName: aws.String("cache")}},
+	}}
+	connector := newTestAWSConnector(stub)
+	connector.SetPageSize(5)
+
+	resources, err := connector.discoverSecurityGroups(context.Background(), "us-east-1")
+	if err != nil {
+		t.Fatalf("discoverSecurityGroups returned error: %v", err)
+	}
+
+	assertIDs(t, resources, "sg-1", "sg-2", "sg-3")
+	assertMaxResults(t, stub, "DescribeSecurityGroups", 3, 5)
+}
+
+func TestDiscoverSecurityGroupsRecordsRuleIDs(t *testing.T) {
+	stub := &stubEC2{
+		securityGroups: [][]ec2Types.SecurityGroup{{
+			{
+				GroupId: aws.String("sg-1"),
+				IpPermissions: []ec2Types.IpPermission{{
+					IpProtocol:       aws.String("tcp"),
+					FromPort:         aws.Int32(443),
+					ToPort:           aws.Int32(443),
+					UserIdGroupPairs: []ec2Types.UserIdGroupPair{{GroupId: aws.String("sg-2")}},
+				}},
+				IpPermissionsEgress: []ec2Types.IpPermission{{
+					IpProtocol: aws.String("-1"),
+					IpRanges:   []ec2Types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+				}},
+			},
+			{GroupId: aws.String("sg-2")},
+		}},
+		rules: [][]ec2Types.SecurityGroupRule{
+			{{
+				SecurityGroupRuleId: aws.String("sgr-ingress"),
+				GroupId:             aws.String("sg-1"),
+				IsEgress:            aws.Bool(false),
+				IpProtocol:          aws.String("tcp"),
+				FromPort:            aws.Int32(443),
+				ToPort:              aws.Int32(443),
+				ReferencedGroupInfo: &ec2Types.ReferencedSecurityGroup{GroupId: aws.String("sg-2")},
+			}},
+			{{
+				SecurityGroupRuleId: aws.String("sgr-egress"),
+				GroupId:             aws.String("sg-1"),
+				IsEgress:            aws.Bool(true),
+				IpProtocol:          aws.String("-1"),
+				FromPort:            aws.Int32(-1),
+				ToPort:              aws.Int32(-1),
+				CidrIpv4:            aws.String("0.0.0.0/0"),
+			}},
+		},
+	}
+	connector := newTestAWSConnector(stub)
+
+	resources, err := connector.discoverSecurityGroups(context.Background(), "us-east-1")
+	if err != nil {
+		t.Fatalf("discoverSecurityGroups returned error: %v", err)
+	}
+
+	assertIDs(t, resources, "sg-1", "sg-2")
+	assertMaxResults(t, stub, "DescribeSecurityGroupRules", 2, defaultAWSPageSize)
+
+	ingress := resources[0].Metadata["ingress_rules"].([]map[string]interface{})
+	if got := ingress[0]["security_groups"].([]map[string]interface{})[0]["rule_id"]; got != "sgr-ingress" {
+		t.Errorf("ingress rule ID = %v, want sgr-ingress", got)
+	}
+	egress := resources[0].Metadata["egress_rules"].([]map[string]interface{})
+	if got := egress[0]["cidr_blocks"].([]map[string]interface{})[0]["rule_id"]; got != "sgr-egress" {
+		t.Errorf("egress rule ID = %v, want sgr-egress", got)
+	}
+}
+
+func TestDiscoverInstancesFollowsNextToken(t *testing.T) {
+	instance := func(id string) ec2Types.Instance {
+		return ec2Types.Instance{InstanceId: aws.String(id), InstanceType: ec2Types.InstanceTypeT3Micro}
+	}
+	stub := &stubEC2{reservations: [][]ec2Types.Reservation{
+		{
+			{Instances: []ec2Types.Instance{instance("i-1"), instance("i-2")}},
+			{Instances: []ec2Types.Instance{instance("i-3")}},
+		},
+		{
+			{Instances: []ec2Types.Instance{instance("i-4")}},
+		},
+	}}
+	connector := newTestAWSConnector(stub)
+	connector.SetPageSize(1000)
+
+	resources, err := connector.discoverInstances(context.Background(), "us-east-1")
+	if err != nil {
+		t.Fatalf("discoverInstances returned error: %v", err)
+	}
+
+	assertIDs(t, resources, "i-1", "i-2", "i-3", "i-4")
+	assertMaxResults(t, stub, "DescribeInstances", 2, 1000)
+}
+
+func TestSetPageSizeClamps(t *testing.T) {
+	tests := []struct {
+		size int32
+		want int32
+	}{
+		{size: 0, want: defaultAWSPageSize},
+		{size: -10, want: defaultAWSPageSize},
+		{size: 1, want: 5},
+		{size: 5, want: 5},
+		{size: 250, want: 250},
+		{size: 1000, want: 1000},
+		{size: 5000, want: 1000},
+	}
+
+	for _, tt := range tests {
+		connector := newTestAWSConnector(&stubEC2{})
+		connector.SetPageSize(tt.size)
+		if connector.pageSize != tt.want {
+			t.Errorf("SetPageSize(%d) set page size %d, want %d", tt.size, connector.pageSize, tt.want)
+		}
+	}
+}
+
+func TestClampedPageSizeIsSent(t *testing.T) {
+	stub := &stubEC2{vpcs: [][]ec2Types.Vpc{{{VpcId: aws.String("vpc-1")}}}}
+	connector := newTestAWSConnector(stub)
+	connector.SetPageSize(2)
+
+	if _, err := connector.discoverVPCs(context.Background(), "us-east-1"); err != nil {
+		t.Fatalf("discoverVPCs returned error: %v", err)
+	}
+
+	assertMaxResults(t, stub, "DescribeVpcs", 1, minAWSPageSize)
+}
+
+// stubRDS serves DescribeDBInstances from fixed pages linked by Marker and
+// records the MaxRecords sent with each call
+type stubRDS struct {
+	rdsAPI
+
+	instances [][]rdsTypes.DBInstance
+	sent      []int32
+}
+
+func (s *stubRDS) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	s.sent = append(s.sent, aws.ToInt32(params.MaxRecords))
+	index, next, err := page(params.Marker, len(s.instances))
+	if err != nil {
+		return nil, err
+	}
+	return &rds.DescribeDBInstancesOutput{DBInstances: s.instances[index], Marker: next}, nil
+}
+
+func TestDiscoverDBInstancesFollowsMarker(t *testing.T) {
+	stub := &stubRDS{instances: [][]rdsTypes.DBInstance{
+		{
+			{DBInstanceArn: aws.String("arn:aws:rds:us-east-1:1:db:app"), Engine: aws.String("postgres")},
+			{DBInstanceArn: aws.String("arn:aws:rds:us-east-1:1:db:docs"), Engine: aws.String("docdb")},
+		},
+		{
+			{DBInstanceArn: aws.String("arn:aws:rds:us-east-1:1:db:aurora-1"), Engine: aws.String("aurora-mysql"),
+				DBClusterIdentifier: aws.String("aurora")},
+		},
+	}}
+	connector := newTestAWSConnector(nil)
+	connector.newRDSClient = func(cfg aws.Config, region string) rdsAPI {
+		return stub
+	}
+	connector.SetPageSize(1000)
+
+	resources, err := connector.discoverDBInstances(context.Background(), "us-east-1")
+	if err != nil {
+		t.Fatalf("discoverDBInstances returned error: %v", err)
+	}
+
+	// DocumentDB instances are left out and Aurora instances are cluster instances
+	assertIDs(t, resources, "arn:aws:rds:us-east-1:1:db:app", "arn:aws:rds:us-east-1:1:db:aurora-1")
+	if resources[1].Type != "aws_rds_cluster_instance" || resources[1].Metadata["cluster_arn"] != "arn:aws:rds:us-east-1:1:cluster:aurora" {
+		t.Errorf("got %s with cluster %v, want an aws_rds_cluster_instance of the aurora cluster", resources[1].Type, resources[1].Metadata["cluster_arn"])
+	}
+	if fmt.Sprint(stub.sent) != fmt.Sprint([]int32{maxRDSPageSize, maxRDSPageSize}) {
+		t.Errorf("sent MaxRecords %v, want %d on both pages", stub.sent, maxRDSPageSize)
+	}
+}
+
+// stubEventBridge serves ListRules from fixed pages linked by NextToken and
+// records the Limit sent with each call. Every rule is on the default bus
+// and has no targets or tags.
+type stubEventBridge struct {
+	eventBridgeAPI
+
+	rules [][]eventBridgeTypes.Rule
+	sent  []int32
+}
+
+func (s *stubEventBridge) ListEventBuses(ctx context.Context, params *eventbridge.ListEventBusesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListEventBusesOutput, error) {
+	return &eventbridge.ListEventBusesOutput{EventBuses: []eventBridgeTypes.EventBus{{Name: aws.String("default")}}}, nil
+}
+
+func (s *stubEventBridge) ListRules(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error) {
+	s.sent = append(s.sent, aws.ToInt32(params.Limit))
+	index, next, err := page(params.NextToken, len(s.rules))
+	if err != nil {
+		return nil, err
+	}
+	return &eventbridge.ListRulesOutput{Rules: s.rules[index], NextToken: next}, nil
+}
+
+func (s *stubEventBridge) ListTargetsByRule(ctx context.Context, params *eventbridge.ListTargetsByRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error) {
+	return &eventbridge.ListTargetsByRuleOutput{}, nil
+}
+
+func (s *stubEventBridge) ListTagsForResource(ctx context.Context, params *eventbridge.ListTagsForResourceInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTagsForResourceOutput, error) {
+	return &eventbridge.ListTagsForResourceOutput{}, nil
+}
+
+func TestDiscoverEventBridgeRulesFollowsNextToken(t *testing.T) {
+	stub := &stubEventBridge{rules: [][]eventBridgeTypes.Rule{
+		{
+			{Arn: aws.String("arn:aws:events:us-east-1:1:rule/nightly"), Name: aws.String("nightly"), EventBusName: aws.String("default")},
+			{Arn: aws.String("arn:aws:events:us-east-1:1:rule/managed"), Name: aws.String("managed"), EventBusName: aws.String("default"),
+				ManagedBy: aws.String("guardduty.amazonaws.com")},
+		},
+		{
+			{Arn: aws.String("arn:aws:events:us-east-1:1:rule/orders"), Name: aws.String("orders"), EventBusName: aws.String("default")},
+		},
+	}}
+	connector := newTestAWSConnector(nil)
+	connector.newEventBridgeClient = func(cfg aws.Config, region string) eventBridgeAPI {
+		return stub
+	}
+	connector.SetPageSize(1000)
+
+	resources, err := connector.discoverEventBridgeRules(context.Background(), "us-east-1")
+	if err != nil {
+		t.Fatalf("discoverEventBridgeRules returned error: %v", err)
+	}
+
+	// Rules managed by other services are left out
+	assertIDs(t, resources, "arn:aws:events:us-east-1:1:rule/nightly", "arn:aws:events:us-east-1:1:rule/orders")
+	if fmt.Sprint(stub.sent) != fmt.Sprint([]int32{maxEventBridgePageSize, maxEventBridgePageSize}) {
+		t.Errorf("sent Limit %v, want %d on both pages", stub.sent, maxEventBridgePageSize)
+	}
+}
+
+func TestDisconnectedConnectorReportsNotConnected(t *testing.T) {
+	connector := newTestAWSConnector(nil)
+	connector.initializeClients()
+	if err := connector.Disconnect(context.Background()); err != nil {
+		t.Fatalf("Disconnect returned error: %v", err)
+	}
+
+	if err := connector.ValidateCredentials(context.Background()); err == nil {
+		t.Error("ValidateCredentials returned no error after Disconnect")
+	}
+	if _, err := connector.GetRegions(context.Background()); err == nil {
+		t.Error("GetRegions returned no error after Disconnect")
+	}
+}
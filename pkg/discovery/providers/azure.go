@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -31,6 +32,9 @@ type AzureConfig struct {
 	Locations      []string `yaml:"locations" json:"locations"`
 }
 
+// Compile-time check that AzureConnector satisfies the ProviderConnector interface
+var _ discovery.ProviderConnector = (*AzureConnector)(nil)
+
 // NewAzureConnector creates a new Azure connector
 func NewAzureConnector(ctx context.Context, subscriptionID string) (*AzureConnector, error) {
 	// Use DefaultAzureCredential which handles various auth methods
@@ -86,6 +90,13 @@ func (c *AzureConnector) initializeClients() error {
 	}
 	c.clients["networkSecurityGroups"] = nsgClient
 
+	// Network Interfaces client
+	networkInterfacesClient, err := armnetwork.NewInterfacesClient(c.subscriptionID, c.credential, clientOptions)
+	if err != nil {
+		return fmt.Errorf("failed to create network interfaces client: %w", err)
+	}
+	c.clients["networkInterfaces"] = networkInterfacesClient
+
 	// Virtual Machines client
 	virtualMachinesClient, err := armcompute.NewVirtualMachinesClient(c.subscriptionID, c.credential, clientOptions)
 	if err != nil {
@@ -101,6 +112,22 @@ func (c *AzureConnector) Provider() discovery.CloudProvider {
 	return discovery.Azure
 }
 
+// Connect establishes the Azure service clients if they are not already initialized
+func (c *AzureConnector) Connect(ctx context.Context) error {
+	if len(c.clients) == 0 {
+		if err := c.initializeClients(); err != nil {
+			return fmt.Errorf("failed to initialize Azure clients: %w", err)
+		}
+	}
+	return nil
+}
+
+// Disconnect releases the Azure service clients
+func (c *AzureConnector) Disconnect(ctx context.Context) error {
+	c.clients = make(map[string]interface{})
+	return nil
+}
+
 // ValidateCredentials validates Azure credentials
 func (c *AzureConnector) ValidateCredentials(ctx context.Context) error {
 	// Test credentials by trying to list resource groups
@@ -143,13 +170,40 @@ func (c *AzureConnector) GetResourceTypes(ctx context.Context) ([]string, error)
 		"virtual_network",
 		"subnet",
 		"network_security_group",
+		"network_interface",
 		"virtual_machine",
 	}, nil
 }
 
-// Discover discovers Azure resources
-func (c *AzureConnector) Discover(ctx context.Context, opts discovery.ProviderDiscoveryOptions) ([]discovery.Resource, error) {
+// GetResourcesByType discovers Azure resources of a specific type in a single location
+func (c *AzureConnector) GetResourcesByType(ctx context.Context, resourceType string, region string) ([]discovery.Resource, error) {
+	resourceType = strings.TrimPrefix(resourceType, "azure_")
+
+	supported, err := c.GetResourceTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(supported, resourceType) {
+		return nil, fmt.Errorf("unsupported Azure resource type: %s", resourceType)
+	}
+
+	var regions []string
+	if region != "" {
+		regions = []string{region}
+	}
+
+	return c.discoverResourceType(ctx, resourceType, regions)
+}
+
+// DiscoverResources discovers Azure resources. Failures for individual resource
+// types are returned as a *discovery.PartialDiscoveryError alongside the
+// resources that were discovered successfully. When no resource type succeeds
+// there is nothing partial to return, so a plain error is returned.
+func (c *AzureConnector) DiscoverResources(ctx context.Context, opts discovery.ProviderDiscoveryOptions) ([]discovery.Resource, error) {
 	var allResources []discovery.Resource
+	var discoveryErrors []discovery.DiscoveryError
+	var firstErr error
+	succeeded := 0
 
 	// Get regions to scan
 	regions := opts.Regions
@@ -180,12 +234,32 @@ func (c *AzureConnector) Discover(ctx context.Context, opts discovery.ProviderDi
 		typeResources, err := c.discoverResourceType(ctx, resourceType, regions)
 		if err != nil {
 			c.logger.Warnf("Failed to discover %s resources: %v", resourceType, err)
+			discoveryErrors = append(discoveryErrors, discovery.DiscoveryError{
+				Provider:     discovery.Azure,
+				ResourceType: resourceType,
+				Message:      err.Error(),
+				Error:        err,
+				Severity:     discovery.SeverityError,
+				Timestamp:    time.Now(),
+			})
+			if firstErr == nil {
+				firstErr = err
+			}
 			continue
 		}
 
+		succeeded++
 		allResources = append(allResources, typeResources...)
 	}
 
+	if succeeded == 0 && firstErr != nil {
+		return nil, fmt.Errorf("all %d Azure resource type scans failed: %w", len(resourceTypes), firstErr)
+	}
+
+	if len(discoveryErrors) > 0 {
+		return allResources, &discovery.PartialDiscoveryError{Errors: discoveryErrors}
+	}
+
 	return allResources, nil
 }
 
@@ -200,6 +274,8 @@ func (c *AzureConnector) discoverResourceType(ctx context.Context, resourceType
 		return c.discoverSubnets(ctx, regions)
 	case "network_security_group":
 		return c.discoverNetworkSecurityGroups(ctx, regions)
+	case "network_interface":
+		return c.discoverNetworkInterfaces(ctx, regions)
 	case "virtual_machine":
 		return c.discoverVirtualMachines(ctx, regions)
 	default:
@@ -293,7 +369,11 @@ func (c *AzureConnector) discoverVirtualNetworks(ctx context.Context, regions []
 				}
 				
 				if vnet.Properties.AddressSpace != nil && vnet.Properties.AddressSpace.AddressPrefixes != nil {
-					resource.Metadata["address_prefixes"] = vnet.Properties.AddressSpace.AddressPrefixes
+					resource.Metadata["address_prefixes"] = c.convertStringPointers(vnet.Properties.AddressSpace.AddressPrefixes)
+				}
+
+				if vnet.Properties.DhcpOptions != nil && len(vnet.Properties.DhcpOptions.DNSServers) > 0 {
+					resource.Metadata["dns_servers"] = c.convertStringPointers(vnet.Properties.DhcpOptions.DNSServers)
 				}
 
 				if vnet.Properties.Subnets != nil {
@@ -346,6 +426,7 @@ func (c *AzureConnector) discoverSubnets(ctx context.Context, regions []string)
 				}
 
 				resource.Metadata["virtual_network"] = vnet.Name
+				resource.Metadata["virtual_network_id"] = vnet.ID
 
 				if subnet.Properties != nil {
 					if subnet.Properties.ProvisioningState != nil {
@@ -355,6 +436,14 @@ func (c *AzureConnector) discoverSubnets(ctx context.Context, regions []string)
 					if subnet.Properties.AddressPrefix != nil {
 						resource.Metadata["address_prefix"] = *subnet.Properties.AddressPrefix
 					}
+
+					if subnet.Properties.AddressPrefixes != nil {
+						resource.Metadata["address_prefixes"] = c.convertStringPointers(subnet.Properties.AddressPrefixes)
+					}
+
+					if subnet.Properties.NetworkSecurityGroup != nil && subnet.Properties.NetworkSecurityGroup.ID != nil {
+						resource.Metadata["network_security_group_id"] = *subnet.Properties.NetworkSecurityGroup.ID
+					}
 				}
 
 				resources = append(resources, resource)
@@ -406,6 +495,7 @@ func (c *AzureConnector) discoverNetworkSecurityGroups(ctx context.Context, regi
 
 				if nsg.Properties.SecurityRules != nil {
 					resource.Metadata["security_rules_count"] = len(nsg.Properties.SecurityRules)
+					resource.Metadata["security_rules"] = c.convertSecurityRules(nsg.Properties.SecurityRules)
 				}
 
 				if nsg.Properties.DefaultSecurityRules != nil {
@@ -420,6 +510,99 @@ func (c *AzureConnector) discoverNetworkSecurityGroups(ctx context.Context, regi
 	return resources, nil
 }
 
+// discoverNetworkInterfaces discovers Azure Network Interfaces
+func (c *AzureConnector) discoverNetworkInterfaces(ctx context.Context, regions []string) ([]discovery.Resource, error) {
+	client := c.clients["networkInterfaces"].(*armnetwork.InterfacesClient)
+
+	var resources []discovery.Resource
+	pager := client.NewListAllPager(nil)
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+		}
+
+		for _, nic := range page.Value {
+			if nic.Name == nil || nic.Location == nil {
+				continue
+			}
+
+			// Filter by regions if specified
+			if len(regions) > 0 && !c.containsLocation(regions, *nic.Location) {
+				continue
+			}
+
+			resource := discovery.Resource{
+				ID:            *nic.ID,
+				Name:          *nic.Name,
+				Type:          "azure_network_interface",
+				Provider:      discovery.Azure,
+				Region:        *nic.Location,
+				ResourceGroup: c.extractResourceGroupFromID(*nic.ID),
+				Metadata:      make(map[string]interface{}),
+				Tags:          c.convertAzureTags(nic.Tags),
+			}
+
+			if nic.Properties != nil {
+				if nic.Properties.ProvisioningState != nil {
+					resource.Metadata["provisioning_state"] = string(*nic.Properties.ProvisioningState)
+				}
+
+				if nic.Properties.EnableAcceleratedNetworking != nil {
+					resource.Metadata["enable_accelerated_networking"] = *nic.Properties.EnableAcceleratedNetworking
+				}
+
+				if nic.Properties.EnableIPForwarding != nil {
+					resource.Metadata["enable_ip_forwarding"] = *nic.Properties.EnableIPForwarding
+				}
+
+				if nic.Properties.NetworkSecurityGroup != nil && nic.Properties.NetworkSecurityGroup.ID != nil {
+					resource.Metadata["network_security_group_id"] = *nic.Properties.NetworkSecurityGroup.ID
+				}
+
+				if nic.Properties.VirtualMachine != nil && nic.Properties.VirtualMachine.ID != nil {
+					resource.Metadata["virtual_machine_id"] = *nic.Properties.VirtualMachine.ID
+				}
+
+				var ipConfigurations []map[string]interface{}
+				for _, ipConfig := range nic.Properties.IPConfigurations {
+					if ipConfig == nil || ipConfig.Name == nil || ipConfig.Properties == nil {
+						continue
+					}
+
+					entry := map[string]interface{}{
+						"name": *ipConfig.Name,
+					}
+					props := ipConfig.Properties
+					if props.Subnet != nil && props.Subnet.ID != nil {
+						entry["subnet_id"] = *props.Subnet.ID
+					}
+					if props.PrivateIPAllocationMethod != nil {
+						entry["private_ip_address_allocation"] = string(*props.PrivateIPAllocationMethod)
+					}
+					if props.PrivateIPAddress != nil {
+						entry["private_ip_address"] = *props.PrivateIPAddress
+					}
+					if props.PublicIPAddress != nil && props.PublicIPAddress.ID != nil {
+						entry["public_ip_address_id"] = *props.PublicIPAddress.ID
+					}
+					if props.Primary != nil {
+						entry["primary"] = *props.Primary
+					}
+
+					ipConfigurations = append(ipConfigurations, entry)
+				}
+				resource.Metadata["ip_configurations"] = ipConfigurations
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
 // discoverVirtualMachines discovers Azure Virtual Machines
 func (c *AzureConnector) discoverVirtualMachines(ctx context.Context, regions []string) ([]discovery.Resource, error) {
 	client := c.clients["virtualMachines"].(*armcompute.VirtualMachinesClient)
@@ -474,6 +657,44 @@ func (c *AzureConnector) discoverVirtualMachines(ctx context.Context, regions []
 					if imageRef.SKU != nil {
 						resource.Metadata["image_sku"] = *imageRef.SKU
 					}
+					if imageRef.Version != nil {
+						resource.Metadata["image_version"] = *imageRef.Version
+					}
+					if imageRef.ID != nil {
+						resource.Metadata["image_id"] = *imageRef.ID
+					}
+				}
+
+				if vm.Properties.StorageProfile != nil && vm.Properties.StorageProfile.OSDisk != nil {
+					osDisk := vm.Properties.StorageProfile.OSDisk
+					disk := make(map[string]interface{})
+					if osDisk.Name != nil {
+						disk["name"] = *osDisk.Name
+					}
+					if osDisk.Caching != nil {
+						disk["caching"] = string(*osDisk.Caching)
+					}
+					if osDisk.DiskSizeGB != nil {
+						disk["disk_size_gb"] = *osDisk.DiskSizeGB
+					}
+					if osDisk.ManagedDisk != nil && osDisk.ManagedDisk.StorageAccountType != nil {
+						disk["storage_account_type"] = string(*osDisk.ManagedDisk.StorageAccountType)
+					}
+					resource.Metadata["os_disk"] = disk
+
+					if osDisk.OSType != nil {
+						resource.Metadata["os_type"] = string(*osDisk.OSType)
+					}
+				}
+
+				if vm.Properties.NetworkProfile != nil {
+					var nicIDs []string
+					for _, nic := range vm.Properties.NetworkProfile.NetworkInterfaces {
+						if nic != nil && nic.ID != nil {
+							nicIDs = append(nicIDs, *nic.ID)
+						}
+					}
+					resource.Metadata["network_interface_ids"] = nicIDs
 				}
 
 				if vm.Properties.OSProfile != nil {
@@ -483,9 +704,21 @@ func (c *AzureConnector) discoverVirtualMachines(ctx context.Context, regions []
 					if vm.Properties.OSProfile.AdminUsername != nil {
 						resource.Metadata["admin_username"] = *vm.Properties.OSProfile.AdminUsername
 					}
+					if linux := vm.Properties.OSProfile.LinuxConfiguration; linux != nil {
+						if linux.DisablePasswordAuthentication != nil {
+							resource.Metadata["disable_password_authentication"] = *linux.DisablePasswordAuthentication
+						}
+						if linux.SSH != nil {
+							resource.Metadata["ssh_key_count"] = len(linux.SSH.PublicKeys)
+						}
+					}
 				}
 			}
 
+			if len(vm.Zones) > 0 {
+				resource.Zone = *vm.Zones[0]
+			}
+
 			resources = append(resources, resource)
 		}
 	}
@@ -495,6 +728,74 @@ func (c *AzureConnector) discoverVirtualMachines(ctx context.Context, regions []
 
 // Helper functions
 
+// convertSecurityRules converts NSG security rules into metadata entries
+func (c *AzureConnector) convertSecurityRules(rules []*armnetwork.SecurityRule) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		if rule == nil || rule.Name == nil || rule.Properties == nil {
+			continue
+		}
+
+		props := rule.Properties
+		entry := map[string]interface{}{
+			"name": *rule.Name,
+		}
+		if props.Priority != nil {
+			entry["priority"] = *props.Priority
+		}
+		if props.Direction != nil {
+			entry["direction"] = string(*props.Direction)
+		}
+		if props.Access != nil {
+			entry["access"] = string(*props.Access)
+		}
+		if props.Protocol != nil {
+			entry["protocol"] = string(*props.Protocol)
+		}
+		if props.Description != nil {
+			entry["description"] = *props.Description
+		}
+		if props.SourcePortRange != nil {
+			entry["source_port_range"] = *props.SourcePortRange
+		}
+		if len(props.SourcePortRanges) > 0 {
+			entry["source_port_ranges"] = c.convertStringPointers(props.SourcePortRanges)
+		}
+		if props.DestinationPortRange != nil {
+			entry["destination_port_range"] = *props.DestinationPortRange
+		}
+		if len(props.DestinationPortRanges) > 0 {
+			entry["destination_port_ranges"] = c.convertStringPointers(props.DestinationPortRanges)
+		}
+		if props.SourceAddressPrefix != nil {
+			entry["source_address_prefix"] = *props.SourceAddressPrefix
+		}
+		if len(props.SourceAddressPrefixes) > 0 {
+			entry["source_address_prefixes"] = c.convertStringPointers(props.SourceAddressPrefixes)
+		}
+		if props.DestinationAddressPrefix != nil {
+			entry["destination_address_prefix"] = *props.DestinationAddressPrefix
+		}
+		if len(props.DestinationAddressPrefixes) > 0 {
+			entry["destination_address_prefixes"] = c.convertStringPointers(props.DestinationAddressPrefixes)
+		}
+
+		result = append(result, entry)
+	}
+	return result
+}
+
+// convertStringPointers converts a slice of string pointers, skipping nils
+func (c *AzureConnector) convertStringPointers(values []*string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			result = append(result, *v)
+		}
+	}
+	return result
+}
+
 // extractResourceGroupFromID extracts the resource group name from an Azure resource ID
 func (c *AzureConnector) extractResourceGroupFromID(resourceID string) string {
 	parts := strings.Split(resourceID, "/")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -28,6 +29,9 @@ type GCPConfig struct {
 	Zones     []string `yaml:"zones" json:"zones"`
 }
 
+// Compile-time check that GCPConnector satisfies the ProviderConnector interface
+var _ discovery.ProviderConnector = (*GCPConnector)(nil)
+
 // NewGCPConnector creates a new GCP connector
 func NewGCPConnector(ctx context.Context, projectID string) (*GCPConnector, error) {
 	if projectID == "" {
@@ -100,6 +104,36 @@ func (c *GCPConnector) Provider() discovery.CloudProvider {
 	return discovery.GCP
 }
 
+// Connect establishes the GCP service clients if they are not already initialized
+func (c *GCPConnector) Connect(ctx context.Context) error {
+	if len(c.clients) == 0 {
+		if err := c.initializeClients(ctx); err != nil {
+			return fmt.Errorf("failed to initialize GCP clients: %w", err)
+		}
+	}
+	return nil
+}
+
+// Disconnect closes the GCP service clients
+func (c *GCPConnector) Disconnect(ctx context.Context) error {
+	var errs []string
+	for name, client := range c.clients {
+		closer, ok := client.(interface{ Close() error })
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	c.clients = make(map[string]interface{})
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close GCP clients: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 // ValidateCredentials validates GCP credentials and project access
 func (c *GCPConnector) ValidateCredentials(ctx context.Context) error {
 	// Test credentials by trying to list regions
@@ -158,9 +192,36 @@ func (c *GCPConnector) GetResourceTypes(ctx context.Context) ([]string, error) {
 	}, nil
 }
 
-// Discover discovers GCP resources
-func (c *GCPConnector) Discover(ctx context.Context, opts discovery.ProviderDiscoveryOptions) ([]discovery.Resource, error) {
+// GetResourcesByType discovers GCP resources of a specific type in a single region.
+// Global resources such as networks and firewalls ignore the region.
+func (c *GCPConnector) GetResourcesByType(ctx context.Context, resourceType string, region string) ([]discovery.Resource, error) {
+	resourceType = strings.TrimPrefix(resourceType, "gcp_compute_")
+
+	supported, err := c.GetResourceTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(supported, resourceType) {
+		return nil, fmt.Errorf("unsupported GCP resource type: %s", resourceType)
+	}
+
+	var regions []string
+	if region != "" {
+		regions = []string{region}
+	}
+
+	return c.discoverResourceType(ctx, resourceType, regions)
+}
+
+// DiscoverResources discovers GCP resources. Failures for individual resource
+// types are returned as a *discovery.PartialDiscoveryError alongside the
+// resources that were discovered successfully. When no resource type succeeds
+// there is nothing partial to return, so a plain error is returned.
+func (c *GCPConnector) DiscoverResources(ctx context.Context, opts discovery.ProviderDiscoveryOptions) ([]discovery.Resource, error) {
 	var allResources []discovery.Resource
+	var discoveryErrors []discovery.DiscoveryError
+	var firstErr error
+	succeeded := 0
 
 	// Get regions to scan
 	regions := opts.Regions
@@ -191,12 +252,32 @@ func (c *GCPConnector) Discover(ctx context.Context, opts discovery.ProviderDisc
 		typeResources, err := c.discoverResourceType(ctx, resourceType, regions)
 		if err != nil {
 			c.logger.Warnf("Failed to discover %s resources: %v", resourceType, err)
+			discoveryErrors = append(discoveryErrors, discovery.DiscoveryError{
+				Provider:     discovery.GCP,
+				ResourceType: resourceType,
+				Message:      err.Error(),
+				Error:        err,
+				Severity:     discovery.SeverityError,
+				Timestamp:    time.Now(),
+			})
+			if firstErr == nil {
+				firstErr = err
+			}
 			continue
 		}
 
+		succeeded++
 		allResources = append(allResources, typeResources...)
 	}
 
+	if succeeded == 0 && firstErr != nil {
+		return nil, fmt.Errorf("all %d GCP resource type scans failed: %w", len(resourceTypes), firstErr)
+	}
+
+	if len(discoveryErrors) > 0 {
+		return allResources, &discovery.PartialDiscoveryError{Errors: discoveryErrors}
+	}
+
 	return allResources, nil
 }
 
@@ -329,6 +410,20 @@ func (c *GCPConnector) discoverSubnetworks(ctx context.Context, regions []string
 				resource.Metadata["private_ip_google_access"] = *subnetwork.PrivateIpGoogleAccess
 			}
 
+			if len(subnetwork.SecondaryIpRanges) > 0 {
+				var secondaryRanges []map[string]interface{}
+				for _, secondary := range subnetwork.SecondaryIpRanges {
+					if secondary.RangeName == nil || secondary.IpCidrRange == nil {
+						continue
+					}
+					secondaryRanges = append(secondaryRanges, map[string]interface{}{
+						"range_name":    *secondary.RangeName,
+						"ip_cidr_range": *secondary.IpCidrRange,
+					})
+				}
+				resource.Metadata["secondary_ip_ranges"] = secondaryRanges
+			}
+
 			resources = append(resources, resource)
 		}
 	}
@@ -384,10 +479,42 @@ func (c *GCPConnector) discoverFirewalls(ctx context.Context) ([]discovery.Resou
 
 		if firewall.Allowed != nil {
 			resource.Metadata["allowed_rules_count"] = len(firewall.Allowed)
+
+			allowed := make([]map[string]interface{}, 0, len(firewall.Allowed))
+			for _, rule := range firewall.Allowed {
+				allowed = append(allowed, c.convertFirewallRule(rule.IPProtocol, rule.Ports))
+			}
+			resource.Metadata["allowed"] = allowed
 		}
 
 		if firewall.Denied != nil {
 			resource.Metadata["denied_rules_count"] = len(firewall.Denied)
+
+			denied := make([]map[string]interface{}, 0, len(firewall.Denied))
+			for _, rule := range firewall.Denied {
+				denied = append(denied, c.convertFirewallRule(rule.IPProtocol, rule.Ports))
+			}
+			resource.Metadata["denied"] = denied
+		}
+
+		if firewall.Disabled != nil {
+			resource.Metadata["disabled"] = *firewall.Disabled
+		}
+
+		if firewall.DestinationRanges != nil {
+			resource.Metadata["destination_ranges"] = firewall.DestinationRanges
+		}
+
+		if firewall.SourceTags != nil {
+			resource.Metadata["source_tags"] = firewall.SourceTags
+		}
+
+		if firewall.SourceServiceAccounts != nil {
+			resource.Metadata["source_service_accounts"] = firewall.SourceServiceAccounts
+		}
+
+		if firewall.TargetServiceAccounts != nil {
+			resource.Metadata["target_service_accounts"] = firewall.TargetServiceAccounts
 		}
 
 		if firewall.SourceRanges != nil {
@@ -461,6 +588,56 @@ func (c *GCPConnector) discoverInstances(ctx context.Context, regions []string)
 				resource.Metadata["creation_timestamp"] = *instance.CreationTimestamp
 			}
 
+			if instance.CanIpForward != nil {
+				resource.Metadata["can_ip_forward"] = *instance.CanIpForward
+			}
+
+			if instance.DeletionProtection != nil {
+				resource.Metadata["deletion_protection"] = *instance.DeletionProtection
+			}
+
+			if instance.Tags != nil && len(instance.Tags.Items) > 0 {
+				resource.Metadata["network_tags"] = instance.Tags.Items
+			}
+
+			if len(instance.ServiceAccounts) > 0 && instance.ServiceAccounts[0].Email != nil {
+				resource.Metadata["service_account_email"] = *instance.ServiceAccounts[0].Email
+				resource.Metadata["service_account_scopes"] = instance.ServiceAccounts[0].Scopes
+			}
+
+			if scheduling := instance.Scheduling; scheduling != nil {
+				schedulingInfo := make(map[string]interface{})
+				if scheduling.Preemptible != nil {
+					schedulingInfo["preemptible"] = *scheduling.Preemptible
+				}
+				if scheduling.AutomaticRestart != nil {
+					schedulingInfo["automatic_restart"] = *scheduling.AutomaticRestart
+				}
+				if scheduling.OnHostMaintenance != nil {
+					schedulingInfo["on_host_maintenance"] = *scheduling.OnHostMaintenance
+				}
+				resource.Metadata["scheduling"] = schedulingInfo
+			}
+
+			// Record every network interface
+			var networkInterfaces []map[string]interface{}
+			for _, networkInterface := range instance.NetworkInterfaces {
+				entry := make(map[string]interface{})
+				if networkInterface.Network != nil {
+					entry["network"] = c.extractNetworkName(*networkInterface.Network)
+				}
+				if networkInterface.Subnetwork != nil {
+					entry["subnetwork"] = c.extractSubnetworkName(*networkInterface.Subnetwork)
+					entry["subnetwork_region"] = c.extractRegionFromSubnetworkURL(*networkInterface.Subnetwork)
+				}
+				if networkInterface.NetworkIP != nil {
+					entry["network_ip"] = *networkInterface.NetworkIP
+				}
+				entry["has_external_ip"] = len(networkInterface.AccessConfigs) > 0
+				networkInterfaces = append(networkInterfaces, entry)
+			}
+			resource.Metadata["network_interfaces"] = networkInterfaces
+
 			// Extract network information
 			if instance.NetworkInterfaces != nil && len(instance.NetworkInterfaces) > 0 {
 				networkInterface := instance.NetworkInterfaces[0]
@@ -489,6 +666,10 @@ func (c *GCPConnector) discoverInstances(ctx context.Context, regions []string)
 					if disk.Boot != nil && *disk.Boot {
 						if disk.Source != nil {
 							resource.Metadata["boot_disk"] = c.extractDiskName(*disk.Source)
+							resource.Metadata["boot_disk_source"] = *disk.Source
+						}
+						if disk.AutoDelete != nil {
+							resource.Metadata["boot_disk_auto_delete"] = *disk.AutoDelete
 						}
 						break
 					}
@@ -593,6 +774,31 @@ func (c *GCPConnector) extractDiskName(diskURL string) string {
 	return diskURL
 }
 
+// extractRegionFromSubnetworkURL extracts the region name from a GCP subnetwork URL
+func (c *GCPConnector) extractRegionFromSubnetworkURL(subnetworkURL string) string {
+	parts := strings.Split(subnetworkURL, "/")
+	for i, part := range parts {
+		if part == "regions" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// convertFirewallRule converts an allowed or denied firewall entry into metadata
+func (c *GCPConnector) convertFirewallRule(protocol *string, ports []string) map[string]interface{} {
+	rule := map[string]interface{}{
+		"protocol": "all",
+	}
+	if protocol != nil {
+		rule["protocol"] = *protocol
+	}
+	if len(ports) > 0 {
+		rule["ports"] = ports
+	}
+	return rule
+}
+
 // extractRegionFromURL extracts the region name from a GCP region URL
 func (c *GCPConnector) extractRegionFromURL(regionURL string) string {
 	parts := strings.Split(regionURL, "/")
@@ -0,0 +1,362 @@
+package arm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// Template schemas for resource group and subscription deployments
+const (
+	deploymentTemplateSchema   = "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#"
+	subscriptionTemplateSchema = "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#"
+	contentVersion             = "1.0.0.0"
+)
+
+// Template is an ARM deployment template; field order is the order
+// properties are written in
+type Template struct {
+	Schema         string                 `json:"$schema"`
+	ContentVersion string                 `json:"contentVersion"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	Parameters     map[string]Parameter   `json:"parameters,omitempty"`
+	Resources      []Resource             `json:"resources"`
+	Outputs        map[string]Output      `json:"outputs,omitempty"`
+}
+
+// Parameter is a template parameter
+type Parameter struct {
+	Type         string            `json:"type"`
+	DefaultValue interface{}       `json:"defaultValue,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// Resource is a template resource
+type Resource struct {
+	Type          string            `json:"type"`
+	APIVersion    string            `json:"apiVersion"`
+	Name          string            `json:"name"`
+	ResourceGroup string            `json:"resourceGroup,omitempty"`
+	Location      interface{}       `json:"location,omitempty"`
+	Zones         []string          `json:"zones,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+	DependsOn     []string          `json:"dependsOn,omitempty"`
+	Properties    interface{}       `json:"properties,omitempty"`
+}
+
+// Output is a template output
+type Output struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Generator generates ARM templates (azuredeploy.json) from mapped Azure resources
+type Generator struct{}
+
+// NewGenerator creates a new ARM template generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Capabilities returns the features of ARM templates
+func (g *Generator) Capabilities() generation.FormatCapabilities {
+	return generation.FormatCapabilities{
+		SupportedProviders: []discovery.CloudProvider{discovery.Azure},
+		SupportsVariables:  true,
+		SupportsOutputs:    true,
+	}
+}
+
+// Generate generates azuredeploy.json. Resources in a single resource group
+// produce a resource group deployment; resources spanning resource groups
+// produce a subscription deployment with one nested deployment per group.
+func (g *Generator) Generate(resources []generation.MappedResource, opts generation.GenerationOptions) ([]generation.GeneratedFile, error) {
+	deployments, skipped, err := buildDeployments(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	var template *Template
+	if len(deployments) == 1 {
+		template = deploymentTemplate(deployments[0], true)
+	} else {
+		template = subscriptionTemplate(deployments)
+	}
+
+	template.Metadata = map[string]interface{}{
+		"_generator": map[string]string{"name": "chimera"},
+	}
+	if len(skipped) > 0 {
+		template.Metadata["unsupportedResources"] = skipped
+	}
+
+	content, err := marshalTemplate(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ARM template: %w", err)
+	}
+
+	resourceCount := 0
+	for _, d := range deployments {
+		resourceCount += len(d.resources)
+	}
+
+	return []generation.GeneratedFile{
+		{
+			Path:          "azuredeploy.json",
+			Content:       content,
+			Type:          generation.FileTypeMain,
+			Format:        generation.ARM,
+			Size:          int64(len(content)),
+			ResourceCount: resourceCount,
+		},
+	}, nil
+}
+
+// deploymentTemplate renders the template deploying one resource group
+func deploymentTemplate(d *deployment, withOutputs bool) *Template {
+	template := &Template{
+		Schema:         deploymentTemplateSchema,
+		ContentVersion: contentVersion,
+		Parameters:     make(map[string]Parameter),
+		Resources:      make([]Resource, 0, len(d.resources)),
+	}
+
+	for _, param := range d.parameters {
+		template.Parameters[param.name] = armParameter(param)
+	}
+
+	for _, r := range d.resources {
+		renderer := &armRenderer{from: d, current: r, dependsOn: make(map[string]bool)}
+
+		resource := Resource{
+			Type:       r.resourceType,
+			APIVersion: r.apiVersion,
+			Name:       r.name,
+			Zones:      r.zones,
+			Tags:       r.tags,
+			Properties: renderer.value(r.properties),
+		}
+		if r.location != nil {
+			resource.Location = renderer.value(r.location)
+		}
+		resource.DependsOn = renderer.dependencies()
+
+		template.Resources = append(template.Resources, resource)
+	}
+
+	if withOutputs {
+		template.Outputs = resourceOutputs(d.resources, d)
+	}
+
+	return template
+}
+
+// subscriptionTemplate renders a subscription deployment creating the
+// discovered resource groups and deploying each group as a nested template
+func subscriptionTemplate(deployments []*deployment) *Template {
+	template := &Template{
+		Schema:         subscriptionTemplateSchema,
+		ContentVersion: contentVersion,
+		Parameters:     make(map[string]Parameter),
+		Outputs:        make(map[string]Output),
+	}
+
+	for _, d := range deployments {
+		if d.group == nil {
+			continue
+		}
+		template.Resources = append(template.Resources, Resource{
+			Type:       "Microsoft.Resources/resourceGroups",
+			APIVersion: resourcesAPIVersion,
+			Name:       d.resourceGroup,
+			Location:   d.location,
+			Tags:       d.groupTags,
+		})
+	}
+
+	for _, d := range deployments {
+		// Location is a default of the nested template; other parameters
+		// have no default and are passed through
+		parameters := make(map[string]interface{})
+		for _, param := range d.parameters {
+			if param.defaultValue != "" {
+				continue
+			}
+			template.Parameters[param.name] = armParameter(param)
+			parameters[param.name] = map[string]string{"value": armParameterRef(param.name)}
+		}
+
+		var dependsOn []string
+		if d.group != nil {
+			dependsOn = append(dependsOn, fmt.Sprintf("[resourceId('Microsoft.Resources/resourceGroups', %s)]", armQuote(d.resourceGroup)))
+		}
+		for other := range d.dependsOn {
+			dependsOn = append(dependsOn, deploymentName(other))
+		}
+		sort.Strings(dependsOn)
+
+		template.Resources = append(template.Resources, Resource{
+			Type:          "Microsoft.Resources/deployments",
+			APIVersion:    resourcesAPIVersion,
+			Name:          deploymentName(d),
+			ResourceGroup: d.resourceGroup,
+			DependsOn:     dependsOn,
+			Properties: map[string]interface{}{
+				"mode":                        "Incremental",
+				"expressionEvaluationOptions": map[string]string{"scope": "inner"},
+				"parameters":                  parameters,
+				"template":                    deploymentTemplate(d, false),
+			},
+		})
+
+		for name, output := range resourceOutputs(d.resources, nil) {
+			template.Outputs[name] = output
+		}
+	}
+
+	return template
+}
+
+// resourceOutputs returns an ID output for every top-level resource
+func resourceOutputs(resources []*resource, from *deployment) map[string]Output {
+	outputs := make(map[string]Output)
+	for _, r := range resources {
+		renderer := &armRenderer{from: from, dependsOn: make(map[string]bool)}
+		outputs[r.symbol+"Id"] = Output{
+			Type:  "string",
+			Value: renderer.value(resourceID{target: r}),
+		}
+	}
+	return outputs
+}
+
+// deploymentName returns the name of the nested deployment for a resource group
+func deploymentName(d *deployment) string {
+	name := "chimera-" + d.resourceGroup
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// armParameter converts a deployment parameter to a template parameter
+func armParameter(param parameter) Parameter {
+	result := Parameter{
+		Type:     "string",
+		Metadata: map[string]string{"description": param.description},
+	}
+	if param.secure {
+		result.Type = "securestring"
+	}
+	if param.defaultValue != "" {
+		result.DefaultValue = escapeLiteral(param.defaultValue)
+	}
+	return result
+}
+
+// armRenderer converts property values to template JSON, collecting the
+// dependencies implied by references for the resource being rendered
+type armRenderer struct {
+	from      *deployment
+	current   *resource
+	dependsOn map[string]bool
+}
+
+// value converts a property value. References become template expressions
+// and literal strings are escaped so they are not evaluated as expressions.
+func (a *armRenderer) value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return escapeLiteral(v)
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = escapeLiteral(item)
+		}
+		return list
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = a.value(item)
+		}
+		return list
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[key] = a.value(item)
+		}
+		return object
+	case parameterRef:
+		return armParameterRef(v.name)
+	case resourceID:
+		if v.target.deployment == a.from && v.target != a.current && a.current != nil {
+			a.dependsOn[resourceIDExpression(resourceID{target: v.target}, false)] = true
+		}
+		return resourceIDExpression(v, v.target.deployment != a.from)
+	default:
+		return v
+	}
+}
+
+// dependencies returns the collected dependencies in sorted order
+func (a *armRenderer) dependencies() []string {
+	var result []string
+	for dependency := range a.dependsOn {
+		result = append(result, dependency)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// resourceIDExpression builds a resourceId() expression for a reference,
+// qualified by resource group when it targets another deployment
+func resourceIDExpression(ref resourceID, qualified bool) string {
+	var args []string
+	if qualified {
+		args = append(args, armQuote(ref.target.deployment.resourceGroup))
+	}
+	args = append(args, armQuote(ref.resourceType()))
+	for _, segment := range ref.target.nameSegments() {
+		args = append(args, armQuote(segment))
+	}
+	if ref.childName != "" {
+		args = append(args, armQuote(ref.childName))
+	}
+	return fmt.Sprintf("[resourceId(%s)]", strings.Join(args, ", "))
+}
+
+// armParameterRef returns a parameters() expression
+func armParameterRef(name string) string {
+	return fmt.Sprintf("[parameters(%s)]", armQuote(name))
+}
+
+// armQuote quotes a string literal inside a template expression
+func armQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// escapeLiteral escapes a literal string that would otherwise be evaluated
+// as a template expression
+func escapeLiteral(s string) string {
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		return "[" + s
+	}
+	return s
+}
+
+// marshalTemplate marshals a template with two-space indentation
+func marshalTemplate(template *Template) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(template); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,378 @@
+package arm
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// bicepIdentifier matches object keys that can be written unquoted
+var bicepIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// bicepEscaper escapes a string for a single-quoted Bicep string literal
+var bicepEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "${", `\${`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// BicepGenerator generates Bicep modules from mapped Azure resources
+type BicepGenerator struct{}
+
+// NewBicepGenerator creates a new Bicep generator
+func NewBicepGenerator() *BicepGenerator {
+	return &BicepGenerator{}
+}
+
+// Capabilities returns the features of Bicep modules
+func (g *BicepGenerator) Capabilities() generation.FormatCapabilities {
+	return generation.FormatCapabilities{
+		SupportedProviders: []discovery.CloudProvider{discovery.Azure},
+		SupportsModules:    true,
+		SupportsVariables:  true,
+		SupportsOutputs:    true,
+	}
+}
+
+// Generate generates main.bicep. Resources in a single resource group are
+// declared in main.bicep directly; resources spanning resource groups are
+// declared in one module per group under modules/, deployed by a
+// subscription-scoped main.bicep.
+func (g *BicepGenerator) Generate(resources []generation.MappedResource, opts generation.GenerationOptions) ([]generation.GeneratedFile, error) {
+	deployments, _, err := buildDeployments(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deployments) == 1 {
+		d := deployments[0]
+		return []generation.GeneratedFile{bicepFile("main.bicep", renderModule(d), len(d.resources))}, nil
+	}
+
+	modules := make(map[*deployment]string)
+	used := make(map[string]bool)
+	for _, d := range deployments {
+		path := fmt.Sprintf("modules/%s.bicep", moduleFileName(d.resourceGroup))
+		for i := 2; used[path]; i++ {
+			path = fmt.Sprintf("modules/%s_%d.bicep", moduleFileName(d.resourceGroup), i)
+		}
+		used[path] = true
+		modules[d] = path
+	}
+
+	files := []generation.GeneratedFile{bicepFile("main.bicep", renderSubscriptionMain(deployments, modules), 0)}
+	for _, d := range deployments {
+		files = append(files, bicepFile(modules[d], renderModule(d), len(d.resources)))
+	}
+
+	return files, nil
+}
+
+// bicepFile builds a generated file for Bicep content
+func bicepFile(path, content string, resourceCount int) generation.GeneratedFile {
+	return generation.GeneratedFile{
+		Path:          path,
+		Content:       content,
+		Type:          generation.FileTypeMain,
+		Format:        generation.Bicep,
+		Size:          int64(len(content)),
+		ResourceCount: resourceCount,
+	}
+}
+
+// renderModule renders the resources of one resource group deployment
+func renderModule(d *deployment) string {
+	w := &bicepWriter{from: d}
+	w.line("// Generated by Chimera")
+
+	for _, param := range d.parameters {
+		w.line("")
+		w.parameter(param)
+	}
+
+	for _, r := range d.resources {
+		w.line("")
+		w.line(fmt.Sprintf("resource %s '%s@%s' = {", r.symbol, r.resourceType, r.apiVersion))
+		w.indent++
+		w.property("name", r.name)
+		if r.location != nil {
+			w.property("location", r.location)
+		}
+		if len(r.zones) > 0 {
+			w.property("zones", r.zones)
+		}
+		if len(r.tags) > 0 {
+			w.property("tags", r.tags)
+		}
+		if len(r.properties) > 0 {
+			w.property("properties", r.properties)
+		}
+		w.indent--
+		w.line("}")
+	}
+
+	if len(d.resources) > 0 {
+		w.line("")
+	}
+	for _, r := range d.resources {
+		w.line(fmt.Sprintf("output %sId string = %s.id", r.symbol, r.symbol))
+	}
+
+	return w.String()
+}
+
+// renderSubscriptionMain renders a subscription-scoped main.bicep creating
+// the discovered resource groups and deploying a module into each group
+func renderSubscriptionMain(deployments []*deployment, modules map[*deployment]string) string {
+	w := &bicepWriter{}
+	w.line("// Generated by Chimera")
+	w.line("")
+	w.line("targetScope = 'subscription'")
+
+	for _, d := range deployments {
+		for _, param := range d.parameters {
+			if param.defaultValue != "" {
+				continue
+			}
+			w.line("")
+			w.parameter(param)
+		}
+	}
+
+	groupSymbols := make(map[*deployment]string)
+	moduleSymbols := make(map[*deployment]string)
+	used := make(map[string]bool)
+	unique := func(symbol string) string {
+		candidate := symbol
+		for i := 2; used[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", symbol, i)
+		}
+		used[candidate] = true
+		return candidate
+	}
+	for _, d := range deployments {
+		if d.group != nil {
+			groupSymbols[d] = unique(symbolName(d.resourceGroup, "Rg"))
+		}
+		moduleSymbols[d] = unique(symbolName(d.resourceGroup, "Module"))
+	}
+
+	for _, d := range deployments {
+		symbol, exists := groupSymbols[d]
+		if !exists {
+			continue
+		}
+		w.line("")
+		w.line(fmt.Sprintf("resource %s 'Microsoft.Resources/resourceGroups@%s' = {", symbol, resourcesAPIVersion))
+		w.indent++
+		w.property("name", d.resourceGroup)
+		w.property("location", d.location)
+		if len(d.groupTags) > 0 {
+			w.property("tags", d.groupTags)
+		}
+		w.indent--
+		w.line("}")
+	}
+
+	for _, d := range deployments {
+		w.line("")
+		w.line(fmt.Sprintf("module %s '%s' = {", moduleSymbols[d], bicepEscaper.Replace(modules[d])))
+		w.indent++
+		w.property("name", deploymentName(d))
+		if symbol, exists := groupSymbols[d]; exists {
+			w.line("scope: " + symbol)
+		} else {
+			w.line(fmt.Sprintf("scope: resourceGroup(%s)", bicepString(d.resourceGroup)))
+		}
+
+		var params []string
+		for _, param := range d.parameters {
+			if param.defaultValue == "" {
+				params = append(params, param.name)
+			}
+		}
+		if len(params) > 0 {
+			w.line("params: {")
+			w.indent++
+			for _, name := range params {
+				w.line(fmt.Sprintf("%s: %s", name, name))
+			}
+			w.indent--
+			w.line("}")
+		}
+
+		var dependsOn []string
+		for other := range d.dependsOn {
+			dependsOn = append(dependsOn, moduleSymbols[other])
+		}
+		if len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			w.line("dependsOn: [")
+			w.indent++
+			for _, symbol := range dependsOn {
+				w.line(symbol)
+			}
+			w.indent--
+			w.line("]")
+		}
+		w.indent--
+		w.line("}")
+	}
+
+	w.line("")
+	for _, d := range deployments {
+		for _, r := range d.resources {
+			w.line(fmt.Sprintf("output %sId string = %s.outputs.%sId", r.symbol, moduleSymbols[d], r.symbol))
+		}
+	}
+
+	return w.String()
+}
+
+// moduleFileName returns a file name for a resource group module
+func moduleFileName(resourceGroup string) string {
+	var result strings.Builder
+	for _, r := range strings.ToLower(resourceGroup) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_':
+			result.WriteRune(r)
+		default:
+			result.WriteRune('_')
+		}
+	}
+	if result.Len() == 0 {
+		return "resource_group"
+	}
+	return result.String()
+}
+
+// bicepWriter writes indented Bicep source for a deployment
+type bicepWriter struct {
+	buf    strings.Builder
+	indent int
+	from   *deployment
+}
+
+// line writes a single indented line
+func (w *bicepWriter) line(s string) {
+	if s != "" {
+		w.buf.WriteString(strings.Repeat("  ", w.indent))
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\n")
+}
+
+// parameter writes a parameter declaration
+func (w *bicepWriter) parameter(param parameter) {
+	if param.secure {
+		w.line("@secure()")
+	}
+	w.line(fmt.Sprintf("@description(%s)", bicepString(param.description)))
+	if param.defaultValue != "" {
+		w.line(fmt.Sprintf("param %s string = %s", param.name, bicepString(param.defaultValue)))
+	} else {
+		w.line(fmt.Sprintf("param %s string", param.name))
+	}
+}
+
+// property writes a key: value property
+func (w *bicepWriter) property(key string, value interface{}) {
+	if !bicepIdentifier.MatchString(key) {
+		key = bicepString(key)
+	}
+	w.buf.WriteString(strings.Repeat("  ", w.indent))
+	w.buf.WriteString(key)
+	w.buf.WriteString(": ")
+	w.value(value)
+	w.buf.WriteString("\n")
+}
+
+// value writes a value, continuing the current line
+func (w *bicepWriter) value(value interface{}) {
+	switch v := value.(type) {
+	case string:
+		w.buf.WriteString(bicepString(v))
+	case bool, int:
+		w.buf.WriteString(fmt.Sprint(v))
+	case parameterRef:
+		w.buf.WriteString(v.name)
+	case resourceID:
+		w.buf.WriteString(w.reference(v))
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		w.value(items)
+	case []interface{}:
+		if len(v) == 0 {
+			w.buf.WriteString("[]")
+			return
+		}
+		w.buf.WriteString("[\n")
+		w.indent++
+		for _, item := range v {
+			w.buf.WriteString(strings.Repeat("  ", w.indent))
+			w.value(item)
+			w.buf.WriteString("\n")
+		}
+		w.indent--
+		w.buf.WriteString(strings.Repeat("  ", w.indent) + "]")
+	case map[string]string:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[key] = item
+		}
+		w.value(object)
+	case map[string]interface{}:
+		if len(v) == 0 {
+			w.buf.WriteString("{}")
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		w.buf.WriteString("{\n")
+		w.indent++
+		for _, key := range keys {
+			w.property(key, v[key])
+		}
+		w.indent--
+		w.buf.WriteString(strings.Repeat("  ", w.indent) + "}")
+	default:
+		w.buf.WriteString(bicepString(fmt.Sprint(v)))
+	}
+}
+
+// reference returns the Bicep expression for a resource ID. Resources in the
+// same module are referenced by symbol, which also orders the deployment.
+func (w *bicepWriter) reference(ref resourceID) string {
+	if ref.target.deployment == w.from {
+		if ref.childName == "" {
+			return ref.target.symbol + ".id"
+		}
+		return fmt.Sprintf("resourceId(%s, %s.name, %s)", bicepString(ref.resourceType()), ref.target.symbol, bicepString(ref.childName))
+	}
+
+	args := []string{bicepString(ref.target.deployment.resourceGroup), bicepString(ref.resourceType())}
+	for _, segment := range ref.target.nameSegments() {
+		args = append(args, bicepString(segment))
+	}
+	if ref.childName != "" {
+		args = append(args, bicepString(ref.childName))
+	}
+	return fmt.Sprintf("resourceId(%s)", strings.Join(args, ", "))
+}
+
+// String returns the written source
+func (w *bicepWriter) String() string {
+	return w.buf.String()
+}
+
+// bicepString returns a single-quoted Bicep string literal
+func bicepString(s string) string {
+	return "'" + bicepEscaper.Replace(s) + "'"
+}
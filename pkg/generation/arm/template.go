@@ -0,0 +1,654 @@
+package arm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// API versions used for generated resources
+const (
+	networkAPIVersion   = "2023-04-01"
+	computeAPIVersion   = "2023-03-01"
+	resourcesAPIVersion = "2022-09-01"
+)
+
+// deployment holds the resources generated into a single resource group
+type deployment struct {
+	resourceGroup string
+	location      string
+
+	// group is the discovered resource group, if it was discovered
+	group     *discovery.Resource
+	groupTags map[string]string
+
+	parameters []parameter
+	resources  []*resource
+
+	// dependsOn records other deployments whose resources are referenced
+	dependsOn map[*deployment]bool
+}
+
+// parameter is a deployment input
+type parameter struct {
+	name         string
+	description  string
+	secure       bool
+	defaultValue string
+}
+
+// resource is an Azure resource in a deployment
+type resource struct {
+	deployment   *deployment
+	symbol       string
+	resourceType string
+	apiVersion   string
+
+	// name is the resource name; child resources whose parent is not
+	// generated carry the full name (vnet/subnet)
+	name string
+
+	location   interface{}
+	tags       map[string]string
+	zones      []string
+	properties map[string]interface{}
+
+	// subnets are the discovered subnets declared inline in a virtual network
+	subnets []discovery.Resource
+
+	original discovery.Resource
+}
+
+// isChild reports whether the resource is a child resource such as a subnet
+func (r *resource) isChild() bool {
+	return strings.Count(r.resourceType, "/") > 1
+}
+
+// nameSegments returns the name segments passed to resourceId()
+func (r *resource) nameSegments() []string {
+	return strings.Split(r.name, "/")
+}
+
+// resourceID references the ID of a generated resource, or of a child of it
+// declared inline such as a subnet of a virtual network
+type resourceID struct {
+	target    *resource
+	childType string
+	childName string
+}
+
+// resourceType returns the full type of the referenced resource
+func (r resourceID) resourceType() string {
+	if r.childType != "" {
+		return r.target.resourceType + "/" + r.childType
+	}
+	return r.target.resourceType
+}
+
+// parameterRef references a deployment parameter
+type parameterRef struct {
+	name string
+}
+
+// resourceOrder orders discovered types so that referenced resources are
+// declared before the resources referencing them
+var resourceOrder = map[string]int{
+	"azure_resource_group":         0,
+	"azure_virtual_network":        1,
+	"azure_network_security_group": 2,
+	"azure_subnet":                 3,
+	"azure_network_interface":      4,
+	"azure_virtual_machine":        5,
+}
+
+// symbolSuffixes are appended to resource names to form symbolic names
+var symbolSuffixes = map[string]string{
+	"azure_resource_group":         "Rg",
+	"azure_virtual_network":        "Vnet",
+	"azure_network_security_group": "Nsg",
+	"azure_subnet":                 "Subnet",
+	"azure_network_interface":      "Nic",
+	"azure_virtual_machine":        "Vm",
+}
+
+// templateBuilder converts discovered Azure resources into deployments, one
+// per resource group
+type templateBuilder struct {
+	deployments []*deployment
+	byGroup     map[string]*deployment
+	ids         map[string]resourceID
+	symbols     map[string]bool
+	skipped     []string
+}
+
+// buildDeployments builds the deployments for the Azure resources in a mapped set
+func buildDeployments(mapped []generation.MappedResource) ([]*deployment, []string, error) {
+	b := &templateBuilder{
+		byGroup: make(map[string]*deployment),
+		ids:     make(map[string]resourceID),
+		symbols: make(map[string]bool),
+	}
+
+	var resources []generation.MappedResource
+	for _, resource := range mapped {
+		if resource.OriginalResource.Provider != discovery.Azure {
+			continue
+		}
+		// ARM associates a network security group through a subnet property
+		if resource.OriginalResource.Type == "azure_subnet_network_security_group_association" {
+			continue
+		}
+		if _, supported := resourceOrder[resource.OriginalResource.Type]; !supported {
+			b.skipped = append(b.skipped, resource.OriginalResource.ID)
+			continue
+		}
+		resources = append(resources, resource)
+	}
+	if len(resources) == 0 {
+		return nil, nil, fmt.Errorf("ARM generation requires Azure resources")
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		a, b := resources[i].OriginalResource, resources[j].OriginalResource
+		if resourceOrder[a.Type] != resourceOrder[b.Type] {
+			return resourceOrder[a.Type] < resourceOrder[b.Type]
+		}
+		if !strings.EqualFold(a.ResourceGroup, b.ResourceGroup) {
+			return strings.ToLower(a.ResourceGroup) < strings.ToLower(b.ResourceGroup)
+		}
+		return a.Name < b.Name
+	})
+
+	// Declare every resource first so references resolve regardless of order
+	var declared []*resource
+	for i := range resources {
+		if r := b.declare(resources[i]); r != nil {
+			declared = append(declared, r)
+		}
+	}
+
+	for _, r := range declared {
+		if err := b.buildProperties(r); err != nil {
+			return nil, nil, fmt.Errorf("failed to convert %s: %w", r.original.ID, err)
+		}
+	}
+
+	for _, d := range b.deployments {
+		if d.location == "" && len(d.resources) > 0 {
+			d.location = d.resources[0].original.Region
+		}
+		for _, r := range d.resources {
+			if r.isChild() {
+				continue
+			}
+			if r.original.Region == "" || strings.EqualFold(r.original.Region, d.location) {
+				r.location = parameterRef{name: "location"}
+			} else {
+				r.location = r.original.Region
+			}
+		}
+		d.parameters = append([]parameter{{
+			name:         "location",
+			description:  "Location of the resources",
+			defaultValue: d.location,
+		}}, d.parameters...)
+	}
+
+	sort.Slice(b.deployments, func(i, j int) bool {
+		return strings.ToLower(b.deployments[i].resourceGroup) < strings.ToLower(b.deployments[j].resourceGroup)
+	})
+
+	return b.deployments, b.skipped, nil
+}
+
+// deploymentFor returns the deployment for a resource group, creating it if needed
+func (b *templateBuilder) deploymentFor(resourceGroup string) *deployment {
+	key := strings.ToLower(resourceGroup)
+	if d, exists := b.byGroup[key]; exists {
+		return d
+	}
+	d := &deployment{
+		resourceGroup: resourceGroup,
+		dependsOn:     make(map[*deployment]bool),
+	}
+	b.byGroup[key] = d
+	b.deployments = append(b.deployments, d)
+	return d
+}
+
+// declare registers a resource and its ID. Resource groups become the target
+// of their deployment and subnets of generated virtual networks are declared
+// inline, so neither is returned as a resource.
+func (b *templateBuilder) declare(mapped generation.MappedResource) *resource {
+	original := mapped.OriginalResource
+
+	if original.Type == "azure_resource_group" {
+		d := b.deploymentFor(original.Name)
+		d.group = &original
+		d.location = original.Region
+		if tags, ok := mapped.Configuration["tags"].(map[string]string); ok && len(tags) > 0 {
+			d.groupTags = tags
+		}
+		return nil
+	}
+
+	if original.Type == "azure_subnet" {
+		vnetId := getStringFromMetadata(original.Metadata, "virtual_network_id", "")
+		if vnet, exists := b.ids[strings.ToLower(vnetId)]; exists && vnet.childType == "" {
+			b.ids[strings.ToLower(original.ID)] = resourceID{target: vnet.target, childType: "subnets", childName: original.Name}
+			vnet.target.subnets = append(vnet.target.subnets, original)
+			return nil
+		}
+	}
+
+	d := b.deploymentFor(original.ResourceGroup)
+	r := &resource{
+		deployment: d,
+		symbol:     b.uniqueSymbol(symbolName(mapped.ResourceName, symbolSuffixes[original.Type])),
+		name:       original.Name,
+		properties: make(map[string]interface{}),
+		original:   original,
+	}
+	if tags, ok := mapped.Configuration["tags"].(map[string]string); ok && len(tags) > 0 {
+		r.tags = tags
+	}
+
+	switch original.Type {
+	case "azure_virtual_network":
+		r.resourceType, r.apiVersion = "Microsoft.Network/virtualNetworks", networkAPIVersion
+	case "azure_network_security_group":
+		r.resourceType, r.apiVersion = "Microsoft.Network/networkSecurityGroups", networkAPIVersion
+	case "azure_subnet":
+		r.resourceType, r.apiVersion = "Microsoft.Network/virtualNetworks/subnets", networkAPIVersion
+		r.name = getStringFromMetadata(original.Metadata, "virtual_network", "") + "/" + original.Name
+	case "azure_network_interface":
+		r.resourceType, r.apiVersion = "Microsoft.Network/networkInterfaces", networkAPIVersion
+	case "azure_virtual_machine":
+		r.resourceType, r.apiVersion = "Microsoft.Compute/virtualMachines", computeAPIVersion
+		if original.Zone != "" {
+			r.zones = []string{original.Zone}
+		}
+	}
+
+	d.resources = append(d.resources, r)
+	b.ids[strings.ToLower(original.ID)] = resourceID{target: r}
+	return r
+}
+
+// reference resolves a discovered resource ID to a reference to the generated
+// resource, or to the literal ID when the resource is not generated
+func (b *templateBuilder) reference(from *deployment, id string) interface{} {
+	ref, exists := b.ids[strings.ToLower(id)]
+	if !exists {
+		return id
+	}
+	if ref.target.deployment != from {
+		from.dependsOn[ref.target.deployment] = true
+	}
+	return ref
+}
+
+// idObject returns an {id: ...} sub-resource object
+func (b *templateBuilder) idObject(from *deployment, id string) map[string]interface{} {
+	return map[string]interface{}{"id": b.reference(from, id)}
+}
+
+// buildProperties converts the discovered metadata of a resource to properties
+func (b *templateBuilder) buildProperties(r *resource) error {
+	metadata := r.original.Metadata
+
+	switch r.original.Type {
+	case "azure_virtual_network":
+		addressPrefixes := getStringSliceFromMetadata(metadata, "address_prefixes")
+		if len(addressPrefixes) == 0 {
+			return fmt.Errorf("virtual network has no address space")
+		}
+		r.properties["addressSpace"] = map[string]interface{}{"addressPrefixes": addressPrefixes}
+		if dnsServers := getStringSliceFromMetadata(metadata, "dns_servers"); len(dnsServers) > 0 {
+			r.properties["dhcpOptions"] = map[string]interface{}{"dnsServers": dnsServers}
+		}
+
+		// Subnets are declared inline; declaring them as child resources
+		// would remove them from the network on every redeployment
+		if len(r.subnets) > 0 {
+			var inline []interface{}
+			for _, subnet := range r.subnets {
+				inline = append(inline, map[string]interface{}{
+					"name":       subnet.Name,
+					"properties": b.subnetProperties(r.deployment, subnet),
+				})
+			}
+			r.properties["subnets"] = inline
+		}
+
+	case "azure_subnet":
+		r.properties = b.subnetProperties(r.deployment, r.original)
+
+	case "azure_network_security_group":
+		var rules []interface{}
+		for _, rule := range getMapSliceFromMetadata(metadata, "security_rules") {
+			properties := map[string]interface{}{
+				"priority":  getIntFromMetadata(rule, "priority", 0),
+				"direction": getStringFromMetadata(rule, "direction", ""),
+				"access":    getStringFromMetadata(rule, "access", ""),
+				"protocol":  getStringFromMetadata(rule, "protocol", ""),
+			}
+			for key, property := range securityRuleStrings {
+				if value := getStringFromMetadata(rule, key, ""); value != "" {
+					properties[property] = value
+				}
+			}
+			for key, property := range securityRuleLists {
+				if values := getStringSliceFromMetadata(rule, key); len(values) > 0 {
+					properties[property] = values
+				}
+			}
+			rules = append(rules, map[string]interface{}{
+				"name":       getStringFromMetadata(rule, "name", ""),
+				"properties": properties,
+			})
+		}
+		if len(rules) > 0 {
+			r.properties["securityRules"] = rules
+		}
+
+	case "azure_network_interface":
+		if _, exists := metadata["enable_accelerated_networking"]; exists {
+			r.properties["enableAcceleratedNetworking"] = getBoolFromMetadata(metadata, "enable_accelerated_networking", false)
+		}
+		if _, exists := metadata["enable_ip_forwarding"]; exists {
+			r.properties["enableIPForwarding"] = getBoolFromMetadata(metadata, "enable_ip_forwarding", false)
+		}
+		if nsgId := getStringFromMetadata(metadata, "network_security_group_id", ""); nsgId != "" {
+			r.properties["networkSecurityGroup"] = b.idObject(r.deployment, nsgId)
+		}
+
+		var ipConfigurations []interface{}
+		for _, ipConfig := range getMapSliceFromMetadata(metadata, "ip_configurations") {
+			allocation := getStringFromMetadata(ipConfig, "private_ip_address_allocation", "Dynamic")
+			properties := map[string]interface{}{
+				"privateIPAllocationMethod": allocation,
+			}
+			if subnetId := getStringFromMetadata(ipConfig, "subnet_id", ""); subnetId != "" {
+				properties["subnet"] = b.idObject(r.deployment, subnetId)
+			}
+			if strings.EqualFold(allocation, "Static") {
+				properties["privateIPAddress"] = getStringFromMetadata(ipConfig, "private_ip_address", "")
+			}
+			if publicIpId := getStringFromMetadata(ipConfig, "public_ip_address_id", ""); publicIpId != "" {
+				properties["publicIPAddress"] = b.idObject(r.deployment, publicIpId)
+			}
+			if _, exists := ipConfig["primary"]; exists {
+				properties["primary"] = getBoolFromMetadata(ipConfig, "primary", false)
+			}
+			ipConfigurations = append(ipConfigurations, map[string]interface{}{
+				"name":       getStringFromMetadata(ipConfig, "name", "internal"),
+				"properties": properties,
+			})
+		}
+		if len(ipConfigurations) == 0 {
+			return fmt.Errorf("network interface has no IP configurations")
+		}
+		r.properties["ipConfigurations"] = ipConfigurations
+
+	case "azure_virtual_machine":
+		return b.virtualMachineProperties(r)
+	}
+
+	return nil
+}
+
+// securityRuleStrings maps discovered security rule fields to ARM properties
+var securityRuleStrings = map[string]string{
+	"description":                "description",
+	"source_port_range":          "sourcePortRange",
+	"destination_port_range":     "destinationPortRange",
+	"source_address_prefix":      "sourceAddressPrefix",
+	"destination_address_prefix": "destinationAddressPrefix",
+}
+
+// securityRuleLists maps discovered security rule list fields to ARM properties
+var securityRuleLists = map[string]string{
+	"source_port_ranges":           "sourcePortRanges",
+	"destination_port_ranges":      "destinationPortRanges",
+	"source_address_prefixes":      "sourceAddressPrefixes",
+	"destination_address_prefixes": "destinationAddressPrefixes",
+}
+
+// subnetProperties converts a discovered subnet to subnet properties
+func (b *templateBuilder) subnetProperties(from *deployment, subnet discovery.Resource) map[string]interface{} {
+	properties := make(map[string]interface{})
+
+	if prefixes := getStringSliceFromMetadata(subnet.Metadata, "address_prefixes"); len(prefixes) > 1 {
+		properties["addressPrefixes"] = prefixes
+	} else if len(prefixes) == 1 {
+		properties["addressPrefix"] = prefixes[0]
+	} else if prefix := getStringFromMetadata(subnet.Metadata, "address_prefix", ""); prefix != "" {
+		properties["addressPrefix"] = prefix
+	}
+
+	if nsgId := getStringFromMetadata(subnet.Metadata, "network_security_group_id", ""); nsgId != "" {
+		properties["networkSecurityGroup"] = b.idObject(from, nsgId)
+	}
+
+	return properties
+}
+
+// virtualMachineProperties converts a discovered virtual machine. Credentials
+// are never discovered, so they are supplied through parameters.
+func (b *templateBuilder) virtualMachineProperties(r *resource) error {
+	metadata := r.original.Metadata
+	adminUsername := getStringFromMetadata(metadata, "admin_username", "azureuser")
+	windows := strings.EqualFold(getStringFromMetadata(metadata, "os_type", ""), "Windows")
+
+	r.properties["hardwareProfile"] = map[string]interface{}{
+		"vmSize": getStringFromMetadata(metadata, "vm_size", "Standard_B2s"),
+	}
+
+	osProfile := map[string]interface{}{
+		"computerName":  getStringFromMetadata(metadata, "computer_name", r.original.Name),
+		"adminUsername": adminUsername,
+	}
+	if windows || !getBoolFromMetadata(metadata, "disable_password_authentication", true) {
+		name := r.symbol + "AdminPassword"
+		r.deployment.parameters = append(r.deployment.parameters, parameter{
+			name:        name,
+			description: fmt.Sprintf("Administrator password for virtual machine %s", r.original.Name),
+			secure:      true,
+		})
+		osProfile["adminPassword"] = parameterRef{name: name}
+		if !windows {
+			osProfile["linuxConfiguration"] = map[string]interface{}{"disablePasswordAuthentication": false}
+		}
+	} else {
+		name := r.symbol + "SshPublicKey"
+		r.deployment.parameters = append(r.deployment.parameters, parameter{
+			name:        name,
+			description: fmt.Sprintf("SSH public key for the administrator of virtual machine %s", r.original.Name),
+		})
+		osProfile["linuxConfiguration"] = map[string]interface{}{
+			"disablePasswordAuthentication": true,
+			"ssh": map[string]interface{}{
+				"publicKeys": []interface{}{
+					map[string]interface{}{
+						"path":    fmt.Sprintf("/home/%s/.ssh/authorized_keys", adminUsername),
+						"keyData": parameterRef{name: name},
+					},
+				},
+			},
+		}
+	}
+	r.properties["osProfile"] = osProfile
+
+	osDisk := getMapFromMetadata(metadata, "os_disk")
+	disk := map[string]interface{}{
+		"createOption": "FromImage",
+		"caching":      getStringFromMetadata(osDisk, "caching", "ReadWrite"),
+		"managedDisk": map[string]interface{}{
+			"storageAccountType": getStringFromMetadata(osDisk, "storage_account_type", "Standard_LRS"),
+		},
+	}
+	if name := getStringFromMetadata(osDisk, "name", ""); name != "" {
+		disk["name"] = name
+	}
+	if size := getIntFromMetadata(osDisk, "disk_size_gb", 0); size > 0 {
+		disk["diskSizeGB"] = size
+	}
+
+	imageReference := map[string]interface{}{}
+	if imageId := getStringFromMetadata(metadata, "image_id", ""); imageId != "" {
+		imageReference["id"] = imageId
+	} else {
+		imageReference["publisher"] = getStringFromMetadata(metadata, "image_publisher", "")
+		imageReference["offer"] = getStringFromMetadata(metadata, "image_offer", "")
+		imageReference["sku"] = getStringFromMetadata(metadata, "image_sku", "")
+		imageReference["version"] = getStringFromMetadata(metadata, "image_version", "latest")
+	}
+	r.properties["storageProfile"] = map[string]interface{}{
+		"imageReference": imageReference,
+		"osDisk":         disk,
+	}
+
+	nicIds := getStringSliceFromMetadata(metadata, "network_interface_ids")
+	if len(nicIds) == 0 {
+		return fmt.Errorf("virtual machine has no network interfaces")
+	}
+	var nics []interface{}
+	for i, nicId := range nicIds {
+		nic := b.idObject(r.deployment, nicId)
+		if len(nicIds) > 1 {
+			nic["properties"] = map[string]interface{}{"primary": i == 0}
+		}
+		nics = append(nics, nic)
+	}
+	r.properties["networkProfile"] = map[string]interface{}{"networkInterfaces": nics}
+
+	return nil
+}
+
+// uniqueSymbol returns symbol, or symbol with a numeric suffix if it is already taken
+func (b *templateBuilder) uniqueSymbol(symbol string) string {
+	candidate := symbol
+	for i := 2; b.symbols[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", symbol, i)
+	}
+	b.symbols[candidate] = true
+	return candidate
+}
+
+// symbolName builds a camelCase symbolic name from a resource name and a type
+// suffix, which is left out when the name already starts or ends with it
+// (nic_web -> nicWeb, web_01 -> web01Vm)
+func symbolName(name, suffix string) string {
+	var result strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			if result.Len() == 0 && r >= '0' && r <= '9' {
+				result.WriteString("resource")
+				upper = true
+			}
+			if upper && r >= 'a' && r <= 'z' {
+				r -= 'a' - 'A'
+			} else if result.Len() == 0 && r >= 'A' && r <= 'Z' {
+				r += 'a' - 'A'
+			}
+			result.WriteRune(r)
+			upper = false
+		default:
+			upper = result.Len() > 0
+		}
+	}
+
+	base := result.String()
+	if base == "" {
+		return strings.ToLower(suffix[:1]) + suffix[1:]
+	}
+	if len(base) > len(suffix) && (strings.EqualFold(base[:len(suffix)], suffix) || strings.EqualFold(base[len(base)-len(suffix):], suffix)) {
+		return base
+	}
+	return base + suffix
+}
+
+// Metadata helper functions
+func getStringFromMetadata(metadata map[string]interface{}, key, defaultValue string) string {
+	if value, exists := metadata[key]; exists {
+		if str, ok := value.(string); ok {
+			return str
+		}
+	}
+	return defaultValue
+}
+
+func getBoolFromMetadata(metadata map[string]interface{}, key string, defaultValue bool) bool {
+	if value, exists := metadata[key]; exists {
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getIntFromMetadata(metadata map[string]interface{}, key string, defaultValue int) int {
+	if value, exists := metadata[key]; exists {
+		switch v := value.(type) {
+		case int:
+			return v
+		case int32:
+			return int(v)
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+	}
+	return defaultValue
+}
+
+// getStringSliceFromMetadata reads a list of strings, accepting both the
+// in-memory form and the []interface{} form produced by a JSON round trip
+func getStringSliceFromMetadata(metadata map[string]interface{}, key string) []string {
+	switch value := metadata[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// getMapSliceFromMetadata reads a list of objects, accepting both the
+// in-memory form and the []interface{} form produced by a JSON round trip
+func getMapSliceFromMetadata(metadata map[string]interface{}, key string) []map[string]interface{} {
+	switch value := metadata[key].(type) {
+	case []map[string]interface{}:
+		return value
+	case []interface{}:
+		result := make([]map[string]interface{}, 0, len(value))
+		for _, item := range value {
+			if entry, ok := item.(map[string]interface{}); ok {
+				result = append(result, entry)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// getMapFromMetadata reads a nested object from metadata
+func getMapFromMetadata(metadata map[string]interface{}, key string) map[string]interface{} {
+	if value, ok := metadata[key].(map[string]interface{}); ok {
+		return value
+	}
+	return nil
+}
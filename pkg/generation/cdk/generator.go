@@ -0,0 +1,282 @@
+package cdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+	"github.com/BigChiefRick/chimera/pkg/generation/cloudformation"
+)
+
+// Language is the language a CDK app is written in
+type Language string
+
+const (
+	TypeScript Language = "typescript"
+	Python     Language = "python"
+)
+
+// cdkVersion is the aws-cdk-lib version generated apps depend on
+const cdkVersion = "2.140.0"
+
+// Generator generates an AWS CDK app from mapped AWS resources. Resources are
+// declared with L1 (Cfn*) constructs, whose properties and logical IDs match
+// the CloudFormation template generator, so cdk import can adopt them.
+type Generator struct {
+	language Language
+}
+
+// NewGenerator creates a new CDK generator for the given language
+func NewGenerator(language Language) *Generator {
+	return &Generator{language: language}
+}
+
+// stackKey identifies the account and region a stack deploys to
+type stackKey struct {
+	account string
+	region  string
+}
+
+// Capabilities returns the features of CDK apps
+func (g *Generator) Capabilities() generation.FormatCapabilities {
+	return generation.FormatCapabilities{
+		SupportedProviders: []discovery.CloudProvider{discovery.AWS},
+		SupportsVariables:  true,
+		SupportsOutputs:    true,
+		SupportsImports:    true,
+	}
+}
+
+// Generate generates the app with one stack per account and region, and a
+// resource mapping per stack for cdk import --resource-mapping
+func (g *Generator) Generate(resources []generation.MappedResource, opts generation.GenerationOptions) ([]generation.GeneratedFile, error) {
+	groups := groupResources(resources)
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("CDK generation requires AWS resources")
+	}
+
+	keys := make([]stackKey, 0, len(groups))
+	accounts := make(map[string]bool)
+	for key := range groups {
+		keys = append(keys, key)
+		accounts[key.account] = true
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].account != keys[j].account {
+			return keys[i].account < keys[j].account
+		}
+		return keys[i].region < keys[j].region
+	})
+
+	var stacks []*stack
+	for _, key := range keys {
+		template, imports, err := cloudformation.BuildTemplate(groups[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to build stack for %s: %w", describeKey(key), err)
+		}
+
+		s, err := buildStack(stackName(key, len(accounts) > 1), template, imports)
+		if err != nil {
+			return nil, err
+		}
+		s.account = key.account
+		s.region = key.region
+		stacks = append(stacks, s)
+	}
+
+	var files []generation.GeneratedFile
+	switch g.language {
+	case TypeScript:
+		files = append(files,
+			g.file("cdk.json", cdkJSON("npx ts-node --prefer-ts-exts bin/app.ts"), generation.FileTypeProject, 0),
+			g.file("package.json", packageJSON(opts.OutputPath), generation.FileTypeVersions, 0),
+			g.file("tsconfig.json", tsconfigJSON, generation.FileTypeProject, 0),
+			g.file("bin/app.ts", renderTypeScriptApp(stacks), generation.FileTypeMain, 0),
+		)
+		for _, s := range stacks {
+			files = append(files, g.file("lib/"+stackFileName(s, "-")+".ts", renderTypeScriptStack(s), generation.FileTypeModule, len(s.constructs)))
+		}
+	case Python:
+		files = append(files,
+			g.file("cdk.json", cdkJSON("python3 app.py"), generation.FileTypeProject, 0),
+			g.file("requirements.txt", requirementsTxt, generation.FileTypeVersions, 0),
+			g.file("app.py", renderPythonApp(stacks), generation.FileTypeMain, 0),
+			g.file("stacks/__init__.py", "", generation.FileTypeModule, 0),
+		)
+		for _, s := range stacks {
+			files = append(files, g.file("stacks/"+stackFileName(s, "_")+".py", renderPythonStack(s), generation.FileTypeModule, len(s.constructs)))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported CDK language: %s", g.language)
+	}
+
+	for _, s := range stacks {
+		content, err := resourceMapping(s.imports)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal resource mapping for %s: %w", s.name, err)
+		}
+		files = append(files, g.file("import-mappings/"+s.name+".json", content, generation.FileTypeImports, len(s.imports)))
+	}
+
+	return files, nil
+}
+
+// format returns the output format for the generator's language
+func (g *Generator) format() generation.IaCFormat {
+	if g.language == Python {
+		return generation.CDKPython
+	}
+	return generation.CDKTypeScript
+}
+
+// file builds a generated file
+func (g *Generator) file(path, content string, fileType generation.FileType, resourceCount int) generation.GeneratedFile {
+	return generation.GeneratedFile{
+		Path:          path,
+		Content:       content,
+		Type:          fileType,
+		Format:        g.format(),
+		Size:          int64(len(content)),
+		ResourceCount: resourceCount,
+	}
+}
+
+// groupResources groups AWS resources by account and region. Resources
+// without an owner account inherit the account of their VPC.
+func groupResources(resources []generation.MappedResource) map[stackKey][]generation.MappedResource {
+	owners := make(map[string]string)
+	for _, resource := range resources {
+		if owner, ok := resource.OriginalResource.Metadata["owner_id"].(string); ok && owner != "" {
+			owners[resource.OriginalResource.ID] = owner
+		}
+	}
+
+	groups := make(map[stackKey][]generation.MappedResource)
+	for _, resource := range resources {
+		if resource.OriginalResource.Provider != discovery.AWS {
+			continue
+		}
+
+		account := owners[resource.OriginalResource.ID]
+		if account == "" {
+			if vpcID, ok := resource.OriginalResource.Metadata["vpc_id"].(string); ok {
+				account = owners[vpcID]
+			}
+		}
+
+		key := stackKey{account: account, region: resource.OriginalResource.Region}
+		groups[key] = append(groups[key], resource)
+	}
+	return groups
+}
+
+// stackName names a stack after its region, and its account when the app
+// spans several accounts (ChimeraUsEast1, Chimera123456789012UsEast1)
+func stackName(key stackKey, withAccount bool) string {
+	name := "Chimera"
+	if withAccount && key.account != "" {
+		name += key.account
+	}
+	for _, word := range splitWords(key.region) {
+		name += strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+	}
+	return name
+}
+
+// describeKey describes a stack key for error messages
+func describeKey(key stackKey) string {
+	if key.account == "" {
+		return key.region
+	}
+	return key.account + "/" + key.region
+}
+
+// stackFileName returns the module file name of a stack, joining words with sep
+func stackFileName(s *stack, sep string) string {
+	return strings.Join(strings.Split(snakeCase(s.className), "_"), sep)
+}
+
+// resourceMapping renders the logical ID to resource identifier mapping read
+// by cdk import --resource-mapping
+func resourceMapping(imports []cloudformation.ResourceToImport) (string, error) {
+	mapping := make(map[string]map[string]string, len(imports))
+	for _, resource := range imports {
+		mapping[resource.LogicalResourceId] = resource.ResourceIdentifier
+	}
+	content, err := json.MarshalIndent(mapping, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(content) + "\n", nil
+}
+
+// cdkJSON renders cdk.json running the given app command
+func cdkJSON(app string) string {
+	content, _ := json.MarshalIndent(map[string]interface{}{
+		"app": app,
+		"watch": map[string][]string{
+			"exclude": {"cdk.out", "node_modules", "**/*.d.ts", "**/*.js", "venv"},
+		},
+	}, "", "  ")
+	return string(content) + "\n"
+}
+
+// packageJSON renders the Node.js dependency manifest
+func packageJSON(outputPath string) string {
+	name := strings.ToLower(strings.Join(splitWords(filepath.Base(filepath.Clean(outputPath))), "-"))
+	if name == "" {
+		name = "chimera-import"
+	}
+
+	content, _ := json.MarshalIndent(map[string]interface{}{
+		"name":    name,
+		"version": "0.1.0",
+		"bin":     map[string]string{"app": "bin/app.js"},
+		"scripts": map[string]string{"build": "tsc", "cdk": "cdk"},
+		"dependencies": map[string]string{
+			"aws-cdk-lib":        cdkVersion,
+			"constructs":         "^10.0.0",
+			"source-map-support": "^0.5.21",
+		},
+		"devDependencies": map[string]string{
+			"@types/node": "^20.0.0",
+			"aws-cdk":     cdkVersion,
+			"ts-node":     "^10.9.2",
+			"typescript":  "~5.4.5",
+		},
+	}, "", "  ")
+	return string(content) + "\n"
+}
+
+// tsconfigJSON is the TypeScript compiler configuration of the CDK app template
+const tsconfigJSON = `{
+  "compilerOptions": {
+    "target": "ES2020",
+    "module": "commonjs",
+    "lib": ["es2020", "dom"],
+    "declaration": true,
+    "strict": true,
+    "noImplicitAny": true,
+    "strictNullChecks": true,
+    "noImplicitThis": true,
+    "alwaysStrict": true,
+    "noUnusedLocals": false,
+    "noUnusedParameters": false,
+    "noImplicitReturns": true,
+    "noFallthroughCasesInSwitch": false,
+    "inlineSourceMap": true,
+    "inlineSources": true,
+    "experimentalDecorators": true,
+    "strictPropertyInitialization": false,
+    "typeRoots": ["./node_modules/@types"]
+  },
+  "exclude": ["node_modules", "cdk.out"]
+}
+`
+
+// requirementsTxt is the Python dependency manifest
+const requirementsTxt = "aws-cdk-lib==" + cdkVersion + "\nconstructs>=10.0.0,<11.0.0\n"
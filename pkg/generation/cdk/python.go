@@ -0,0 +1,163 @@
+package cdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// pyIndent is the indentation unit of generated Python
+const pyIndent = "    "
+
+// renderPythonApp renders app.py instantiating every stack
+func renderPythonApp(stacks []*stack) string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env python3\n")
+	b.WriteString("\"\"\"Generated by Chimera\"\"\"\n\n")
+	b.WriteString("import os\n\n")
+	b.WriteString("import aws_cdk as cdk\n\n")
+	for _, s := range stacks {
+		fmt.Fprintf(&b, "from stacks.%s import %s\n", stackFileName(s, "_"), s.className)
+	}
+
+	b.WriteString("\napp = cdk.App()\n")
+	for _, s := range stacks {
+		account := `os.getenv("CDK_DEFAULT_ACCOUNT")`
+		if s.account != "" {
+			account = pyString(s.account)
+		}
+		region := `os.getenv("CDK_DEFAULT_REGION")`
+		if s.region != "" {
+			region = pyString(s.region)
+		}
+		fmt.Fprintf(&b, "%s(\n", s.className)
+		fmt.Fprintf(&b, "%sapp,\n%s%s,\n", pyIndent, pyIndent, pyString(s.name))
+		fmt.Fprintf(&b, "%senv=cdk.Environment(account=%s, region=%s),\n", pyIndent, account, region)
+		b.WriteString(")\n")
+	}
+	b.WriteString("\napp.synth()\n")
+
+	return b.String()
+}
+
+// renderPythonStack renders stacks/<stack>.py
+func renderPythonStack(s *stack) string {
+	var b strings.Builder
+	b.WriteString("\"\"\"Generated by Chimera\"\"\"\n")
+	for _, address := range s.unsupported {
+		fmt.Fprintf(&b, "# Not generated, no CloudFormation mapping: %s\n", address)
+	}
+	b.WriteString("\nimport aws_cdk as cdk\n")
+	for _, service := range s.services {
+		fmt.Fprintf(&b, "from aws_cdk import aws_%s as %s\n", service, service)
+	}
+	b.WriteString("from constructs import Construct\n")
+
+	body := strings.Repeat(pyIndent, 2)
+	fmt.Fprintf(&b, "\n\nclass %s(cdk.Stack):\n", s.className)
+	fmt.Fprintf(&b, "%sdef __init__(self, scope: Construct, construct_id: str, **kwargs) -> None:\n", pyIndent)
+	fmt.Fprintf(&b, "%ssuper().__init__(scope, construct_id, **kwargs)\n", body)
+
+	for _, p := range s.parameters {
+		fields := map[string]interface{}{"type": p.cfnType}
+		if p.desc != "" {
+			fields["description"] = p.desc
+		}
+		if p.defaultTo != nil {
+			fields["default"] = p.defaultTo
+		}
+		if p.noEcho {
+			fields["no_echo"] = true
+		}
+		fmt.Fprintf(&b, "\n%s%s = cdk.CfnParameter(\n", body, p.ident)
+		fmt.Fprintf(&b, "%s%sself,\n%s%s%s,\n", body, pyIndent, body, pyIndent, pyString(p.logicalID))
+		pyKeywords(&b, fields, false, 2)
+		fmt.Fprintf(&b, "%s)\n", body)
+	}
+
+	for _, c := range s.constructs {
+		fmt.Fprintf(&b, "\n%s%s = %s.%s(\n", body, c.ident, c.service, c.class)
+		fmt.Fprintf(&b, "%s%sself,\n%s%s%s,\n", body, pyIndent, body, pyIndent, pyString(c.logicalID))
+		pyKeywords(&b, c.props.fields, true, 2)
+		fmt.Fprintf(&b, "%s)\n", body)
+		fmt.Fprintf(&b, "%s%s.apply_removal_policy(cdk.RemovalPolicy.RETAIN)\n", body, c.ident)
+	}
+
+	for _, o := range s.outputs {
+		fields := map[string]interface{}{"value": o.value}
+		if o.desc != "" {
+			fields["description"] = o.desc
+		}
+		fmt.Fprintf(&b, "\n%scdk.CfnOutput(\n", body)
+		fmt.Fprintf(&b, "%s%sself,\n%s%s%s,\n", body, pyIndent, body, pyIndent, pyString(o.logicalID))
+		pyKeywords(&b, fields, false, 2)
+		fmt.Fprintf(&b, "%s)\n", body)
+	}
+
+	return b.String()
+}
+
+// pyKeywords writes keyword arguments one per line
+func pyKeywords(b *strings.Builder, fields map[string]interface{}, properties bool, indent int) {
+	inner := strings.Repeat(pyIndent, indent+1)
+	for _, key := range sortedKeys(fields) {
+		name := key
+		if properties {
+			name = snakeCase(key)
+		}
+		fmt.Fprintf(b, "%s%s=%s,\n", inner, name, pyValue(fields[key], indent+1))
+	}
+}
+
+// pyValue renders a value at the given indentation level
+func pyValue(value interface{}, indent int) string {
+	switch v := value.(type) {
+	case string:
+		return pyString(v)
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case ref:
+		return v.construct.ident + ".ref"
+	case getAtt:
+		return v.construct.ident + "." + snakeCase("attr_"+strings.ReplaceAll(v.attribute, ".", ""))
+	case parameterRef:
+		return v.parameter.ident + ".value_as_string"
+	case base64:
+		return fmt.Sprintf("cdk.Fn.base64(%s)", pyValue(v.value, indent))
+	case *object:
+		if len(v.fields) == 0 {
+			return "{}"
+		}
+		inner := strings.Repeat(pyIndent, indent+1)
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, key := range sortedKeys(v.fields) {
+			b.WriteString(inner + pyString(snakeCase(key)) + ": " + pyValue(v.fields[key], indent+1) + ",\n")
+		}
+		b.WriteString(strings.Repeat(pyIndent, indent) + "}")
+		return b.String()
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]"
+		}
+		inner := strings.Repeat(pyIndent, indent+1)
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, item := range v {
+			b.WriteString(inner + pyValue(item, indent+1) + ",\n")
+		}
+		b.WriteString(strings.Repeat(pyIndent, indent) + "]")
+		return b.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// pyString returns a double-quoted string literal
+func pyString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
@@ -0,0 +1,438 @@
+package cdk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/generation/cloudformation"
+)
+
+// stack is a language-independent CDK stack built from a CloudFormation template
+type stack struct {
+	name        string
+	className   string
+	account     string
+	region      string
+	services    []string
+	parameters  []*parameter
+	constructs  []*construct
+	outputs     []output
+	imports     []cloudformation.ResourceToImport
+	unsupported []string
+}
+
+// parameter is a CfnParameter
+type parameter struct {
+	logicalID string
+	ident     string
+	cfnType   string
+	defaultTo interface{}
+	desc      string
+	noEcho    bool
+}
+
+// construct is an L1 construct for one template resource
+type construct struct {
+	logicalID    string
+	ident        string
+	service      string
+	class        string
+	props        *object
+	dependencies map[*construct]bool
+}
+
+// output is a CfnOutput
+type output struct {
+	logicalID string
+	desc      string
+	value     interface{}
+}
+
+// object is a property bag; keys are CloudFormation property names
+type object struct {
+	fields map[string]interface{}
+}
+
+// ref is the Ref of a construct
+type ref struct {
+	construct *construct
+}
+
+// parameterRef is the value of a parameter
+type parameterRef struct {
+	parameter *parameter
+}
+
+// getAtt is an attribute of a construct
+type getAtt struct {
+	construct *construct
+	attribute string
+}
+
+// base64 is an Fn::Base64 of a value
+type base64 struct {
+	value interface{}
+}
+
+// stackBuilder converts a template to a stack
+type stackBuilder struct {
+	stack      *stack
+	constructs map[string]*construct
+	parameters map[string]*parameter
+	current    *construct
+	idents     map[string]bool
+}
+
+// buildStack converts a CloudFormation template to a stack, ordering
+// constructs so every construct is declared after those it references
+func buildStack(name string, template *cloudformation.Template, imports []cloudformation.ResourceToImport) (*stack, error) {
+	b := &stackBuilder{
+		stack:      &stack{name: name, className: name + "Stack", imports: imports},
+		constructs: make(map[string]*construct),
+		parameters: make(map[string]*parameter),
+		idents:     make(map[string]bool),
+	}
+
+	if chimera, ok := template.Metadata["Chimera"].(map[string]interface{}); ok {
+		if skipped, ok := chimera["UnsupportedResources"].([]string); ok {
+			b.stack.unsupported = skipped
+		}
+	}
+
+	services := make(map[string]bool)
+	for _, logicalID := range sortedKeys(template.Resources) {
+		service, class, err := constructClass(template.Resources[logicalID].Type)
+		if err != nil {
+			return nil, err
+		}
+		services[service] = true
+		b.idents[service] = true
+		b.constructs[logicalID] = &construct{
+			logicalID:    logicalID,
+			service:      service,
+			class:        class,
+			dependencies: make(map[*construct]bool),
+		}
+	}
+	for service := range services {
+		b.stack.services = append(b.stack.services, service)
+	}
+	sort.Strings(b.stack.services)
+
+	for _, logicalID := range sortedKeys(template.Parameters) {
+		param := template.Parameters[logicalID]
+		p := &parameter{
+			logicalID: logicalID,
+			ident:     b.uniqueIdent(logicalID),
+			cfnType:   param.Type,
+			defaultTo: param.Default,
+			desc:      param.Description,
+			noEcho:    param.NoEcho,
+		}
+		b.parameters[logicalID] = p
+		b.stack.parameters = append(b.stack.parameters, p)
+	}
+
+	for _, logicalID := range sortedKeys(template.Resources) {
+		c := b.constructs[logicalID]
+		c.ident = b.uniqueIdent(logicalID)
+
+		b.current = c
+		props, err := b.convertObject(template.Resources[logicalID].Properties)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", logicalID, err)
+		}
+		c.props = props
+		b.current = nil
+
+		b.stack.constructs = append(b.stack.constructs, c)
+	}
+	if err := b.orderConstructs(); err != nil {
+		return nil, fmt.Errorf("failed to order constructs: %w", err)
+	}
+
+	for _, logicalID := range sortedKeys(template.Outputs) {
+		value, err := b.convertValue(template.Outputs[logicalID].Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert output %s: %w", logicalID, err)
+		}
+		b.stack.outputs = append(b.stack.outputs, output{
+			logicalID: logicalID,
+			desc:      template.Outputs[logicalID].Description,
+			value:     value,
+		})
+	}
+
+	return b.stack, nil
+}
+
+// constructClass returns the module and L1 class for a CloudFormation type
+// (AWS::EC2::VPC -> ec2, CfnVPC)
+func constructClass(cfnType string) (string, string, error) {
+	parts := strings.Split(cfnType, "::")
+	if len(parts) != 3 || parts[0] != "AWS" {
+		return "", "", fmt.Errorf("unsupported CloudFormation type: %s", cfnType)
+	}
+	return strings.ToLower(parts[1]), "Cfn" + parts[2], nil
+}
+
+// convertObject converts template properties
+func (b *stackBuilder) convertObject(properties map[string]interface{}) (*object, error) {
+	result := &object{fields: make(map[string]interface{}, len(properties))}
+	for key, value := range properties {
+		converted, err := b.convertValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		result.fields[key] = converted
+	}
+	return result, nil
+}
+
+// convertValue converts a template value, resolving intrinsic functions
+func (b *stackBuilder) convertValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 1 {
+			for key, argument := range v {
+				if strings.HasPrefix(key, "Fn::") || key == "Ref" {
+					return b.convertIntrinsic(key, argument)
+				}
+			}
+		}
+		return b.convertObject(v)
+	case map[string]string:
+		fields := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			fields[key] = item
+		}
+		return &object{fields: fields}, nil
+	case []map[string]interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted, err := b.convertValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, converted)
+		}
+		return items, nil
+	case []map[string]string:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted, _ := b.convertValue(item)
+			items = append(items, converted)
+		}
+		return items, nil
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, nil
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted, err := b.convertValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, converted)
+		}
+		return items, nil
+	default:
+		return v, nil
+	}
+}
+
+// convertIntrinsic converts Ref, Fn::GetAtt and Fn::Base64
+func (b *stackBuilder) convertIntrinsic(function string, argument interface{}) (interface{}, error) {
+	switch function {
+	case "Ref":
+		name, _ := argument.(string)
+		if c, exists := b.constructs[name]; exists {
+			b.depend(c)
+			return ref{construct: c}, nil
+		}
+		if p, exists := b.parameters[name]; exists {
+			return parameterRef{parameter: p}, nil
+		}
+		return nil, fmt.Errorf("reference to undeclared %s", name)
+	case "Fn::GetAtt":
+		parts, ok := argument.([]string)
+		if !ok || len(parts) != 2 {
+			return nil, fmt.Errorf("malformed Fn::GetAtt %v", argument)
+		}
+		c, exists := b.constructs[parts[0]]
+		if !exists {
+			return nil, fmt.Errorf("attribute of undeclared %s", parts[0])
+		}
+		b.depend(c)
+		return getAtt{construct: c, attribute: parts[1]}, nil
+	case "Fn::Base64":
+		value, err := b.convertValue(argument)
+		if err != nil {
+			return nil, err
+		}
+		return base64{value: value}, nil
+	default:
+		return nil, fmt.Errorf("unsupported intrinsic function %s", function)
+	}
+}
+
+// depend records that the construct being converted references c
+func (b *stackBuilder) depend(c *construct) {
+	if b.current != nil && b.current != c {
+		b.current.dependencies[c] = true
+	}
+}
+
+// orderConstructs sorts constructs after the constructs they reference.
+// Security group rules that reference another group are generated as
+// separate rule resources, so a template that still has a reference cycle
+// would be rejected by CloudFormation and is reported as an error.
+func (b *stackBuilder) orderConstructs() error {
+	var ordered []*construct
+	placed := make(map[*construct]bool)
+
+	for len(ordered) < len(b.stack.constructs) {
+		progress := false
+		for _, c := range b.stack.constructs {
+			if placed[c] {
+				continue
+			}
+			ready := true
+			for dependency := range c.dependencies {
+				if !placed[dependency] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, c)
+				placed[c] = true
+				progress = true
+			}
+		}
+		if !progress {
+			var remaining []string
+			for _, c := range b.stack.constructs {
+				if !placed[c] {
+					remaining = append(remaining, c.logicalID)
+				}
+			}
+			return fmt.Errorf("reference cycle between constructs %s", strings.Join(remaining, ", "))
+		}
+	}
+
+	b.stack.constructs = ordered
+	return nil
+}
+
+// uniqueIdent returns a snake_case identifier for a logical ID that is not a
+// keyword and not already taken
+func (b *stackBuilder) uniqueIdent(logicalID string) string {
+	ident := snakeCase(logicalID)
+	if reservedWords[ident] || reservedWords[camelCase(ident)] {
+		ident += "_resource"
+	}
+	base := ident
+	for i := 2; b.idents[ident] || b.idents[camelCase(ident)]; i++ {
+		ident = fmt.Sprintf("%s_%d", base, i)
+	}
+	b.idents[ident] = true
+	b.idents[camelCase(ident)] = true
+	return ident
+}
+
+// reservedWords holds TypeScript and Python keywords and names used by the
+// generated stacks
+var reservedWords = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true, "break": true,
+	"case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "def": true, "default": true, "del": true, "delete": true, "do": true,
+	"elif": true, "else": true, "enum": true, "except": true, "export": true, "extends": true,
+	"false": true, "finally": true, "for": true, "from": true, "function": true,
+	"global": true, "if": true, "implements": true, "import": true, "in": true,
+	"instanceof": true, "interface": true, "is": true, "lambda": true, "let": true,
+	"new": true, "nonlocal": true, "not": true, "null": true, "or": true, "package": true,
+	"pass": true, "private": true, "protected": true, "public": true, "raise": true,
+	"return": true, "static": true, "super": true, "switch": true, "this": true,
+	"throw": true, "true": true, "try": true, "typeof": true, "var": true, "void": true,
+	"while": true, "with": true, "yield": true, "self": true, "scope": true, "id": true,
+	"props": true, "kwargs": true, "construct": true, "construct_id": true,
+	"constructs": true, "cdk": true, "os": true, "app": true,
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// camelCase converts a PascalCase or snake_case name to camelCase, lowering
+// a leading acronym as jsii does (SSESpecification -> sseSpecification)
+func camelCase(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+		} else {
+			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// snakeCase converts a PascalCase or camelCase name to snake_case
+func snakeCase(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
+
+// splitWords splits a name into words at underscores and case changes,
+// keeping acronyms and trailing digits together (Ipv6CidrBlock -> Ipv6 Cidr Block)
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case !isAlnum(r):
+			flush()
+		case isUpper(r):
+			previous := i > 0 && isAlnum(runes[i-1])
+			lowerBefore := i > 0 && (isLower(runes[i-1]) || isDigit(runes[i-1]))
+			lowerAfter := i+1 < len(runes) && isLower(runes[i+1])
+			upperBefore := i > 0 && isUpper(runes[i-1])
+			if previous && (lowerBefore || (upperBefore && lowerAfter)) {
+				flush()
+			}
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+func isUpper(r rune) bool { return r >= 'A' && r <= 'Z' }
+func isLower(r rune) bool { return r >= 'a' && r <= 'z' }
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+func isAlnum(r rune) bool { return isUpper(r) || isLower(r) || isDigit(r) }
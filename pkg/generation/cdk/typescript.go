@@ -0,0 +1,162 @@
+package cdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tsIdentifier matches object keys that can be written unquoted
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsIndent is the indentation unit of generated TypeScript
+const tsIndent = "    "
+
+// renderTypeScriptApp renders bin/app.ts instantiating every stack
+func renderTypeScriptApp(stacks []*stack) string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env node\n")
+	b.WriteString("// Generated by Chimera\n")
+	b.WriteString("import 'source-map-support/register';\n")
+	b.WriteString("import * as cdk from 'aws-cdk-lib';\n")
+	for _, s := range stacks {
+		fmt.Fprintf(&b, "import { %s } from '../lib/%s';\n", s.className, stackFileName(s, "-"))
+	}
+
+	b.WriteString("\nconst app = new cdk.App();\n")
+	for _, s := range stacks {
+		account := "process.env.CDK_DEFAULT_ACCOUNT"
+		if s.account != "" {
+			account = tsString(s.account)
+		}
+		region := "process.env.CDK_DEFAULT_REGION"
+		if s.region != "" {
+			region = tsString(s.region)
+		}
+		fmt.Fprintf(&b, "new %s(app, %s, {\n", s.className, tsString(s.name))
+		fmt.Fprintf(&b, "%senv: { account: %s, region: %s },\n", tsIndent, account, region)
+		b.WriteString("});\n")
+	}
+
+	return b.String()
+}
+
+// renderTypeScriptStack renders lib/<stack>.ts
+func renderTypeScriptStack(s *stack) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Chimera\n")
+	for _, address := range s.unsupported {
+		fmt.Fprintf(&b, "// Not generated, no CloudFormation mapping: %s\n", address)
+	}
+	b.WriteString("import * as cdk from 'aws-cdk-lib';\n")
+	for _, service := range s.services {
+		fmt.Fprintf(&b, "import * as %s from 'aws-cdk-lib/aws-%s';\n", service, service)
+	}
+	b.WriteString("import { Construct } from 'constructs';\n")
+
+	body := strings.Repeat(tsIndent, 2)
+	fmt.Fprintf(&b, "\nexport class %s extends cdk.Stack {\n", s.className)
+	fmt.Fprintf(&b, "%sconstructor(scope: Construct, id: string, props?: cdk.StackProps) {\n", tsIndent)
+	fmt.Fprintf(&b, "%ssuper(scope, id, props);\n", body)
+
+	for _, p := range s.parameters {
+		fields := map[string]interface{}{"type": p.cfnType}
+		if p.desc != "" {
+			fields["description"] = p.desc
+		}
+		if p.defaultTo != nil {
+			fields["default"] = p.defaultTo
+		}
+		if p.noEcho {
+			fields["noEcho"] = true
+		}
+		fmt.Fprintf(&b, "\n%sconst %s = new cdk.CfnParameter(this, %s, %s);\n",
+			body, camelCase(p.ident), tsString(p.logicalID), tsObject(fields, false, 2))
+	}
+
+	for _, c := range s.constructs {
+		fmt.Fprintf(&b, "\n%sconst %s = new %s.%s(this, %s, %s);\n",
+			body, camelCase(c.ident), c.service, c.class, tsString(c.logicalID), tsObject(c.props.fields, true, 2))
+		fmt.Fprintf(&b, "%s%s.applyRemovalPolicy(cdk.RemovalPolicy.RETAIN);\n", body, camelCase(c.ident))
+	}
+
+	if len(s.outputs) > 0 {
+		b.WriteString("\n")
+	}
+	for _, o := range s.outputs {
+		fields := map[string]interface{}{"value": o.value}
+		if o.desc != "" {
+			fields["description"] = o.desc
+		}
+		fmt.Fprintf(&b, "%snew cdk.CfnOutput(this, %s, %s);\n", body, tsString(o.logicalID), tsObject(fields, false, 2))
+	}
+
+	fmt.Fprintf(&b, "%s}\n}\n", tsIndent)
+	return b.String()
+}
+
+// tsValue renders a value at the given indentation level
+func tsValue(value interface{}, indent int) string {
+	switch v := value.(type) {
+	case string:
+		return tsString(v)
+	case ref:
+		return camelCase(v.construct.ident) + ".ref"
+	case getAtt:
+		return camelCase(v.construct.ident) + "." + camelCase("attr_"+strings.ReplaceAll(v.attribute, ".", ""))
+	case parameterRef:
+		return camelCase(v.parameter.ident) + ".valueAsString"
+	case base64:
+		return fmt.Sprintf("cdk.Fn.base64(%s)", tsValue(v.value, indent))
+	case *object:
+		return tsObject(v.fields, true, indent)
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]"
+		}
+		inner := strings.Repeat(tsIndent, indent+1)
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, item := range v {
+			b.WriteString(inner + tsValue(item, indent+1) + ",\n")
+		}
+		b.WriteString(strings.Repeat(tsIndent, indent) + "]")
+		return b.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// tsObject renders an object literal; CloudFormation property names are
+// converted to the camelCase names of the construct props
+func tsObject(fields map[string]interface{}, properties bool, indent int) string {
+	if len(fields) == 0 {
+		return "{}"
+	}
+
+	inner := strings.Repeat(tsIndent, indent+1)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range sortedKeys(fields) {
+		name := key
+		if properties {
+			name = camelCase(key)
+		}
+		if !tsIdentifier.MatchString(name) {
+			name = tsString(name)
+		}
+		b.WriteString(inner + name + ": " + tsValue(fields[key], indent+1) + ",\n")
+	}
+	b.WriteString(strings.Repeat(tsIndent, indent) + "}")
+	return b.String()
+}
+
+// tsString returns a single-quoted string literal
+func tsString(s string) string {
+	quoted, _ := json.Marshal(s)
+	inner := string(quoted[1 : len(quoted)-1])
+	inner = strings.ReplaceAll(inner, `\"`, `"`)
+	inner = strings.ReplaceAll(inner, `'`, `\'`)
+	return "'" + inner + "'"
+}
@@ -0,0 +1,425 @@
+package cloudformation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// Encoding selects the serialization of the generated template
+type Encoding string
+
+const (
+	YAML Encoding = "yaml"
+	JSON Encoding = "json"
+)
+
+// templateFormatVersion is the only CloudFormation template format version
+const templateFormatVersion = "2010-09-09"
+
+// Template is a CloudFormation template; field order is the order sections
+// are written in
+type Template struct {
+	AWSTemplateFormatVersion string                 `json:"AWSTemplateFormatVersion" yaml:"AWSTemplateFormatVersion"`
+	Description              string                 `json:"Description,omitempty" yaml:"Description,omitempty"`
+	Metadata                 map[string]interface{} `json:"Metadata,omitempty" yaml:"Metadata,omitempty"`
+	Parameters               map[string]Parameter   `json:"Parameters,omitempty" yaml:"Parameters,omitempty"`
+	Resources                map[string]Resource    `json:"Resources" yaml:"Resources"`
+	Outputs                  map[string]Output      `json:"Outputs,omitempty" yaml:"Outputs,omitempty"`
+}
+
+// Parameter is a template input parameter
+type Parameter struct {
+	Type        string      `json:"Type" yaml:"Type"`
+	Description string      `json:"Description,omitempty" yaml:"Description,omitempty"`
+	Default     interface{} `json:"Default,omitempty" yaml:"Default,omitempty"`
+	NoEcho      bool        `json:"NoEcho,omitempty" yaml:"NoEcho,omitempty"`
+}
+
+// Resource is a template resource. Resources are retained on deletion and
+// replacement, which CloudFormation requires for resources adopted by import.
+type Resource struct {
+	Type                string                 `json:"Type" yaml:"Type"`
+	DeletionPolicy      string                 `json:"DeletionPolicy" yaml:"DeletionPolicy"`
+	UpdateReplacePolicy string                 `json:"UpdateReplacePolicy" yaml:"UpdateReplacePolicy"`
+	Properties          map[string]interface{} `json:"Properties,omitempty" yaml:"Properties,omitempty"`
+}
+
+// Output is a template output
+type Output struct {
+	Description string      `json:"Description,omitempty" yaml:"Description,omitempty"`
+	Value       interface{} `json:"Value" yaml:"Value"`
+}
+
+// ResourceToImport is one entry of the descriptor passed to
+// aws cloudformation create-change-set --change-set-type IMPORT --resources-to-import
+type ResourceToImport struct {
+	ResourceType       string            `json:"ResourceType"`
+	LogicalResourceId  string            `json:"LogicalResourceId"`
+	ResourceIdentifier map[string]string `json:"ResourceIdentifier"`
+}
+
+// Generator generates CloudFormation templates from mapped AWS resources
+type Generator struct {
+	encoding Encoding
+}
+
+// NewGenerator creates a new CloudFormation generator writing the given encoding
+func NewGenerator(encoding Encoding) *Generator {
+	if encoding != JSON {
+		encoding = YAML
+	}
+	return &Generator{encoding: encoding}
+}
+
+// Capabilities returns the features of CloudFormation templates
+func (g *Generator) Capabilities() generation.FormatCapabilities {
+	return generation.FormatCapabilities{
+		SupportedProviders: []discovery.CloudProvider{discovery.AWS},
+		SupportsVariables:  true,
+		SupportsOutputs:    true,
+		SupportsImports:    true,
+	}
+}
+
+// Generate generates a template and an import descriptor for the AWS resources
+// in the mapped set
+func (g *Generator) Generate(resources []generation.MappedResource, opts generation.GenerationOptions) ([]generation.GeneratedFile, error) {
+	var awsResources []generation.MappedResource
+	for _, resource := range resources {
+		if resource.OriginalResource.Provider == discovery.AWS {
+			awsResources = append(awsResources, resource)
+		}
+	}
+	if len(awsResources) == 0 {
+		return nil, fmt.Errorf("CloudFormation generation requires AWS resources")
+	}
+
+	template, imports, err := BuildTemplate(awsResources)
+	if err != nil {
+		return nil, err
+	}
+
+	templateContent, err := g.marshal(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	importContent, err := json.MarshalIndent(imports, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal import descriptor: %w", err)
+	}
+
+	templatePath := "template.yaml"
+	if g.encoding == JSON {
+		templatePath = "template.json"
+	}
+
+	return []generation.GeneratedFile{
+		{
+			Path:          templatePath,
+			Content:       templateContent,
+			Type:          generation.FileTypeMain,
+			Format:        generation.CloudFormation,
+			Size:          int64(len(templateContent)),
+			ResourceCount: len(template.Resources),
+		},
+		{
+			Path:          "import-resources.json",
+			Content:       string(importContent) + "\n",
+			Type:          generation.FileTypeImports,
+			Format:        generation.CloudFormation,
+			Size:          int64(len(importContent) + 1),
+			ResourceCount: len(imports),
+		},
+	}, nil
+}
+
+// marshal serializes a template in the generator's encoding
+func (g *Generator) marshal(template *Template) (string, error) {
+	var buf bytes.Buffer
+
+	if g.encoding == JSON {
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(template); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(template); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// BuildTemplate converts mapped AWS resources to a template and the
+// descriptor of the resources to import into it
+func BuildTemplate(resources []generation.MappedResource) (*Template, []ResourceToImport, error) {
+	return newTemplateBuilder(resources).build()
+}
+
+// templateBuilder accumulates a template from mapped resources, resolving
+// references between them to Ref and Fn::GetAtt
+type templateBuilder struct {
+	resources       []generation.MappedResource
+	template        *Template
+	logicalIDs      map[string]string
+	groupsWithRules map[string]bool
+	usedIDs         map[string]bool
+}
+
+// newTemplateBuilder assigns logical IDs to every supported resource
+func newTemplateBuilder(resources []generation.MappedResource) *templateBuilder {
+	b := &templateBuilder{
+		resources: resources,
+		template: &Template{
+			AWSTemplateFormatVersion: templateFormatVersion,
+			Description:              "Existing AWS infrastructure generated by Chimera",
+			Parameters:               make(map[string]Parameter),
+			Resources:                make(map[string]Resource),
+			Outputs:                  make(map[string]Output),
+		},
+		logicalIDs:      make(map[string]string),
+		groupsWithRules: make(map[string]bool),
+		usedIDs:         make(map[string]bool),
+	}
+
+	sorted := make([]generation.MappedResource, len(resources))
+	copy(sorted, resources)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ResourceType != sorted[j].ResourceType {
+			return sorted[i].ResourceType < sorted[j].ResourceType
+		}
+		return sorted[i].ResourceName < sorted[j].ResourceName
+	})
+	b.resources = sorted
+
+	for _, resource := range sorted {
+		spec, supported := resourceSpecs[resource.ResourceType]
+		if !supported {
+			continue
+		}
+		b.logicalIDs[resource.OriginalResource.ID] = b.uniqueID(logicalID(resource.ResourceName, spec.suffix))
+
+		if spec.cfnType == "AWS::EC2::SecurityGroupIngress" || spec.cfnType == "AWS::EC2::SecurityGroupEgress" {
+			b.groupsWithRules[getStringFromMetadata(resource.OriginalResource.Metadata, "security_group_id", "")] = true
+		}
+	}
+
+	return b
+}
+
+// build converts every supported resource and returns the template and the
+// import descriptor
+func (b *templateBuilder) build() (*Template, []ResourceToImport, error) {
+	var imports []ResourceToImport
+	var skipped []string
+
+	for _, resource := range b.resources {
+		spec, supported := resourceSpecs[resource.ResourceType]
+		if !supported {
+			skipped = append(skipped, fmt.Sprintf("%s.%s", resource.ResourceType, resource.ResourceName))
+			continue
+		}
+
+		logicalID := b.logicalIDs[resource.OriginalResource.ID]
+		ctx := &resourceContext{builder: b, logicalID: logicalID}
+
+		properties, err := spec.build(ctx, resource)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert %s.%s: %w", resource.ResourceType, resource.ResourceName, err)
+		}
+		if tags := convertTags(resource.Configuration["tags"]); len(tags) > 0 && spec.taggable {
+			properties["Tags"] = tags
+		}
+
+		b.template.Resources[logicalID] = Resource{
+			Type:                spec.cfnType,
+			DeletionPolicy:      "Retain",
+			UpdateReplacePolicy: "Retain",
+			Properties:          properties,
+		}
+
+		if spec.output != nil {
+			b.template.Outputs[logicalID+"Id"] = Output{
+				Description: fmt.Sprintf("ID of %s %s", spec.cfnType, resource.OriginalResource.ID),
+				Value:       spec.output(logicalID),
+			}
+		}
+
+		identifierValue := resource.OriginalResource.ID
+		if spec.identifierValue != nil {
+			identifierValue = spec.identifierValue(resource)
+		}
+		if identifierValue != "" {
+			imports = append(imports, ResourceToImport{
+				ResourceType:       spec.cfnType,
+				LogicalResourceId:  logicalID,
+				ResourceIdentifier: map[string]string{spec.identifier: identifierValue},
+			})
+		}
+	}
+
+	if len(b.template.Resources) == 0 {
+		return nil, nil, fmt.Errorf("no resources supported by CloudFormation generation")
+	}
+
+	if len(skipped) > 0 {
+		b.template.Metadata = map[string]interface{}{
+			"Chimera": map[string]interface{}{
+				"UnsupportedResources": skipped,
+			},
+		}
+	}
+
+	return b.template, imports, nil
+}
+
+// uniqueID returns id, or id with a numeric suffix if it is already taken
+func (b *templateBuilder) uniqueID(id string) string {
+	candidate := id
+	for i := 2; b.usedIDs[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", id, i)
+	}
+	b.usedIDs[candidate] = true
+	return candidate
+}
+
+// resourceContext resolves references for the resource being converted.
+// Dependencies are implied by Ref and Fn::GetAtt, so no DependsOn is written.
+type resourceContext struct {
+	builder   *templateBuilder
+	logicalID string
+}
+
+// ref references a resource in the template with Ref, or falls back to a
+// parameter defaulting to the existing resource ID
+func (c *resourceContext) ref(id, kind string) interface{} {
+	if logicalID, exists := c.builder.logicalIDs[id]; exists {
+		return map[string]interface{}{"Ref": logicalID}
+	}
+	return c.parameter(id, kind)
+}
+
+// securityGroupRef references a security group by its GroupId attribute.
+// References from a group to itself use the literal ID, since an inline
+// self-reference would be a circular dependency.
+func (c *resourceContext) securityGroupRef(id string) interface{} {
+	logicalID, exists := c.builder.logicalIDs[id]
+	if !exists {
+		return c.parameter(id, "security_group")
+	}
+	if logicalID == c.logicalID {
+		return id
+	}
+	return map[string]interface{}{"Fn::GetAtt": []string{logicalID, "GroupId"}}
+}
+
+// parameter declares a parameter holding the ID of a resource outside the template
+func (c *resourceContext) parameter(id, kind string) interface{} {
+	parameterType, exists := parameterTypes[kind]
+	if !exists {
+		parameterType = "String"
+	}
+
+	name := logicalName(fmt.Sprintf("%s_%s_id", kind, idSuffix(id)))
+	c.builder.template.Parameters[name] = Parameter{
+		Type:        parameterType,
+		Description: fmt.Sprintf("ID of existing %s %s", strings.ReplaceAll(kind, "_", " "), id),
+		Default:     id,
+	}
+	return map[string]interface{}{"Ref": name}
+}
+
+// secret declares a NoEcho parameter for a value discovery cannot read
+func (c *resourceContext) secret(name, description string) interface{} {
+	name = c.logicalID + name
+	c.builder.template.Parameters[name] = Parameter{
+		Type:        "String",
+		Description: description,
+		NoEcho:      true,
+	}
+	return map[string]interface{}{"Ref": name}
+}
+
+// parameterTypes holds AWS-specific parameter types for referenced IDs
+var parameterTypes = map[string]string{
+	"vpc":            "AWS::EC2::VPC::Id",
+	"subnet":         "AWS::EC2::Subnet::Id",
+	"security_group": "AWS::EC2::SecurityGroup::Id",
+}
+
+// logicalName converts a Terraform-style name to an alphanumeric PascalCase logical ID
+func logicalName(name string) string {
+	var result strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			if upper && r >= 'a' && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			result.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	if result.Len() == 0 {
+		return "Resource"
+	}
+	return result.String()
+}
+
+// logicalID builds a logical ID from a resource name and a type suffix,
+// which is not repeated when the name already ends with it (main_vpc -> MainVPC)
+func logicalID(name, suffix string) string {
+	base := logicalName(name)
+	if len(base) > len(suffix) && strings.EqualFold(base[len(base)-len(suffix):], suffix) {
+		base = base[:len(base)-len(suffix)]
+	}
+	return base + suffix
+}
+
+// idSuffix returns the unique part of an AWS resource ID (sg-0abc -> 0abc)
+func idSuffix(id string) string {
+	if idx := strings.LastIndex(id, "-"); idx >= 0 {
+		return id[idx+1:]
+	}
+	return id
+}
+
+// convertTags converts a Terraform tags map to a sorted CloudFormation tag list
+func convertTags(value interface{}) []map[string]string {
+	tags, ok := value.(map[string]string)
+	if !ok || len(tags) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, map[string]string{"Key": key, "Value": tags[key]})
+	}
+	return result
+}
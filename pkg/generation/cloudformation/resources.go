@@ -0,0 +1,489 @@
+package cloudformation
+
+import (
+	"fmt"
+
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// resourceSpec describes how a Terraform resource type is written to a template
+type resourceSpec struct {
+	// cfnType is the CloudFormation resource type
+	cfnType string
+
+	// suffix is appended to the resource name to form its logical ID
+	suffix string
+
+	// identifier is the resource identifier property used by import
+	identifier string
+
+	// taggable reports whether the type accepts a Tags property
+	taggable bool
+
+	// build converts the resource to CloudFormation properties
+	build func(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error)
+
+	// output returns the value exported for the resource, if any
+	output func(logicalID string) interface{}
+
+	// identifierValue returns the import identifier when it is not the resource ID
+	identifierValue func(resource generation.MappedResource) string
+}
+
+// resourceSpecs holds the supported resource types keyed by Terraform type
+var resourceSpecs = map[string]resourceSpec{
+	"aws_vpc": {
+		cfnType:    "AWS::EC2::VPC",
+		suffix:     "VPC",
+		identifier: "VpcId",
+		taggable:   true,
+		build:      buildVPC,
+		output:     refOutput,
+	},
+	"aws_subnet": {
+		cfnType:    "AWS::EC2::Subnet",
+		suffix:     "Subnet",
+		identifier: "SubnetId",
+		taggable:   true,
+		build:      buildSubnet,
+		output:     refOutput,
+	},
+	"aws_security_group": {
+		cfnType:    "AWS::EC2::SecurityGroup",
+		suffix:     "SecurityGroup",
+		identifier: "Id",
+		taggable:   true,
+		build:      buildSecurityGroup,
+		output: func(logicalID string) interface{} {
+			return map[string]interface{}{"Fn::GetAtt": []string{logicalID, "GroupId"}}
+		},
+	},
+	"aws_vpc_security_group_ingress_rule": {
+		cfnType:    "AWS::EC2::SecurityGroupIngress",
+		suffix:     "Ingress",
+		identifier: "Id",
+		build:      buildSecurityGroupRule(true),
+		identifierValue: func(resource generation.MappedResource) string {
+			return resource.ImportID
+		},
+	},
+	"aws_vpc_security_group_egress_rule": {
+		cfnType:    "AWS::EC2::SecurityGroupEgress",
+		suffix:     "Egress",
+		identifier: "Id",
+		build:      buildSecurityGroupRule(false),
+		identifierValue: func(resource generation.MappedResource) string {
+			return resource.ImportID
+		},
+	},
+	"aws_instance": {
+		cfnType:    "AWS::EC2::Instance",
+		suffix:     "Instance",
+		identifier: "InstanceId",
+		taggable:   true,
+		build:      buildInstance,
+		output:     refOutput,
+	},
+	"aws_internet_gateway": {
+		cfnType:    "AWS::EC2::InternetGateway",
+		suffix:     "InternetGateway",
+		identifier: "InternetGatewayId",
+		taggable:   true,
+		build: func(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+			return map[string]interface{}{}, nil
+		},
+		output: refOutput,
+	},
+	"aws_route_table": {
+		cfnType:    "AWS::EC2::RouteTable",
+		suffix:     "RouteTable",
+		identifier: "RouteTableId",
+		taggable:   true,
+		build:      buildRouteTable,
+		output:     refOutput,
+	},
+	"aws_ebs_volume": {
+		cfnType:    "AWS::EC2::Volume",
+		suffix:     "Volume",
+		identifier: "VolumeId",
+		taggable:   true,
+		build:      buildVolume,
+		output:     refOutput,
+	},
+	"aws_key_pair": {
+		cfnType:    "AWS::EC2::KeyPair",
+		suffix:     "KeyPair",
+		identifier: "KeyName",
+		taggable:   true,
+		build:      buildKeyPair,
+		identifierValue: func(resource generation.MappedResource) string {
+			return resource.ImportID
+		},
+	},
+}
+
+// refOutput exports the value returned by Ref, which is the physical ID
+func refOutput(logicalID string) interface{} {
+	return map[string]interface{}{"Ref": logicalID}
+}
+
+// buildVPC converts an aws_vpc to AWS::EC2::VPC properties
+func buildVPC(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+	metadata := resource.OriginalResource.Metadata
+
+	return map[string]interface{}{
+		"CidrBlock":          getStringFromMetadata(metadata, "cidr_block", "10.0.0.0/16"),
+		"EnableDnsHostnames": getBoolFromMetadata(metadata, "enable_dns_hostnames", true),
+		"EnableDnsSupport":   getBoolFromMetadata(metadata, "enable_dns_support", true),
+	}, nil
+}
+
+// buildSubnet converts an aws_subnet to AWS::EC2::Subnet properties
+func buildSubnet(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+	metadata := resource.OriginalResource.Metadata
+
+	vpcId := getStringFromMetadata(metadata, "vpc_id", "")
+	if vpcId == "" {
+		return nil, fmt.Errorf("subnet has no VPC")
+	}
+
+	properties := map[string]interface{}{
+		"VpcId":               ctx.ref(vpcId, "vpc"),
+		"CidrBlock":           getStringFromMetadata(metadata, "cidr_block", "10.0.1.0/24"),
+		"MapPublicIpOnLaunch": getBoolFromMetadata(metadata, "map_public_ip_on_launch", false),
+	}
+	if zone := resource.OriginalResource.Zone; zone != "" {
+		properties["AvailabilityZone"] = zone
+	}
+
+	return properties, nil
+}
+
+// buildSecurityGroup converts an aws_security_group to AWS::EC2::SecurityGroup
+// properties, with inline rules unless they were discovered as rule resources
+func buildSecurityGroup(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+	metadata := resource.OriginalResource.Metadata
+
+	properties := map[string]interface{}{
+		"GroupName":        resource.OriginalResource.Name,
+		"GroupDescription": getStringFromMetadata(metadata, "description", "Security group managed by Chimera"),
+	}
+	if vpcId := getStringFromMetadata(metadata, "vpc_id", ""); vpcId != "" {
+		properties["VpcId"] = ctx.ref(vpcId, "vpc")
+	}
+
+	if !ctx.builder.groupsWithRules[resource.OriginalResource.ID] {
+		if ingress := buildInlineRules(ctx, getMapSliceFromMetadata(metadata, "ingress_rules"), true); len(ingress) > 0 {
+			properties["SecurityGroupIngress"] = ingress
+		}
+		if egress := buildInlineRules(ctx, getMapSliceFromMetadata(metadata, "egress_rules"), false); len(egress) > 0 {
+			properties["SecurityGroupEgress"] = egress
+		}
+	}
+
+	return properties, nil
+}
+
+// buildInlineRules expands discovered IP permissions into inline rules, one
+// rule per source so every description is kept
+func buildInlineRules(ctx *resourceContext, permissions []map[string]interface{}, ingress bool) []map[string]interface{} {
+	var rules []map[string]interface{}
+
+	for _, perm := range permissions {
+		newRule := func(description string) map[string]interface{} {
+			rule := protocolProperties(getStringFromMetadata(perm, "protocol", "-1"),
+				getIntFromMetadata(perm, "from_port", 0), getIntFromMetadata(perm, "to_port", 0))
+			if description != "" {
+				rule["Description"] = description
+			}
+			return rule
+		}
+
+		for _, r := range getMapSliceFromMetadata(perm, "cidr_blocks") {
+			rule := newRule(getStringFromMetadata(r, "description", ""))
+			rule["CidrIp"] = getStringFromMetadata(r, "cidr", "")
+			rules = append(rules, rule)
+		}
+
+		for _, r := range getMapSliceFromMetadata(perm, "ipv6_cidr_blocks") {
+			rule := newRule(getStringFromMetadata(r, "description", ""))
+			rule["CidrIpv6"] = getStringFromMetadata(r, "cidr", "")
+			rules = append(rules, rule)
+		}
+
+		for _, p := range getMapSliceFromMetadata(perm, "prefix_list_ids") {
+			rule := newRule(getStringFromMetadata(p, "description", ""))
+			rule[directional(ingress, "PrefixListId")] = getStringFromMetadata(p, "prefix_list_id", "")
+			rules = append(rules, rule)
+		}
+
+		for _, g := range getMapSliceFromMetadata(perm, "security_groups") {
+			rule := newRule(getStringFromMetadata(g, "description", ""))
+			rule[directional(ingress, "SecurityGroupId")] = ctx.securityGroupRef(getStringFromMetadata(g, "group_id", ""))
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// buildSecurityGroupRule returns a builder converting a standalone security
+// group rule to AWS::EC2::SecurityGroupIngress or SecurityGroupEgress properties
+func buildSecurityGroupRule(ingress bool) func(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+	return func(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+		metadata := resource.OriginalResource.Metadata
+
+		groupId := getStringFromMetadata(metadata, "security_group_id", "")
+		if groupId == "" {
+			return nil, fmt.Errorf("security group rule has no security group")
+		}
+
+		properties := protocolProperties(getStringFromMetadata(metadata, "ip_protocol", "-1"),
+			getIntFromMetadata(metadata, "from_port", 0), getIntFromMetadata(metadata, "to_port", 0))
+		properties["GroupId"] = ctx.securityGroupRef(groupId)
+
+		if cidr := getStringFromMetadata(metadata, "cidr_ipv4", ""); cidr != "" {
+			properties["CidrIp"] = cidr
+		}
+		if cidr := getStringFromMetadata(metadata, "cidr_ipv6", ""); cidr != "" {
+			properties["CidrIpv6"] = cidr
+		}
+		if prefixList := getStringFromMetadata(metadata, "prefix_list_id", ""); prefixList != "" {
+			properties[directional(ingress, "PrefixListId")] = prefixList
+		}
+		if referenced := getStringFromMetadata(metadata, "referenced_security_group_id", ""); referenced != "" {
+			properties[directional(ingress, "SecurityGroupId")] = ctx.securityGroupRef(referenced)
+		}
+		if description := getStringFromMetadata(metadata, "description", ""); description != "" {
+			properties["Description"] = description
+		}
+
+		return properties, nil
+	}
+}
+
+// protocolProperties returns the protocol and port range of a rule. Ports are
+// omitted when all protocols are permitted.
+func protocolProperties(protocol string, fromPort, toPort int) map[string]interface{} {
+	properties := map[string]interface{}{"IpProtocol": protocol}
+	if protocol != "-1" {
+		properties["FromPort"] = fromPort
+		properties["ToPort"] = toPort
+	}
+	return properties
+}
+
+// directional prefixes a rule peer property with Source for ingress rules
+// and Destination for egress rules
+func directional(ingress bool, property string) string {
+	if ingress {
+		return "Source" + property
+	}
+	return "Destination" + property
+}
+
+// buildInstance converts an aws_instance to AWS::EC2::Instance properties
+func buildInstance(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+	metadata := resource.OriginalResource.Metadata
+
+	imageId := getStringFromMetadata(metadata, "image_id", "")
+	if imageId == "" {
+		return nil, fmt.Errorf("instance has no image ID")
+	}
+
+	properties := map[string]interface{}{
+		"ImageId":      imageId,
+		"InstanceType": getStringFromMetadata(metadata, "instance_type", "t3.micro"),
+	}
+
+	if subnetId := getStringFromMetadata(metadata, "subnet_id", ""); subnetId != "" {
+		properties["SubnetId"] = ctx.ref(subnetId, "subnet")
+	}
+
+	if groupIds := getStringSliceFromMetadata(metadata, "security_group_ids"); len(groupIds) > 0 {
+		groups := make([]interface{}, 0, len(groupIds))
+		for _, groupId := range groupIds {
+			groups = append(groups, ctx.securityGroupRef(groupId))
+		}
+		properties["SecurityGroupIds"] = groups
+	}
+
+	if keyName := getStringFromMetadata(metadata, "key_name", ""); keyName != "" {
+		properties["KeyName"] = keyName
+	}
+	if profile := getStringFromMetadata(metadata, "iam_instance_profile", ""); profile != "" {
+		properties["IamInstanceProfile"] = profile
+	}
+	if _, exists := metadata["ebs_optimized"]; exists {
+		properties["EbsOptimized"] = getBoolFromMetadata(metadata, "ebs_optimized", false)
+	}
+	if _, exists := metadata["monitoring"]; exists {
+		properties["Monitoring"] = getBoolFromMetadata(metadata, "monitoring", false)
+	}
+
+	var mappings []map[string]interface{}
+	for _, device := range getMapSliceFromMetadata(metadata, "block_devices") {
+		deviceName := getStringFromMetadata(device, "device_name", "")
+		if deviceName == "" {
+			continue
+		}
+		mappings = append(mappings, map[string]interface{}{
+			"DeviceName": deviceName,
+			"Ebs":        buildEbs(device),
+		})
+	}
+	if len(mappings) > 0 {
+		properties["BlockDeviceMappings"] = mappings
+	}
+
+	// User data is never recorded by discovery, so it must be supplied
+	if userDataHash := getStringFromMetadata(metadata, "user_data_hash", ""); userDataHash != "" {
+		userData := ctx.secret("UserData", fmt.Sprintf("User data for instance %s (discovered SHA-256: %s)", resource.OriginalResource.ID, userDataHash))
+		properties["UserData"] = map[string]interface{}{"Fn::Base64": userData}
+	}
+
+	return properties, nil
+}
+
+// buildEbs converts discovered block device metadata to an Ebs property
+func buildEbs(device map[string]interface{}) map[string]interface{} {
+	ebs := map[string]interface{}{
+		"DeleteOnTermination": getBoolFromMetadata(device, "delete_on_termination", true),
+	}
+
+	if size := getIntFromMetadata(device, "volume_size", 0); size > 0 {
+		ebs["VolumeSize"] = size
+	}
+	if volumeType := getStringFromMetadata(device, "volume_type", ""); volumeType != "" {
+		ebs["VolumeType"] = volumeType
+	}
+	if _, exists := device["encrypted"]; exists {
+		ebs["Encrypted"] = getBoolFromMetadata(device, "encrypted", false)
+	}
+	if iops := getIntFromMetadata(device, "iops", 0); iops > 0 {
+		ebs["Iops"] = iops
+	}
+	if throughput := getIntFromMetadata(device, "throughput", 0); throughput > 0 {
+		ebs["Throughput"] = throughput
+	}
+	if kmsKeyId := getStringFromMetadata(device, "kms_key_id", ""); kmsKeyId != "" {
+		ebs["KmsKeyId"] = kmsKeyId
+	}
+	if snapshotId := getStringFromMetadata(device, "snapshot_id", ""); snapshotId != "" {
+		ebs["SnapshotId"] = snapshotId
+	}
+
+	return ebs
+}
+
+// buildRouteTable converts an aws_route_table to AWS::EC2::RouteTable properties
+func buildRouteTable(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+	vpcId := getStringFromMetadata(resource.OriginalResource.Metadata, "vpc_id", "")
+	if vpcId == "" {
+		return nil, fmt.Errorf("route table has no VPC")
+	}
+
+	return map[string]interface{}{
+		"VpcId": ctx.ref(vpcId, "vpc"),
+	}, nil
+}
+
+// buildVolume converts an aws_ebs_volume to AWS::EC2::Volume properties
+func buildVolume(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+	metadata := resource.OriginalResource.Metadata
+
+	properties := map[string]interface{}{
+		"AvailabilityZone": resource.OriginalResource.Zone,
+		"Size":             getIntFromMetadata(metadata, "size", 20),
+		"VolumeType":       getStringFromMetadata(metadata, "volume_type", "gp3"),
+	}
+	if getBoolFromMetadata(metadata, "encrypted", false) {
+		properties["Encrypted"] = true
+	}
+
+	return properties, nil
+}
+
+// buildKeyPair converts an aws_key_pair to AWS::EC2::KeyPair properties. The
+// public key cannot be discovered, so it is supplied as a parameter.
+func buildKeyPair(ctx *resourceContext, resource generation.MappedResource) (map[string]interface{}, error) {
+	if resource.ImportID == "" {
+		return nil, fmt.Errorf("key pair has no key name")
+	}
+
+	return map[string]interface{}{
+		"KeyName":           resource.ImportID,
+		"PublicKeyMaterial": ctx.secret("PublicKey", fmt.Sprintf("Public key material for key pair %s", resource.ImportID)),
+	}, nil
+}
+
+// Metadata helper functions
+func getStringFromMetadata(metadata map[string]interface{}, key, defaultValue string) string {
+	if value, exists := metadata[key]; exists {
+		if str, ok := value.(string); ok {
+			return str
+		}
+	}
+	return defaultValue
+}
+
+func getBoolFromMetadata(metadata map[string]interface{}, key string, defaultValue bool) bool {
+	if value, exists := metadata[key]; exists {
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getIntFromMetadata(metadata map[string]interface{}, key string, defaultValue int) int {
+	if value, exists := metadata[key]; exists {
+		switch v := value.(type) {
+		case int:
+			return v
+		case int32:
+			return int(v)
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+	}
+	return defaultValue
+}
+
+// getMapSliceFromMetadata reads a list of objects, accepting both the
+// in-memory form and the []interface{} form produced by a JSON round trip
+func getMapSliceFromMetadata(metadata map[string]interface{}, key string) []map[string]interface{} {
+	switch value := metadata[key].(type) {
+	case []map[string]interface{}:
+		return value
+	case []interface{}:
+		result := make([]map[string]interface{}, 0, len(value))
+		for _, item := range value {
+			if entry, ok := item.(map[string]interface{}); ok {
+				result = append(result, entry)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// getStringSliceFromMetadata reads a list of strings, accepting both the
+// in-memory form and the []interface{} form produced by a JSON round trip
+func getStringSliceFromMetadata(metadata map[string]interface{}, key string) []string {
+	switch value := metadata[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
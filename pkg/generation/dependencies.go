@@ -0,0 +1,406 @@
+package generation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+)
+
+// Dependency edge types
+const (
+	DependencyDeclared  = "declared"  // listed in the Dependencies of the resource
+	DependencyReference = "reference" // metadata holds the ID or URL of the dependency
+	DependencyName      = "name"      // metadata holds the name of the dependency
+	DependencyParent    = "parent"    // the dependency contains the resource
+)
+
+// backReferenceKeys are metadata keys pointing from a resource to the
+// resource it is attached to, such as the VM of an Azure network interface
+// or the instances an EBS volume is attached to. The attached resource
+// depends on this one, so they are not dependencies.
+var backReferenceKeys = map[string]bool{
+	"virtual_machine_id":   true,
+	"attached_instance_id": true,
+}
+
+// DependencyCycleError is returned when resources depend on each other, such
+// as two security groups whose rules reference one another
+type DependencyCycleError struct {
+	// Cycles holds the resource IDs of each cycle, sorted
+	Cycles [][]string
+}
+
+// Error implements the error interface
+func (e *DependencyCycleError) Error() string {
+	cycles := make([]string, 0, len(e.Cycles))
+	for _, cycle := range e.Cycles {
+		cycles = append(cycles, strings.Join(cycle, " <-> "))
+	}
+	return fmt.Sprintf("dependency cycle detected (%d cycles): %s", len(e.Cycles), strings.Join(cycles, "; "))
+}
+
+// MetadataDependencyAnalyzer builds a dependency graph across providers from
+// the dependencies recorded by discovery and from discovery metadata: AWS IDs
+// (vpc_id, subnet_id, security group rules), Azure resource IDs and their
+// parent resources, and GCP network and subnetwork names and URLs
+type MetadataDependencyAnalyzer struct{}
+
+// NewMetadataDependencyAnalyzer creates a new metadata dependency analyzer
+func NewMetadataDependencyAnalyzer() *MetadataDependencyAnalyzer {
+	return &MetadataDependencyAnalyzer{}
+}
+
+// AnalyzeDependencies returns the IDs each resource depends on, keyed by
+// resource ID; every resource has an entry
+func (a *MetadataDependencyAnalyzer) AnalyzeDependencies(resources []discovery.Resource) (map[string][]string, error) {
+	edges := newResourceIndex(resources).edges()
+
+	dependencies := make(map[string][]string, len(resources))
+	for _, resource := range resources {
+		dependencies[resource.ID] = []string{}
+	}
+	for _, edge := range edges {
+		dependencies[edge.From] = append(dependencies[edge.From], edge.To)
+	}
+	return dependencies, nil
+}
+
+// GetDependencyGraph returns the dependency graph, with nodes ordered by
+// level. Resources in a cycle share the level of the cycle.
+func (a *MetadataDependencyAnalyzer) GetDependencyGraph(resources []discovery.Resource) (*DependencyGraph, error) {
+	edges := newResourceIndex(resources).edges()
+
+	dependencies := make(map[string][]string, len(resources))
+	for _, edge := range edges {
+		dependencies[edge.From] = append(dependencies[edge.From], edge.To)
+	}
+	levels := dependencyLevels(dependencies, resourceIDs(resources))
+
+	graph := &DependencyGraph{
+		Nodes: make([]DependencyNode, 0, len(resources)),
+		Edges: edges,
+	}
+	seen := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		if seen[resource.ID] {
+			continue
+		}
+		seen[resource.ID] = true
+		graph.Nodes = append(graph.Nodes, DependencyNode{
+			ID:           resource.ID,
+			ResourceType: resource.Type,
+			Provider:     resource.Provider,
+			Name:         resource.Name,
+			Level:        levels[resource.ID],
+		})
+	}
+	sort.SliceStable(graph.Nodes, func(i, j int) bool {
+		if graph.Nodes[i].Level != graph.Nodes[j].Level {
+			return graph.Nodes[i].Level < graph.Nodes[j].Level
+		}
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+
+	return graph, nil
+}
+
+// ValidateDependencies checks that every dependency is a known resource and
+// returns a *DependencyCycleError when resources depend on each other
+func (a *MetadataDependencyAnalyzer) ValidateDependencies(dependencies map[string][]string) error {
+	for _, id := range sortedDependencyKeys(dependencies) {
+		for _, dependency := range dependencies[id] {
+			if _, exists := dependencies[dependency]; !exists {
+				return fmt.Errorf("resource %s depends on unknown resource %s", id, dependency)
+			}
+		}
+	}
+
+	if cycles := dependencyCycles(dependencies); len(cycles) > 0 {
+		return &DependencyCycleError{Cycles: cycles}
+	}
+	return nil
+}
+
+// resourceIndex looks up resources by the identifiers metadata refers to
+type resourceIndex struct {
+	resources []discovery.Resource
+	byID      map[string]string
+	byName    map[string]string
+}
+
+// newResourceIndex indexes resources by normalized ID and, for GCP networks
+// and subnetworks, by name
+func newResourceIndex(resources []discovery.Resource) *resourceIndex {
+	index := &resourceIndex{
+		resources: resources,
+		byID:      make(map[string]string, len(resources)),
+		byName:    make(map[string]string),
+	}
+	for _, resource := range resources {
+		index.byID[normalizeReference(resource.ID)] = resource.ID
+		switch resource.Type {
+		case "gcp_compute_network":
+			index.byName[gcpNameKey(resource.Type, resource.Project, "", resource.Name)] = resource.ID
+		case "gcp_compute_subnetwork":
+			index.byName[gcpNameKey(resource.Type, resource.Project, resource.Region, resource.Name)] = resource.ID
+		}
+	}
+	return index
+}
+
+// edges returns the dependency edges of every resource, sorted and without
+// duplicates or self references
+func (x *resourceIndex) edges() []DependencyEdge {
+	var edges []DependencyEdge
+	seen := make(map[[2]string]bool)
+	add := func(from, to, edgeType, attribute string) {
+		if from == to || seen[[2]string{from, to}] {
+			return
+		}
+		seen[[2]string{from, to}] = true
+		edges = append(edges, DependencyEdge{
+			From:      from,
+			To:        to,
+			Type:      edgeType,
+			Required:  true,
+			Attribute: attribute,
+		})
+	}
+
+	for _, resource := range x.resources {
+		for _, dependency := range resource.Dependencies {
+			if id, exists := x.byID[normalizeReference(dependency)]; exists {
+				add(resource.ID, id, DependencyDeclared, "")
+			}
+		}
+
+		if parent := x.azureParent(resource.ID); parent != "" {
+			add(resource.ID, parent, DependencyParent, "")
+		}
+
+		for _, key := range sortedMetadataKeys(resource.Metadata) {
+			x.walk(resource, key, resource.Metadata[key], add)
+		}
+		if resource.Provider == discovery.GCP {
+			x.gcpNames(resource, "", resource.Metadata, add)
+		}
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// walk records edges for every string in a metadata value that identifies
+// another resource
+func (x *resourceIndex) walk(resource discovery.Resource, path string, value interface{}, add func(from, to, edgeType, attribute string)) {
+	if backReferenceKeys[path[strings.LastIndex(path, ".")+1:]] {
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		if id, exists := x.byID[normalizeReference(v)]; exists {
+			add(resource.ID, id, DependencyReference, path)
+		}
+	case []string:
+		for _, item := range v {
+			x.walk(resource, path, item, add)
+		}
+	case []interface{}:
+		for _, item := range v {
+			x.walk(resource, path, item, add)
+		}
+	case []map[string]interface{}:
+		for _, item := range v {
+			x.walk(resource, path, item, add)
+		}
+	case map[string]interface{}:
+		for _, key := range sortedMetadataKeys(v) {
+			x.walk(resource, path+"."+key, v[key], add)
+		}
+		if resource.Provider == discovery.GCP {
+			x.gcpNames(resource, path+".", v, add)
+		}
+	}
+}
+
+// gcpNames records edges for the network and subnetwork names GCP discovery
+// stores in place of URLs
+func (x *resourceIndex) gcpNames(resource discovery.Resource, prefix string, fields map[string]interface{}, add func(from, to, edgeType, attribute string)) {
+	if network, ok := fields["network"].(string); ok {
+		if id, exists := x.byName[gcpNameKey("gcp_compute_network", resource.Project, "", network)]; exists {
+			add(resource.ID, id, DependencyName, prefix+"network")
+		}
+	}
+	if subnetwork, ok := fields["subnetwork"].(string); ok {
+		region, _ := fields["subnetwork_region"].(string)
+		if region == "" {
+			region = resource.Region
+		}
+		if id, exists := x.byName[gcpNameKey("gcp_compute_subnetwork", resource.Project, region, subnetwork)]; exists {
+			add(resource.ID, id, DependencyName, prefix+"subnetwork")
+		}
+	}
+}
+
+// azureParent returns the closest discovered resource containing an Azure
+// resource, such as the virtual network of a subnet or the resource group of
+// a virtual network
+func (x *resourceIndex) azureParent(id string) string {
+	if !strings.HasPrefix(strings.ToLower(id), "/subscriptions/") {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(id, "/"), "/")
+	for end := len(segments) - 1; end > 0; end-- {
+		if parent, exists := x.byID[normalizeReference("/"+strings.Join(segments[:end], "/"))]; exists {
+			return parent
+		}
+	}
+	return ""
+}
+
+// normalizeReference normalizes a resource identifier for lookup: Azure
+// resource IDs are case-insensitive and GCP URLs are reduced to the
+// projects/... path discovery uses as ID
+func normalizeReference(reference string) string {
+	reference = strings.ToLower(strings.TrimSpace(reference))
+	if strings.HasPrefix(reference, "https://") {
+		if i := strings.Index(reference, "/projects/"); i >= 0 {
+			reference = reference[i+1:]
+		}
+	}
+	return reference
+}
+
+// gcpNameKey returns the name index key of a GCP network or subnetwork
+func gcpNameKey(resourceType, project, region, name string) string {
+	return strings.Join([]string{resourceType, project, region, name}, "/")
+}
+
+// resourceIDs returns the IDs of resources
+func resourceIDs(resources []discovery.Resource) []string {
+	ids := make([]string, len(resources))
+	for i, resource := range resources {
+		ids[i] = resource.ID
+	}
+	return ids
+}
+
+// sortedMetadataKeys returns the keys of a metadata map in sorted order
+func sortedMetadataKeys(metadata map[string]interface{}) []string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedDependencyKeys returns the keys of a dependency map in sorted order
+func sortedDependencyKeys(dependencies map[string][]string) []string {
+	keys := make([]string, 0, len(dependencies))
+	for key := range dependencies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stronglyConnected returns the strongly connected components of the
+// dependency graph using Tarjan's algorithm, dependencies before dependents
+func stronglyConnected(dependencies map[string][]string, ids []string) [][]string {
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = len(index)
+		lowLink[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, dependency := range dependencies[id] {
+			if _, visited := index[dependency]; !visited {
+				visit(dependency)
+				lowLink[id] = min(lowLink[id], lowLink[dependency])
+			} else if onStack[dependency] {
+				lowLink[id] = min(lowLink[id], index[dependency])
+			}
+		}
+
+		if lowLink[id] == index[id] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, id := range ids {
+		if _, visited := index[id]; !visited {
+			visit(id)
+		}
+	}
+	return components
+}
+
+// dependencyCycles returns the cycles of the dependency graph
+func dependencyCycles(dependencies map[string][]string) [][]string {
+	var cycles [][]string
+	for _, component := range stronglyConnected(dependencies, sortedDependencyKeys(dependencies)) {
+		if len(component) > 1 {
+			cycles = append(cycles, component)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+// dependencyLevels assigns each resource its level: 0 without dependencies,
+// otherwise one more than its deepest dependency. Resources in a cycle share
+// a level.
+func dependencyLevels(dependencies map[string][]string, ids []string) map[string]int {
+	levels := make(map[string]int, len(ids))
+	// Components are produced dependencies first, so each dependency outside
+	// the component already has its level
+	for _, component := range stronglyConnected(dependencies, ids) {
+		members := make(map[string]bool, len(component))
+		for _, id := range component {
+			members[id] = true
+		}
+
+		level := 0
+		for _, id := range component {
+			for _, dependency := range dependencies[id] {
+				if !members[dependency] {
+					level = max(level, levels[dependency]+1)
+				}
+			}
+		}
+		for _, id := range component {
+			levels[id] = level
+		}
+	}
+	return levels
+}
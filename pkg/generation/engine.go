@@ -3,9 +3,11 @@ package generation
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,8 +19,7 @@ import (
 // Engine implements the GenerationEngine interface
 type Engine struct {
 	mappers      map[discovery.CloudProvider]ResourceMapper
-	generators   map[IaCFormat]TerraformGenerator
-	organizer    FileOrganizer
+	generators   map[IaCFormat]FormatGenerator
 	validator    Validator
 	analyzer     DependencyAnalyzer
 	templateEng  TemplateEngine
@@ -53,7 +54,8 @@ func NewEngine(config EngineConfig) *Engine {
 
 	return &Engine{
 		mappers:     make(map[discovery.CloudProvider]ResourceMapper),
-		generators:  make(map[IaCFormat]TerraformGenerator),
+		generators:  make(map[IaCFormat]FormatGenerator),
+		analyzer:    NewMetadataDependencyAnalyzer(),
 		logger:      logrus.New(),
 		config:      config,
 	}
@@ -66,22 +68,18 @@ func (e *Engine) RegisterMapper(mapper ResourceMapper) {
 }
 
 // RegisterGenerator registers an IaC generator for a specific format
-func (e *Engine) RegisterGenerator(format IaCFormat, generator TerraformGenerator) {
+func (e *Engine) RegisterGenerator(format IaCFormat, generator FormatGenerator) {
 	e.generators[format] = generator
 	e.logger.Infof("Registered generator for format: %s", format)
 }
 
-// SetOrganizer sets the file organizer
-func (e *Engine) SetOrganizer(organizer FileOrganizer) {
-	e.organizer = organizer
-}
-
 // SetValidator sets the validator
 func (e *Engine) SetValidator(validator Validator) {
 	e.validator = validator
 }
 
-// SetDependencyAnalyzer sets the dependency analyzer
+// SetDependencyAnalyzer replaces the default metadata dependency analyzer;
+// nil disables dependency analysis
 func (e *Engine) SetDependencyAnalyzer(analyzer DependencyAnalyzer) {
 	e.analyzer = analyzer
 }
@@ -105,7 +103,7 @@ func (e *Engine) Generate(ctx context.Context, opts GenerationOptions) (*Generat
 	if opts.Format == "" {
 		opts.Format = e.config.DefaultFormat
 	}
-	if opts.Organization == "" {
+	if opts.Organization = ResolvePattern(opts); opts.Organization == "" {
 		opts.Organization = e.config.DefaultOrg
 	}
 	if opts.Timestamp == "" {
@@ -148,25 +146,36 @@ func (e *Engine) Generate(ctx context.Context, opts GenerationOptions) (*Generat
 
 	// Analyze dependencies if analyzer is available
 	if e.analyzer != nil {
-		if err := e.analyzeDependencies(ctx, mappedResources); err != nil {
+		dependencyWarnings, err := e.analyzeDependencies(ctx, mappedResources)
+		if err != nil {
 			result.Warnings = append(result.Warnings, GenerationWarning{
 				Message: fmt.Sprintf("dependency analysis failed: %v", err),
 				Type:    WarningTypeBestPractice,
 			})
 		}
+		result.Warnings = append(result.Warnings, dependencyWarnings...)
 	}
 
-	// Organize resources into files
-	organizedFiles, err := e.organizeResources(mappedResources, opts)
-	if err != nil {
-		return result, fmt.Errorf("failed to organize resources: %w", err)
-	}
+	// Collect the provider configurations needed
+	opts.Providers = e.collectProviderConfigs(mappedResources)
 
 	// Generate files
-	genFiles, genErrors, genWarnings := e.generateFiles(ctx, organizedFiles, generator, opts)
+	var genFiles []GeneratedFile
+	var err error
+	if reporter, ok := generator.(WarningReporter); ok {
+		var generationWarnings []GenerationWarning
+		genFiles, generationWarnings, err = reporter.GenerateWithWarnings(mappedResources, opts)
+		result.Warnings = append(result.Warnings, generationWarnings...)
+	} else {
+		genFiles, err = generator.Generate(mappedResources, opts)
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to generate %s: %w", opts.Format, err)
+	}
+	for i := range genFiles {
+		genFiles[i].Checksum = e.calculateChecksum(genFiles[i].Content)
+	}
 	result.Files = append(result.Files, genFiles...)
-	result.Errors = append(result.Errors, genErrors...)
-	result.Warnings = append(result.Warnings, genWarnings...)
 
 	// Write files to disk if output path specified
 	if opts.OutputPath != "" {
@@ -259,6 +268,22 @@ func (e *Engine) mapResources(ctx context.Context, resources []discovery.Resourc
 	var errors []GenerationError
 	var warnings []GenerationWarning
 
+	// Let mappers split resources before the full set is indexed
+	for _, mapper := range e.mappers {
+		if expander, ok := mapper.(ResourceExpander); ok {
+			var expandWarnings []GenerationWarning
+			resources, expandWarnings = expander.ExpandResources(resources)
+			warnings = append(warnings, expandWarnings...)
+		}
+	}
+
+	// Give mappers the full resource set so references can be resolved
+	for _, mapper := range e.mappers {
+		if setMapper, ok := mapper.(ResourceSetMapper); ok {
+			setMapper.SetResources(resources)
+		}
+	}
+
 	for _, resource := range resources {
 		mapper, exists := e.mappers[resource.Provider]
 		if !exists {
@@ -290,288 +315,220 @@ func (e *Engine) mapResources(ctx context.Context, resources []discovery.Resourc
 	return mapped, errors, warnings
 }
 
-// analyzeDependencies analyzes dependencies between resources
-func (e *Engine) analyzeDependencies(ctx context.Context, resources []MappedResource) error {
+// analyzeDependencies analyzes dependencies between resources, adding the
+// dependencies that are neither declared by the mapper, referenced in the
+// configuration nor implied by another dependency. Cycles are reported as
+// warnings and their edges are left out.
+func (e *Engine) analyzeDependencies(ctx context.Context, resources []MappedResource) ([]GenerationWarning, error) {
 	// Convert MappedResource back to discovery.Resource for analysis
 	discoveryResources := make([]discovery.Resource, len(resources))
+	addresses := make(map[string]string, len(resources))
 	for i, resource := range resources {
 		discoveryResources[i] = resource.OriginalResource
+		addresses[resource.OriginalResource.ID] = resource.ResourceType + "." + resource.ResourceName
 	}
 
 	dependencies, err := e.analyzer.AnalyzeDependencies(discoveryResources)
 	if err != nil {
-		return fmt.Errorf("dependency analysis failed: %w", err)
+		return nil, fmt.Errorf("dependency analysis failed: %w", err)
 	}
 
-	// Update resources with dependency information
-	for i := range resources {
-		resourceID := resources[i].OriginalResource.ID
-		if deps, exists := dependencies[resourceID]; exists {
-			resources[i].Dependencies = deps
+	var warnings []GenerationWarning
+	cycleOf := make(map[string]int)
+	if err := e.analyzer.ValidateDependencies(dependencies); err != nil {
+		var cycleErr *DependencyCycleError
+		if !errors.As(err, &cycleErr) {
+			return nil, err
 		}
-	}
 
-	return nil
-}
+		for i, cycle := range cycleErr.Cycles {
+			members := make([]string, len(cycle))
+			for j, id := range cycle {
+				cycleOf[id] = i + 1
+				members[j] = id
+				if address, exists := addresses[id]; exists {
+					members[j] = address
+				}
+			}
 
-// organizeResources organizes resources into file structure
-func (e *Engine) organizeResources(resources []MappedResource, opts GenerationOptions) (map[string][]MappedResource, error) {
-	if e.organizer == nil {
-		// Default flat organization
-		return map[string][]MappedResource{
-			"main.tf": resources,
-		}, nil
-	}
-
-	// Convert MappedResource to TerraformResource for organizer interface
-	terraformResources := make([]TerraformResource, len(resources))
-	for i, mapped := range resources {
-		terraformResources[i] = TerraformResource{
-			Type:         mapped.ResourceType,
-			Name:         mapped.ResourceName,
-			Provider:     mapped.OriginalResource.Provider,
-			Config:       mapped.Configuration,
-			Dependencies: mapped.Dependencies,
-			Outputs:      make(map[string]string), // Convert from map[string]Output
-			Variables:    mapped.Variables,
-			SourceInfo: SourceInfo{
-				OriginalID:       mapped.OriginalResource.ID,
-				OriginalType:     mapped.OriginalResource.Type,
-				OriginalProvider: mapped.OriginalResource.Provider,
-				OriginalRegion:   mapped.OriginalResource.Region,
-				DiscoveredAt:     time.Now(), // Use current time for now
-				Metadata:         mapped.OriginalResource.Metadata,
-				Tags:             mapped.OriginalResource.Tags,
-			},
-		}
-		// Convert outputs
-		for name, output := range mapped.Outputs {
-			terraformResources[i].Outputs[name] = output.Value
-		}
-	}
-
-	organization := opts.Organization
-	if organization == "" {
-		organization = e.config.DefaultOrg
-	}
-
-	organizedTerraform, err := e.organizer.OrganizeFiles(terraformResources, organization)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert back to MappedResource
-	result := make(map[string][]MappedResource)
-	for path, tfResources := range organizedTerraform {
-		mappedResources := make([]MappedResource, len(tfResources))
-		for i, tfRes := range tfResources {
-			// Find the original mapped resource
-			for _, orig := range resources {
-				if orig.ResourceName == tfRes.Name && orig.ResourceType == tfRes.Type {
-					mappedResources[i] = orig
+			warning := GenerationWarning{
+				ResourceID: cycle[0],
+				Message:    fmt.Sprintf("dependency cycle between %s", strings.Join(members, ", ")),
+				Type:       WarningTypeManualAction,
+				Suggestion: "Resources in a cycle cannot be created in one apply; move the references into separate resources, such as security group rules",
+			}
+			for _, resource := range discoveryResources {
+				if resource.ID == cycle[0] {
+					warning.ResourceType = resource.Type
+					warning.Provider = resource.Provider
 					break
 				}
 			}
+			warnings = append(warnings, warning)
 		}
-		result[path] = mappedResources
 	}
 
-	return result, nil
-}
-
-// generateFiles generates the actual IaC files
-func (e *Engine) generateFiles(ctx context.Context, organizedFiles map[string][]MappedResource, generator TerraformGenerator, opts GenerationOptions) ([]GeneratedFile, []GenerationError, []GenerationWarning) {
-	var files []GeneratedFile
-	var errors []GenerationError
-	var warnings []GenerationWarning
-
-	// Collect all providers needed
-	providerConfigs := e.collectProviderConfigs(organizedFiles, opts)
-
-	// Generate provider configuration files
-	if opts.IncludeProvider && len(providerConfigs) > 0 {
-		// Generate versions.tf
-		versionsContent, err := generator.GenerateVersions(providerConfigs)
-		if err != nil {
-			errors = append(errors, GenerationError{
-				Message:  fmt.Sprintf("failed to generate versions.tf: %v", err),
-				Severity: ErrorSeverityMedium,
-			})
-		} else {
-			files = append(files, GeneratedFile{
-				Path:    "versions.tf",
-				Content: versionsContent,
-				Type:    FileTypeVersions,
-				Format:  opts.Format,
-				Size:    int64(len(versionsContent)),
-			})
-		}
-
-		// Generate providers.tf
-		for _, config := range providerConfigs {
-			providerContent, err := generator.GenerateProvider(config)
-			if err != nil {
-				errors = append(errors, GenerationError{
-					Message:  fmt.Sprintf("failed to generate provider %s: %v", config.Name, err),
-					Severity: ErrorSeverityMedium,
-				})
+	// Update resources with dependency information
+	for i := range resources {
+		resourceID := resources[i].OriginalResource.ID
+		direct := dependencies[resourceID]
+		for _, dependency := range direct {
+			address, exists := addresses[dependency]
+			if !exists || (cycleOf[resourceID] != 0 && cycleOf[resourceID] == cycleOf[dependency]) {
 				continue
 			}
-
-			files = append(files, GeneratedFile{
-				Path:    fmt.Sprintf("provider_%s.tf", config.Name),
-				Content: providerContent,
-				Type:    FileTypeProvider,
-				Format:  opts.Format,
-				Size:    int64(len(providerContent)),
-			})
+			if containsString(resources[i].Dependencies, address) ||
+				referencesAddress(resources[i].Configuration, address) ||
+				impliedDependency(dependencies, direct, dependency) {
+				continue
+			}
+			resources[i].Dependencies = append(resources[i].Dependencies, address)
 		}
 	}
 
-	// Generate main resource files
-	for filePath, resources := range organizedFiles {
-		content, err := e.generateResourceFile(resources, generator)
-		if err != nil {
-			errors = append(errors, GenerationError{
-				File:     filePath,
-				Message:  fmt.Sprintf("failed to generate file %s: %v", filePath, err),
-				Severity: ErrorSeverityHigh,
-			})
-			continue
-		}
+	return warnings, nil
+}
 
-		files = append(files, GeneratedFile{
-			Path:          filePath,
-			Content:       content,
-			Type:          FileTypeMain,
-			Format:        opts.Format,
-			Size:          int64(len(content)),
-			ResourceCount: len(resources),
-			Checksum:      e.calculateChecksum(content),
-		})
+// impliedDependency reports whether dependency is reachable through another
+// of the direct dependencies of a resource
+func impliedDependency(dependencies map[string][]string, direct []string, dependency string) bool {
+	visited := make(map[string]bool)
+	var reaches func(id string) bool
+	reaches = func(id string) bool {
+		if visited[id] {
+			return false
+		}
+		visited[id] = true
+		for _, next := range dependencies[id] {
+			if next == dependency || reaches(next) {
+				return true
+			}
+		}
+		return false
 	}
 
-	// Generate variables.tf if needed
-	variables := e.collectVariables(organizedFiles)
-	if len(variables) > 0 {
-		variablesContent, err := generator.GenerateVariables(variables)
-		if err != nil {
-			warnings = append(warnings, GenerationWarning{
-				Message: fmt.Sprintf("failed to generate variables.tf: %v", err),
-				Type:    WarningTypeBestPractice,
-			})
-		} else {
-			files = append(files, GeneratedFile{
-				Path:    "variables.tf",
-				Content: variablesContent,
-				Type:    FileTypeVariables,
-				Format:  opts.Format,
-				Size:    int64(len(variablesContent)),
-			})
+	for _, other := range direct {
+		if other != dependency && reaches(other) {
+			return true
 		}
 	}
+	return false
+}
 
-	// Generate outputs.tf if needed
-	outputs := e.collectOutputs(organizedFiles)
-	if len(outputs) > 0 {
-		outputsContent, err := generator.GenerateOutputs(outputs)
-		if err != nil {
-			warnings = append(warnings, GenerationWarning{
-				Message: fmt.Sprintf("failed to generate outputs.tf: %v", err),
-				Type:    WarningTypeBestPractice,
-			})
-		} else {
-			files = append(files, GeneratedFile{
-				Path:    "outputs.tf",
-				Content: outputsContent,
-				Type:    FileTypeOutputs,
-				Format:  opts.Format,
-				Size:    int64(len(outputsContent)),
-			})
+// referencesAddress reports whether a configuration value holds an
+// expression referring to the resource at address
+func referencesAddress(value interface{}, address string) bool {
+	switch v := value.(type) {
+	case Expression:
+		return strings.Contains(string(v), address+".")
+	case map[string]interface{}:
+		for _, item := range v {
+			if referencesAddress(item, address) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if referencesAddress(item, address) {
+				return true
+			}
+		}
+	case []map[string]interface{}:
+		for _, item := range v {
+			if referencesAddress(item, address) {
+				return true
+			}
+		}
+	case []Expression:
+		for _, item := range v {
+			if referencesAddress(item, address) {
+				return true
+			}
 		}
 	}
-
-	return files, errors, warnings
+	return false
 }
 
-// generateResourceFile generates content for a single resource file
-func (e *Engine) generateResourceFile(resources []MappedResource, generator TerraformGenerator) (string, error) {
-	var content strings.Builder
-
-	content.WriteString("# Generated by Chimera\n")
-	content.WriteString(fmt.Sprintf("# Generated at: %s\n\n", time.Now().Format(time.RFC3339)))
-
-	for _, resource := range resources {
-		resourceContent, err := generator.GenerateResourceHCL(resource)
-		if err != nil {
-			return "", fmt.Errorf("failed to generate resource %s: %w", resource.ResourceName, err)
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
-
-		content.WriteString(resourceContent)
-		content.WriteString("\n\n")
 	}
-
-	return content.String(), nil
+	return false
 }
 
-// collectProviderConfigs collects all unique provider configurations needed
-func (e *Engine) collectProviderConfigs(organizedFiles map[string][]MappedResource, opts GenerationOptions) []ProviderConfig {
-	providerMap := make(map[discovery.CloudProvider]ProviderConfig)
+// collectProviderConfigs collects the provider configurations needed. A
+// mapper gives a configuration an alias when it only applies to some
+// resources, such as the region of AWS resources. When the resources of a
+// provider need several aliased configurations, each is declared and the
+// resources select theirs through Provider; resources of no alias, such as
+// global ones, use the first. Otherwise the provider has a single default
+// configuration.
+func (e *Engine) collectProviderConfigs(resources []MappedResource) []ProviderConfig {
+	byProvider := make(map[string]map[string]ProviderConfig)
+	names := make([]string, len(resources))
+	aliases := make([]string, len(resources))
 
-	for _, resources := range organizedFiles {
-		for _, resource := range resources {
-			if mapper, exists := e.mappers[resource.OriginalResource.Provider]; exists {
-				config, err := mapper.GetProviderConfig([]discovery.Resource{resource.OriginalResource})
-				if err == nil {
-					providerMap[resource.OriginalResource.Provider] = *config
-				}
-			}
+	for i, resource := range resources {
+		mapper, exists := e.mappers[resource.OriginalResource.Provider]
+		if !exists {
+			continue
+		}
+		config, err := mapper.GetProviderConfig([]discovery.Resource{resource.OriginalResource})
+		if err != nil {
+			continue
 		}
+		if byProvider[config.Name] == nil {
+			byProvider[config.Name] = make(map[string]ProviderConfig)
+		}
+		byProvider[config.Name][config.Alias] = *config
+		names[i], aliases[i] = config.Name, config.Alias
 	}
 
 	var configs []ProviderConfig
-	for _, config := range providerMap {
-		configs = append(configs, config)
-	}
-
-	return configs
-}
-
-// collectVariables collects all variables from resources
-func (e *Engine) collectVariables(organizedFiles map[string][]MappedResource) map[string]Variable {
-	variables := make(map[string]Variable)
-
-	for _, resources := range organizedFiles {
-		for _, resource := range resources {
-			for name, variable := range resource.Variables {
-				if existing, exists := variables[name]; exists {
-					// Merge variable definitions, preferring required ones
-					if variable.Required && !existing.Required {
-						variables[name] = variable
-					}
-				} else {
-					variables[name] = variable
-				}
+	for name, byAlias := range byProvider {
+		var aliased []string
+		for alias := range byAlias {
+			if alias != "" {
+				aliased = append(aliased, alias)
 			}
 		}
-	}
-
-	return variables
-}
+		sort.Strings(aliased)
 
-// collectOutputs collects all outputs from resources
-func (e *Engine) collectOutputs(organizedFiles map[string][]MappedResource) map[string]Output {
-	outputs := make(map[string]Output)
+		if len(aliased) <= 1 {
+			config := byAlias[""]
+			if len(aliased) == 1 {
+				config = byAlias[aliased[0]]
+			}
+			config.Alias = ""
+			configs = append(configs, config)
+			continue
+		}
 
-	for _, resources := range organizedFiles {
-		for _, resource := range resources {
-			for name, output := range resource.Outputs {
-				outputs[name] = output
+		for _, alias := range aliased {
+			configs = append(configs, byAlias[alias])
+		}
+		for i := range resources {
+			if names[i] != name {
+				continue
 			}
+			alias := aliases[i]
+			if alias == "" {
+				alias = aliased[0]
+			}
+			resources[i].Provider = name + "." + alias
 		}
 	}
+	sort.Slice(configs, func(i, j int) bool {
+		if configs[i].Name != configs[j].Name {
+			return configs[i].Name < configs[j].Name
+		}
+		return configs[i].Alias < configs[j].Alias
+	})
 
-	return outputs
+	return configs
 }
 
 // writeFiles writes generated files to disk
@@ -588,7 +545,7 @@ func (e *Engine) writeFiles(files []GeneratedFile, outputPath string) error {
 			return fmt.Errorf("failed to create directory for %s: %w", file.Path, err)
 		}
 
-		if err := os.WriteFile(filePath, []byte(file.Content), 0644); err != nil {
+		if err := os.WriteFile(filePath, []byte(file.Content), fileMode(file)); err != nil {
 			return fmt.Errorf("failed to write file %s: %w", file.Path, err)
 		}
 	}
@@ -596,6 +553,14 @@ func (e *Engine) writeFiles(files []GeneratedFile, outputPath string) error {
 	return nil
 }
 
+// fileMode returns the permissions a generated file is written with
+func fileMode(file GeneratedFile) os.FileMode {
+	if file.Type == FileTypeScript {
+		return 0755
+	}
+	return 0644
+}
+
 // validateOutput validates generated files
 func (e *Engine) validateOutput(files []GeneratedFile, opts GenerationOptions) error {
 	if e.validator == nil {
@@ -623,6 +588,9 @@ func (e *Engine) ListFormats() []IaCFormat {
 	for format := range e.generators {
 		formats = append(formats, format)
 	}
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i] < formats[j]
+	})
 	return formats
 }
 
@@ -640,51 +608,44 @@ func (e *Engine) ValidateOptions(opts GenerationOptions) error {
 		return fmt.Errorf("unsupported format: %s", opts.Format)
 	}
 
-	if opts.Organization == "" {
+	if opts.Organization = ResolvePattern(opts); opts.Organization == "" {
 		opts.Organization = e.config.DefaultOrg
 	}
 
 	return nil
 }
 
-// GetFormatCapabilities returns capabilities for a specific format
+// GetFormatCapabilities returns capabilities for a specific format, as
+// declared by its generator. Generators that do not restrict the providers
+// they support accept every provider with a registered mapper.
 func (e *Engine) GetFormatCapabilities(format IaCFormat) FormatCapabilities {
-	capabilities := FormatCapabilities{
-		Format:             format,
-		SupportedProviders: make([]discovery.CloudProvider, 0),
-		SupportedResources: make(map[string][]string),
-		OrganizationPatterns: []OrganizationPattern{
-			OrganizeByProvider,
-			OrganizeByService,
-			OrganizeByRegion,
-			OrganizeByResourceType,
-			OrganizeFlat,
-		},
+	generator, exists := e.generators[format]
+	if !exists {
+		return FormatCapabilities{
+			Format:             format,
+			SupportedProviders: make([]discovery.CloudProvider, 0),
+			SupportedResources: make(map[string][]string),
+		}
 	}
 
-	// Collect supported providers and resources
-	for provider, mapper := range e.mappers {
-		capabilities.SupportedProviders = append(capabilities.SupportedProviders, provider)
-		capabilities.SupportedResources[string(provider)] = mapper.GetSupportedTypes()
+	capabilities := generator.Capabilities()
+	capabilities.Format = format
+
+	if len(capabilities.SupportedProviders) == 0 {
+		for provider := range e.mappers {
+			capabilities.SupportedProviders = append(capabilities.SupportedProviders, provider)
+		}
+		sort.Slice(capabilities.SupportedProviders, func(i, j int) bool {
+			return capabilities.SupportedProviders[i] < capabilities.SupportedProviders[j]
+		})
 	}
 
-	// Set format-specific capabilities
-	switch format {
-	case Terraform, TerraformJSON:
-		capabilities.SupportsModules = true
-		capabilities.SupportsState = true
-		capabilities.SupportsVariables = true
-		capabilities.SupportsOutputs = true
-		capabilities.SupportsValidation = true
-		capabilities.SupportsImports = true
-	default:
-		// Other formats have limited support for now
-		capabilities.SupportsModules = false
-		capabilities.SupportsState = false
-		capabilities.SupportsVariables = false
-		capabilities.SupportsOutputs = false
-		capabilities.SupportsValidation = false
-		capabilities.SupportsImports = false
+	// Collect the resources supported for each provider
+	capabilities.SupportedResources = make(map[string][]string)
+	for _, provider := range capabilities.SupportedProviders {
+		if mapper, exists := e.mappers[provider]; exists {
+			capabilities.SupportedResources[string(provider)] = mapper.GetSupportedTypes()
+		}
 	}
 
 	return capabilities
@@ -719,19 +680,42 @@ func (e *Engine) Preview(ctx context.Context, opts GenerationOptions) (*Generati
 	}
 
 	// Estimate file structure
-	if e.organizer != nil {
-		organization := opts.Organization
+	if len(e.GetFormatCapabilities(opts.Format).OrganizationPatterns) > 0 {
+		organization := ResolvePattern(opts)
 		if organization == "" {
 			organization = e.config.DefaultOrg
 		}
 
-		// Simple estimation - in real implementation, this would be more detailed
-		preview.FileStructure = append(preview.FileStructure, PreviewFile{
-			Path:          "main.tf",
-			Type:          FileTypeMain,
-			ResourceCount: len(resources),
-			EstimatedSize: int64(len(resources) * 200), // Rough estimation
-		})
+		// Estimate resource files from the discovered resource types
+		organizer := NewPatternOrganizer()
+		counts := make(map[string]int)
+		var paths []string
+		for _, resource := range resources {
+			filePath, err := organizer.GetFilePath(TerraformResource{
+				Type:     resource.Type,
+				Provider: resource.Provider,
+				SourceInfo: SourceInfo{
+					OriginalProvider: resource.Provider,
+					OriginalRegion:   resource.Region,
+				},
+			}, organization)
+			if err != nil {
+				return nil, fmt.Errorf("failed to organize resources: %w", err)
+			}
+			if counts[filePath] == 0 {
+				paths = append(paths, filePath)
+			}
+			counts[filePath]++
+		}
+		sort.Strings(paths)
+		for _, filePath := range paths {
+			preview.FileStructure = append(preview.FileStructure, PreviewFile{
+				Path:          filePath,
+				Type:          FileTypeMain,
+				ResourceCount: counts[filePath],
+				EstimatedSize: int64(counts[filePath] * 200), // Rough estimation
+			})
+		}
 
 		if opts.IncludeProvider {
 			preview.FileStructure = append(preview.FileStructure, PreviewFile{
@@ -0,0 +1,133 @@
+package generation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// templateSequences escapes the sequences HCL would read as templates
+var templateSequences = strings.NewReplacer("${", "$${", "%{", "%%{")
+
+// JSONEncode returns a jsonencode expression for a JSON document, such as an
+// IAM policy, with the document written as an HCL object
+func JSONEncode(document string) (Expression, error) {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("invalid JSON document: %w", err)
+	}
+
+	var buf strings.Builder
+	buf.WriteString("jsonencode(")
+	if err := writeHCLValue(&buf, value, ""); err != nil {
+		return "", err
+	}
+	buf.WriteString(")")
+	return Expression(buf.String()), nil
+}
+
+// writeHCLValue writes a decoded JSON value as an HCL expression; objects
+// are written with sorted keys, one attribute per line
+func writeHCLValue(buf *strings.Builder, value interface{}, indent string) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		fmt.Fprintf(buf, "%t", v)
+	case json.Number:
+		buf.WriteString(v.String())
+	case string:
+		quoted, err := quoteHCLString(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(quoted)
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for _, item := range v {
+			buf.WriteString(indent + "  ")
+			if err := writeHCLValue(buf, item, indent+"  "); err != nil {
+				return err
+			}
+			buf.WriteString(",\n")
+		}
+		buf.WriteString(indent + "]")
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("{\n")
+		for _, key := range keys {
+			name := key
+			if !hclsyntax.ValidIdentifier(key) {
+				quoted, err := quoteHCLString(key)
+				if err != nil {
+					return err
+				}
+				name = quoted
+			}
+			buf.WriteString(indent + "  " + name + " = ")
+			if err := writeHCLValue(buf, v[key], indent+"  "); err != nil {
+				return err
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "}")
+	default:
+		return fmt.Errorf("unsupported JSON value type %T", value)
+	}
+	return nil
+}
+
+// quoteHCLString quotes a string as an HCL literal. JSON string escapes are
+// valid in HCL, so only template sequences need escaping.
+func quoteHCLString(s string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return "", err
+	}
+	return templateSequences.Replace(strings.TrimSuffix(buf.String(), "\n")), nil
+}
+
+// EvaluateString evaluates an expression that holds no references, such as
+// one built by JSONEncode, reporting false when it is not a constant string
+func EvaluateString(expr Expression) (string, bool) {
+	parsed, diags := hclsyntax.ParseExpression([]byte(expr), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", false
+	}
+
+	value, diags := parsed.Value(&hcl.EvalContext{
+		Functions: map[string]function.Function{
+			"jsonencode": stdlib.JSONEncodeFunc,
+		},
+	})
+	if diags.HasErrors() || !value.IsWhollyKnown() || value.IsNull() || !value.Type().Equals(cty.String) {
+		return "", false
+	}
+	return value.AsString(), true
+}
@@ -0,0 +1,126 @@
+package generation
+
+import "testing"
+
+func TestJSONEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     Expression
+	}{
+		{name: "empty object", document: `{}`, want: `jsonencode({})`},
+		{name: "empty array", document: `[]`, want: `jsonencode([])`},
+		{name: "scalars", document: `[true, null, 1.50, "a"]`, want: "jsonencode([\n  true,\n  null,\n  1.50,\n  \"a\",\n])"},
+		{
+			name:     "sorted keys",
+			document: `{"Version": "2012-10-17", "Statement": []}`,
+			want:     "jsonencode({\n  Statement = []\n  Version = \"2012-10-17\"\n})",
+		},
+		{
+			name:     "quoted keys",
+			document: `{"aws:SourceArn": "arn", "my key": 1}`,
+			want:     "jsonencode({\n  \"aws:SourceArn\" = \"arn\"\n  \"my key\" = 1\n})",
+		},
+		{
+			name:     "template sequences",
+			document: `{"Resource": "arn:aws:s3:::bucket/${aws:username}/%{x}"}`,
+			want:     "jsonencode({\n  Resource = \"arn:aws:s3:::bucket/$${aws:username}/%%{x}\"\n})",
+		},
+		{
+			name:     "escapes",
+			document: `{"a": "line\nbreak \"quoted\" <tag> \\"}`,
+			want:     "jsonencode({\n  a = \"line\\nbreak \\\"quoted\\\" <tag> \\\\\"\n})",
+		},
+		{
+			name:     "nested",
+			document: `{"Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"]}]}`,
+			want: `jsonencode({
+  Statement = [
+    {
+      Action = [
+        "s3:GetObject",
+      ]
+      Effect = "Allow"
+    },
+  ]
+})`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONEncode(tt.document)
+			if err != nil {
+				t.Fatalf("JSONEncode returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONEncodeRejectsInvalidJSON(t *testing.T) {
+	for _, document := range []string{"", "{", `{"a": }`, "not json"} {
+		if _, err := JSONEncode(document); err == nil {
+			t.Errorf("JSONEncode(%q) returned no error", document)
+		}
+	}
+}
+
+func TestJSONEncodeRoundTrips(t *testing.T) {
+	tests := []struct {
+		document string
+		want     string
+	}{
+		{document: `{}`, want: `{}`},
+		{document: `{"b": 1, "a": [true, null]}`, want: `{"a":[true,null],"b":1}`},
+		{document: `{"Resource": "${aws:username}"}`, want: `{"Resource":"${aws:username}"}`},
+		{document: `{"a": "say \"hi\"\n"}`, want: `{"a":"say \"hi\"\n"}`},
+		{document: `{"aws:SourceArn": "x"}`, want: `{"aws:SourceArn":"x"}`},
+	}
+
+	for _, tt := range tests {
+		expr, err := JSONEncode(tt.document)
+		if err != nil {
+			t.Fatalf("JSONEncode(%q) returned error: %v", tt.document, err)
+		}
+		got, ok := EvaluateString(expr)
+		if !ok {
+			t.Errorf("EvaluateString(%s) could not evaluate the expression", expr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("JSONEncode(%q) evaluates to %s, want %s", tt.document, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateString(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   Expression
+		want   string
+		wantOK bool
+	}{
+		{name: "string literal", expr: `"web"`, want: "web", wantOK: true},
+		{name: "escaped template", expr: `"$${a}"`, want: "${a}", wantOK: true},
+		{name: "jsonencode", expr: `jsonencode({ a = "b" })`, want: `{"a":"b"}`, wantOK: true},
+		{name: "resource reference", expr: "aws_vpc.main.id"},
+		{name: "variable", expr: "var.region"},
+		{name: "template with reference", expr: `"${aws_vpc.main.id}-x"`},
+		{name: "number", expr: "1"},
+		{name: "null", expr: "null"},
+		{name: "unknown function", expr: `upper("a")`},
+		{name: "invalid", expr: "(("},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := EvaluateString(tt.expr)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("EvaluateString(%s) = %q, %t, want %q, %t", tt.expr, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// Format is a dependency graph output format
+type Format string
+
+const (
+	DOT     Format = "dot"
+	Mermaid Format = "mermaid"
+	JSON    Format = "json"
+)
+
+// ClusterBy groups the nodes of a rendered graph
+type ClusterBy string
+
+const (
+	ClusterNone     ClusterBy = ""
+	ClusterProvider ClusterBy = "provider"
+	ClusterRegion   ClusterBy = "region"
+	ClusterVPC      ClusterBy = "vpc"
+)
+
+// Options configures how a dependency graph is rendered
+type Options struct {
+	Format    Format
+	ClusterBy ClusterBy
+	// Root limits the graph to the resources the root depends on and the
+	// resources depending on it, transitively
+	Root string
+}
+
+// Render builds the dependency graph of resources and renders it
+func Render(resources []discovery.Resource, opts Options) (string, error) {
+	analyzer := generation.NewMetadataDependencyAnalyzer()
+	graph, err := analyzer.GetDependencyGraph(resources)
+	if err != nil {
+		return "", fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+
+	if opts.Root != "" {
+		graph, err = Subgraph(graph, opts.Root)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	labels := clusters(resources, opts.ClusterBy)
+	nodes := make(map[string]bool, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = true
+	}
+	for id := range labels {
+		if !nodes[id] {
+			delete(labels, id)
+		}
+	}
+
+	switch opts.Format {
+	case DOT:
+		return renderDOT(graph, labels), nil
+	case Mermaid:
+		return renderMermaid(graph, labels), nil
+	case JSON:
+		content, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal dependency graph: %w", err)
+		}
+		return string(content) + "\n", nil
+	default:
+		return "", fmt.Errorf("unsupported graph format: %s", opts.Format)
+	}
+}
+
+// Subgraph returns the part of a graph connected to root: the resources root
+// depends on and the resources depending on root, transitively
+func Subgraph(graph *generation.DependencyGraph, root string) (*generation.DependencyGraph, error) {
+	found := false
+	for _, node := range graph.Nodes {
+		if node.ID == root {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("resource %s not found in dependency graph", root)
+	}
+
+	dependencies := make(map[string][]string)
+	dependents := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		dependencies[edge.From] = append(dependencies[edge.From], edge.To)
+		dependents[edge.To] = append(dependents[edge.To], edge.From)
+	}
+
+	included := map[string]bool{root: true}
+	for _, adjacent := range []map[string][]string{dependencies, dependents} {
+		queue := []string{root}
+		visited := map[string]bool{root: true}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, next := range adjacent[id] {
+				if !visited[next] {
+					visited[next] = true
+					included[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	subgraph := &generation.DependencyGraph{
+		Nodes: make([]generation.DependencyNode, 0, len(included)),
+		Edges: make([]generation.DependencyEdge, 0),
+	}
+	for _, node := range graph.Nodes {
+		if included[node.ID] {
+			subgraph.Nodes = append(subgraph.Nodes, node)
+		}
+	}
+	for _, edge := range graph.Edges {
+		if included[edge.From] && included[edge.To] {
+			subgraph.Edges = append(subgraph.Edges, edge)
+		}
+	}
+	return subgraph, nil
+}
+
+// clusters returns the cluster label of each resource; resources without a
+// label are drawn outside any cluster
+func clusters(resources []discovery.Resource, by ClusterBy) map[string]string {
+	labels := make(map[string]string, len(resources))
+	if by == ClusterNone {
+		return labels
+	}
+
+	names := make(map[string]string, len(resources))
+	for _, resource := range resources {
+		names[strings.ToLower(resource.ID)] = resource.Name
+	}
+
+	for _, resource := range resources {
+		switch by {
+		case ClusterProvider:
+			labels[resource.ID] = string(resource.Provider)
+		case ClusterRegion:
+			region := resource.Region
+			if region == "" {
+				region = "global"
+			}
+			labels[resource.ID] = string(resource.Provider) + "/" + region
+		case ClusterVPC:
+			if network := networkOf(resource); network != "" {
+				label := network
+				if name := names[strings.ToLower(network)]; name != "" && name != network {
+					label = fmt.Sprintf("%s (%s)", name, network)
+				}
+				labels[resource.ID] = label
+			}
+		}
+	}
+	return labels
+}
+
+// networkOf returns the VPC or virtual network a resource belongs to: the
+// AWS VPC ID, the Azure virtual network ID or the GCP network name
+func networkOf(resource discovery.Resource) string {
+	switch resource.Type {
+	case "aws_vpc", "azure_virtual_network":
+		return resource.ID
+	case "gcp_compute_network":
+		return resource.Name
+	}
+
+	for _, key := range []string{"vpc_id", "virtual_network_id", "network"} {
+		if value, ok := resource.Metadata[key].(string); ok && value != "" {
+			return value
+		}
+	}
+
+	// Azure child resources are nested under their virtual network
+	lower := strings.ToLower(resource.ID)
+	if i := strings.Index(lower, "/virtualnetworks/"); i >= 0 {
+		rest := resource.ID[i+len("/virtualnetworks/"):]
+		return resource.ID[:i+len("/virtualnetworks/")] + strings.SplitN(rest, "/", 2)[0]
+	}
+	return ""
+}
+
+// clusterOrder returns the cluster labels in sorted order
+func clusterOrder(labels map[string]string) []string {
+	seen := make(map[string]bool)
+	var order []string
+	for _, label := range labels {
+		if !seen[label] {
+			seen[label] = true
+			order = append(order, label)
+		}
+	}
+	sort.Strings(order)
+	return order
+}
+
+// nodeLabel returns the text drawn for a node
+func nodeLabel(node generation.DependencyNode) string {
+	name := node.Name
+	if name == "" {
+		name = node.ID
+	}
+	return node.ResourceType + "\n" + name
+}
@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// renderDOT renders a Graphviz digraph; edges point from a resource to the
+// resource it depends on
+func renderDOT(graph *generation.DependencyGraph, labels map[string]string) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Chimera\n")
+	b.WriteString("digraph chimera {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+
+	writeNode := func(indent string, node generation.DependencyNode) {
+		fmt.Fprintf(&b, "%s%s [label=%s];\n", indent, strconv.Quote(node.ID), strconv.Quote(nodeLabel(node)))
+	}
+
+	for i, cluster := range clusterOrder(labels) {
+		fmt.Fprintf(&b, "\n  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%s;\n", strconv.Quote(cluster))
+		for _, node := range graph.Nodes {
+			if labels[node.ID] == cluster {
+				writeNode("    ", node)
+			}
+		}
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("\n")
+	for _, node := range graph.Nodes {
+		if _, clustered := labels[node.ID]; !clustered {
+			writeNode("  ", node)
+		}
+	}
+
+	if len(graph.Edges) > 0 {
+		b.WriteString("\n")
+	}
+	for _, edge := range graph.Edges {
+		attributes := ""
+		if edge.Attribute != "" {
+			attributes = fmt.Sprintf(" [label=%s]", strconv.Quote(edge.Attribute))
+		} else if edge.Type == generation.DependencyParent {
+			attributes = " [style=dashed]"
+		}
+		fmt.Fprintf(&b, "  %s -> %s%s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To), attributes)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// renderMermaid renders a Mermaid flowchart; edges point from a resource to
+// the resource it depends on
+func renderMermaid(graph *generation.DependencyGraph, labels map[string]string) string {
+	// Mermaid node IDs cannot hold the characters of cloud resource IDs
+	ids := make(map[string]string, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("%% Generated by Chimera\n")
+	b.WriteString("flowchart LR\n")
+
+	writeNode := func(indent string, node generation.DependencyNode) {
+		label := strings.ReplaceAll(mermaidEscape(nodeLabel(node)), "\n", "<br/>")
+		fmt.Fprintf(&b, "%s%s[\"%s\"]\n", indent, ids[node.ID], label)
+	}
+
+	for i, cluster := range clusterOrder(labels) {
+		fmt.Fprintf(&b, "  subgraph c%d[\"%s\"]\n", i, mermaidEscape(cluster))
+		for _, node := range graph.Nodes {
+			if labels[node.ID] == cluster {
+				writeNode("    ", node)
+			}
+		}
+		b.WriteString("  end\n")
+	}
+
+	for _, node := range graph.Nodes {
+		if _, clustered := labels[node.ID]; !clustered {
+			writeNode("  ", node)
+		}
+	}
+
+	for _, edge := range graph.Edges {
+		switch {
+		case edge.Attribute != "":
+			fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[edge.From], mermaidEscape(edge.Attribute), ids[edge.To])
+		case edge.Type == generation.DependencyParent:
+			fmt.Fprintf(&b, "  %s -.-> %s\n", ids[edge.From], ids[edge.To])
+		default:
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+		}
+	}
+
+	return b.String()
+}
+
+// mermaidEscape escapes the characters Mermaid treats as syntax in labels
+func mermaidEscape(s string) string {
+	replacer := strings.NewReplacer(`"`, "#quot;", "|", "#124;", "<", "#lt;", ">", "#gt;")
+	return replacer.Replace(s)
+}
@@ -15,6 +15,7 @@ const (
 	TerraformJSON    IaCFormat = "terraform-json"
 	CloudFormation   IaCFormat = "cloudformation"
 	ARM              IaCFormat = "arm"
+	Bicep            IaCFormat = "bicep"
 	Pulumi           IaCFormat = "pulumi"
 	PulumiTypeScript IaCFormat = "pulumi-typescript"
 	PulumiPython     IaCFormat = "pulumi-python"
@@ -74,8 +75,13 @@ type GenerationOptions struct {
 	CompactOutput    bool          `json:"compact_output"`
 	ValidateOutput   bool          `json:"validate_output"`
 	GenerateImports  bool          `json:"generate_imports"`
+	ImportScript     bool          `json:"import_script"`
 	Timeout          time.Duration `json:"timeout"`
 	Timestamp        string        `json:"timestamp,omitempty"`
+	
+	// Providers holds the provider configurations of the mapped resources,
+	// filled in by the engine before the format generator runs
+	Providers []ProviderConfig `json:"-"`
 }
 
 // ModuleStructure defines how to organize generated code into modules
@@ -122,7 +128,9 @@ const (
 	FileTypeModule       FileType = "module"
 	FileTypeData         FileType = "data"
 	FileTypeImports      FileType = "imports"
+	FileTypeScript       FileType = "script"
 	FileTypeTerraformRC  FileType = "terraformrc"
+	FileTypeProject      FileType = "project"
 )
 
 // GenerationMetadata contains metadata about the generation operation
@@ -208,8 +216,20 @@ type MappedResource struct {
 	Dependencies     []string                  `json:"dependencies"`      // List of resource dependencies
 	Variables        map[string]Variable       `json:"variables"`         // Required variables for this resource
 	Outputs          map[string]Output         `json:"outputs"`           // Outputs generated by this resource
+	ImportID         string                    `json:"import_id,omitempty"` // Provider-native ID used by terraform import
+	Provider         string                    `json:"provider,omitempty"`  // Aliased provider configuration (e.g., "aws.eu-west-1"); empty for the default one
 }
 
+// Expression is a raw HCL expression, such as a reference to another resource
+// (aws_vpc.main.id) or a variable (var.region). Configuration values of this
+// type are written unquoted, while plain strings are always written as literals.
+type Expression string
+
+// Map is an object attribute whose values may be expressions, such as the
+// environment variables of a Lambda function. Unlike map[string]interface{},
+// which is written as a nested block, it is written as an object.
+type Map map[string]interface{}
+
 // Variable represents a Terraform variable
 type Variable struct {
 	Name        string      `json:"name"`
@@ -256,7 +276,7 @@ type ModuleConfig struct {
 // Output represents a Terraform output
 type Output struct {
 	Name        string      `json:"name"`
-	Value       string      `json:"value"`       // Terraform expression
+	Value       string      `json:"value"`       // Terraform expression, e.g. aws_vpc.main.id
 	Description string      `json:"description"`
 	Sensitive   bool        `json:"sensitive"`
 	Type        string      `json:"type,omitempty"`
@@ -301,6 +321,23 @@ type ResourceMapper interface {
 	Provider() discovery.CloudProvider
 }
 
+// ResourceSetMapper is implemented by mappers that need to see every resource
+// being generated, for example to resolve references between them
+type ResourceSetMapper interface {
+	// SetResources provides the full set of resources before mapping starts
+	SetResources(resources []discovery.Resource)
+}
+
+// ResourceExpander is implemented by mappers that generate some discovered
+// resources as several resources, such as security groups whose rules must
+// be generated on their own to avoid reference cycles between groups
+type ResourceExpander interface {
+	// ExpandResources returns the resources to map in place of the discovered
+	// ones, with warnings about resources that need manual action, such as
+	// split rules that cannot be imported
+	ExpandResources(resources []discovery.Resource) ([]discovery.Resource, []GenerationWarning)
+}
+
 // TerraformGenerator defines the interface for Terraform-specific generation
 type TerraformGenerator interface {
 	// GenerateResource generates Terraform HCL for a single resource
@@ -323,11 +360,47 @@ type TerraformGenerator interface {
 	
 	// GenerateModule generates a complete module
 	GenerateModule(config ModuleConfig) (map[string]string, error)
+
+	// GenerateImports generates Terraform 1.5+ import blocks for resources
+	GenerateImports(resources []MappedResource) (string, error)
+
+	// GenerateImportScript generates a legacy terraform import shell script
+	GenerateImportScript(resources []MappedResource) (string, error)
+
+	// GenerateState generates a terraform.tfstate describing existing
+	// resources; resources that cannot be recorded are left out and reported
+	// as warnings
+	GenerateState(resources []MappedResource, providers []ProviderConfig) (string, []GenerationWarning, error)
 	
 	// ValidateSyntax validates generated Terraform syntax
 	ValidateSyntax(content string) error
 }
 
+// ResourceFileGenerator is implemented by generators whose resource files must
+// be produced as a single document rather than concatenated per resource,
+// such as Terraform JSON
+type ResourceFileGenerator interface {
+	GenerateResourceFile(resources []MappedResource) (string, error)
+}
+
+// WarningReporter is implemented by format generators that report warnings
+// about resources they could only generate in part, such as resources left
+// out of a Terraform state
+type WarningReporter interface {
+	GenerateWithWarnings(resources []MappedResource, opts GenerationOptions) ([]GeneratedFile, []GenerationWarning, error)
+}
+
+// FormatGenerator generates an IaC format, such as a Terraform configuration
+// or a CloudFormation template, from the complete set of mapped resources
+type FormatGenerator interface {
+	// Generate generates the files of the format
+	Generate(resources []MappedResource, opts GenerationOptions) ([]GeneratedFile, error)
+	
+	// Capabilities returns the features the format supports; an empty list of
+	// supported providers means every provider
+	Capabilities() FormatCapabilities
+}
+
 // TemplateEngine defines the interface for template-based generation
 type TemplateEngine interface {
 	// Render renders a template with the provided data
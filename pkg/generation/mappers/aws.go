@@ -9,7 +9,25 @@ import (
 )
 
 // AWSMapper implements ResourceMapper for AWS resources
-type AWSMapper struct{}
+type AWSMapper struct {
+	// names maps discovered resource IDs to unique Terraform resource names
+	names map[string]string
+
+	// addresses maps discovered resource IDs to Terraform addresses (type.name)
+	addresses map[string]string
+
+	// groupsWithRules records security groups whose rules were discovered as
+	// separate rule resources and must not be repeated inline
+	groupsWithRules map[string]bool
+
+	// attachedVolumes records EBS volumes whose attachments were discovered as
+	// separate resources and must not be repeated as instance block devices
+	attachedVolumes map[string]bool
+
+	// natInterfaces records the network interfaces of NAT gateways, whose
+	// Elastic IP associations are managed by the NAT gateway
+	natInterfaces map[string]bool
+}
 
 // NewAWSMapper creates a new AWS resource mapper
 func NewAWSMapper() *AWSMapper {
@@ -18,6 +36,17 @@ func NewAWSMapper() *AWSMapper {
 
 // MapResource maps a single discovered resource to an IaC resource (required by ResourceMapper interface)
 func (m *AWSMapper) MapResource(resource discovery.Resource) (*generation.MappedResource, error) {
+	mapped, err := m.mapResourceByType(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped.ImportID = m.generateImportID(resource)
+	return mapped, nil
+}
+
+// mapResourceByType dispatches a resource to its type-specific mapping
+func (m *AWSMapper) mapResourceByType(resource discovery.Resource) (*generation.MappedResource, error) {
 	switch resource.Type {
 	case "aws_vpc":
 		return m.mapVPC(resource)
@@ -25,98 +54,255 @@ func (m *AWSMapper) MapResource(resource discovery.Resource) (*generation.Mapped
 		return m.mapSubnet(resource)
 	case "aws_security_group":
 		return m.mapSecurityGroup(resource)
+	case "aws_vpc_security_group_ingress_rule", "aws_vpc_security_group_egress_rule":
+		return m.mapSecurityGroupRule(resource)
 	case "aws_instance":
 		return m.mapInstance(resource)
 	case "aws_internet_gateway":
 		return m.mapInternetGateway(resource)
+	case "aws_egress_only_internet_gateway":
+		return m.mapEgressOnlyInternetGateway(resource)
+	case "aws_nat_gateway":
+		return m.mapNATGateway(resource)
 	case "aws_route_table":
 		return m.mapRouteTable(resource)
+	case "aws_route_table_association":
+		return m.mapRouteTableAssociation(resource)
 	case "aws_key_pair":
 		return m.mapKeyPair(resource)
 	case "aws_ebs_volume":
 		return m.mapEBSVolume(resource)
+	case "aws_volume_attachment":
+		return m.mapVolumeAttachment(resource)
 	case "aws_elastic_ip":
 		return m.mapElasticIP(resource)
+	case "aws_s3_bucket":
+		return m.mapS3Bucket(resource)
+	case "aws_s3_bucket_versioning", "aws_s3_bucket_server_side_encryption_configuration",
+		"aws_s3_bucket_lifecycle_configuration", "aws_s3_bucket_public_access_block",
+		"aws_s3_bucket_policy", "aws_s3_bucket_logging", "aws_s3_bucket_replication_configuration":
+		return m.mapS3BucketConfiguration(resource)
+	case "aws_iam_role":
+		return m.mapIAMRole(resource)
+	case "aws_iam_policy":
+		return m.mapIAMPolicy(resource)
+	case "aws_iam_user", "aws_iam_group":
+		return m.mapIAMPrincipal(resource)
+	case "aws_iam_instance_profile":
+		return m.mapIAMInstanceProfile(resource)
+	case "aws_iam_role_policy", "aws_iam_user_policy", "aws_iam_group_policy",
+		"aws_iam_role_policy_attachment", "aws_iam_user_policy_attachment", "aws_iam_group_policy_attachment":
+		return m.mapIAMPrincipalPolicy(resource)
+	case "aws_iam_user_group_membership":
+		return m.mapIAMUserGroupMembership(resource)
+	case "aws_db_instance":
+		return m.mapDBInstance(resource)
+	case "aws_rds_cluster_instance":
+		return m.mapRDSClusterInstance(resource)
+	case "aws_rds_cluster":
+		return m.mapRDSCluster(resource)
+	case "aws_db_subnet_group":
+		return m.mapDBSubnetGroup(resource)
+	case "aws_db_parameter_group", "aws_rds_cluster_parameter_group":
+		return m.mapRDSParameterGroup(resource)
+	case "aws_db_option_group":
+		return m.mapDBOptionGroup(resource)
+	case "aws_lambda_function":
+		return m.mapLambdaFunction(resource)
+	case "aws_lambda_event_source_mapping":
+		return m.mapLambdaEventSourceMapping(resource)
+	case "aws_api_gateway_rest_api":
+		return m.mapAPIGatewayRestAPI(resource)
+	case "aws_api_gateway_resource":
+		return m.mapAPIGatewayResource(resource)
+	case "aws_api_gateway_method":
+		return m.mapAPIGatewayMethod(resource)
+	case "aws_api_gateway_integration":
+		return m.mapAPIGatewayIntegration(resource)
+	case "aws_api_gateway_stage":
+		return m.mapAPIGatewayStage(resource)
+	case "aws_apigatewayv2_api":
+		return m.mapAPIGatewayV2API(resource)
+	case "aws_apigatewayv2_integration":
+		return m.mapAPIGatewayV2Integration(resource)
+	case "aws_apigatewayv2_route":
+		return m.mapAPIGatewayV2Route(resource)
+	case "aws_apigatewayv2_stage":
+		return m.mapAPIGatewayV2Stage(resource)
+	case "aws_cloudwatch_event_rule":
+		return m.mapEventBridgeRule(resource)
+	case "aws_cloudwatch_event_target":
+		return m.mapEventBridgeTarget(resource)
 	default:
 		return nil, fmt.Errorf("unsupported AWS resource type: %s", resource.Type)
 	}
 }
 
-// GetProviderConfig returns the provider configuration needed (required by ResourceMapper interface)
+// SetResources indexes the full resource set so that references between
+// resources resolve to the names they are generated under
+func (m *AWSMapper) SetResources(resources []discovery.Resource) {
+	m.names = make(map[string]string)
+	m.addresses = make(map[string]string)
+	m.groupsWithRules = make(map[string]bool)
+	m.attachedVolumes = make(map[string]bool)
+	m.natInterfaces = make(map[string]bool)
+	used := make(map[string]bool)
+
+	var dependents []discovery.Resource
+	for _, resource := range resources {
+		if resource.Provider != discovery.AWS {
+			continue
+		}
+		switch {
+		case m.isSecurityGroupRule(resource.Type):
+			m.groupsWithRules[m.getStringFromMetadata(resource.Metadata, "security_group_id", "")] = true
+			dependents = append(dependents, resource)
+			continue
+		case resource.Type == "aws_volume_attachment":
+			m.attachedVolumes[m.getStringFromMetadata(resource.Metadata, "volume_id", "")] = true
+			dependents = append(dependents, resource)
+			continue
+		case resource.Type == "aws_route_table_association", m.isS3BucketConfiguration(resource.Type),
+			m.iamPrincipalKind(resource.Type) != "", resource.Type == "aws_iam_user_group_membership",
+			resource.Type == "aws_lambda_event_source_mapping", m.isAPIGatewayChild(resource.Type),
+			resource.Type == "aws_cloudwatch_event_target":
+			dependents = append(dependents, resource)
+			continue
+		case resource.Type == "aws_nat_gateway":
+			if eni := m.getStringFromMetadata(resource.Metadata, "network_interface_id", ""); eni != "" {
+				m.natInterfaces[eni] = true
+			}
+		}
+		m.registerName(resource, m.baseResourceName(resource), used)
+	}
+
+	// Rules, associations, attachments, bucket configurations, principal
+	// policies, event source mappings, API parts and event targets are named
+	// after the resources they connect, so they are registered last
+	for _, resource := range dependents {
+		name := m.sanitizeResourceName(resource.Name)
+		if resource.Name == "" {
+			name = m.dependentName(resource)
+		}
+		m.registerName(resource, name, used)
+	}
+}
+
+// dependentName returns the name of an unnamed security group rule, route
+// table association, volume attachment, bucket configuration, IAM principal
+// policy, event source mapping, API part or event target, derived from the
+// resources it connects
+func (m *AWSMapper) dependentName(resource discovery.Resource) string {
+	nameOf := func(key, fallback string) string {
+		id := m.getStringFromMetadata(resource.Metadata, key, "")
+		if name := m.names[id]; name != "" {
+			return name
+		}
+		if suffix := m.idSuffix(id); suffix != "" {
+			return fmt.Sprintf("%s_%s", fallback, suffix)
+		}
+		return fallback
+	}
+
+	switch {
+	case resource.Type == "aws_route_table_association":
+		target := nameOf("subnet_id", "subnet")
+		if m.getStringFromMetadata(resource.Metadata, "subnet_id", "") == "" {
+			target = nameOf("gateway_id", "gateway")
+		}
+		return fmt.Sprintf("%s_%s", nameOf("route_table_id", "route_table"), target)
+	case resource.Type == "aws_volume_attachment":
+		return fmt.Sprintf("%s_%s", nameOf("volume_id", "volume"), nameOf("instance_id", "instance"))
+	case m.isS3BucketConfiguration(resource.Type):
+		// Each configuration has its own type, so it shares the bucket's name
+		return nameOf("bucket", "bucket")
+	case m.iamPrincipalKind(resource.Type) != "":
+		kind := m.iamPrincipalKind(resource.Type)
+		policy := m.getStringFromMetadata(resource.Metadata, "name", "")
+		if policy == "" {
+			policy = m.getStringFromMetadata(resource.Metadata, "policy_name", "")
+		}
+		return fmt.Sprintf("%s_%s", nameOf(kind+"_arn", kind), m.sanitizeResourceName(policy))
+	case resource.Type == "aws_iam_user_group_membership":
+		return nameOf("user_arn", "user")
+	case resource.Type == "aws_lambda_event_source_mapping":
+		source := m.lambdaSourceName(m.getStringFromMetadata(resource.Metadata, "event_source_arn", ""))
+		return fmt.Sprintf("%s_%s", nameOf("function_arn", "function"), source)
+	case m.isAPIGatewayChild(resource.Type):
+		return m.apiGatewayChildName(resource)
+	case resource.Type == "aws_cloudwatch_event_target":
+		target := m.sanitizeResourceName(m.getStringFromMetadata(resource.Metadata, "target_id", ""))
+		return fmt.Sprintf("%s_%s", nameOf("rule_arn", "rule"), target)
+	default:
+		groupName := m.names[m.getStringFromMetadata(resource.Metadata, "security_group_id", "")]
+		if groupName == "" {
+			groupName = "security_group"
+		}
+		direction := "ingress"
+		if resource.Type == "aws_vpc_security_group_egress_rule" {
+			direction = "egress"
+		}
+		return fmt.Sprintf("%s_%s_%s", groupName, direction, m.idSuffix(resource.ID))
+	}
+}
+
+// registerName records a unique Terraform name and address for a resource
+func (m *AWSMapper) registerName(resource discovery.Resource, name string, used map[string]bool) {
+	resourceType := m.terraformType(resource)
+
+	if used[resourceType+"."+name] {
+		name = fmt.Sprintf("%s_%s", name, m.idSuffix(resource.ID))
+	}
+	base := name
+	for i := 2; used[resourceType+"."+name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+
+	used[resourceType+"."+name] = true
+	m.names[resource.ID] = name
+	m.addresses[resource.ID] = resourceType + "." + name
+}
+
+// GetProviderConfig returns the provider configuration for the region the
+// resources were discovered in, aliased by the region so that resources of
+// several regions each select theirs. Global resources such as IAM roles have
+// no region and use us-east-1 unless they share a configuration with
+// regional resources (required by ResourceMapper interface)
 func (m *AWSMapper) GetProviderConfig(resources []discovery.Resource) (*generation.ProviderConfig, error) {
-	return &generation.ProviderConfig{
+	config := &generation.ProviderConfig{
 		Name:     "aws",
 		Source:   "hashicorp/aws",
 		Version:  "~> 5.0",
 		Required: true,
 		Config: map[string]interface{}{
-			"region": "us-east-1", // Default region
+			"region": "us-east-1",
 		},
-	}, nil
-}
-
-// GetDependencies analyzes and returns resource dependencies (required by ResourceMapper interface)
-func (m *AWSMapper) GetDependencies(resource discovery.Resource, allResources []discovery.Resource) ([]string, error) {
-	var dependencies []string
-
-	switch resource.Type {
-	case "aws_subnet":
-		// Subnets depend on VPCs
-		if vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", ""); vpcId != "" {
-			for _, res := range allResources {
-				if res.ID == vpcId && res.Type == "aws_vpc" {
-					dependencies = append(dependencies, fmt.Sprintf("aws_vpc.%s", m.sanitizeResourceName(res.Name)))
-					break
-				}
-			}
-		}
-
-	case "aws_instance":
-		// Instances depend on subnets and security groups
-		if subnetId := m.getStringFromMetadata(resource.Metadata, "subnet_id", ""); subnetId != "" {
-			for _, res := range allResources {
-				if res.ID == subnetId && res.Type == "aws_subnet" {
-					dependencies = append(dependencies, fmt.Sprintf("aws_subnet.%s", m.sanitizeResourceName(res.Name)))
-					break
-				}
-			}
-		}
+	}
 
-	case "aws_internet_gateway":
-		// Internet gateways depend on VPCs
-		if vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", ""); vpcId != "" {
-			for _, res := range allResources {
-				if res.ID == vpcId && res.Type == "aws_vpc" {
-					dependencies = append(dependencies, fmt.Sprintf("aws_vpc.%s", m.sanitizeResourceName(res.Name)))
-					break
-				}
-			}
+	for _, resource := range resources {
+		if resource.Region != "" && resource.Region != "global" {
+			config.Config["region"] = resource.Region
+			config.Alias = resource.Region
+			break
 		}
+	}
 
-	case "aws_route_table":
-		// Route tables depend on VPCs
-		if vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", ""); vpcId != "" {
-			for _, res := range allResources {
-				if res.ID == vpcId && res.Type == "aws_vpc" {
-					dependencies = append(dependencies, fmt.Sprintf("aws_vpc.%s", m.sanitizeResourceName(res.Name)))
-					break
-				}
-			}
-		}
+	return config, nil
+}
 
-	case "aws_security_group":
-		// Security groups depend on VPCs
-		if vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", ""); vpcId != "" {
-			for _, res := range allResources {
-				if res.ID == vpcId && res.Type == "aws_vpc" {
-					dependencies = append(dependencies, fmt.Sprintf("aws_vpc.%s", m.sanitizeResourceName(res.Name)))
-					break
-				}
-			}
-		}
+// GetDependencies returns the addresses of the resources a resource
+// references, as recorded when it is mapped, indexing allResources unless
+// SetResources was called (required by ResourceMapper interface)
+func (m *AWSMapper) GetDependencies(resource discovery.Resource, allResources []discovery.Resource) ([]string, error) {
+	if m.addresses == nil {
+		m.SetResources(allResources)
 	}
 
-	return dependencies, nil
+	mapped, err := m.mapResourceByType(resource)
+	if err != nil {
+		return nil, err
+	}
+	return mapped.Dependencies, nil
 }
 
 // ValidateMapping validates that the mapping is correct (required by ResourceMapper interface)
@@ -146,12 +332,58 @@ func (m *AWSMapper) GetSupportedTypes() []string {
 		"aws_vpc",
 		"aws_subnet",
 		"aws_security_group",
+		"aws_vpc_security_group_ingress_rule",
+		"aws_vpc_security_group_egress_rule",
 		"aws_instance",
 		"aws_internet_gateway",
+		"aws_egress_only_internet_gateway",
+		"aws_nat_gateway",
 		"aws_route_table",
+		"aws_route_table_association",
 		"aws_key_pair",
 		"aws_ebs_volume",
+		"aws_volume_attachment",
 		"aws_elastic_ip",
+		"aws_s3_bucket",
+		"aws_s3_bucket_versioning",
+		"aws_s3_bucket_server_side_encryption_configuration",
+		"aws_s3_bucket_lifecycle_configuration",
+		"aws_s3_bucket_public_access_block",
+		"aws_s3_bucket_policy",
+		"aws_s3_bucket_logging",
+		"aws_s3_bucket_replication_configuration",
+		"aws_iam_role",
+		"aws_iam_policy",
+		"aws_iam_user",
+		"aws_iam_group",
+		"aws_iam_instance_profile",
+		"aws_iam_role_policy",
+		"aws_iam_user_policy",
+		"aws_iam_group_policy",
+		"aws_iam_role_policy_attachment",
+		"aws_iam_user_policy_attachment",
+		"aws_iam_group_policy_attachment",
+		"aws_iam_user_group_membership",
+		"aws_db_instance",
+		"aws_rds_cluster",
+		"aws_rds_cluster_instance",
+		"aws_db_subnet_group",
+		"aws_db_parameter_group",
+		"aws_rds_cluster_parameter_group",
+		"aws_db_option_group",
+		"aws_lambda_function",
+		"aws_lambda_event_source_mapping",
+		"aws_api_gateway_rest_api",
+		"aws_api_gateway_resource",
+		"aws_api_gateway_method",
+		"aws_api_gateway_integration",
+		"aws_api_gateway_stage",
+		"aws_apigatewayv2_api",
+		"aws_apigatewayv2_integration",
+		"aws_apigatewayv2_route",
+		"aws_apigatewayv2_stage",
+		"aws_cloudwatch_event_rule",
+		"aws_cloudwatch_event_target",
 	}
 }
 
@@ -211,26 +443,24 @@ func (m *AWSMapper) mapSubnet(resource discovery.Resource) (*generation.MappedRe
 	vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", "")
 	mapPublicIpOnLaunch := m.getBoolFromMetadata(resource.Metadata, "map_public_ip_on_launch", false)
 
+	dependencies := []string{}
+	variables := m.generateSubnetVariables(resource)
+
 	config := map[string]interface{}{
-		"vpc_id":                   m.generateVPCReference(vpcId),
+		"vpc_id":                   m.generateVPCReference(vpcId, &dependencies, variables),
 		"cidr_block":              cidrBlock,
 		"availability_zone":       resource.Zone,
 		"map_public_ip_on_launch": mapPublicIpOnLaunch,
 		"tags":                    m.convertTags(resource.Tags),
 	}
 
-	dependencies := []string{}
-	if vpcId != "" {
-		dependencies = append(dependencies, fmt.Sprintf("aws_vpc.%s", m.sanitizeResourceName(vpcId)))
-	}
-
 	mapped := &generation.MappedResource{
 		OriginalResource: resource,
 		ResourceType:     "aws_subnet",
 		ResourceName:     m.generateResourceName(resource),
 		Configuration:    config,
 		Dependencies:     dependencies,
-		Variables:        m.generateSubnetVariables(resource),
+		Variables:        variables,
 		Outputs:          m.generateSubnetOutputs(resource),
 	}
 
@@ -242,45 +472,87 @@ func (m *AWSMapper) mapSecurityGroup(resource discovery.Resource) (*generation.M
 	vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", "")
 	description := m.getStringFromMetadata(resource.Metadata, "description", "Security group managed by Chimera")
 
+	dependencies := []string{}
+	variables := make(map[string]generation.Variable)
+
 	config := map[string]interface{}{
 		"name":        resource.Name,
 		"description": description,
-		"vpc_id":      m.generateVPCReference(vpcId),
+		"vpc_id":      m.generateVPCReference(vpcId, &dependencies, variables),
 		"tags":        m.convertTags(resource.Tags),
 	}
 
-	// Add default ingress and egress rules
-	config["ingress"] = []map[string]interface{}{
-		{
-			"from_port":   22,
-			"to_port":     22,
-			"protocol":    "tcp",
-			"cidr_blocks": []string{"0.0.0.0/0"},
-		},
+	// Rules discovered as separate resources are generated on their own;
+	// otherwise reproduce the discovered rules as inline blocks
+	if !m.groupsWithRules[resource.ID] {
+		if ingress := m.buildInlineRules(resource.ID, m.getMapSliceFromMetadata(resource.Metadata, "ingress_rules")); len(ingress) > 0 {
+			config["ingress"] = ingress
+		}
+		if egress := m.buildInlineRules(resource.ID, m.getMapSliceFromMetadata(resource.Metadata, "egress_rules")); len(egress) > 0 {
+			config["egress"] = egress
+		}
 	}
 
-	config["egress"] = []map[string]interface{}{
-		{
-			"from_port":   0,
-			"to_port":     0,
-			"protocol":    "-1",
-			"cidr_blocks": []string{"0.0.0.0/0"},
-		},
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_security_group",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        variables,
+		Outputs:          m.generateSecurityGroupOutputs(resource),
+	}
+
+	return mapped, nil
+}
+
+// mapSecurityGroupRule maps an AWS security group rule to a standalone
+// aws_vpc_security_group_ingress_rule or egress_rule resource
+func (m *AWSMapper) mapSecurityGroupRule(resource discovery.Resource) (*generation.MappedResource, error) {
+	groupId := m.getStringFromMetadata(resource.Metadata, "security_group_id", "")
+	if groupId == "" {
+		return nil, fmt.Errorf("security group rule %s has no security group", resource.ID)
+	}
+	protocol := m.getStringFromMetadata(resource.Metadata, "ip_protocol", "-1")
+
+	config := map[string]interface{}{
+		"security_group_id": m.generateSecurityGroupReference(groupId),
+		"ip_protocol":       protocol,
+		"tags":              m.convertTags(resource.Tags),
+	}
+
+	// Ports are not allowed when all protocols are permitted
+	if protocol != "-1" {
+		config["from_port"] = m.getIntFromMetadata(resource.Metadata, "from_port", 0)
+		config["to_port"] = m.getIntFromMetadata(resource.Metadata, "to_port", 0)
+	}
+
+	for _, key := range []string{"cidr_ipv4", "cidr_ipv6", "prefix_list_id", "description"} {
+		if value := m.getStringFromMetadata(resource.Metadata, key, ""); value != "" {
+			config[key] = value
+		}
 	}
 
 	dependencies := []string{}
-	if vpcId != "" {
-		dependencies = append(dependencies, fmt.Sprintf("aws_vpc.%s", m.sanitizeResourceName(vpcId)))
+	if address, exists := m.addresses[groupId]; exists {
+		dependencies = append(dependencies, address)
+	}
+
+	if referenced := m.getStringFromMetadata(resource.Metadata, "referenced_security_group_id", ""); referenced != "" {
+		config["referenced_security_group_id"] = m.generateSecurityGroupReference(referenced)
+		if address, exists := m.addresses[referenced]; exists && referenced != groupId {
+			dependencies = append(dependencies, address)
+		}
 	}
 
 	mapped := &generation.MappedResource{
 		OriginalResource: resource,
-		ResourceType:     "aws_security_group",
+		ResourceType:     resource.Type,
 		ResourceName:     m.generateResourceName(resource),
 		Configuration:    config,
 		Dependencies:     dependencies,
 		Variables:        make(map[string]generation.Variable),
-		Outputs:          m.generateSecurityGroupOutputs(resource),
+		Outputs:          make(map[string]generation.Output),
 	}
 
 	return mapped, nil
@@ -289,94 +561,321 @@ func (m *AWSMapper) mapSecurityGroup(resource discovery.Resource) (*generation.M
 // mapInstance maps an AWS EC2 Instance to Terraform resource
 func (m *AWSMapper) mapInstance(resource discovery.Resource) (*generation.MappedResource, error) {
 	instanceType := m.getStringFromMetadata(resource.Metadata, "instance_type", "t3.micro")
+	imageId := m.getStringFromMetadata(resource.Metadata, "image_id", "")
 	subnetId := m.getStringFromMetadata(resource.Metadata, "subnet_id", "")
-	vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", "")
 	keyName := m.getStringFromMetadata(resource.Metadata, "key_name", "")
+	resourceName := m.generateResourceName(resource)
+
+	if imageId == "" {
+		return nil, fmt.Errorf("instance %s has no image ID", resource.ID)
+	}
 
 	config := map[string]interface{}{
-		"ami":           "${data.aws_ami.latest.id}",
+		"ami":           imageId,
 		"instance_type": instanceType,
-		"subnet_id":     m.generateSubnetReference(subnetId),
 		"tags":          m.convertTags(resource.Tags),
 	}
 
+	variables := m.generateInstanceVariables(resource)
+	dependencies := []string{}
+
+	// Reference discovered resources, falling back to a variable holding the ID
+	reference := func(id, kind string) generation.Expression {
+		if address, exists := m.addresses[id]; exists {
+			dependencies = append(dependencies, address)
+			return generation.Expression(address + ".id")
+		}
+		return m.idVariable(id, kind, variables)
+	}
+
+	if subnetId != "" {
+		config["subnet_id"] = reference(subnetId, "subnet")
+	}
+
+	if groupIds := m.getStringSliceFromMetadata(resource.Metadata, "security_group_ids"); len(groupIds) > 0 {
+		groups := make([]interface{}, 0, len(groupIds))
+		for _, groupId := range groupIds {
+			groups = append(groups, reference(groupId, "security_group"))
+		}
+		config["vpc_security_group_ids"] = groups
+	}
+
 	if keyName != "" {
 		config["key_name"] = keyName
 	}
 
-	// Add security groups if available
-	config["vpc_security_group_ids"] = []string{"${aws_security_group.default.id}"}
+	if profile := m.getStringFromMetadata(resource.Metadata, "iam_instance_profile", ""); profile != "" {
+		config["iam_instance_profile"] = profile
+		if arn := m.getStringFromMetadata(resource.Metadata, "iam_instance_profile_arn", ""); arn != "" {
+			if _, exists := m.addresses[arn]; exists {
+				config["iam_instance_profile"] = m.generateAttributeReference(arn, "name", &dependencies)
+			}
+		}
+	}
 
-	dependencies := []string{}
-	if subnetId != "" {
-		dependencies = append(dependencies, fmt.Sprintf("aws_subnet.%s", m.sanitizeResourceName(subnetId)))
+	if _, exists := resource.Metadata["ebs_optimized"]; exists {
+		config["ebs_optimized"] = m.getBoolFromMetadata(resource.Metadata, "ebs_optimized", false)
+	}
+
+	if _, exists := resource.Metadata["monitoring"]; exists {
+		config["monitoring"] = m.getBoolFromMetadata(resource.Metadata, "monitoring", false)
+	}
+
+	if options := m.getMapFromMetadata(resource.Metadata, "metadata_options"); len(options) > 0 {
+		block := make(map[string]interface{})
+		for _, key := range []string{"http_endpoint", "http_tokens", "instance_metadata_tags"} {
+			if value := m.getStringFromMetadata(options, key, ""); value != "" {
+				block[key] = value
+			}
+		}
+		if hopLimit := m.getIntFromMetadata(options, "http_put_response_hop_limit", 0); hopLimit > 0 {
+			block["http_put_response_hop_limit"] = hopLimit
+		}
+		config["metadata_options"] = []map[string]interface{}{block}
+	}
+
+	var ebsDevices []map[string]interface{}
+	for _, device := range m.getMapSliceFromMetadata(resource.Metadata, "block_devices") {
+		block := m.buildBlockDevice(device)
+		if m.getBoolFromMetadata(device, "is_root", false) {
+			config["root_block_device"] = []map[string]interface{}{block}
+			continue
+		}
+		// Volumes with a discovered attachment are generated on their own
+		if m.attachedVolumes[m.getStringFromMetadata(device, "volume_id", "")] {
+			continue
+		}
+		block["device_name"] = m.getStringFromMetadata(device, "device_name", "")
+		if snapshotId := m.getStringFromMetadata(device, "snapshot_id", ""); snapshotId != "" {
+			block["snapshot_id"] = snapshotId
+		}
+		ebsDevices = append(ebsDevices, block)
 	}
-	if vpcId != "" {
-		dependencies = append(dependencies, fmt.Sprintf("aws_vpc.%s", m.sanitizeResourceName(vpcId)))
+	if len(ebsDevices) > 0 {
+		config["ebs_block_device"] = ebsDevices
+	}
+
+	// User data is never recorded by discovery, so it must be supplied
+	if userDataHash := m.getStringFromMetadata(resource.Metadata, "user_data_hash", ""); userDataHash != "" {
+		name := fmt.Sprintf("%s_user_data", resourceName)
+		config["user_data"] = generation.Expression("var." + name)
+		variables[name] = generation.Variable{
+			Name:        name,
+			Type:        "string",
+			Description: fmt.Sprintf("User data for instance %s (discovered SHA-256: %s)", resource.ID, userDataHash),
+			Sensitive:   true,
+			Required:    true,
+		}
 	}
 
 	mapped := &generation.MappedResource{
 		OriginalResource: resource,
 		ResourceType:     "aws_instance",
-		ResourceName:     m.generateResourceName(resource),
+		ResourceName:     resourceName,
 		Configuration:    config,
 		Dependencies:     dependencies,
-		Variables:        m.generateInstanceVariables(resource),
+		Variables:        variables,
 		Outputs:          m.generateInstanceOutputs(resource),
 	}
 
 	return mapped, nil
 }
 
+// buildBlockDevice converts discovered block device metadata into the
+// attributes shared by root_block_device and ebs_block_device
+func (m *AWSMapper) buildBlockDevice(device map[string]interface{}) map[string]interface{} {
+	block := map[string]interface{}{
+		"delete_on_termination": m.getBoolFromMetadata(device, "delete_on_termination", true),
+	}
+
+	if size := m.getIntFromMetadata(device, "volume_size", 0); size > 0 {
+		block["volume_size"] = size
+	}
+	if volumeType := m.getStringFromMetadata(device, "volume_type", ""); volumeType != "" {
+		block["volume_type"] = volumeType
+	}
+	if _, exists := device["encrypted"]; exists {
+		block["encrypted"] = m.getBoolFromMetadata(device, "encrypted", false)
+	}
+	if iops := m.getIntFromMetadata(device, "iops", 0); iops > 0 {
+		block["iops"] = iops
+	}
+	if throughput := m.getIntFromMetadata(device, "throughput", 0); throughput > 0 {
+		block["throughput"] = throughput
+	}
+	if kmsKeyId := m.getStringFromMetadata(device, "kms_key_id", ""); kmsKeyId != "" {
+		block["kms_key_id"] = kmsKeyId
+	}
+
+	return block
+}
+
 // mapInternetGateway maps an AWS Internet Gateway to Terraform resource
 func (m *AWSMapper) mapInternetGateway(resource discovery.Resource) (*generation.MappedResource, error) {
+	config := map[string]interface{}{
+		"tags": m.convertTags(resource.Tags),
+	}
+
+	// Detached gateways have no VPC
+	dependencies := []string{}
+	variables := make(map[string]generation.Variable)
+	if vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", ""); vpcId != "" {
+		config["vpc_id"] = m.generateVPCReference(vpcId, &dependencies, variables)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_internet_gateway",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        variables,
+		Outputs:          m.generateInternetGatewayOutputs(resource),
+	}
+
+	return mapped, nil
+}
+
+// mapEgressOnlyInternetGateway maps an AWS egress-only internet gateway to
+// Terraform resource
+func (m *AWSMapper) mapEgressOnlyInternetGateway(resource discovery.Resource) (*generation.MappedResource, error) {
 	vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", "")
+	if vpcId == "" {
+		return nil, fmt.Errorf("egress-only internet gateway %s is not attached to a VPC", resource.ID)
+	}
+
+	dependencies := []string{}
+	variables := make(map[string]generation.Variable)
 
 	config := map[string]interface{}{
-		"vpc_id": m.generateVPCReference(vpcId),
+		"vpc_id": m.generateVPCReference(vpcId, &dependencies, variables),
 		"tags":   m.convertTags(resource.Tags),
 	}
 
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_egress_only_internet_gateway",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        variables,
+		Outputs:          m.generateEgressOnlyInternetGatewayOutputs(resource),
+	}
+
+	return mapped, nil
+}
+
+// mapNATGateway maps an AWS NAT gateway to Terraform resource
+func (m *AWSMapper) mapNATGateway(resource discovery.Resource) (*generation.MappedResource, error) {
+	subnetId := m.getStringFromMetadata(resource.Metadata, "subnet_id", "")
+	if subnetId == "" {
+		return nil, fmt.Errorf("NAT gateway %s has no subnet", resource.ID)
+	}
+
 	dependencies := []string{}
-	if vpcId != "" {
-		dependencies = append(dependencies, fmt.Sprintf("aws_vpc.%s", m.sanitizeResourceName(vpcId)))
+	config := map[string]interface{}{
+		"subnet_id": m.generateReference(subnetId, &dependencies),
+		"tags":      m.convertTags(resource.Tags),
+	}
+
+	if connectivityType := m.getStringFromMetadata(resource.Metadata, "connectivity_type", ""); connectivityType != "" {
+		config["connectivity_type"] = connectivityType
+	}
+	if allocationId := m.getStringFromMetadata(resource.Metadata, "allocation_id", ""); allocationId != "" {
+		config["allocation_id"] = m.generateReference(allocationId, &dependencies)
+	}
+	if privateIp := m.getStringFromMetadata(resource.Metadata, "private_ip", ""); privateIp != "" {
+		config["private_ip"] = privateIp
+	}
+	if allocationIds := m.getStringSliceFromMetadata(resource.Metadata, "secondary_allocation_ids"); len(allocationIds) > 0 {
+		references := make([]interface{}, 0, len(allocationIds))
+		for _, allocationId := range allocationIds {
+			references = append(references, m.generateReference(allocationId, &dependencies))
+		}
+		config["secondary_allocation_ids"] = references
+	}
+	if privateIps := m.getStringSliceFromMetadata(resource.Metadata, "secondary_private_ip_addresses"); len(privateIps) > 0 {
+		config["secondary_private_ip_addresses"] = privateIps
 	}
 
 	mapped := &generation.MappedResource{
 		OriginalResource: resource,
-		ResourceType:     "aws_internet_gateway",
+		ResourceType:     "aws_nat_gateway",
 		ResourceName:     m.generateResourceName(resource),
 		Configuration:    config,
 		Dependencies:     dependencies,
 		Variables:        make(map[string]generation.Variable),
-		Outputs:          m.generateInternetGatewayOutputs(resource),
+		Outputs:          m.generateNATGatewayOutputs(resource),
 	}
 
 	return mapped, nil
 }
 
-// mapRouteTable maps an AWS Route Table to Terraform resource
+// routeDestinations maps discovered route destinations to route block attributes
+var routeDestinations = []struct{ key, attribute string }{
+	{"destination_cidr_block", "cidr_block"},
+	{"destination_ipv6_cidr_block", "ipv6_cidr_block"},
+	{"destination_prefix_list_id", "destination_prefix_list_id"},
+}
+
+// routeTargets maps discovered route targets to route block attributes.
+// Routes to an instance also carry its network interface, which is used
+// since the AWS provider no longer accepts instance targets.
+var routeTargets = []struct{ key, attribute string }{
+	{"gateway_id", "gateway_id"},
+	{"nat_gateway_id", "nat_gateway_id"},
+	{"egress_only_internet_gateway_id", "egress_only_gateway_id"},
+	{"transit_gateway_id", "transit_gateway_id"},
+	{"vpc_peering_connection_id", "vpc_peering_connection_id"},
+	{"network_interface_id", "network_interface_id"},
+	{"carrier_gateway_id", "carrier_gateway_id"},
+	{"local_gateway_id", "local_gateway_id"},
+	{"core_network_arn", "core_network_arn"},
+}
+
+// mapRouteTable maps an AWS Route Table to Terraform resource, with every
+// discovered route except the local route and propagated routes
 func (m *AWSMapper) mapRouteTable(resource discovery.Resource) (*generation.MappedResource, error) {
 	vpcId := m.getStringFromMetadata(resource.Metadata, "vpc_id", "")
 
+	dependencies := []string{}
+	variables := make(map[string]generation.Variable)
+
 	config := map[string]interface{}{
-		"vpc_id": m.generateVPCReference(vpcId),
+		"vpc_id": m.generateVPCReference(vpcId, &dependencies, variables),
 		"tags":   m.convertTags(resource.Tags),
 	}
 
-	// Add default route if this is a public route table
-	if strings.Contains(resource.Name, "public") || strings.Contains(strings.ToLower(resource.Name), "public") {
-		config["route"] = []map[string]interface{}{
-			{
-				"cidr_block": "0.0.0.0/0",
-				"gateway_id": "${aws_internet_gateway.main.id}",
-			},
+	var routes []map[string]interface{}
+	for _, route := range m.getMapSliceFromMetadata(resource.Metadata, "routes") {
+		switch m.getStringFromMetadata(route, "origin", "") {
+		case "CreateRouteTable", "EnableVgwRoutePropagation":
+			continue
+		}
+		if m.getStringFromMetadata(route, "gateway_id", "") == "local" {
+			continue
+		}
+
+		block := make(map[string]interface{})
+		for _, destination := range routeDestinations {
+			if value := m.getStringFromMetadata(route, destination.key, ""); value != "" {
+				block[destination.attribute] = value
+			}
 		}
+		for _, target := range routeTargets {
+			if value := m.getStringFromMetadata(route, target.key, ""); value != "" {
+				block[target.attribute] = m.generateReference(value, &dependencies)
+				break
+			}
+		}
+		routes = append(routes, block)
+	}
+	if len(routes) > 0 {
+		config["route"] = routes
 	}
 
-	dependencies := []string{}
-	if vpcId != "" {
-		dependencies = append(dependencies, fmt.Sprintf("aws_vpc.%s", m.sanitizeResourceName(vpcId)))
+	if gateways := m.getStringSliceFromMetadata(resource.Metadata, "propagating_vgws"); len(gateways) > 0 {
+		config["propagating_vgws"] = gateways
 	}
 
 	mapped := &generation.MappedResource{
@@ -385,13 +884,47 @@ func (m *AWSMapper) mapRouteTable(resource discovery.Resource) (*generation.Mapp
 		ResourceName:     m.generateResourceName(resource),
 		Configuration:    config,
 		Dependencies:     dependencies,
-		Variables:        make(map[string]generation.Variable),
+		Variables:        variables,
 		Outputs:          m.generateRouteTableOutputs(resource),
 	}
 
 	return mapped, nil
 }
 
+// mapRouteTableAssociation maps the association of a route table with a
+// subnet or gateway to Terraform resource
+func (m *AWSMapper) mapRouteTableAssociation(resource discovery.Resource) (*generation.MappedResource, error) {
+	routeTableId := m.getStringFromMetadata(resource.Metadata, "route_table_id", "")
+	if routeTableId == "" {
+		return nil, fmt.Errorf("route table association %s has no route table", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"route_table_id": m.generateReference(routeTableId, &dependencies),
+	}
+
+	if subnetId := m.getStringFromMetadata(resource.Metadata, "subnet_id", ""); subnetId != "" {
+		config["subnet_id"] = m.generateReference(subnetId, &dependencies)
+	} else if gatewayId := m.getStringFromMetadata(resource.Metadata, "gateway_id", ""); gatewayId != "" {
+		config["gateway_id"] = m.generateReference(gatewayId, &dependencies)
+	} else {
+		return nil, fmt.Errorf("route table association %s has no subnet or gateway", resource.ID)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_route_table_association",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
 // mapKeyPair maps an AWS Key Pair to Terraform resource
 func (m *AWSMapper) mapKeyPair(resource discovery.Resource) (*generation.MappedResource, error) {
 	keyName := resource.Name
@@ -401,17 +934,24 @@ func (m *AWSMapper) mapKeyPair(resource discovery.Resource) (*generation.MappedR
 
 	config := map[string]interface{}{
 		"key_name":   keyName,
-		"public_key": "${var.public_key}",
+		"public_key": generation.Expression("var.public_key"),
 		"tags":       m.convertTags(resource.Tags),
 	}
 
+	// Discovered public keys are used as is
+	variables := m.generateKeyPairVariables(resource)
+	if publicKey := m.getStringFromMetadata(resource.Metadata, "public_key", ""); publicKey != "" {
+		config["public_key"] = publicKey
+		variables = make(map[string]generation.Variable)
+	}
+
 	mapped := &generation.MappedResource{
 		OriginalResource: resource,
 		ResourceType:     "aws_key_pair",
 		ResourceName:     m.generateResourceName(resource),
 		Configuration:    config,
 		Dependencies:     []string{},
-		Variables:        m.generateKeyPairVariables(resource),
+		Variables:        variables,
 		Outputs:          m.generateKeyPairOutputs(resource),
 	}
 
@@ -435,6 +975,21 @@ func (m *AWSMapper) mapEBSVolume(resource discovery.Resource) (*generation.Mappe
 		config["encrypted"] = true
 	}
 
+	if iops := m.getIntFromMetadata(resource.Metadata, "iops", 0); iops > 0 && volumeType != "gp2" {
+		config["iops"] = iops
+	}
+	if throughput := m.getIntFromMetadata(resource.Metadata, "throughput", 0); throughput > 0 {
+		config["throughput"] = throughput
+	}
+	for _, key := range []string{"kms_key_id", "snapshot_id", "outpost_arn"} {
+		if value := m.getStringFromMetadata(resource.Metadata, key, ""); value != "" {
+			config[key] = value
+		}
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "multi_attach_enabled", false) {
+		config["multi_attach_enabled"] = true
+	}
+
 	mapped := &generation.MappedResource{
 		OriginalResource: resource,
 		ResourceType:     "aws_ebs_volume",
@@ -448,6 +1003,36 @@ func (m *AWSMapper) mapEBSVolume(resource discovery.Resource) (*generation.Mappe
 	return mapped, nil
 }
 
+// mapVolumeAttachment maps the attachment of an EBS volume to an instance to
+// Terraform resource
+func (m *AWSMapper) mapVolumeAttachment(resource discovery.Resource) (*generation.MappedResource, error) {
+	volumeId := m.getStringFromMetadata(resource.Metadata, "volume_id", "")
+	instanceId := m.getStringFromMetadata(resource.Metadata, "instance_id", "")
+	deviceName := m.getStringFromMetadata(resource.Metadata, "device_name", "")
+	if volumeId == "" || instanceId == "" || deviceName == "" {
+		return nil, fmt.Errorf("volume attachment %s has no volume, instance or device", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"device_name": deviceName,
+		"volume_id":   m.generateReference(volumeId, &dependencies),
+		"instance_id": m.generateReference(instanceId, &dependencies),
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_volume_attachment",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
 // mapElasticIP maps an AWS Elastic IP to Terraform resource
 func (m *AWSMapper) mapElasticIP(resource discovery.Resource) (*generation.MappedResource, error) {
 	domain := m.getStringFromMetadata(resource.Metadata, "domain", "vpc")
@@ -457,9 +1042,27 @@ func (m *AWSMapper) mapElasticIP(resource discovery.Resource) (*generation.Mappe
 		"tags":   m.convertTags(resource.Tags),
 	}
 
-	// Associate with instance if specified
+	if pool := m.getStringFromMetadata(resource.Metadata, "public_ipv4_pool", ""); pool != "" && pool != "amazon" {
+		config["public_ipv4_pool"] = pool
+	}
+	if group := m.getStringFromMetadata(resource.Metadata, "network_border_group", ""); group != "" {
+		config["network_border_group"] = group
+	}
+
+	// Associate with the instance or network interface; NAT gateways manage
+	// the association of their own addresses
+	dependencies := []string{}
+	associated := true
+	networkInterfaceId := m.getStringFromMetadata(resource.Metadata, "network_interface_id", "")
 	if instanceId := m.getStringFromMetadata(resource.Metadata, "instance_id", ""); instanceId != "" {
-		config["instance"] = fmt.Sprintf("${aws_instance.%s.id}", m.sanitizeResourceName(instanceId))
+		config["instance"] = m.generateReference(instanceId, &dependencies)
+	} else if networkInterfaceId != "" && !m.natInterfaces[networkInterfaceId] {
+		config["network_interface"] = networkInterfaceId
+	} else {
+		associated = false
+	}
+	if privateIp := m.getStringFromMetadata(resource.Metadata, "private_ip", ""); associated && privateIp != "" {
+		config["associate_with_private_ip"] = privateIp
 	}
 
 	mapped := &generation.MappedResource{
@@ -467,7 +1070,7 @@ func (m *AWSMapper) mapElasticIP(resource discovery.Resource) (*generation.Mappe
 		ResourceType:     "aws_eip",
 		ResourceName:     m.generateResourceName(resource),
 		Configuration:    config,
-		Dependencies:     []string{},
+		Dependencies:     dependencies,
 		Variables:        make(map[string]generation.Variable),
 		Outputs:          m.generateElasticIPOutputs(resource),
 	}
@@ -477,8 +1080,17 @@ func (m *AWSMapper) mapElasticIP(resource discovery.Resource) (*generation.Mappe
 
 // Helper methods
 
-// generateResourceName creates a Terraform-safe resource name
+// generateResourceName returns the Terraform name for a resource, preferring
+// the unique name assigned by SetResources
 func (m *AWSMapper) generateResourceName(resource discovery.Resource) string {
+	if name, exists := m.names[resource.ID]; exists {
+		return name
+	}
+	return m.baseResourceName(resource)
+}
+
+// baseResourceName creates a Terraform-safe resource name
+func (m *AWSMapper) baseResourceName(resource discovery.Resource) string {
 	name := resource.Name
 	if name == "" {
 		// Extract name from ID
@@ -520,20 +1132,356 @@ func (m *AWSMapper) sanitizeResourceName(name string) string {
 	return cleaned
 }
 
-// generateVPCReference generates a reference to a VPC resource
-func (m *AWSMapper) generateVPCReference(vpcId string) string {
-	if vpcId == "" {
-		return "${aws_vpc.main.id}"
+// generateImportID returns the ID terraform import expects for a resource;
+// most AWS resources import by ID, key pairs import by name, route table
+// associations by subnet or gateway ID and route table ID, bucket
+// configurations by bucket name, IAM resources as iamImportID describes, RDS
+// resources by identifier or name, Lambda functions by name, and API Gateway
+// and EventBridge resources as apiGatewayImportID and eventBridgeImportID
+// describe. Security group rules split from their group without a rule ID
+// have no import ID.
+func (m *AWSMapper) generateImportID(resource discovery.Resource) string {
+	if m.isS3BucketConfiguration(resource.Type) {
+		return m.getStringFromMetadata(resource.Metadata, "bucket", resource.ID)
+	}
+	if m.isRDSResource(resource.Type) {
+		return resource.Name
+	}
+	if strings.HasPrefix(resource.Type, "aws_iam_") {
+		return m.iamImportID(resource)
 	}
-	return fmt.Sprintf("${aws_vpc.%s.id}", m.sanitizeResourceName(vpcId))
+	if m.isAPIGatewayResource(resource.Type) {
+		return m.apiGatewayImportID(resource)
+	}
+
+	switch resource.Type {
+	case "aws_key_pair":
+		if keyName := m.getStringFromMetadata(resource.Metadata, "key_name", ""); keyName != "" {
+			return keyName
+		}
+		return resource.Name
+	case "aws_lambda_function":
+		return resource.Name
+	case "aws_vpc_security_group_ingress_rule", "aws_vpc_security_group_egress_rule":
+		// Rules split from a group discovered without rule IDs cannot be imported
+		if !strings.HasPrefix(resource.ID, "sgr-") {
+			return ""
+		}
+	case "aws_cloudwatch_event_rule", "aws_cloudwatch_event_target":
+		return m.eventBridgeImportID(resource)
+	case "aws_route_table_association":
+		target := m.getStringFromMetadata(resource.Metadata, "subnet_id", "")
+		if target == "" {
+			target = m.getStringFromMetadata(resource.Metadata, "gateway_id", "")
+		}
+		return target + "/" + m.getStringFromMetadata(resource.Metadata, "route_table_id", "")
+	}
+	return resource.ID
 }
 
-// generateSubnetReference generates a reference to a subnet resource
-func (m *AWSMapper) generateSubnetReference(subnetId string) string {
-	if subnetId == "" {
-		return "${aws_subnet.main.id}"
+// generateVPCReference references a discovered VPC, recording it as a
+// dependency, or falls back to a variable holding the ID of an existing VPC
+func (m *AWSMapper) generateVPCReference(vpcId string, dependencies *[]string, variables map[string]generation.Variable) generation.Expression {
+	if address, exists := m.addresses[vpcId]; exists {
+		*dependencies = append(*dependencies, address)
+		return generation.Expression(address + ".id")
+	}
+	return m.idVariable(vpcId, "vpc", variables)
+}
+
+// idVariable declares a variable holding the ID of an existing resource that
+// is not being generated and returns a reference to it. The variable has no
+// default when the ID is unknown.
+func (m *AWSMapper) idVariable(id, kind string, variables map[string]generation.Variable) generation.Expression {
+	description := strings.ReplaceAll(kind, "_", " ")
+	if id == "" {
+		name := kind + "_id"
+		variables[name] = generation.Variable{
+			Name:        name,
+			Type:        "string",
+			Description: fmt.Sprintf("ID of the existing %s", description),
+			Required:    true,
+		}
+		return generation.Expression("var." + name)
+	}
+
+	name := fmt.Sprintf("%s_%s_id", kind, m.idSuffix(id))
+	variables[name] = generation.Variable{
+		Name:        name,
+		Type:        "string",
+		Description: fmt.Sprintf("ID of existing %s %s", description, id),
+		Default:     id,
+	}
+	return generation.Expression("var." + name)
+}
+
+// generateReference references a discovered resource, recording it as a
+// dependency, or falls back to the literal ID when it is not being generated
+func (m *AWSMapper) generateReference(id string, dependencies *[]string) interface{} {
+	return m.generateAttributeReference(id, "id", dependencies)
+}
+
+// generateAttributeReference references an attribute of a discovered
+// resource like generateReference
+func (m *AWSMapper) generateAttributeReference(id, attribute string, dependencies *[]string) interface{} {
+	address, exists := m.addresses[id]
+	if !exists {
+		return id
+	}
+	for _, dependency := range *dependencies {
+		if dependency == address {
+			return generation.Expression(address + "." + attribute)
+		}
+	}
+	*dependencies = append(*dependencies, address)
+	return generation.Expression(address + "." + attribute)
+}
+
+// nameReference references the name of a discovered resource, such as an
+// IAM role or a DB subnet group, or falls back to the literal name when it
+// is not being generated
+func (m *AWSMapper) nameReference(name, id string, dependencies *[]string) interface{} {
+	if _, exists := m.addresses[id]; exists {
+		return m.generateAttributeReference(id, "name", dependencies)
+	}
+	return name
+}
+
+// generateSecurityGroupReference references a discovered security group, or
+// falls back to the literal group ID when it is not being generated
+func (m *AWSMapper) generateSecurityGroupReference(groupId string) interface{} {
+	if address, exists := m.addresses[groupId]; exists {
+		return generation.Expression(address + ".id")
+	}
+	return groupId
+}
+
+// buildInlineRules expands discovered IP permissions into inline ingress or
+// egress blocks, one block per source so every description is kept
+func (m *AWSMapper) buildInlineRules(groupId string, permissions []map[string]interface{}) []map[string]interface{} {
+	var blocks []map[string]interface{}
+
+	for _, perm := range permissions {
+		newBlock := func(description string) map[string]interface{} {
+			block := map[string]interface{}{
+				"protocol":  m.getStringFromMetadata(perm, "protocol", "-1"),
+				"from_port": m.getIntFromMetadata(perm, "from_port", 0),
+				"to_port":   m.getIntFromMetadata(perm, "to_port", 0),
+			}
+			if description != "" {
+				block["description"] = description
+			}
+			return block
+		}
+
+		for _, r := range m.getMapSliceFromMetadata(perm, "cidr_blocks") {
+			block := newBlock(m.getStringFromMetadata(r, "description", ""))
+			block["cidr_blocks"] = []string{m.getStringFromMetadata(r, "cidr", "")}
+			blocks = append(blocks, block)
+		}
+
+		for _, r := range m.getMapSliceFromMetadata(perm, "ipv6_cidr_blocks") {
+			block := newBlock(m.getStringFromMetadata(r, "description", ""))
+			block["ipv6_cidr_blocks"] = []string{m.getStringFromMetadata(r, "cidr", "")}
+			blocks = append(blocks, block)
+		}
+
+		for _, p := range m.getMapSliceFromMetadata(perm, "prefix_list_ids") {
+			block := newBlock(m.getStringFromMetadata(p, "description", ""))
+			block["prefix_list_ids"] = []string{m.getStringFromMetadata(p, "prefix_list_id", "")}
+			blocks = append(blocks, block)
+		}
+
+		for _, g := range m.getMapSliceFromMetadata(perm, "security_groups") {
+			block := newBlock(m.getStringFromMetadata(g, "description", ""))
+			if referenced := m.getStringFromMetadata(g, "group_id", ""); referenced == groupId {
+				block["self"] = true
+			} else {
+				block["security_groups"] = []interface{}{m.generateSecurityGroupReference(referenced)}
+			}
+			blocks = append(blocks, block)
+		}
+	}
+
+	return blocks
+}
+
+// ExpandResources splits the rules of security groups that reference another
+// group being generated into standalone rule resources (required by
+// ResourceExpander interface). Inline references between groups, or from a
+// group to an instance's group that references it back, form cycles that
+// neither Terraform nor CloudFormation can create. Rules discovered without a
+// rule ID cannot be imported and are reported as warnings.
+func (m *AWSMapper) ExpandResources(resources []discovery.Resource) ([]discovery.Resource, []generation.GenerationWarning) {
+	groups := make(map[string]bool)
+	groupsWithRules := make(map[string]bool)
+	for _, resource := range resources {
+		switch {
+		case resource.Provider != discovery.AWS:
+		case resource.Type == "aws_security_group":
+			groups[resource.ID] = true
+		case m.isSecurityGroupRule(resource.Type):
+			groupsWithRules[m.getStringFromMetadata(resource.Metadata, "security_group_id", "")] = true
+		}
+	}
+
+	expanded := make([]discovery.Resource, 0, len(resources))
+	var warnings []generation.GenerationWarning
+	for _, resource := range resources {
+		if resource.Provider != discovery.AWS || resource.Type != "aws_security_group" ||
+			groupsWithRules[resource.ID] || !m.referencesOtherGroup(resource, groups) {
+			expanded = append(expanded, resource)
+			continue
+		}
+
+		// The rule resources carry the rules from now on, so the group no
+		// longer refers to the groups they reference
+		group := resource
+		group.Metadata = make(map[string]interface{}, len(resource.Metadata))
+		for key, value := range resource.Metadata {
+			if key != "ingress_rules" && key != "egress_rules" {
+				group.Metadata[key] = value
+			}
+		}
+		expanded = append(expanded, group)
+
+		rules := m.splitSecurityGroupRules(resource)
+		expanded = append(expanded, rules...)
+		for _, rule := range rules {
+			if strings.HasPrefix(rule.ID, "sgr-") {
+				continue
+			}
+			warnings = append(warnings, generation.GenerationWarning{
+				ResourceID:   rule.ID,
+				ResourceType: rule.Type,
+				Provider:     discovery.AWS,
+				Message:      fmt.Sprintf("%s of security group %s was discovered without a rule ID and cannot be imported", m.describeSecurityGroupRule(rule), resource.ID),
+				Type:         generation.WarningTypeManualAction,
+				Suggestion:   "Rediscover with permission for ec2:DescribeSecurityGroupRules, or import the rule by hand once its sgr- ID is known",
+			})
+		}
+	}
+	return expanded, warnings
+}
+
+// describeSecurityGroupRule describes a split rule by its direction, protocol,
+// ports and source, such as "ingress rule tcp 22-22 from 10.0.0.0/8"
+func (m *AWSMapper) describeSecurityGroupRule(rule discovery.Resource) string {
+	direction, preposition := "ingress", "from"
+	if m.getBoolFromMetadata(rule.Metadata, "is_egress", false) {
+		direction, preposition = "egress", "to"
+	}
+
+	var source string
+	for _, key := range []string{"cidr_ipv4", "cidr_ipv6", "prefix_list_id", "referenced_security_group_id"} {
+		if source = m.getStringFromMetadata(rule.Metadata, key, ""); source != "" {
+			break
+		}
+	}
+
+	return fmt.Sprintf("%s rule %s %d-%d %s %s", direction,
+		m.getStringFromMetadata(rule.Metadata, "ip_protocol", "-1"),
+		m.getIntFromMetadata(rule.Metadata, "from_port", 0),
+		m.getIntFromMetadata(rule.Metadata, "to_port", 0),
+		preposition, source)
+}
+
+// referencesOtherGroup reports whether a security group has a rule whose
+// source or destination is another of the given groups
+func (m *AWSMapper) referencesOtherGroup(group discovery.Resource, groups map[string]bool) bool {
+	for _, key := range []string{"ingress_rules", "egress_rules"} {
+		for _, perm := range m.getMapSliceFromMetadata(group.Metadata, key) {
+			for _, g := range m.getMapSliceFromMetadata(perm, "security_groups") {
+				if referenced := m.getStringFromMetadata(g, "group_id", ""); referenced != group.ID && groups[referenced] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// splitSecurityGroupRules returns a standalone rule resource for every source
+// of the discovered rules of a security group, identified by its rule ID when
+// discovery recorded one
+func (m *AWSMapper) splitSecurityGroupRules(group discovery.Resource) []discovery.Resource {
+	var rules []discovery.Resource
+
+	for _, direction := range []string{"ingress", "egress"} {
+		for _, perm := range m.getMapSliceFromMetadata(group.Metadata, direction+"_rules") {
+			newRule := func(source map[string]interface{}, key, value string) {
+				metadata := map[string]interface{}{
+					"security_group_id": group.ID,
+					"ip_protocol":       m.getStringFromMetadata(perm, "protocol", "-1"),
+					"from_port":         m.getIntFromMetadata(perm, "from_port", 0),
+					"to_port":           m.getIntFromMetadata(perm, "to_port", 0),
+					"is_egress":         direction == "egress",
+					key:                 value,
+				}
+				if description := m.getStringFromMetadata(source, "description", ""); description != "" {
+					metadata["description"] = description
+				}
+
+				id := m.getStringFromMetadata(source, "rule_id", "")
+				if id == "" {
+					id = fmt.Sprintf("%s-%s-%d", group.ID, direction, len(rules)+1)
+				}
+				rules = append(rules, discovery.Resource{
+					ID:       id,
+					Type:     "aws_vpc_security_group_" + direction + "_rule",
+					Provider: discovery.AWS,
+					Region:   group.Region,
+					Metadata: metadata,
+				})
+			}
+
+			for _, r := range m.getMapSliceFromMetadata(perm, "cidr_blocks") {
+				newRule(r, "cidr_ipv4", m.getStringFromMetadata(r, "cidr", ""))
+			}
+			for _, r := range m.getMapSliceFromMetadata(perm, "ipv6_cidr_blocks") {
+				newRule(r, "cidr_ipv6", m.getStringFromMetadata(r, "cidr", ""))
+			}
+			for _, p := range m.getMapSliceFromMetadata(perm, "prefix_list_ids") {
+				newRule(p, "prefix_list_id", m.getStringFromMetadata(p, "prefix_list_id", ""))
+			}
+			for _, g := range m.getMapSliceFromMetadata(perm, "security_groups") {
+				newRule(g, "referenced_security_group_id", m.getStringFromMetadata(g, "group_id", ""))
+			}
+		}
+	}
+
+	return rules
+}
+
+// isSecurityGroupRule reports whether a type is a standalone security group rule
+func (m *AWSMapper) isSecurityGroupRule(resourceType string) bool {
+	return resourceType == "aws_vpc_security_group_ingress_rule" || resourceType == "aws_vpc_security_group_egress_rule"
+}
+
+// terraformType returns the Terraform resource type for a discovered resource
+func (m *AWSMapper) terraformType(resource discovery.Resource) string {
+	switch {
+	case resource.Type == "aws_elastic_ip":
+		return "aws_eip"
+	case m.isServiceLinkedRole(resource):
+		return "aws_iam_service_linked_role"
+	}
+	return resource.Type
+}
+
+// idSuffix returns the unique part of an AWS resource ID (e.g. "0abc" from "sg-0abc")
+func (m *AWSMapper) idSuffix(id string) string {
+	if idx := strings.LastIndex(id, "-"); idx >= 0 {
+		id = id[idx+1:]
+	}
+
+	var result strings.Builder
+	for _, r := range id {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			result.WriteRune(r)
+		}
 	}
-	return fmt.Sprintf("${aws_subnet.%s.id}", m.sanitizeResourceName(subnetId))
+	return result.String()
 }
 
 // convertTags converts discovery tags to Terraform format
@@ -616,12 +1564,12 @@ func (m *AWSMapper) generateVPCOutputs(resource discovery.Resource) map[string]g
 	return map[string]generation.Output{
 		"id": {
 			Name:        fmt.Sprintf("%s_id", resourceName),
-			Value:       fmt.Sprintf("${aws_vpc.%s.id}", resourceName),
+			Value:       fmt.Sprintf("aws_vpc.%s.id", resourceName),
 			Description: "ID of the VPC",
 		},
 		"cidr_block": {
 			Name:        fmt.Sprintf("%s_cidr", resourceName),
-			Value:       fmt.Sprintf("${aws_vpc.%s.cidr_block}", resourceName),
+			Value:       fmt.Sprintf("aws_vpc.%s.cidr_block", resourceName),
 			Description: "CIDR block of the VPC",
 		},
 	}
@@ -632,7 +1580,7 @@ func (m *AWSMapper) generateSubnetOutputs(resource discovery.Resource) map[strin
 	return map[string]generation.Output{
 		"id": {
 			Name:        fmt.Sprintf("%s_id", resourceName),
-			Value:       fmt.Sprintf("${aws_subnet.%s.id}", resourceName),
+			Value:       fmt.Sprintf("aws_subnet.%s.id", resourceName),
 			Description: "ID of the subnet",
 		},
 	}
@@ -643,7 +1591,7 @@ func (m *AWSMapper) generateSecurityGroupOutputs(resource discovery.Resource) ma
 	return map[string]generation.Output{
 		"id": {
 			Name:        fmt.Sprintf("%s_id", resourceName),
-			Value:       fmt.Sprintf("${aws_security_group.%s.id}", resourceName),
+			Value:       fmt.Sprintf("aws_security_group.%s.id", resourceName),
 			Description: "ID of the security group",
 		},
 	}
@@ -654,12 +1602,12 @@ func (m *AWSMapper) generateInstanceOutputs(resource discovery.Resource) map[str
 	return map[string]generation.Output{
 		"id": {
 			Name:        fmt.Sprintf("%s_id", resourceName),
-			Value:       fmt.Sprintf("${aws_instance.%s.id}", resourceName),
+			Value:       fmt.Sprintf("aws_instance.%s.id", resourceName),
 			Description: "ID of the instance",
 		},
 		"public_ip": {
 			Name:        fmt.Sprintf("%s_public_ip", resourceName),
-			Value:       fmt.Sprintf("${aws_instance.%s.public_ip}", resourceName),
+			Value:       fmt.Sprintf("aws_instance.%s.public_ip", resourceName),
 			Description: "Public IP of the instance",
 		},
 	}
@@ -670,18 +1618,40 @@ func (m *AWSMapper) generateInternetGatewayOutputs(resource discovery.Resource)
 	return map[string]generation.Output{
 		"id": {
 			Name:        fmt.Sprintf("%s_id", resourceName),
-			Value:       fmt.Sprintf("${aws_internet_gateway.%s.id}", resourceName),
+			Value:       fmt.Sprintf("aws_internet_gateway.%s.id", resourceName),
 			Description: "ID of the internet gateway",
 		},
 	}
 }
 
+func (m *AWSMapper) generateEgressOnlyInternetGatewayOutputs(resource discovery.Resource) map[string]generation.Output {
+	resourceName := m.generateResourceName(resource)
+	return map[string]generation.Output{
+		"id": {
+			Name:        fmt.Sprintf("%s_id", resourceName),
+			Value:       fmt.Sprintf("aws_egress_only_internet_gateway.%s.id", resourceName),
+			Description: "ID of the egress-only internet gateway",
+		},
+	}
+}
+
+func (m *AWSMapper) generateNATGatewayOutputs(resource discovery.Resource) map[string]generation.Output {
+	resourceName := m.generateResourceName(resource)
+	return map[string]generation.Output{
+		"id": {
+			Name:        fmt.Sprintf("%s_id", resourceName),
+			Value:       fmt.Sprintf("aws_nat_gateway.%s.id", resourceName),
+			Description: "ID of the NAT gateway",
+		},
+	}
+}
+
 func (m *AWSMapper) generateRouteTableOutputs(resource discovery.Resource) map[string]generation.Output {
 	resourceName := m.generateResourceName(resource)
 	return map[string]generation.Output{
 		"id": {
 			Name:        fmt.Sprintf("%s_id", resourceName),
-			Value:       fmt.Sprintf("${aws_route_table.%s.id}", resourceName),
+			Value:       fmt.Sprintf("aws_route_table.%s.id", resourceName),
 			Description: "ID of the route table",
 		},
 	}
@@ -692,7 +1662,7 @@ func (m *AWSMapper) generateKeyPairOutputs(resource discovery.Resource) map[stri
 	return map[string]generation.Output{
 		"name": {
 			Name:        fmt.Sprintf("%s_name", resourceName),
-			Value:       fmt.Sprintf("${aws_key_pair.%s.key_name}", resourceName),
+			Value:       fmt.Sprintf("aws_key_pair.%s.key_name", resourceName),
 			Description: "Name of the key pair",
 		},
 	}
@@ -703,7 +1673,7 @@ func (m *AWSMapper) generateEBSVolumeOutputs(resource discovery.Resource) map[st
 	return map[string]generation.Output{
 		"id": {
 			Name:        fmt.Sprintf("%s_id", resourceName),
-			Value:       fmt.Sprintf("${aws_ebs_volume.%s.id}", resourceName),
+			Value:       fmt.Sprintf("aws_ebs_volume.%s.id", resourceName),
 			Description: "ID of the EBS volume",
 		},
 	}
@@ -714,7 +1684,7 @@ func (m *AWSMapper) generateElasticIPOutputs(resource discovery.Resource) map[st
 	return map[string]generation.Output{
 		"public_ip": {
 			Name:        fmt.Sprintf("%s_public_ip", resourceName),
-			Value:       fmt.Sprintf("${aws_eip.%s.public_ip}", resourceName),
+			Value:       fmt.Sprintf("aws_eip.%s.public_ip", resourceName),
 			Description: "Public IP of the Elastic IP",
 		},
 	}
@@ -756,3 +1726,77 @@ func (m *AWSMapper) getIntFromMetadata(metadata map[string]interface{}, key stri
 	}
 	return defaultValue
 }
+
+func (m *AWSMapper) getFloatFromMetadata(metadata map[string]interface{}, key string, defaultValue float64) float64 {
+	if value, exists := metadata[key]; exists {
+		if f64, ok := value.(float64); ok {
+			return f64
+		}
+		if i, ok := value.(int); ok {
+			return float64(i)
+		}
+	}
+	return defaultValue
+}
+
+// getMapSliceFromMetadata reads a list of objects, accepting both the
+// in-memory form and the []interface{} form produced by a JSON round trip
+func (m *AWSMapper) getMapSliceFromMetadata(metadata map[string]interface{}, key string) []map[string]interface{} {
+	switch value := metadata[key].(type) {
+	case []map[string]interface{}:
+		return value
+	case []interface{}:
+		result := make([]map[string]interface{}, 0, len(value))
+		for _, item := range value {
+			if entry, ok := item.(map[string]interface{}); ok {
+				result = append(result, entry)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// getStringSliceFromMetadata reads a list of strings, accepting both the
+// in-memory form and the []interface{} form produced by a JSON round trip
+func (m *AWSMapper) getStringSliceFromMetadata(metadata map[string]interface{}, key string) []string {
+	switch value := metadata[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// getMapFromMetadata reads a nested object from metadata
+func (m *AWSMapper) getMapFromMetadata(metadata map[string]interface{}, key string) map[string]interface{} {
+	if value, ok := metadata[key].(map[string]interface{}); ok {
+		return value
+	}
+	return nil
+}
+
+// getStringMapFromMetadata reads a map of strings, such as tags, accepting
+// both the in-memory form and the form produced by a JSON round trip
+func (m *AWSMapper) getStringMapFromMetadata(metadata map[string]interface{}, key string) map[string]string {
+	switch value := metadata[key].(type) {
+	case map[string]string:
+		return value
+	case map[string]interface{}:
+		result := make(map[string]string, len(value))
+		for k, item := range value {
+			if str, ok := item.(string); ok {
+				result[k] = str
+			}
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,482 @@
+package mappers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// isAPIGatewayResource reports whether a type is an API Gateway REST or HTTP
+// API resource
+func (m *AWSMapper) isAPIGatewayResource(resourceType string) bool {
+	return strings.HasPrefix(resourceType, "aws_api_gateway_") || strings.HasPrefix(resourceType, "aws_apigatewayv2_")
+}
+
+// isAPIGatewayChild reports whether a type is part of a REST or HTTP API,
+// such as a resource, method, route or stage, and named after its API
+func (m *AWSMapper) isAPIGatewayChild(resourceType string) bool {
+	return m.isAPIGatewayResource(resourceType) &&
+		resourceType != "aws_api_gateway_rest_api" && resourceType != "aws_apigatewayv2_api"
+}
+
+// mapAPIGatewayRestAPI maps an API Gateway REST API to Terraform resource
+func (m *AWSMapper) mapAPIGatewayRestAPI(resource discovery.Resource) (*generation.MappedResource, error) {
+	resourceName := m.generateResourceName(resource)
+	config := map[string]interface{}{
+		"name": resource.Name,
+		"tags": m.convertTags(resource.Tags),
+	}
+
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	if types := m.getStringSliceFromMetadata(resource.Metadata, "endpoint_types"); len(types) > 0 {
+		config["endpoint_configuration"] = map[string]interface{}{"types": types}
+	}
+	if mediaTypes := m.getStringSliceFromMetadata(resource.Metadata, "binary_media_types"); len(mediaTypes) > 0 {
+		config["binary_media_types"] = mediaTypes
+	}
+	if source := m.getStringFromMetadata(resource.Metadata, "api_key_source", ""); source != "" && source != "HEADER" {
+		config["api_key_source"] = source
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "disable_execute_api_endpoint", false) {
+		config["disable_execute_api_endpoint"] = true
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_api_gateway_rest_api",
+		ResourceName:     resourceName,
+		Configuration:    config,
+		Dependencies:     []string{},
+		Variables:        make(map[string]generation.Variable),
+		Outputs: map[string]generation.Output{
+			"id": {
+				Name:        fmt.Sprintf("%s_rest_api_id", resourceName),
+				Value:       fmt.Sprintf("aws_api_gateway_rest_api.%s.id", resourceName),
+				Description: "ID of the API Gateway REST API",
+			},
+		},
+	}
+
+	return mapped, nil
+}
+
+// mapAPIGatewayResource maps a path of a REST API to Terraform resource
+func (m *AWSMapper) mapAPIGatewayResource(resource discovery.Resource) (*generation.MappedResource, error) {
+	api := m.getStringFromMetadata(resource.Metadata, "rest_api_id", "")
+	if api == "" {
+		return nil, fmt.Errorf("API Gateway resource %s has no REST API", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"rest_api_id": m.generateReference(api, &dependencies),
+		"parent_id":   m.apiGatewayResourceReference(resource, m.getStringFromMetadata(resource.Metadata, "parent_id", ""), &dependencies),
+		"path_part":   m.getStringFromMetadata(resource.Metadata, "path_part", ""),
+	}
+
+	return m.apiGatewayChild(resource, config, dependencies), nil
+}
+
+// mapAPIGatewayMethod maps a method of a REST API resource to Terraform resource
+func (m *AWSMapper) mapAPIGatewayMethod(resource discovery.Resource) (*generation.MappedResource, error) {
+	api := m.getStringFromMetadata(resource.Metadata, "rest_api_id", "")
+	if api == "" {
+		return nil, fmt.Errorf("API Gateway method %s has no REST API", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"rest_api_id":   m.generateReference(api, &dependencies),
+		"resource_id":   m.apiGatewayResourceReference(resource, m.getStringFromMetadata(resource.Metadata, "resource_id", ""), &dependencies),
+		"http_method":   m.getStringFromMetadata(resource.Metadata, "http_method", ""),
+		"authorization": m.getStringFromMetadata(resource.Metadata, "authorization", "NONE"),
+	}
+
+	// Authorizers are not discovered, so they are referenced by ID
+	if authorizer := m.getStringFromMetadata(resource.Metadata, "authorizer_id", ""); authorizer != "" {
+		config["authorizer_id"] = authorizer
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "api_key_required", false) {
+		config["api_key_required"] = true
+	}
+	if operation := m.getStringFromMetadata(resource.Metadata, "operation_name", ""); operation != "" {
+		config["operation_name"] = operation
+	}
+	if parameters := m.getMapFromMetadata(resource.Metadata, "request_parameters"); len(parameters) > 0 {
+		config["request_parameters"] = generation.Map(parameters)
+	}
+
+	return m.apiGatewayChild(resource, config, dependencies), nil
+}
+
+// mapAPIGatewayIntegration maps the integration of a REST API method to
+// Terraform resource, invoking the discovered Lambda function it proxies to
+func (m *AWSMapper) mapAPIGatewayIntegration(resource discovery.Resource) (*generation.MappedResource, error) {
+	api := m.getStringFromMetadata(resource.Metadata, "rest_api_id", "")
+	if api == "" {
+		return nil, fmt.Errorf("API Gateway integration %s has no REST API", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"rest_api_id": m.generateReference(api, &dependencies),
+		"resource_id": m.apiGatewayResourceReference(resource, m.getStringFromMetadata(resource.Metadata, "resource_id", ""), &dependencies),
+		"http_method": m.getStringFromMetadata(resource.Metadata, "http_method", ""),
+		"type":        m.getStringFromMetadata(resource.Metadata, "type", ""),
+	}
+
+	// The integration can only be created once its method exists
+	if method := m.getStringFromMetadata(resource.Metadata, "method_id", ""); method != "" {
+		if _, exists := m.addresses[method]; exists {
+			config["http_method"] = m.generateAttributeReference(method, "http_method", &dependencies)
+		}
+	}
+
+	if function := m.getStringFromMetadata(resource.Metadata, "function_arn", ""); m.addresses[function] != "" {
+		config["uri"] = m.generateAttributeReference(function, "invoke_arn", &dependencies)
+	} else if uri := m.getStringFromMetadata(resource.Metadata, "uri", ""); uri != "" {
+		config["uri"] = uri
+	}
+	if credentials := m.getStringFromMetadata(resource.Metadata, "credentials", ""); credentials != "" {
+		config["credentials"] = m.generateAttributeReference(credentials, "arn", &dependencies)
+	}
+
+	for _, key := range []string{"integration_http_method", "passthrough_behavior", "content_handling", "connection_id"} {
+		if value := m.getStringFromMetadata(resource.Metadata, key, ""); value != "" {
+			config[key] = value
+		}
+	}
+	// Integrations connect over the internet within 29 seconds by default
+	if connection := m.getStringFromMetadata(resource.Metadata, "connection_type", ""); connection != "" && connection != "INTERNET" {
+		config["connection_type"] = connection
+	}
+	if timeout := m.getIntFromMetadata(resource.Metadata, "timeout_milliseconds", 0); timeout > 0 && timeout != 29000 {
+		config["timeout_milliseconds"] = timeout
+	}
+	if parameters := m.getStringMapFromMetadata(resource.Metadata, "request_parameters"); len(parameters) > 0 {
+		config["request_parameters"] = parameters
+	}
+	if templates := m.getStringMapFromMetadata(resource.Metadata, "request_templates"); len(templates) > 0 {
+		config["request_templates"] = templates
+	}
+
+	return m.apiGatewayChild(resource, config, dependencies), nil
+}
+
+// mapAPIGatewayStage maps a stage of a REST API to Terraform resource.
+// Deployments are snapshots of the API that are not discovered, so the
+// stage keeps pointing at the deployment it currently serves.
+func (m *AWSMapper) mapAPIGatewayStage(resource discovery.Resource) (*generation.MappedResource, error) {
+	api := m.getStringFromMetadata(resource.Metadata, "rest_api_id", "")
+	if api == "" {
+		return nil, fmt.Errorf("API Gateway stage %s has no REST API", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"rest_api_id":   m.generateReference(api, &dependencies),
+		"stage_name":    m.getStringFromMetadata(resource.Metadata, "stage_name", ""),
+		"deployment_id": m.getStringFromMetadata(resource.Metadata, "deployment_id", ""),
+		"tags":          m.convertTags(resource.Tags),
+	}
+
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "cache_cluster_enabled", false) {
+		config["cache_cluster_enabled"] = true
+		if size := m.getStringFromMetadata(resource.Metadata, "cache_cluster_size", ""); size != "" {
+			config["cache_cluster_size"] = size
+		}
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "xray_tracing_enabled", false) {
+		config["xray_tracing_enabled"] = true
+	}
+	if variables := m.getStringMapFromMetadata(resource.Metadata, "variables"); len(variables) > 0 {
+		config["variables"] = variables
+	}
+
+	mapped := m.apiGatewayChild(resource, config, dependencies)
+	mapped.Outputs = m.generateStageOutputs(resource, "aws_api_gateway_stage")
+	return mapped, nil
+}
+
+// mapAPIGatewayV2API maps an API Gateway HTTP API to Terraform resource
+func (m *AWSMapper) mapAPIGatewayV2API(resource discovery.Resource) (*generation.MappedResource, error) {
+	resourceName := m.generateResourceName(resource)
+	config := map[string]interface{}{
+		"name":          resource.Name,
+		"protocol_type": m.getStringFromMetadata(resource.Metadata, "protocol_type", "HTTP"),
+		"tags":          m.convertTags(resource.Tags),
+	}
+
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "disable_execute_api_endpoint", false) {
+		config["disable_execute_api_endpoint"] = true
+	}
+	if cors := m.getMapFromMetadata(resource.Metadata, "cors_configuration"); cors != nil {
+		block := make(map[string]interface{})
+		for _, key := range []string{"allow_headers", "allow_methods", "allow_origins", "expose_headers"} {
+			if values := m.getStringSliceFromMetadata(cors, key); len(values) > 0 {
+				block[key] = values
+			}
+		}
+		if m.getBoolFromMetadata(cors, "allow_credentials", false) {
+			block["allow_credentials"] = true
+		}
+		if age := m.getIntFromMetadata(cors, "max_age", 0); age > 0 {
+			block["max_age"] = age
+		}
+		if len(block) > 0 {
+			config["cors_configuration"] = block
+		}
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_apigatewayv2_api",
+		ResourceName:     resourceName,
+		Configuration:    config,
+		Dependencies:     []string{},
+		Variables:        make(map[string]generation.Variable),
+		Outputs: map[string]generation.Output{
+			"api_endpoint": {
+				Name:        fmt.Sprintf("%s_api_endpoint", resourceName),
+				Value:       fmt.Sprintf("aws_apigatewayv2_api.%s.api_endpoint", resourceName),
+				Description: "Default endpoint of the API Gateway HTTP API",
+			},
+		},
+	}
+
+	return mapped, nil
+}
+
+// mapAPIGatewayV2Integration maps an integration of an HTTP API to Terraform
+// resource, invoking the discovered Lambda function it proxies to
+func (m *AWSMapper) mapAPIGatewayV2Integration(resource discovery.Resource) (*generation.MappedResource, error) {
+	api := m.getStringFromMetadata(resource.Metadata, "api_id", "")
+	if api == "" {
+		return nil, fmt.Errorf("API Gateway integration %s has no HTTP API", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"api_id":           m.generateReference(api, &dependencies),
+		"integration_type": m.getStringFromMetadata(resource.Metadata, "integration_type", ""),
+	}
+
+	// Lambda proxy integrations take either the function ARN or its invoke ARN
+	uri := m.getStringFromMetadata(resource.Metadata, "integration_uri", "")
+	if function := m.getStringFromMetadata(resource.Metadata, "function_arn", ""); m.addresses[function] != "" {
+		attribute := "invoke_arn"
+		if uri == function {
+			attribute = "arn"
+		}
+		config["integration_uri"] = m.generateAttributeReference(function, attribute, &dependencies)
+	} else if uri != "" {
+		config["integration_uri"] = uri
+	}
+	if credentials := m.getStringFromMetadata(resource.Metadata, "credentials_arn", ""); credentials != "" {
+		config["credentials_arn"] = m.generateAttributeReference(credentials, "arn", &dependencies)
+	}
+
+	for _, key := range []string{"integration_method", "payload_format_version", "connection_id", "description"} {
+		if value := m.getStringFromMetadata(resource.Metadata, key, ""); value != "" {
+			config[key] = value
+		}
+	}
+	// Integrations connect over the internet within 30 seconds by default
+	if connection := m.getStringFromMetadata(resource.Metadata, "connection_type", ""); connection != "" && connection != "INTERNET" {
+		config["connection_type"] = connection
+	}
+	if timeout := m.getIntFromMetadata(resource.Metadata, "timeout_milliseconds", 0); timeout > 0 && timeout != 30000 {
+		config["timeout_milliseconds"] = timeout
+	}
+	if parameters := m.getStringMapFromMetadata(resource.Metadata, "request_parameters"); len(parameters) > 0 {
+		config["request_parameters"] = parameters
+	}
+
+	return m.apiGatewayChild(resource, config, dependencies), nil
+}
+
+// mapAPIGatewayV2Route maps a route of an HTTP API to Terraform resource
+func (m *AWSMapper) mapAPIGatewayV2Route(resource discovery.Resource) (*generation.MappedResource, error) {
+	api := m.getStringFromMetadata(resource.Metadata, "api_id", "")
+	if api == "" {
+		return nil, fmt.Errorf("API Gateway route %s has no HTTP API", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"api_id":    m.generateReference(api, &dependencies),
+		"route_key": m.getStringFromMetadata(resource.Metadata, "route_key", ""),
+	}
+
+	// The target embeds the integration ID, which stays literal so that every
+	// generator can render it; the dependency orders creation instead
+	if target := m.getStringFromMetadata(resource.Metadata, "target", ""); target != "" {
+		config["target"] = target
+		integration := m.getStringFromMetadata(resource.Metadata, "integration_id", "")
+		if address, exists := m.addresses[integration]; exists {
+			dependencies = append(dependencies, address)
+		}
+	}
+	if authorization := m.getStringFromMetadata(resource.Metadata, "authorization_type", ""); authorization != "" && authorization != "NONE" {
+		config["authorization_type"] = authorization
+	}
+	if authorizer := m.getStringFromMetadata(resource.Metadata, "authorizer_id", ""); authorizer != "" {
+		config["authorizer_id"] = authorizer
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "api_key_required", false) {
+		config["api_key_required"] = true
+	}
+	if operation := m.getStringFromMetadata(resource.Metadata, "operation_name", ""); operation != "" {
+		config["operation_name"] = operation
+	}
+
+	return m.apiGatewayChild(resource, config, dependencies), nil
+}
+
+// mapAPIGatewayV2Stage maps a stage of an HTTP API to Terraform resource;
+// stages that deploy automatically track the latest deployment themselves
+func (m *AWSMapper) mapAPIGatewayV2Stage(resource discovery.Resource) (*generation.MappedResource, error) {
+	api := m.getStringFromMetadata(resource.Metadata, "api_id", "")
+	if api == "" {
+		return nil, fmt.Errorf("API Gateway stage %s has no HTTP API", resource.ID)
+	}
+
+	dependencies := []string{}
+	autoDeploy := m.getBoolFromMetadata(resource.Metadata, "auto_deploy", false)
+	config := map[string]interface{}{
+		"api_id":      m.generateReference(api, &dependencies),
+		"name":        m.getStringFromMetadata(resource.Metadata, "stage_name", ""),
+		"auto_deploy": autoDeploy,
+		"tags":        m.convertTags(resource.Tags),
+	}
+
+	if deployment := m.getStringFromMetadata(resource.Metadata, "deployment_id", ""); deployment != "" && !autoDeploy {
+		config["deployment_id"] = deployment
+	}
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	if variables := m.getStringMapFromMetadata(resource.Metadata, "stage_variables"); len(variables) > 0 {
+		config["stage_variables"] = variables
+	}
+
+	mapped := m.apiGatewayChild(resource, config, dependencies)
+	mapped.Outputs = m.generateStageOutputs(resource, "aws_apigatewayv2_stage")
+	return mapped, nil
+}
+
+// apiGatewayChild builds the mapped resource of a part of a REST or HTTP API
+func (m *AWSMapper) apiGatewayChild(resource discovery.Resource, config map[string]interface{}, dependencies []string) *generation.MappedResource {
+	return &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     resource.Type,
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+}
+
+// apiGatewayResourceReference references a REST API resource by ID. The
+// root resource is created with the API, so it is referenced through the
+// API's root_resource_id attribute.
+func (m *AWSMapper) apiGatewayResourceReference(resource discovery.Resource, id string, dependencies *[]string) interface{} {
+	if id == m.getStringFromMetadata(resource.Metadata, "root_resource_id", "") {
+		api := m.getStringFromMetadata(resource.Metadata, "rest_api_id", "")
+		if _, exists := m.addresses[api]; exists {
+			return m.generateAttributeReference(api, "root_resource_id", dependencies)
+		}
+		return id
+	}
+	return m.generateReference(id, dependencies)
+}
+
+// apiGatewayPathName converts an API path, route key or stage name to a
+// Terraform name, such as "get_items_id" for "GET /items/{id}"
+func (m *AWSMapper) apiGatewayPathName(path string) string {
+	words := strings.FieldsFunc(strings.ToLower(path), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "root"
+	}
+	return m.sanitizeResourceName(strings.Join(words, "_"))
+}
+
+// apiGatewayChildName returns the name of a part of a REST or HTTP API,
+// derived from the name of its API and its path, route or stage
+func (m *AWSMapper) apiGatewayChildName(resource discovery.Resource) string {
+	apiKey := "api_id"
+	if strings.HasPrefix(resource.Type, "aws_api_gateway_") {
+		apiKey = "rest_api_id"
+	}
+	api := m.names[m.getStringFromMetadata(resource.Metadata, apiKey, "")]
+	if api == "" {
+		api = "api"
+	}
+
+	switch resource.Type {
+	case "aws_api_gateway_resource":
+		return fmt.Sprintf("%s_%s", api, m.apiGatewayPathName(m.getStringFromMetadata(resource.Metadata, "path", "")))
+	case "aws_api_gateway_method", "aws_api_gateway_integration":
+		return fmt.Sprintf("%s_%s_%s", api,
+			m.apiGatewayPathName(m.getStringFromMetadata(resource.Metadata, "path", "")),
+			m.apiGatewayPathName(m.getStringFromMetadata(resource.Metadata, "http_method", "")))
+	case "aws_api_gateway_stage", "aws_apigatewayv2_stage":
+		return fmt.Sprintf("%s_%s", api, m.apiGatewayPathName(m.getStringFromMetadata(resource.Metadata, "stage_name", "")))
+	case "aws_apigatewayv2_route":
+		return fmt.Sprintf("%s_%s", api, m.apiGatewayPathName(m.getStringFromMetadata(resource.Metadata, "route_key", "")))
+	case "aws_apigatewayv2_integration":
+		if function := m.names[m.getStringFromMetadata(resource.Metadata, "function_arn", "")]; function != "" {
+			return fmt.Sprintf("%s_%s", api, function)
+		}
+	}
+	return fmt.Sprintf("%s_%s", api, m.idSuffix(resource.ID))
+}
+
+// apiGatewayImportID returns the ID terraform import expects for an API
+// Gateway resource: APIs import by ID and their parts by API ID followed by
+// the resource ID, the resource ID and method, or the stage name
+func (m *AWSMapper) apiGatewayImportID(resource discovery.Resource) string {
+	switch resource.Type {
+	case "aws_api_gateway_resource":
+		return m.getStringFromMetadata(resource.Metadata, "rest_api_id", "") + "/" + resource.ID
+	case "aws_api_gateway_method", "aws_api_gateway_integration":
+		return strings.Join([]string{
+			m.getStringFromMetadata(resource.Metadata, "rest_api_id", ""),
+			m.getStringFromMetadata(resource.Metadata, "resource_id", ""),
+			m.getStringFromMetadata(resource.Metadata, "http_method", ""),
+		}, "/")
+	case "aws_apigatewayv2_integration", "aws_apigatewayv2_route":
+		return m.getStringFromMetadata(resource.Metadata, "api_id", "") + "/" + resource.ID
+	}
+	return resource.ID
+}
+
+// generateStageOutputs returns the invoke URL of a REST or HTTP API stage
+func (m *AWSMapper) generateStageOutputs(resource discovery.Resource, resourceType string) map[string]generation.Output {
+	resourceName := m.generateResourceName(resource)
+	kind := "stage"
+	if resourceType == "aws_apigatewayv2_stage" {
+		kind = "http_stage"
+	}
+	return map[string]generation.Output{
+		"invoke_url": {
+			Name:        fmt.Sprintf("%s_%s_invoke_url", resourceName, kind),
+			Value:       fmt.Sprintf("%s.%s.invoke_url", resourceType, resourceName),
+			Description: "URL that invokes the API stage",
+		},
+	}
+}
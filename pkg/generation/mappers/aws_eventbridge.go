@@ -0,0 +1,126 @@
+package mappers
+
+import (
+	"fmt"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// defaultEventBus is the event bus rules belong to when none is named
+const defaultEventBus = "default"
+
+// mapEventBridgeRule maps an EventBridge rule to Terraform resource
+func (m *AWSMapper) mapEventBridgeRule(resource discovery.Resource) (*generation.MappedResource, error) {
+	pattern := m.getStringFromMetadata(resource.Metadata, "event_pattern", "")
+	schedule := m.getStringFromMetadata(resource.Metadata, "schedule_expression", "")
+	if pattern == "" && schedule == "" {
+		return nil, fmt.Errorf("rule %s has neither an event pattern nor a schedule", resource.Name)
+	}
+
+	resourceName := m.generateResourceName(resource)
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"name":  resource.Name,
+		"state": m.getStringFromMetadata(resource.Metadata, "state", "ENABLED"),
+		"tags":  m.convertTags(resource.Tags),
+	}
+
+	if pattern != "" {
+		config["event_pattern"] = m.policyDocument(pattern)
+	}
+	if schedule != "" {
+		config["schedule_expression"] = schedule
+	}
+	if bus := m.getStringFromMetadata(resource.Metadata, "event_bus_name", ""); bus != "" && bus != defaultEventBus {
+		config["event_bus_name"] = bus
+	}
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	if role := m.getStringFromMetadata(resource.Metadata, "role_arn", ""); role != "" {
+		config["role_arn"] = m.generateAttributeReference(role, "arn", &dependencies)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_cloudwatch_event_rule",
+		ResourceName:     resourceName,
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs: map[string]generation.Output{
+			"arn": {
+				Name:        fmt.Sprintf("%s_event_rule_arn", resourceName),
+				Value:       fmt.Sprintf("aws_cloudwatch_event_rule.%s.arn", resourceName),
+				Description: "ARN of the EventBridge rule",
+			},
+		},
+	}
+
+	return mapped, nil
+}
+
+// mapEventBridgeTarget maps a target of an EventBridge rule to Terraform
+// resource, referencing the discovered rule, target and role
+func (m *AWSMapper) mapEventBridgeTarget(resource discovery.Resource) (*generation.MappedResource, error) {
+	rule := m.getStringFromMetadata(resource.Metadata, "rule", "")
+	arn := m.getStringFromMetadata(resource.Metadata, "arn", "")
+	if rule == "" || arn == "" {
+		return nil, fmt.Errorf("event target %s has no rule or target ARN", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"rule":      m.nameReference(rule, m.getStringFromMetadata(resource.Metadata, "rule_arn", ""), &dependencies),
+		"target_id": m.getStringFromMetadata(resource.Metadata, "target_id", ""),
+		"arn":       m.generateAttributeReference(arn, "arn", &dependencies),
+	}
+
+	if bus := m.getStringFromMetadata(resource.Metadata, "event_bus_name", ""); bus != "" && bus != defaultEventBus {
+		config["event_bus_name"] = bus
+	}
+	if role := m.getStringFromMetadata(resource.Metadata, "role_arn", ""); role != "" {
+		config["role_arn"] = m.generateAttributeReference(role, "arn", &dependencies)
+	}
+	for _, key := range []string{"input", "input_path"} {
+		if value := m.getStringFromMetadata(resource.Metadata, key, ""); value != "" {
+			config[key] = value
+		}
+	}
+	if transformer := m.getMapFromMetadata(resource.Metadata, "input_transformer"); transformer != nil {
+		block := map[string]interface{}{
+			"input_template": m.getStringFromMetadata(transformer, "input_template", ""),
+		}
+		if paths := m.getStringMapFromMetadata(transformer, "input_paths"); len(paths) > 0 {
+			block["input_paths"] = paths
+		}
+		config["input_transformer"] = block
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_cloudwatch_event_target",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
+// eventBridgeImportID returns the ID terraform import expects for a rule or
+// target: the rule name, then the target ID, prefixed with the event bus
+// name unless the rule is on the default bus
+func (m *AWSMapper) eventBridgeImportID(resource discovery.Resource) string {
+	id := resource.Name
+	if resource.Type == "aws_cloudwatch_event_target" {
+		id = m.getStringFromMetadata(resource.Metadata, "rule", "") + "/" + m.getStringFromMetadata(resource.Metadata, "target_id", "")
+	}
+	if bus := m.getStringFromMetadata(resource.Metadata, "event_bus_name", ""); bus != "" && bus != defaultEventBus {
+		return bus + "/" + id
+	}
+	return id
+}
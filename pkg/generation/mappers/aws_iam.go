@@ -0,0 +1,355 @@
+package mappers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// iamPrincipalKinds are the IAM principals that hold inline policies and
+// managed policy attachments
+var iamPrincipalKinds = []string{"role", "user", "group"}
+
+// iamPrincipalKind returns the principal kind of an inline policy or policy
+// attachment type, such as "role" for aws_iam_role_policy_attachment
+func (m *AWSMapper) iamPrincipalKind(resourceType string) string {
+	for _, kind := range iamPrincipalKinds {
+		if resourceType == "aws_iam_"+kind+"_policy" || resourceType == "aws_iam_"+kind+"_policy_attachment" {
+			return kind
+		}
+	}
+	return ""
+}
+
+// isServiceLinkedRole reports whether a role is linked to an AWS service,
+// which Terraform manages as aws_iam_service_linked_role
+func (m *AWSMapper) isServiceLinkedRole(resource discovery.Resource) bool {
+	return resource.Type == "aws_iam_role" && m.getBoolFromMetadata(resource.Metadata, "service_linked", false)
+}
+
+// mapIAMRole maps an IAM role to Terraform resource
+func (m *AWSMapper) mapIAMRole(resource discovery.Resource) (*generation.MappedResource, error) {
+	if m.isServiceLinkedRole(resource) {
+		return m.mapIAMServiceLinkedRole(resource)
+	}
+
+	trustPolicy := m.getStringFromMetadata(resource.Metadata, "assume_role_policy", "")
+	if trustPolicy == "" {
+		return nil, fmt.Errorf("role %s has no trust policy", resource.Name)
+	}
+
+	config := map[string]interface{}{
+		"name":               resource.Name,
+		"path":               m.getStringFromMetadata(resource.Metadata, "path", "/"),
+		"assume_role_policy": m.policyDocument(trustPolicy),
+		"tags":               m.convertTags(resource.Tags),
+	}
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	if duration := m.getIntFromMetadata(resource.Metadata, "max_session_duration", 0); duration > 0 {
+		config["max_session_duration"] = duration
+	}
+
+	dependencies := []string{}
+	if boundary := m.getStringFromMetadata(resource.Metadata, "permissions_boundary", ""); boundary != "" {
+		config["permissions_boundary"] = m.generateAttributeReference(boundary, "arn", &dependencies)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_iam_role",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          m.generateIAMOutputs(resource, "aws_iam_role"),
+	}
+
+	return mapped, nil
+}
+
+// mapIAMServiceLinkedRole maps a service-linked role, which is created for
+// the service named in its trust policy
+func (m *AWSMapper) mapIAMServiceLinkedRole(resource discovery.Resource) (*generation.MappedResource, error) {
+	service := m.trustedService(m.getStringFromMetadata(resource.Metadata, "assume_role_policy", ""))
+	if service == "" {
+		return nil, fmt.Errorf("service-linked role %s trusts no AWS service", resource.Name)
+	}
+
+	config := map[string]interface{}{
+		"aws_service_name": service,
+		"tags":             m.convertTags(resource.Tags),
+	}
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	// Roles created with a custom suffix are named AWSServiceRoleFor<Service>_<suffix>
+	if idx := strings.Index(resource.Name, "_"); idx >= 0 {
+		config["custom_suffix"] = resource.Name[idx+1:]
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_iam_service_linked_role",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     []string{},
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          m.generateIAMOutputs(resource, "aws_iam_service_linked_role"),
+	}
+
+	return mapped, nil
+}
+
+// mapIAMPolicy maps a customer managed IAM policy to Terraform resource
+func (m *AWSMapper) mapIAMPolicy(resource discovery.Resource) (*generation.MappedResource, error) {
+	document := m.getStringFromMetadata(resource.Metadata, "policy", "")
+	if document == "" {
+		return nil, fmt.Errorf("policy %s has no document", resource.Name)
+	}
+
+	config := map[string]interface{}{
+		"name":   resource.Name,
+		"path":   m.getStringFromMetadata(resource.Metadata, "path", "/"),
+		"policy": m.policyDocument(document),
+		"tags":   m.convertTags(resource.Tags),
+	}
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_iam_policy",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     []string{},
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          m.generateIAMOutputs(resource, "aws_iam_policy"),
+	}
+
+	return mapped, nil
+}
+
+// mapIAMPrincipal maps an IAM user or group to Terraform resource
+func (m *AWSMapper) mapIAMPrincipal(resource discovery.Resource) (*generation.MappedResource, error) {
+	config := map[string]interface{}{
+		"name": resource.Name,
+		"path": m.getStringFromMetadata(resource.Metadata, "path", "/"),
+	}
+
+	dependencies := []string{}
+	if resource.Type == "aws_iam_user" {
+		config["tags"] = m.convertTags(resource.Tags)
+		if boundary := m.getStringFromMetadata(resource.Metadata, "permissions_boundary", ""); boundary != "" {
+			config["permissions_boundary"] = m.generateAttributeReference(boundary, "arn", &dependencies)
+		}
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     resource.Type,
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          m.generateIAMOutputs(resource, resource.Type),
+	}
+
+	return mapped, nil
+}
+
+// mapIAMInstanceProfile maps an IAM instance profile to Terraform resource
+func (m *AWSMapper) mapIAMInstanceProfile(resource discovery.Resource) (*generation.MappedResource, error) {
+	config := map[string]interface{}{
+		"name": resource.Name,
+		"path": m.getStringFromMetadata(resource.Metadata, "path", "/"),
+		"tags": m.convertTags(resource.Tags),
+	}
+
+	dependencies := []string{}
+	if role := m.getStringFromMetadata(resource.Metadata, "role", ""); role != "" {
+		config["role"] = m.nameReference(role, m.getStringFromMetadata(resource.Metadata, "role_arn", ""), &dependencies)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_iam_instance_profile",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          m.generateIAMOutputs(resource, "aws_iam_instance_profile"),
+	}
+
+	return mapped, nil
+}
+
+// mapIAMPrincipalPolicy maps an inline policy or managed policy attachment of
+// a role, user or group to the Terraform resource of the same type
+func (m *AWSMapper) mapIAMPrincipalPolicy(resource discovery.Resource) (*generation.MappedResource, error) {
+	kind := m.iamPrincipalKind(resource.Type)
+	principal := m.getStringFromMetadata(resource.Metadata, kind, "")
+	if principal == "" {
+		return nil, fmt.Errorf("%s %s has no %s", resource.Type, resource.ID, kind)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		kind: m.nameReference(principal, m.getStringFromMetadata(resource.Metadata, kind+"_arn", ""), &dependencies),
+	}
+
+	if strings.HasSuffix(resource.Type, "_attachment") {
+		policyARN := m.getStringFromMetadata(resource.Metadata, "policy_arn", "")
+		if policyARN == "" {
+			return nil, fmt.Errorf("%s %s has no policy ARN", resource.Type, resource.ID)
+		}
+		config["policy_arn"] = m.generateAttributeReference(policyARN, "arn", &dependencies)
+	} else {
+		document := m.getStringFromMetadata(resource.Metadata, "policy", "")
+		if document == "" {
+			return nil, fmt.Errorf("inline policy %s of %s %s has no document", m.getStringFromMetadata(resource.Metadata, "name", ""), kind, principal)
+		}
+		config["name"] = m.getStringFromMetadata(resource.Metadata, "name", "")
+		config["policy"] = m.policyDocument(document)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     resource.Type,
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
+// mapIAMUserGroupMembership maps the group memberships of an IAM user to
+// Terraform resource
+func (m *AWSMapper) mapIAMUserGroupMembership(resource discovery.Resource) (*generation.MappedResource, error) {
+	user := m.getStringFromMetadata(resource.Metadata, "user", "")
+	if user == "" {
+		return nil, fmt.Errorf("group membership %s has no user", resource.ID)
+	}
+
+	dependencies := []string{}
+	groupARNs := m.getStringSliceFromMetadata(resource.Metadata, "groups")
+	groupNames := m.getStringSliceFromMetadata(resource.Metadata, "group_names")
+	if len(groupNames) == 0 {
+		return nil, fmt.Errorf("user %s is not a member of any group", user)
+	}
+
+	groups := make([]interface{}, 0, len(groupNames))
+	for i, name := range groupNames {
+		groupARN := ""
+		if i < len(groupARNs) {
+			groupARN = groupARNs[i]
+		}
+		groups = append(groups, m.nameReference(name, groupARN, &dependencies))
+	}
+
+	config := map[string]interface{}{
+		"user":   m.nameReference(user, m.getStringFromMetadata(resource.Metadata, "user_arn", ""), &dependencies),
+		"groups": groups,
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_iam_user_group_membership",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
+// policyDocument returns a policy document as a jsonencode expression, or
+// as the literal document when it is not valid JSON
+func (m *AWSMapper) policyDocument(document string) interface{} {
+	expr, err := generation.JSONEncode(document)
+	if err != nil {
+		return document
+	}
+	return expr
+}
+
+// trustedService returns the AWS service a trust policy allows to assume a
+// role, such as elasticloadbalancing.amazonaws.com
+func (m *AWSMapper) trustedService(trustPolicy string) string {
+	var policy struct {
+		Statement []struct {
+			Principal struct {
+				Service interface{}
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(trustPolicy), &policy); err != nil {
+		return ""
+	}
+
+	for _, statement := range policy.Statement {
+		switch service := statement.Principal.Service.(type) {
+		case string:
+			return service
+		case []interface{}:
+			if len(service) > 0 {
+				if name, ok := service[0].(string); ok {
+					return name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// iamImportID returns the ID terraform import expects for an IAM resource:
+// roles, users, groups and instance profiles import by name, policies and
+// service-linked roles by ARN, inline policies by principal and policy name,
+// attachments by principal and policy ARN and group memberships by user and
+// group names
+func (m *AWSMapper) iamImportID(resource discovery.Resource) string {
+	switch resource.Type {
+	case "aws_iam_role":
+		if m.isServiceLinkedRole(resource) {
+			return resource.ID
+		}
+		return resource.Name
+	case "aws_iam_user", "aws_iam_group", "aws_iam_instance_profile":
+		return resource.Name
+	case "aws_iam_user_group_membership":
+		parts := append([]string{m.getStringFromMetadata(resource.Metadata, "user", "")},
+			m.getStringSliceFromMetadata(resource.Metadata, "group_names")...)
+		return strings.Join(parts, "/")
+	}
+
+	if kind := m.iamPrincipalKind(resource.Type); kind != "" {
+		principal := m.getStringFromMetadata(resource.Metadata, kind, "")
+		if strings.HasSuffix(resource.Type, "_attachment") {
+			return principal + "/" + m.getStringFromMetadata(resource.Metadata, "policy_arn", "")
+		}
+		return principal + ":" + m.getStringFromMetadata(resource.Metadata, "name", "")
+	}
+	return resource.ID
+}
+
+func (m *AWSMapper) generateIAMOutputs(resource discovery.Resource, resourceType string) map[string]generation.Output {
+	resourceName := m.generateResourceName(resource)
+	kind := strings.TrimPrefix(resourceType, "aws_iam_")
+	return map[string]generation.Output{
+		"arn": {
+			Name:        fmt.Sprintf("%s_%s_arn", resourceName, kind),
+			Value:       fmt.Sprintf("%s.%s.arn", resourceType, resourceName),
+			Description: fmt.Sprintf("ARN of the IAM %s", strings.ReplaceAll(kind, "_", " ")),
+		},
+	}
+}
@@ -0,0 +1,186 @@
+package mappers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// mapLambdaFunction maps a Lambda function to Terraform resource. The
+// deployment package cannot be downloaded into the configuration, so zip
+// functions read it from a variable, and the values of environment variables
+// are read from sensitive variables as they may be secrets.
+func (m *AWSMapper) mapLambdaFunction(resource discovery.Resource) (*generation.MappedResource, error) {
+	role := m.getStringFromMetadata(resource.Metadata, "role", "")
+	if role == "" {
+		return nil, fmt.Errorf("Lambda function %s has no execution role", resource.Name)
+	}
+
+	resourceName := m.generateResourceName(resource)
+	dependencies := []string{}
+	variables := make(map[string]generation.Variable)
+
+	config := map[string]interface{}{
+		"function_name": resource.Name,
+		"role":          m.generateAttributeReference(role, "arn", &dependencies),
+		"memory_size":   m.getIntFromMetadata(resource.Metadata, "memory_size", 128),
+		"timeout":       m.getIntFromMetadata(resource.Metadata, "timeout", 3),
+		"tags":          m.convertTags(resource.Tags),
+	}
+
+	if m.getStringFromMetadata(resource.Metadata, "package_type", "Zip") == "Image" {
+		config["package_type"] = "Image"
+		config["image_uri"] = m.getStringFromMetadata(resource.Metadata, "image_uri", "")
+	} else {
+		config["filename"] = m.lambdaPackageVariable(resourceName, resource, variables)
+	}
+
+	for _, key := range []string{"runtime", "handler", "description", "kms_key_arn"} {
+		if value := m.getStringFromMetadata(resource.Metadata, key, ""); value != "" {
+			config[key] = value
+		}
+	}
+	if architectures := m.getStringSliceFromMetadata(resource.Metadata, "architectures"); len(architectures) > 0 {
+		config["architectures"] = architectures
+	}
+	if layers := m.getStringSliceFromMetadata(resource.Metadata, "layers"); len(layers) > 0 {
+		config["layers"] = layers
+	}
+
+	if names := m.getStringSliceFromMetadata(resource.Metadata, "environment_variables"); len(names) > 0 {
+		values := make(generation.Map, len(names))
+		for _, name := range names {
+			variable := fmt.Sprintf("%s_env_%s", resourceName, strings.ToLower(m.sanitizeResourceName(name)))
+			variables[variable] = generation.Variable{
+				Name:        variable,
+				Type:        "string",
+				Description: fmt.Sprintf("Value of the %s environment variable of Lambda function %s", name, resource.Name),
+				Sensitive:   true,
+				Required:    true,
+			}
+			values[name] = generation.Expression("var." + variable)
+		}
+		config["environment"] = map[string]interface{}{"variables": values}
+	}
+
+	if subnetIds := m.getStringSliceFromMetadata(resource.Metadata, "subnet_ids"); len(subnetIds) > 0 {
+		subnets := make([]interface{}, 0, len(subnetIds))
+		for _, subnetId := range subnetIds {
+			subnets = append(subnets, m.generateReference(subnetId, &dependencies))
+		}
+		groupIds := m.getStringSliceFromMetadata(resource.Metadata, "security_group_ids")
+		groups := make([]interface{}, 0, len(groupIds))
+		for _, groupId := range groupIds {
+			groups = append(groups, m.generateReference(groupId, &dependencies))
+		}
+		config["vpc_config"] = map[string]interface{}{
+			"subnet_ids":         subnets,
+			"security_group_ids": groups,
+		}
+	}
+
+	// 512 MB of /tmp storage and pass-through tracing are the defaults
+	if size := m.getIntFromMetadata(resource.Metadata, "ephemeral_storage_size", 0); size > 512 {
+		config["ephemeral_storage"] = map[string]interface{}{"size": size}
+	}
+	if mode := m.getStringFromMetadata(resource.Metadata, "tracing_mode", ""); mode != "" && mode != "PassThrough" {
+		config["tracing_config"] = map[string]interface{}{"mode": mode}
+	}
+	if target := m.getStringFromMetadata(resource.Metadata, "dead_letter_target_arn", ""); target != "" {
+		config["dead_letter_config"] = map[string]interface{}{
+			"target_arn": m.generateAttributeReference(target, "arn", &dependencies),
+		}
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_lambda_function",
+		ResourceName:     resourceName,
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        variables,
+		Outputs: map[string]generation.Output{
+			"arn": {
+				Name:        fmt.Sprintf("%s_function_arn", resourceName),
+				Value:       fmt.Sprintf("aws_lambda_function.%s.arn", resourceName),
+				Description: "ARN of the Lambda function",
+			},
+		},
+	}
+
+	return mapped, nil
+}
+
+// mapLambdaEventSourceMapping maps an event source mapping to Terraform
+// resource, referencing the function and event source when discovered
+func (m *AWSMapper) mapLambdaEventSourceMapping(resource discovery.Resource) (*generation.MappedResource, error) {
+	function := m.getStringFromMetadata(resource.Metadata, "function_arn", "")
+	if function == "" {
+		return nil, fmt.Errorf("event source mapping %s has no function", resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"function_name": m.generateAttributeReference(function, "arn", &dependencies),
+		"enabled":       m.getBoolFromMetadata(resource.Metadata, "enabled", true),
+	}
+
+	if source := m.getStringFromMetadata(resource.Metadata, "event_source_arn", ""); source != "" {
+		config["event_source_arn"] = m.generateAttributeReference(source, "arn", &dependencies)
+	}
+	if size := m.getIntFromMetadata(resource.Metadata, "batch_size", 0); size > 0 {
+		config["batch_size"] = size
+	}
+	if window := m.getIntFromMetadata(resource.Metadata, "maximum_batching_window", 0); window > 0 {
+		config["maximum_batching_window_in_seconds"] = window
+	}
+	if position := m.getStringFromMetadata(resource.Metadata, "starting_position", ""); position != "" {
+		config["starting_position"] = position
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_lambda_event_source_mapping",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
+// lambdaPackageVariable declares a variable for the path of the deployment
+// package of a zip function and returns a reference to it
+func (m *AWSMapper) lambdaPackageVariable(resourceName string, resource discovery.Resource, variables map[string]generation.Variable) generation.Expression {
+	name := resourceName + "_package"
+	description := fmt.Sprintf("Path to the deployment package of Lambda function %s", resource.Name)
+	if hash := m.getStringFromMetadata(resource.Metadata, "code_sha256", ""); hash != "" {
+		description += fmt.Sprintf(" (deployed package SHA-256 %s)", hash)
+	}
+
+	variables[name] = generation.Variable{
+		Name:        name,
+		Type:        "string",
+		Description: description,
+		Required:    true,
+	}
+	return generation.Expression("var." + name)
+}
+
+// lambdaSourceName returns the name of the queue, stream or table an event
+// source mapping reads from, such as "orders" for the stream ARN
+// arn:aws:dynamodb:us-east-1:123456789012:table/orders/stream/2024-01-01T00:00:00.000
+func (m *AWSMapper) lambdaSourceName(sourceARN string) string {
+	name := sourceARN
+	if parts := strings.SplitN(sourceARN, ":", 6); len(parts) == 6 {
+		name = parts[5]
+	}
+	if parts := strings.Split(name, "/"); len(parts) > 1 {
+		name = parts[1]
+	}
+	return m.sanitizeResourceName(name)
+}
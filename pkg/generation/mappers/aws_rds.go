@@ -0,0 +1,445 @@
+package mappers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// isRDSResource reports whether a type is an RDS resource, which Terraform
+// imports by identifier or name rather than by the ARN discovery records
+func (m *AWSMapper) isRDSResource(resourceType string) bool {
+	switch resourceType {
+	case "aws_db_instance", "aws_rds_cluster", "aws_rds_cluster_instance", "aws_db_subnet_group",
+		"aws_db_parameter_group", "aws_rds_cluster_parameter_group", "aws_db_option_group":
+		return true
+	}
+	return false
+}
+
+// mapDBInstance maps an RDS DB instance to Terraform resource. The master
+// password cannot be discovered, so it is read from a sensitive variable
+// unless RDS manages it in Secrets Manager or the instance is a replica.
+func (m *AWSMapper) mapDBInstance(resource discovery.Resource) (*generation.MappedResource, error) {
+	resourceName := m.generateResourceName(resource)
+	dependencies := []string{}
+	variables := make(map[string]generation.Variable)
+
+	config := map[string]interface{}{
+		"identifier":                 resource.Name,
+		"instance_class":             m.getStringFromMetadata(resource.Metadata, "instance_class", ""),
+		"publicly_accessible":        m.getBoolFromMetadata(resource.Metadata, "publicly_accessible", false),
+		"multi_az":                   m.getBoolFromMetadata(resource.Metadata, "multi_az", false),
+		"backup_retention_period":    m.getIntFromMetadata(resource.Metadata, "backup_retention_period", 0),
+		"auto_minor_version_upgrade": m.getBoolFromMetadata(resource.Metadata, "auto_minor_version_upgrade", true),
+		"deletion_protection":        m.getBoolFromMetadata(resource.Metadata, "deletion_protection", false),
+		"copy_tags_to_snapshot":      m.getBoolFromMetadata(resource.Metadata, "copy_tags_to_snapshot", false),
+		"tags":                       m.convertTags(resource.Tags),
+	}
+
+	// Replicas inherit the engine, storage and credentials of their source
+	if source := m.getStringFromMetadata(resource.Metadata, "replicate_source_db", ""); source != "" {
+		config["replicate_source_db"] = m.getStringFromMetadata(resource.Metadata, "replicate_source_db_name", source)
+		if _, exists := m.addresses[source]; exists {
+			config["replicate_source_db"] = m.generateAttributeReference(source, "identifier", &dependencies)
+		}
+	} else {
+		config["engine"] = m.getStringFromMetadata(resource.Metadata, "engine", "")
+		config["engine_version"] = m.getStringFromMetadata(resource.Metadata, "engine_version", "")
+		config["allocated_storage"] = m.getIntFromMetadata(resource.Metadata, "allocated_storage", 20)
+		config["storage_encrypted"] = m.getBoolFromMetadata(resource.Metadata, "storage_encrypted", false)
+		config["username"] = m.getStringFromMetadata(resource.Metadata, "username", "")
+		if dbName := m.getStringFromMetadata(resource.Metadata, "db_name", ""); dbName != "" {
+			config["db_name"] = dbName
+		}
+		if m.getBoolFromMetadata(resource.Metadata, "manage_master_password", false) {
+			config["manage_master_user_password"] = true
+		} else {
+			config["password"] = m.passwordVariable(resourceName+"_password", resource, variables)
+		}
+	}
+
+	for _, key := range []string{"storage_type", "kms_key_id", "backup_window", "maintenance_window", "license_model", "ca_cert_identifier"} {
+		if value := m.getStringFromMetadata(resource.Metadata, key, ""); value != "" {
+			config[key] = value
+		}
+	}
+	for _, key := range []string{"max_allocated_storage", "iops", "storage_throughput", "port"} {
+		if value := m.getIntFromMetadata(resource.Metadata, key, 0); value > 0 {
+			config[key] = value
+		}
+	}
+	if !m.getBoolFromMetadata(resource.Metadata, "multi_az", false) {
+		if zone := m.getStringFromMetadata(resource.Metadata, "availability_zone", ""); zone != "" {
+			config["availability_zone"] = zone
+		}
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "iam_authentication", false) {
+		config["iam_database_authentication_enabled"] = true
+	}
+
+	m.addRDSMonitoring(resource, config, &dependencies)
+	m.addRDSNetworking(resource, config, &dependencies)
+
+	if name := m.getStringFromMetadata(resource.Metadata, "parameter_group_name", ""); name != "" {
+		config["parameter_group_name"] = m.nameReference(name, m.getStringFromMetadata(resource.Metadata, "parameter_group_arn", ""), &dependencies)
+	}
+	if name := m.getStringFromMetadata(resource.Metadata, "option_group_name", ""); name != "" {
+		config["option_group_name"] = m.nameReference(name, m.getStringFromMetadata(resource.Metadata, "option_group_arn", ""), &dependencies)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_db_instance",
+		ResourceName:     resourceName,
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        variables,
+		Outputs:          m.generateRDSOutputs(resource, "aws_db_instance"),
+	}
+
+	return mapped, nil
+}
+
+// mapRDSCluster maps an Aurora or Multi-AZ DB cluster to Terraform resource;
+// like DB instances, its master password is read from a sensitive variable
+func (m *AWSMapper) mapRDSCluster(resource discovery.Resource) (*generation.MappedResource, error) {
+	resourceName := m.generateResourceName(resource)
+	dependencies := []string{}
+	variables := make(map[string]generation.Variable)
+
+	config := map[string]interface{}{
+		"cluster_identifier":      resource.Name,
+		"engine":                  m.getStringFromMetadata(resource.Metadata, "engine", ""),
+		"engine_version":          m.getStringFromMetadata(resource.Metadata, "engine_version", ""),
+		"master_username":         m.getStringFromMetadata(resource.Metadata, "master_username", ""),
+		"storage_encrypted":       m.getBoolFromMetadata(resource.Metadata, "storage_encrypted", false),
+		"backup_retention_period": m.getIntFromMetadata(resource.Metadata, "backup_retention_period", 1),
+		"deletion_protection":     m.getBoolFromMetadata(resource.Metadata, "deletion_protection", false),
+		"copy_tags_to_snapshot":   m.getBoolFromMetadata(resource.Metadata, "copy_tags_to_snapshot", false),
+		"tags":                    m.convertTags(resource.Tags),
+	}
+
+	if m.getBoolFromMetadata(resource.Metadata, "manage_master_password", false) {
+		config["manage_master_user_password"] = true
+	} else {
+		config["master_password"] = m.passwordVariable(resourceName+"_master_password", resource, variables)
+	}
+
+	// provisioned is the default engine mode
+	if mode := m.getStringFromMetadata(resource.Metadata, "engine_mode", ""); mode != "" && mode != "provisioned" {
+		config["engine_mode"] = mode
+	}
+	for _, key := range []string{"database_name", "kms_key_id"} {
+		if value := m.getStringFromMetadata(resource.Metadata, key, ""); value != "" {
+			config[key] = value
+		}
+	}
+	if window := m.getStringFromMetadata(resource.Metadata, "backup_window", ""); window != "" {
+		config["preferred_backup_window"] = window
+	}
+	if window := m.getStringFromMetadata(resource.Metadata, "maintenance_window", ""); window != "" {
+		config["preferred_maintenance_window"] = window
+	}
+	if port := m.getIntFromMetadata(resource.Metadata, "port", 0); port > 0 {
+		config["port"] = port
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "iam_authentication", false) {
+		config["iam_database_authentication_enabled"] = true
+	}
+	if scaling := m.getMapFromMetadata(resource.Metadata, "serverlessv2_scaling"); len(scaling) > 0 {
+		config["serverlessv2_scaling_configuration"] = map[string]interface{}{
+			"min_capacity": m.getFloatFromMetadata(scaling, "min_capacity", 0.5),
+			"max_capacity": m.getFloatFromMetadata(scaling, "max_capacity", 1),
+		}
+	}
+
+	m.addRDSNetworking(resource, config, &dependencies)
+
+	if name := m.getStringFromMetadata(resource.Metadata, "cluster_parameter_group_name", ""); name != "" {
+		config["db_cluster_parameter_group_name"] = m.nameReference(name, m.getStringFromMetadata(resource.Metadata, "cluster_parameter_group_arn", ""), &dependencies)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_rds_cluster",
+		ResourceName:     resourceName,
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        variables,
+		Outputs:          m.generateRDSOutputs(resource, "aws_rds_cluster"),
+	}
+
+	return mapped, nil
+}
+
+// mapRDSClusterInstance maps an instance of an Aurora cluster to Terraform resource
+func (m *AWSMapper) mapRDSClusterInstance(resource discovery.Resource) (*generation.MappedResource, error) {
+	cluster := m.getStringFromMetadata(resource.Metadata, "cluster_identifier", "")
+	if cluster == "" {
+		return nil, fmt.Errorf("cluster instance %s has no cluster", resource.Name)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"identifier":                 resource.Name,
+		"cluster_identifier":         cluster,
+		"instance_class":             m.getStringFromMetadata(resource.Metadata, "instance_class", ""),
+		"engine":                     m.getStringFromMetadata(resource.Metadata, "engine", ""),
+		"publicly_accessible":        m.getBoolFromMetadata(resource.Metadata, "publicly_accessible", false),
+		"auto_minor_version_upgrade": m.getBoolFromMetadata(resource.Metadata, "auto_minor_version_upgrade", true),
+		"promotion_tier":             m.getIntFromMetadata(resource.Metadata, "promotion_tier", 0),
+		"tags":                       m.convertTags(resource.Tags),
+	}
+
+	// The cluster manages the engine version and instances follow it
+	if clusterARN := m.getStringFromMetadata(resource.Metadata, "cluster_arn", ""); clusterARN != "" {
+		if _, exists := m.addresses[clusterARN]; exists {
+			config["cluster_identifier"] = m.generateReference(clusterARN, &dependencies)
+			config["engine"] = m.generateAttributeReference(clusterARN, "engine", &dependencies)
+			config["engine_version"] = m.generateAttributeReference(clusterARN, "engine_version", &dependencies)
+		}
+	}
+
+	if zone := m.getStringFromMetadata(resource.Metadata, "availability_zone", ""); zone != "" {
+		config["availability_zone"] = zone
+	}
+	if certificate := m.getStringFromMetadata(resource.Metadata, "ca_cert_identifier", ""); certificate != "" {
+		config["ca_cert_identifier"] = certificate
+	}
+
+	m.addRDSMonitoring(resource, config, &dependencies)
+
+	if name := m.getStringFromMetadata(resource.Metadata, "db_subnet_group_name", ""); name != "" {
+		config["db_subnet_group_name"] = m.nameReference(name, m.getStringFromMetadata(resource.Metadata, "db_subnet_group_arn", ""), &dependencies)
+	}
+	if name := m.getStringFromMetadata(resource.Metadata, "parameter_group_name", ""); name != "" {
+		config["db_parameter_group_name"] = m.nameReference(name, m.getStringFromMetadata(resource.Metadata, "parameter_group_arn", ""), &dependencies)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_rds_cluster_instance",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          m.generateRDSOutputs(resource, "aws_rds_cluster_instance"),
+	}
+
+	return mapped, nil
+}
+
+// mapDBSubnetGroup maps a DB subnet group to Terraform resource
+func (m *AWSMapper) mapDBSubnetGroup(resource discovery.Resource) (*generation.MappedResource, error) {
+	subnetIds := m.getStringSliceFromMetadata(resource.Metadata, "subnet_ids")
+	if len(subnetIds) == 0 {
+		return nil, fmt.Errorf("DB subnet group %s has no subnets", resource.Name)
+	}
+
+	dependencies := []string{}
+	subnets := make([]interface{}, 0, len(subnetIds))
+	for _, subnetId := range subnetIds {
+		subnets = append(subnets, m.generateReference(subnetId, &dependencies))
+	}
+
+	config := map[string]interface{}{
+		"name":       resource.Name,
+		"subnet_ids": subnets,
+		"tags":       m.convertTags(resource.Tags),
+	}
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_db_subnet_group",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
+// mapRDSParameterGroup maps a DB or DB cluster parameter group to the
+// Terraform resource of the same type, with a parameter block for every
+// parameter changed from the engine default
+func (m *AWSMapper) mapRDSParameterGroup(resource discovery.Resource) (*generation.MappedResource, error) {
+	family := m.getStringFromMetadata(resource.Metadata, "family", "")
+	if family == "" {
+		return nil, fmt.Errorf("parameter group %s has no family", resource.Name)
+	}
+
+	config := map[string]interface{}{
+		"name":   resource.Name,
+		"family": family,
+		"tags":   m.convertTags(resource.Tags),
+	}
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+
+	var parameters []map[string]interface{}
+	for _, parameter := range m.getMapSliceFromMetadata(resource.Metadata, "parameters") {
+		block := map[string]interface{}{
+			"name":  m.getStringFromMetadata(parameter, "name", ""),
+			"value": m.getStringFromMetadata(parameter, "value", ""),
+		}
+		if method := m.getStringFromMetadata(parameter, "apply_method", ""); method != "" {
+			block["apply_method"] = method
+		}
+		parameters = append(parameters, block)
+	}
+	if len(parameters) > 0 {
+		config["parameter"] = parameters
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     resource.Type,
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     []string{},
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
+// mapDBOptionGroup maps a DB option group to Terraform resource
+func (m *AWSMapper) mapDBOptionGroup(resource discovery.Resource) (*generation.MappedResource, error) {
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"name":                 resource.Name,
+		"engine_name":          m.getStringFromMetadata(resource.Metadata, "engine_name", ""),
+		"major_engine_version": m.getStringFromMetadata(resource.Metadata, "major_engine_version", ""),
+		"tags":                 m.convertTags(resource.Tags),
+	}
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["option_group_description"] = description
+	}
+
+	var options []map[string]interface{}
+	for _, option := range m.getMapSliceFromMetadata(resource.Metadata, "options") {
+		block := map[string]interface{}{
+			"option_name": m.getStringFromMetadata(option, "option_name", ""),
+		}
+		if version := m.getStringFromMetadata(option, "version", ""); version != "" {
+			block["version"] = version
+		}
+		if port := m.getIntFromMetadata(option, "port", 0); port > 0 {
+			block["port"] = port
+		}
+		if groupIds := m.getStringSliceFromMetadata(option, "security_group_ids"); len(groupIds) > 0 {
+			groups := make([]interface{}, 0, len(groupIds))
+			for _, groupId := range groupIds {
+				groups = append(groups, m.generateReference(groupId, &dependencies))
+			}
+			block["vpc_security_group_memberships"] = groups
+		}
+
+		settings := m.getStringMapFromMetadata(option, "settings")
+		var settingBlocks []map[string]interface{}
+		for _, name := range sortedStringKeys(settings) {
+			settingBlocks = append(settingBlocks, map[string]interface{}{
+				"name":  name,
+				"value": settings[name],
+			})
+		}
+		if len(settingBlocks) > 0 {
+			block["option_settings"] = settingBlocks
+		}
+
+		options = append(options, block)
+	}
+	if len(options) > 0 {
+		config["option"] = options
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_db_option_group",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
+// addRDSNetworking sets the subnet group and security groups of a DB
+// instance or cluster, referencing the discovered ones
+func (m *AWSMapper) addRDSNetworking(resource discovery.Resource, config map[string]interface{}, dependencies *[]string) {
+	if name := m.getStringFromMetadata(resource.Metadata, "db_subnet_group_name", ""); name != "" {
+		config["db_subnet_group_name"] = m.nameReference(name, m.getStringFromMetadata(resource.Metadata, "db_subnet_group_arn", ""), dependencies)
+	}
+
+	if groupIds := m.getStringSliceFromMetadata(resource.Metadata, "security_group_ids"); len(groupIds) > 0 {
+		groups := make([]interface{}, 0, len(groupIds))
+		for _, groupId := range groupIds {
+			groups = append(groups, m.generateReference(groupId, dependencies))
+		}
+		config["vpc_security_group_ids"] = groups
+	}
+}
+
+// addRDSMonitoring sets the Performance Insights and enhanced monitoring
+// settings of a DB instance or cluster instance
+func (m *AWSMapper) addRDSMonitoring(resource discovery.Resource, config map[string]interface{}, dependencies *[]string) {
+	if m.getBoolFromMetadata(resource.Metadata, "performance_insights_enabled", false) {
+		config["performance_insights_enabled"] = true
+	}
+	if interval := m.getIntFromMetadata(resource.Metadata, "monitoring_interval", 0); interval > 0 {
+		config["monitoring_interval"] = interval
+		if role := m.getStringFromMetadata(resource.Metadata, "monitoring_role_arn", ""); role != "" {
+			config["monitoring_role_arn"] = m.generateAttributeReference(role, "arn", dependencies)
+		}
+	}
+}
+
+// passwordVariable declares a sensitive variable for a master password that
+// cannot be discovered and returns a reference to it
+func (m *AWSMapper) passwordVariable(name string, resource discovery.Resource, variables map[string]generation.Variable) generation.Expression {
+	variables[name] = generation.Variable{
+		Name:        name,
+		Type:        "string",
+		Description: fmt.Sprintf("Master password of %s", resource.Name),
+		Sensitive:   true,
+		Required:    true,
+	}
+	return generation.Expression("var." + name)
+}
+
+// sortedStringKeys returns the keys of a string map in sorted order
+func sortedStringKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// generateRDSOutputs returns the connection endpoint of a DB instance,
+// cluster or cluster instance
+func (m *AWSMapper) generateRDSOutputs(resource discovery.Resource, resourceType string) map[string]generation.Output {
+	resourceName := m.generateResourceName(resource)
+	kind := strings.TrimPrefix(strings.TrimPrefix(resourceType, "aws_db_"), "aws_rds_")
+	return map[string]generation.Output{
+		"endpoint": {
+			Name:        fmt.Sprintf("%s_%s_endpoint", resourceName, kind),
+			Value:       fmt.Sprintf("%s.%s.endpoint", resourceType, resourceName),
+			Description: fmt.Sprintf("Connection endpoint of the DB %s", strings.ReplaceAll(kind, "_", " ")),
+		},
+	}
+}
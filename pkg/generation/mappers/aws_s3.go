@@ -0,0 +1,324 @@
+package mappers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// isS3BucketConfiguration reports whether a type is one of the bucket
+// configuration resources the AWS provider manages apart from aws_s3_bucket
+func (m *AWSMapper) isS3BucketConfiguration(resourceType string) bool {
+	return strings.HasPrefix(resourceType, "aws_s3_bucket_")
+}
+
+// mapS3Bucket maps an S3 bucket to Terraform resource. Its configuration is
+// mapped from the separate aws_s3_bucket_* resources found by discovery.
+func (m *AWSMapper) mapS3Bucket(resource discovery.Resource) (*generation.MappedResource, error) {
+	config := map[string]interface{}{
+		"bucket": resource.ID,
+		"tags":   m.convertTags(resource.Tags),
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     "aws_s3_bucket",
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     []string{},
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          m.generateS3BucketOutputs(resource),
+	}
+
+	return mapped, nil
+}
+
+// mapS3BucketConfiguration maps a bucket configuration resource to the
+// Terraform resource of the same type
+func (m *AWSMapper) mapS3BucketConfiguration(resource discovery.Resource) (*generation.MappedResource, error) {
+	bucket := m.getStringFromMetadata(resource.Metadata, "bucket", "")
+	if bucket == "" {
+		return nil, fmt.Errorf("%s %s has no bucket", resource.Type, resource.ID)
+	}
+
+	dependencies := []string{}
+	config := map[string]interface{}{
+		"bucket": m.generateReference(bucket, &dependencies),
+	}
+
+	switch resource.Type {
+	case "aws_s3_bucket_versioning":
+		versioning := map[string]interface{}{
+			"status": m.getStringFromMetadata(resource.Metadata, "status", "Enabled"),
+		}
+		if mfaDelete := m.getStringFromMetadata(resource.Metadata, "mfa_delete", ""); mfaDelete != "" {
+			versioning["mfa_delete"] = mfaDelete
+		}
+		config["versioning_configuration"] = versioning
+
+	case "aws_s3_bucket_server_side_encryption_configuration":
+		var rules []map[string]interface{}
+		for _, rule := range m.getMapSliceFromMetadata(resource.Metadata, "rules") {
+			block := map[string]interface{}{
+				"bucket_key_enabled": m.getBoolFromMetadata(rule, "bucket_key_enabled", false),
+			}
+			if algorithm := m.getStringFromMetadata(rule, "sse_algorithm", ""); algorithm != "" {
+				defaults := map[string]interface{}{
+					"sse_algorithm": algorithm,
+				}
+				if keyId := m.getStringFromMetadata(rule, "kms_master_key_id", ""); keyId != "" {
+					defaults["kms_master_key_id"] = keyId
+				}
+				block["apply_server_side_encryption_by_default"] = defaults
+			}
+			rules = append(rules, block)
+		}
+		if len(rules) == 0 {
+			return nil, fmt.Errorf("encryption configuration of bucket %s has no rules", bucket)
+		}
+		config["rule"] = rules
+
+	case "aws_s3_bucket_lifecycle_configuration":
+		var rules []map[string]interface{}
+		for _, rule := range m.getMapSliceFromMetadata(resource.Metadata, "rules") {
+			rules = append(rules, m.buildLifecycleRule(rule))
+		}
+		if len(rules) == 0 {
+			return nil, fmt.Errorf("lifecycle configuration of bucket %s has no rules", bucket)
+		}
+		config["rule"] = rules
+
+	case "aws_s3_bucket_public_access_block":
+		for _, key := range []string{"block_public_acls", "block_public_policy", "ignore_public_acls", "restrict_public_buckets"} {
+			config[key] = m.getBoolFromMetadata(resource.Metadata, key, false)
+		}
+
+	case "aws_s3_bucket_policy":
+		policy := m.getStringFromMetadata(resource.Metadata, "policy", "")
+		if policy == "" {
+			return nil, fmt.Errorf("policy of bucket %s is empty", bucket)
+		}
+		config["policy"] = m.policyDocument(policy)
+
+	case "aws_s3_bucket_logging":
+		config["target_bucket"] = m.generateReference(m.getStringFromMetadata(resource.Metadata, "target_bucket", ""), &dependencies)
+		config["target_prefix"] = m.getStringFromMetadata(resource.Metadata, "target_prefix", "")
+
+	case "aws_s3_bucket_replication_configuration":
+		config["role"] = m.getStringFromMetadata(resource.Metadata, "role", "")
+		var rules []map[string]interface{}
+		for _, rule := range m.getMapSliceFromMetadata(resource.Metadata, "rules") {
+			rules = append(rules, m.buildReplicationRule(rule, &dependencies))
+		}
+		if len(rules) == 0 {
+			return nil, fmt.Errorf("replication configuration of bucket %s has no rules", bucket)
+		}
+		config["rule"] = rules
+
+		// Replication can only be configured once versioning is enabled
+		if address, exists := m.addresses[bucket+"/versioning"]; exists {
+			dependencies = append(dependencies, address)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported AWS resource type: %s", resource.Type)
+	}
+
+	mapped := &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     resource.Type,
+		ResourceName:     m.generateResourceName(resource),
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		Outputs:          make(map[string]generation.Output),
+	}
+
+	return mapped, nil
+}
+
+// buildLifecycleRule builds a lifecycle rule block from a discovered rule
+func (m *AWSMapper) buildLifecycleRule(rule map[string]interface{}) map[string]interface{} {
+	block := map[string]interface{}{
+		"id":     m.getStringFromMetadata(rule, "id", ""),
+		"status": m.getStringFromMetadata(rule, "status", "Enabled"),
+	}
+
+	// Rules without a filter apply to the whole bucket; the provider expects
+	// an empty filter rather than none
+	filter := m.buildS3Filter(m.getMapFromMetadata(rule, "filter"), true)
+	if prefix, ok := rule["prefix"].(string); ok && len(filter) == 0 {
+		filter["prefix"] = prefix
+	}
+	block["filter"] = filter
+
+	if expiration := m.getMapFromMetadata(rule, "expiration"); len(expiration) > 0 {
+		fields := make(map[string]interface{})
+		if _, ok := expiration["days"]; ok {
+			fields["days"] = m.getIntFromMetadata(expiration, "days", 0)
+		}
+		if date := m.getStringFromMetadata(expiration, "date", ""); date != "" {
+			fields["date"] = date
+		}
+		if _, ok := expiration["expired_object_delete_marker"]; ok {
+			fields["expired_object_delete_marker"] = m.getBoolFromMetadata(expiration, "expired_object_delete_marker", false)
+		}
+		block["expiration"] = fields
+	}
+
+	var transitions []map[string]interface{}
+	for _, transition := range m.getMapSliceFromMetadata(rule, "transitions") {
+		fields := map[string]interface{}{
+			"storage_class": m.getStringFromMetadata(transition, "storage_class", ""),
+		}
+		if _, ok := transition["days"]; ok {
+			fields["days"] = m.getIntFromMetadata(transition, "days", 0)
+		}
+		if date := m.getStringFromMetadata(transition, "date", ""); date != "" {
+			fields["date"] = date
+		}
+		transitions = append(transitions, fields)
+	}
+	if len(transitions) > 0 {
+		block["transition"] = transitions
+	}
+
+	if expiration := m.getMapFromMetadata(rule, "noncurrent_version_expiration"); len(expiration) > 0 {
+		fields := map[string]interface{}{
+			"noncurrent_days": m.getIntFromMetadata(expiration, "noncurrent_days", 0),
+		}
+		if _, ok := expiration["newer_noncurrent_versions"]; ok {
+			fields["newer_noncurrent_versions"] = m.getIntFromMetadata(expiration, "newer_noncurrent_versions", 0)
+		}
+		block["noncurrent_version_expiration"] = fields
+	}
+
+	var noncurrentTransitions []map[string]interface{}
+	for _, transition := range m.getMapSliceFromMetadata(rule, "noncurrent_version_transitions") {
+		fields := map[string]interface{}{
+			"noncurrent_days": m.getIntFromMetadata(transition, "noncurrent_days", 0),
+			"storage_class":   m.getStringFromMetadata(transition, "storage_class", ""),
+		}
+		if _, ok := transition["newer_noncurrent_versions"]; ok {
+			fields["newer_noncurrent_versions"] = m.getIntFromMetadata(transition, "newer_noncurrent_versions", 0)
+		}
+		noncurrentTransitions = append(noncurrentTransitions, fields)
+	}
+	if len(noncurrentTransitions) > 0 {
+		block["noncurrent_version_transition"] = noncurrentTransitions
+	}
+
+	if _, ok := rule["abort_incomplete_multipart_upload_days"]; ok {
+		block["abort_incomplete_multipart_upload"] = map[string]interface{}{
+			"days_after_initiation": m.getIntFromMetadata(rule, "abort_incomplete_multipart_upload_days", 0),
+		}
+	}
+
+	return block
+}
+
+// buildReplicationRule builds a replication rule block from a discovered rule
+func (m *AWSMapper) buildReplicationRule(rule map[string]interface{}, dependencies *[]string) map[string]interface{} {
+	block := map[string]interface{}{
+		"id":     m.getStringFromMetadata(rule, "id", ""),
+		"status": m.getStringFromMetadata(rule, "status", "Enabled"),
+	}
+
+	// Rules with a filter use the current schema, which also requires a
+	// priority and delete marker replication; others use the legacy prefix
+	if filter := m.getMapFromMetadata(rule, "filter"); filter != nil {
+		block["filter"] = m.buildS3Filter(filter, false)
+		block["priority"] = m.getIntFromMetadata(rule, "priority", 0)
+		block["delete_marker_replication"] = map[string]interface{}{
+			"status": m.getStringFromMetadata(rule, "delete_marker_replication", "Disabled"),
+		}
+	} else {
+		block["prefix"] = m.getStringFromMetadata(rule, "prefix", "")
+	}
+
+	destination := m.getMapFromMetadata(rule, "destination")
+	fields := map[string]interface{}{
+		"bucket": m.getStringFromMetadata(destination, "bucket_arn", ""),
+	}
+	if bucket := m.getStringFromMetadata(destination, "bucket", ""); bucket != "" {
+		if _, exists := m.addresses[bucket]; exists {
+			fields["bucket"] = m.generateAttributeReference(bucket, "arn", dependencies)
+		}
+	}
+	if storageClass := m.getStringFromMetadata(destination, "storage_class", ""); storageClass != "" {
+		fields["storage_class"] = storageClass
+	}
+	if account := m.getStringFromMetadata(destination, "account", ""); account != "" {
+		fields["account"] = account
+	}
+	if keyId := m.getStringFromMetadata(destination, "replica_kms_key_id", ""); keyId != "" {
+		fields["encryption_configuration"] = map[string]interface{}{
+			"replica_kms_key_id": keyId,
+		}
+	}
+	block["destination"] = fields
+
+	return block
+}
+
+// buildS3Filter builds a lifecycle or replication filter block. A single tag
+// is matched by a tag block and several by an and block; only lifecycle
+// filters have object size conditions.
+func (m *AWSMapper) buildS3Filter(filter map[string]interface{}, objectSizes bool) map[string]interface{} {
+	block := make(map[string]interface{})
+	if filter == nil {
+		return block
+	}
+
+	if prefix, ok := filter["prefix"].(string); ok {
+		block["prefix"] = prefix
+	}
+	for key, value := range m.getStringMapFromMetadata(filter, "tags") {
+		block["tag"] = map[string]interface{}{"key": key, "value": value}
+	}
+	if objectSizes {
+		for _, key := range []string{"object_size_greater_than", "object_size_less_than"} {
+			if _, ok := filter[key]; ok {
+				block[key] = m.getIntFromMetadata(filter, key, 0)
+			}
+		}
+	}
+
+	if and := m.getMapFromMetadata(filter, "and"); and != nil {
+		fields := make(map[string]interface{})
+		if prefix, ok := and["prefix"].(string); ok {
+			fields["prefix"] = prefix
+		}
+		if tags := m.getStringMapFromMetadata(and, "tags"); len(tags) > 0 {
+			fields["tags"] = tags
+		}
+		if objectSizes {
+			for _, key := range []string{"object_size_greater_than", "object_size_less_than"} {
+				if _, ok := and[key]; ok {
+					fields[key] = m.getIntFromMetadata(and, key, 0)
+				}
+			}
+		}
+		block["and"] = fields
+	}
+
+	return block
+}
+
+func (m *AWSMapper) generateS3BucketOutputs(resource discovery.Resource) map[string]generation.Output {
+	resourceName := m.generateResourceName(resource)
+	return map[string]generation.Output{
+		"id": {
+			Name:        fmt.Sprintf("%s_id", resourceName),
+			Value:       fmt.Sprintf("aws_s3_bucket.%s.id", resourceName),
+			Description: "Name of the S3 bucket",
+		},
+		"arn": {
+			Name:        fmt.Sprintf("%s_arn", resourceName),
+			Value:       fmt.Sprintf("aws_s3_bucket.%s.arn", resourceName),
+			Description: "ARN of the S3 bucket",
+		},
+	}
+}
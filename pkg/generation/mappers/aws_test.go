@@ -0,0 +1,63 @@
+package mappers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+func TestAWSExpandResourcesWarnsAboutRulesWithoutID(t *testing.T) {
+	resources := []discovery.Resource{
+		{ID: "sg-2", Type: "aws_security_group", Provider: discovery.AWS},
+		{
+			ID: "sg-1", Type: "aws_security_group", Provider: discovery.AWS,
+			Metadata: map[string]interface{}{
+				"ingress_rules": []interface{}{
+					map[string]interface{}{
+						"protocol":        "tcp",
+						"from_port":       443,
+						"to_port":         443,
+						"security_groups": []interface{}{map[string]interface{}{"group_id": "sg-2", "rule_id": "sgr-1"}},
+						"cidr_blocks":     []interface{}{map[string]interface{}{"cidr": "10.0.0.0/8"}},
+					},
+				},
+			},
+		},
+	}
+
+	mapper := NewAWSMapper()
+	expanded, warnings := mapper.ExpandResources(resources)
+
+	var ids []string
+	for _, resource := range expanded {
+		ids = append(ids, resource.ID)
+	}
+	if want := []string{"sg-2", "sg-1", "sg-1-ingress-1", "sgr-1"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("got resources %v, want %v", ids, want)
+	}
+
+	mapper.SetResources(expanded)
+	for _, resource := range expanded[2:] {
+		mapped, err := mapper.MapResource(resource)
+		if err != nil {
+			t.Fatalf("MapResource(%s) returned error: %v", resource.ID, err)
+		}
+		if want := map[string]string{"sgr-1": "sgr-1"}[resource.ID]; mapped.ImportID != want {
+			t.Errorf("got import ID %q for %s, want %q", mapped.ImportID, resource.ID, want)
+		}
+	}
+
+	want := []generation.GenerationWarning{{
+		ResourceID:   "sg-1-ingress-1",
+		ResourceType: "aws_vpc_security_group_ingress_rule",
+		Provider:     discovery.AWS,
+		Message:      "ingress rule tcp 443-443 from 10.0.0.0/8 of security group sg-1 was discovered without a rule ID and cannot be imported",
+		Type:         generation.WarningTypeManualAction,
+		Suggestion:   "Rediscover with permission for ec2:DescribeSecurityGroupRules, or import the rule by hand once its sgr- ID is known",
+	}}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %+v, want %+v", warnings, want)
+	}
+}
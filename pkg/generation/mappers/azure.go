@@ -0,0 +1,661 @@
+package mappers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// AzureMapper implements ResourceMapper for Azure resources
+type AzureMapper struct {
+	// names maps lower-cased Azure resource IDs to unique Terraform resource names
+	names map[string]string
+
+	// addresses maps lower-cased Azure resource IDs to Terraform addresses (type.name)
+	addresses map[string]string
+
+	// resourceGroups maps lower-cased resource group names to Terraform addresses
+	resourceGroups map[string]string
+}
+
+// NewAzureMapper creates a new Azure resource mapper
+func NewAzureMapper() *AzureMapper {
+	return &AzureMapper{}
+}
+
+// MapResource maps a single discovered resource to an IaC resource (required by ResourceMapper interface)
+func (m *AzureMapper) MapResource(resource discovery.Resource) (*generation.MappedResource, error) {
+	switch resource.Type {
+	case "azure_resource_group":
+		return m.mapResourceGroup(resource)
+	case "azure_virtual_network":
+		return m.mapVirtualNetwork(resource)
+	case "azure_subnet":
+		return m.mapSubnet(resource)
+	case "azure_subnet_network_security_group_association":
+		return m.mapSubnetNetworkSecurityGroupAssociation(resource)
+	case "azure_network_security_group":
+		return m.mapNetworkSecurityGroup(resource)
+	case "azure_network_interface":
+		return m.mapNetworkInterface(resource)
+	case "azure_virtual_machine":
+		return m.mapVirtualMachine(resource)
+	default:
+		return nil, fmt.Errorf("unsupported Azure resource type: %s", resource.Type)
+	}
+}
+
+// ExpandResources adds the association of every subnet with its network
+// security group, which Terraform manages as a resource of its own (required
+// by ResourceExpander interface)
+func (m *AzureMapper) ExpandResources(resources []discovery.Resource) ([]discovery.Resource, []generation.GenerationWarning) {
+	expanded := make([]discovery.Resource, 0, len(resources))
+	for _, resource := range resources {
+		expanded = append(expanded, resource)
+		if resource.Provider != discovery.Azure || resource.Type != "azure_subnet" {
+			continue
+		}
+
+		nsgId := m.getStringFromMetadata(resource.Metadata, "network_security_group_id", "")
+		if nsgId == "" {
+			continue
+		}
+		expanded = append(expanded, discovery.Resource{
+			ID:            resource.ID + "/networkSecurityGroupAssociation",
+			Name:          resource.Name,
+			Type:          "azure_subnet_network_security_group_association",
+			Provider:      discovery.Azure,
+			Region:        resource.Region,
+			ResourceGroup: resource.ResourceGroup,
+			Metadata: map[string]interface{}{
+				"subnet_id":                 resource.ID,
+				"network_security_group_id": nsgId,
+			},
+		})
+	}
+	return expanded, nil
+}
+
+// SetResources indexes the full resource set so that references between
+// resources resolve to the names they are generated under
+func (m *AzureMapper) SetResources(resources []discovery.Resource) {
+	m.names = make(map[string]string)
+	m.addresses = make(map[string]string)
+	m.resourceGroups = make(map[string]string)
+	used := make(map[string]bool)
+
+	for _, resource := range resources {
+		if resource.Provider != discovery.Azure {
+			continue
+		}
+
+		resourceType := m.terraformType(resource)
+		name := m.sanitizeResourceName(resource.Name)
+		if used[resourceType+"."+name] && resource.ResourceGroup != "" {
+			name = m.sanitizeResourceName(resource.ResourceGroup + "_" + resource.Name)
+		}
+		base := name
+		for i := 2; used[resourceType+"."+name]; i++ {
+			name = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[resourceType+"."+name] = true
+
+		key := strings.ToLower(resource.ID)
+		m.names[key] = name
+		m.addresses[key] = resourceType + "." + name
+
+		if resource.Type == "azure_resource_group" {
+			m.resourceGroups[strings.ToLower(resource.Name)] = resourceType + "." + name
+		}
+	}
+}
+
+// GetProviderConfig returns the provider configuration needed (required by ResourceMapper interface)
+func (m *AzureMapper) GetProviderConfig(resources []discovery.Resource) (*generation.ProviderConfig, error) {
+	return &generation.ProviderConfig{
+		Name:     "azurerm",
+		Source:   "hashicorp/azurerm",
+		Version:  "~> 3.0",
+		Required: true,
+		Config: map[string]interface{}{
+			"features": map[string]interface{}{},
+		},
+	}, nil
+}
+
+// GetDependencies returns the addresses of the resources a resource
+// references, as recorded when it is mapped, indexing allResources unless
+// SetResources was called (required by ResourceMapper interface)
+func (m *AzureMapper) GetDependencies(resource discovery.Resource, allResources []discovery.Resource) ([]string, error) {
+	if m.addresses == nil {
+		m.SetResources(allResources)
+	}
+
+	mapped, err := m.MapResource(resource)
+	if err != nil {
+		return nil, err
+	}
+	return mapped.Dependencies, nil
+}
+
+// ValidateMapping validates that the mapping is correct (required by ResourceMapper interface)
+func (m *AzureMapper) ValidateMapping(original discovery.Resource, mapped generation.MappedResource) error {
+	if mapped.ResourceType == "" {
+		return fmt.Errorf("mapped resource type cannot be empty")
+	}
+	if mapped.ResourceName == "" {
+		return fmt.Errorf("mapped resource name cannot be empty")
+	}
+	if mapped.Configuration == nil {
+		return fmt.Errorf("mapped resource configuration cannot be nil")
+	}
+
+	// Validate Azure-specific requirements
+	if !strings.HasPrefix(mapped.ResourceType, "azurerm_") {
+		return fmt.Errorf("Azure resource type must start with 'azurerm_', got: %s", mapped.ResourceType)
+	}
+
+	return nil
+}
+
+// GetSupportedTypes returns the resource types this mapper supports (required by ResourceMapper interface)
+func (m *AzureMapper) GetSupportedTypes() []string {
+	return []string{
+		"azure_resource_group",
+		"azure_virtual_network",
+		"azure_subnet",
+		"azure_subnet_network_security_group_association",
+		"azure_network_security_group",
+		"azure_network_interface",
+		"azure_virtual_machine",
+	}
+}
+
+// Provider returns the cloud provider this mapper supports (required by ResourceMapper interface)
+func (m *AzureMapper) Provider() discovery.CloudProvider {
+	return discovery.Azure
+}
+
+// MapResources maps Azure discovery resources to IaC representations
+func (m *AzureMapper) MapResources(resources []discovery.Resource, opts generation.GenerationOptions) ([]generation.MappedResource, error) {
+	var mapped []generation.MappedResource
+
+	for _, resource := range resources {
+		mappedResource, err := m.MapResource(resource)
+		if err != nil {
+			continue
+		}
+		if mappedResource != nil {
+			mapped = append(mapped, *mappedResource)
+		}
+	}
+
+	return mapped, nil
+}
+
+// mapResourceGroup maps an Azure Resource Group to Terraform resource
+func (m *AzureMapper) mapResourceGroup(resource discovery.Resource) (*generation.MappedResource, error) {
+	config := map[string]interface{}{
+		"name":     resource.Name,
+		"location": resource.Region,
+		"tags":     m.convertTags(resource.Tags),
+	}
+
+	return m.newMappedResource(resource, "azurerm_resource_group", config, nil, nil), nil
+}
+
+// mapVirtualNetwork maps an Azure Virtual Network to Terraform resource
+func (m *AzureMapper) mapVirtualNetwork(resource discovery.Resource) (*generation.MappedResource, error) {
+	addressSpace := m.getStringSliceFromMetadata(resource.Metadata, "address_prefixes")
+	if len(addressSpace) == 0 {
+		return nil, fmt.Errorf("virtual network %s has no address space", resource.Name)
+	}
+
+	config := map[string]interface{}{
+		"name":                resource.Name,
+		"location":            resource.Region,
+		"resource_group_name": m.generateResourceGroupReference(resource.ResourceGroup),
+		"address_space":       addressSpace,
+		"tags":                m.convertTags(resource.Tags),
+	}
+
+	if dnsServers := m.getStringSliceFromMetadata(resource.Metadata, "dns_servers"); len(dnsServers) > 0 {
+		config["dns_servers"] = dnsServers
+	}
+
+	return m.newMappedResource(resource, "azurerm_virtual_network", config, m.resourceGroupDependencies(resource), nil), nil
+}
+
+// mapSubnet maps an Azure Subnet to Terraform resource
+func (m *AzureMapper) mapSubnet(resource discovery.Resource) (*generation.MappedResource, error) {
+	addressPrefixes := m.getStringSliceFromMetadata(resource.Metadata, "address_prefixes")
+	if len(addressPrefixes) == 0 {
+		if prefix := m.getStringFromMetadata(resource.Metadata, "address_prefix", ""); prefix != "" {
+			addressPrefixes = []string{prefix}
+		}
+	}
+
+	// Subnets do not support tags in Azure
+	config := map[string]interface{}{
+		"name":                 resource.Name,
+		"resource_group_name":  m.generateResourceGroupReference(resource.ResourceGroup),
+		"virtual_network_name": m.getStringFromMetadata(resource.Metadata, "virtual_network", ""),
+		"address_prefixes":     addressPrefixes,
+	}
+
+	dependencies := m.resourceGroupDependencies(resource)
+	vnetId := m.getStringFromMetadata(resource.Metadata, "virtual_network_id", "")
+	if address, exists := m.addresses[strings.ToLower(vnetId)]; exists {
+		config["virtual_network_name"] = generation.Expression(address + ".name")
+		dependencies = append(dependencies, address)
+	}
+
+	return m.newMappedResource(resource, "azurerm_subnet", config, dependencies, nil), nil
+}
+
+// mapSubnetNetworkSecurityGroupAssociation maps the association of a subnet
+// with a network security group to Terraform resource, imported by the
+// subnet ID
+func (m *AzureMapper) mapSubnetNetworkSecurityGroupAssociation(resource discovery.Resource) (*generation.MappedResource, error) {
+	subnetId := m.getStringFromMetadata(resource.Metadata, "subnet_id", "")
+	nsgId := m.getStringFromMetadata(resource.Metadata, "network_security_group_id", "")
+	if subnetId == "" || nsgId == "" {
+		return nil, fmt.Errorf("network security group association %s has no subnet or network security group", resource.ID)
+	}
+
+	dependencies := []string{}
+	reference := func(id string) interface{} {
+		if address, exists := m.addresses[strings.ToLower(id)]; exists {
+			dependencies = append(dependencies, address)
+			return generation.Expression(address + ".id")
+		}
+		return id
+	}
+
+	config := map[string]interface{}{
+		"subnet_id":                 reference(subnetId),
+		"network_security_group_id": reference(nsgId),
+	}
+
+	mapped := m.newMappedResource(resource, "azurerm_subnet_network_security_group_association", config, dependencies, nil)
+	mapped.ImportID = subnetId
+	mapped.Outputs = make(map[string]generation.Output)
+	return mapped, nil
+}
+
+// mapNetworkSecurityGroup maps an Azure Network Security Group to Terraform resource
+func (m *AzureMapper) mapNetworkSecurityGroup(resource discovery.Resource) (*generation.MappedResource, error) {
+	config := map[string]interface{}{
+		"name":                resource.Name,
+		"location":            resource.Region,
+		"resource_group_name": m.generateResourceGroupReference(resource.ResourceGroup),
+		"tags":                m.convertTags(resource.Tags),
+	}
+
+	var rules []map[string]interface{}
+	for _, rule := range m.getMapSliceFromMetadata(resource.Metadata, "security_rules") {
+		block := map[string]interface{}{
+			"name":      m.getStringFromMetadata(rule, "name", ""),
+			"priority":  m.getIntFromMetadata(rule, "priority", 0),
+			"direction": m.getStringFromMetadata(rule, "direction", ""),
+			"access":    m.getStringFromMetadata(rule, "access", ""),
+			"protocol":  m.getStringFromMetadata(rule, "protocol", ""),
+		}
+
+		for _, key := range []string{"description", "source_port_range", "destination_port_range", "source_address_prefix", "destination_address_prefix"} {
+			if value := m.getStringFromMetadata(rule, key, ""); value != "" {
+				block[key] = value
+			}
+		}
+		for _, key := range []string{"source_port_ranges", "destination_port_ranges", "source_address_prefixes", "destination_address_prefixes"} {
+			if values := m.getStringSliceFromMetadata(rule, key); len(values) > 0 {
+				block[key] = values
+			}
+		}
+
+		rules = append(rules, block)
+	}
+	if len(rules) > 0 {
+		config["security_rule"] = rules
+	}
+
+	return m.newMappedResource(resource, "azurerm_network_security_group", config, m.resourceGroupDependencies(resource), nil), nil
+}
+
+// mapNetworkInterface maps an Azure Network Interface to Terraform resource
+func (m *AzureMapper) mapNetworkInterface(resource discovery.Resource) (*generation.MappedResource, error) {
+	config := map[string]interface{}{
+		"name":                resource.Name,
+		"location":            resource.Region,
+		"resource_group_name": m.generateResourceGroupReference(resource.ResourceGroup),
+		"tags":                m.convertTags(resource.Tags),
+	}
+
+	if _, exists := resource.Metadata["enable_accelerated_networking"]; exists {
+		config["enable_accelerated_networking"] = m.getBoolFromMetadata(resource.Metadata, "enable_accelerated_networking", false)
+	}
+	if _, exists := resource.Metadata["enable_ip_forwarding"]; exists {
+		config["enable_ip_forwarding"] = m.getBoolFromMetadata(resource.Metadata, "enable_ip_forwarding", false)
+	}
+
+	dependencies := m.resourceGroupDependencies(resource)
+
+	var ipConfigurations []map[string]interface{}
+	for _, ipConfig := range m.getMapSliceFromMetadata(resource.Metadata, "ip_configurations") {
+		allocation := m.getStringFromMetadata(ipConfig, "private_ip_address_allocation", "Dynamic")
+		block := map[string]interface{}{
+			"name":                          m.getStringFromMetadata(ipConfig, "name", "internal"),
+			"private_ip_address_allocation": allocation,
+		}
+
+		if subnetId := m.getStringFromMetadata(ipConfig, "subnet_id", ""); subnetId != "" {
+			block["subnet_id"] = subnetId
+			if address, exists := m.addresses[strings.ToLower(subnetId)]; exists {
+				block["subnet_id"] = generation.Expression(address + ".id")
+				dependencies = append(dependencies, address)
+			}
+		}
+		if strings.EqualFold(allocation, "Static") {
+			block["private_ip_address"] = m.getStringFromMetadata(ipConfig, "private_ip_address", "")
+		}
+		if publicIpId := m.getStringFromMetadata(ipConfig, "public_ip_address_id", ""); publicIpId != "" {
+			block["public_ip_address_id"] = publicIpId
+		}
+		if _, exists := ipConfig["primary"]; exists {
+			block["primary"] = m.getBoolFromMetadata(ipConfig, "primary", false)
+		}
+
+		ipConfigurations = append(ipConfigurations, block)
+	}
+	if len(ipConfigurations) == 0 {
+		return nil, fmt.Errorf("network interface %s has no IP configurations", resource.Name)
+	}
+	config["ip_configuration"] = ipConfigurations
+
+	return m.newMappedResource(resource, "azurerm_network_interface", config, dependencies, nil), nil
+}
+
+// mapVirtualMachine maps an Azure Virtual Machine to a Linux or Windows Terraform resource
+func (m *AzureMapper) mapVirtualMachine(resource discovery.Resource) (*generation.MappedResource, error) {
+	resourceType := m.terraformType(resource)
+	resourceName := m.generateResourceName(resource)
+	adminUsername := m.getStringFromMetadata(resource.Metadata, "admin_username", "azureuser")
+
+	config := map[string]interface{}{
+		"name":                resource.Name,
+		"location":            resource.Region,
+		"resource_group_name": m.generateResourceGroupReference(resource.ResourceGroup),
+		"size":                m.getStringFromMetadata(resource.Metadata, "vm_size", "Standard_B2s"),
+		"admin_username":      adminUsername,
+		"tags":                m.convertTags(resource.Tags),
+	}
+
+	if computerName := m.getStringFromMetadata(resource.Metadata, "computer_name", ""); computerName != "" {
+		config["computer_name"] = computerName
+	}
+	if resource.Zone != "" {
+		config["zone"] = resource.Zone
+	}
+
+	dependencies := m.resourceGroupDependencies(resource)
+
+	nicIds := m.getStringSliceFromMetadata(resource.Metadata, "network_interface_ids")
+	if len(nicIds) == 0 {
+		return nil, fmt.Errorf("virtual machine %s has no network interfaces", resource.Name)
+	}
+	nics := make([]interface{}, 0, len(nicIds))
+	for _, nicId := range nicIds {
+		if address, exists := m.addresses[strings.ToLower(nicId)]; exists {
+			nics = append(nics, generation.Expression(address+".id"))
+			dependencies = append(dependencies, address)
+		} else {
+			nics = append(nics, nicId)
+		}
+	}
+	config["network_interface_ids"] = nics
+
+	osDisk := m.getMapFromMetadata(resource.Metadata, "os_disk")
+	diskBlock := map[string]interface{}{
+		"caching":              m.getStringFromMetadata(osDisk, "caching", "ReadWrite"),
+		"storage_account_type": m.getStringFromMetadata(osDisk, "storage_account_type", "Standard_LRS"),
+	}
+	if name := m.getStringFromMetadata(osDisk, "name", ""); name != "" {
+		diskBlock["name"] = name
+	}
+	if size := m.getIntFromMetadata(osDisk, "disk_size_gb", 0); size > 0 {
+		diskBlock["disk_size_gb"] = size
+	}
+	config["os_disk"] = []map[string]interface{}{diskBlock}
+
+	if imageId := m.getStringFromMetadata(resource.Metadata, "image_id", ""); imageId != "" {
+		config["source_image_id"] = imageId
+	} else {
+		config["source_image_reference"] = []map[string]interface{}{
+			{
+				"publisher": m.getStringFromMetadata(resource.Metadata, "image_publisher", ""),
+				"offer":     m.getStringFromMetadata(resource.Metadata, "image_offer", ""),
+				"sku":       m.getStringFromMetadata(resource.Metadata, "image_sku", ""),
+				"version":   m.getStringFromMetadata(resource.Metadata, "image_version", "latest"),
+			},
+		}
+	}
+
+	// Credentials are never discovered, so they are supplied through variables
+	variables := make(map[string]generation.Variable)
+	passwordAuth := resourceType == "azurerm_windows_virtual_machine" ||
+		!m.getBoolFromMetadata(resource.Metadata, "disable_password_authentication", true)
+
+	if passwordAuth {
+		name := fmt.Sprintf("%s_admin_password", resourceName)
+		config["admin_password"] = generation.Expression("var." + name)
+		variables[name] = generation.Variable{
+			Name:        name,
+			Type:        "string",
+			Description: fmt.Sprintf("Administrator password for virtual machine %s", resource.Name),
+			Sensitive:   true,
+			Required:    true,
+		}
+		if resourceType == "azurerm_linux_virtual_machine" {
+			config["disable_password_authentication"] = false
+		}
+	} else {
+		name := fmt.Sprintf("%s_admin_ssh_public_key", resourceName)
+		config["admin_ssh_key"] = []map[string]interface{}{
+			{
+				"username":   adminUsername,
+				"public_key": generation.Expression("var." + name),
+			},
+		}
+		variables[name] = generation.Variable{
+			Name:        name,
+			Type:        "string",
+			Description: fmt.Sprintf("SSH public key for the administrator of virtual machine %s", resource.Name),
+			Required:    true,
+		}
+	}
+
+	return m.newMappedResource(resource, resourceType, config, dependencies, variables), nil
+}
+
+// Helper methods
+
+// newMappedResource assembles a MappedResource with the standard id output
+func (m *AzureMapper) newMappedResource(resource discovery.Resource, resourceType string, config map[string]interface{}, dependencies []string, variables map[string]generation.Variable) *generation.MappedResource {
+	if dependencies == nil {
+		dependencies = []string{}
+	}
+	if variables == nil {
+		variables = make(map[string]generation.Variable)
+	}
+
+	resourceName := m.generateResourceName(resource)
+
+	return &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     resourceType,
+		ResourceName:     resourceName,
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        variables,
+		ImportID:         resource.ID,
+		Outputs: map[string]generation.Output{
+			"id": {
+				Name:        fmt.Sprintf("%s_id", resourceName),
+				Value:       fmt.Sprintf("%s.%s.id", resourceType, resourceName),
+				Description: fmt.Sprintf("ID of the %s", strings.ReplaceAll(strings.TrimPrefix(resourceType, "azurerm_"), "_", " ")),
+			},
+		},
+	}
+}
+
+// terraformType returns the Terraform resource type for a discovered resource
+func (m *AzureMapper) terraformType(resource discovery.Resource) string {
+	if resource.Type == "azure_virtual_machine" {
+		if strings.EqualFold(m.getStringFromMetadata(resource.Metadata, "os_type", ""), "Windows") {
+			return "azurerm_windows_virtual_machine"
+		}
+		return "azurerm_linux_virtual_machine"
+	}
+	return "azurerm_" + strings.TrimPrefix(resource.Type, "azure_")
+}
+
+// generateResourceName returns the Terraform name for a resource, preferring
+// the unique name assigned by SetResources
+func (m *AzureMapper) generateResourceName(resource discovery.Resource) string {
+	if name, exists := m.names[strings.ToLower(resource.ID)]; exists {
+		return name
+	}
+	return m.sanitizeResourceName(resource.Name)
+}
+
+// sanitizeResourceName sanitizes a string for use as Terraform resource name
+func (m *AzureMapper) sanitizeResourceName(name string) string {
+	name = strings.ToLower(name)
+
+	var result strings.Builder
+	for _, r := range name {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_':
+			result.WriteRune(r)
+		case r == '-' || r == ' ' || r == '.':
+			result.WriteRune('_')
+		}
+	}
+
+	cleaned := result.String()
+
+	// Ensure it starts with a letter or underscore
+	if len(cleaned) > 0 && cleaned[0] >= '0' && cleaned[0] <= '9' {
+		cleaned = "resource_" + cleaned
+	}
+
+	if cleaned == "" {
+		cleaned = "resource"
+	}
+
+	return cleaned
+}
+
+// generateResourceGroupReference references a discovered resource group, or
+// falls back to its literal name when it is not being generated
+func (m *AzureMapper) generateResourceGroupReference(resourceGroup string) interface{} {
+	if address, exists := m.resourceGroups[strings.ToLower(resourceGroup)]; exists {
+		return generation.Expression(address + ".name")
+	}
+	return resourceGroup
+}
+
+// resourceGroupDependencies returns the resource group a resource depends on, if generated
+func (m *AzureMapper) resourceGroupDependencies(resource discovery.Resource) []string {
+	if address, exists := m.resourceGroups[strings.ToLower(resource.ResourceGroup)]; exists {
+		return []string{address}
+	}
+	return []string{}
+}
+
+// convertTags converts discovery tags to Terraform format
+func (m *AzureMapper) convertTags(tags map[string]string) map[string]string {
+	result := make(map[string]string)
+	for k, v := range tags {
+		result[k] = v
+	}
+	result["ManagedBy"] = "Chimera"
+
+	return result
+}
+
+// Metadata helper methods
+func (m *AzureMapper) getStringFromMetadata(metadata map[string]interface{}, key, defaultValue string) string {
+	if value, exists := metadata[key]; exists {
+		if str, ok := value.(string); ok {
+			return str
+		}
+	}
+	return defaultValue
+}
+
+func (m *AzureMapper) getBoolFromMetadata(metadata map[string]interface{}, key string, defaultValue bool) bool {
+	if value, exists := metadata[key]; exists {
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func (m *AzureMapper) getIntFromMetadata(metadata map[string]interface{}, key string, defaultValue int) int {
+	if value, exists := metadata[key]; exists {
+		switch v := value.(type) {
+		case int:
+			return v
+		case int32:
+			return int(v)
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+	}
+	return defaultValue
+}
+
+func (m *AzureMapper) getStringSliceFromMetadata(metadata map[string]interface{}, key string) []string {
+	switch value := metadata[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func (m *AzureMapper) getMapSliceFromMetadata(metadata map[string]interface{}, key string) []map[string]interface{} {
+	switch value := metadata[key].(type) {
+	case []map[string]interface{}:
+		return value
+	case []interface{}:
+		result := make([]map[string]interface{}, 0, len(value))
+		for _, item := range value {
+			if entry, ok := item.(map[string]interface{}); ok {
+				result = append(result, entry)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func (m *AzureMapper) getMapFromMetadata(metadata map[string]interface{}, key string) map[string]interface{} {
+	if value, ok := metadata[key].(map[string]interface{}); ok {
+		return value
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,80 @@
+package mappers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+)
+
+func TestAzureGetDependenciesUsesGeneratedNames(t *testing.T) {
+	const (
+		rgA    = "/subscriptions/s/resourceGroups/rg-a"
+		rgB    = "/subscriptions/s/resourceGroups/rg-b"
+		vnetA  = rgA + "/providers/Microsoft.Network/virtualNetworks/vnet"
+		vnetB  = rgB + "/providers/Microsoft.Network/virtualNetworks/vnet"
+		subnet = vnetB + "/subnets/default"
+		nsg    = rgB + "/providers/Microsoft.Network/networkSecurityGroups/web"
+		nic    = rgB + "/providers/Microsoft.Network/networkInterfaces/web"
+	)
+	resources := []discovery.Resource{
+		{ID: rgA, Name: "rg-a", Type: "azure_resource_group", Provider: discovery.Azure},
+		{ID: rgB, Name: "rg-b", Type: "azure_resource_group", Provider: discovery.Azure},
+		{
+			ID: vnetA, Name: "vnet", Type: "azure_virtual_network", Provider: discovery.Azure, ResourceGroup: "rg-a",
+			Metadata: map[string]interface{}{"address_prefixes": []string{"10.0.0.0/16"}},
+		},
+		{
+			ID: vnetB, Name: "vnet", Type: "azure_virtual_network", Provider: discovery.Azure, ResourceGroup: "rg-b",
+			Metadata: map[string]interface{}{"address_prefixes": []string{"10.0.0.0/16"}},
+		},
+		{ID: nsg, Name: "web", Type: "azure_network_security_group", Provider: discovery.Azure, ResourceGroup: "rg-b"},
+		{
+			ID: subnet, Name: "default", Type: "azure_subnet", Provider: discovery.Azure, ResourceGroup: "rg-b",
+			Metadata: map[string]interface{}{
+				"address_prefix":            "10.0.1.0/24",
+				"virtual_network":           "vnet",
+				"virtual_network_id":        vnetB,
+				"network_security_group_id": nsg,
+			},
+		},
+		{
+			ID: nic, Name: "web", Type: "azure_network_interface", Provider: discovery.Azure, ResourceGroup: "rg-b",
+			Metadata: map[string]interface{}{
+				"ip_configurations": []interface{}{map[string]interface{}{"subnet_id": subnet}},
+			},
+		},
+	}
+
+	mapper := NewAzureMapper()
+	resources, _ = mapper.ExpandResources(resources)
+	mapper.SetResources(resources)
+
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{id: vnetB, want: []string{"azurerm_resource_group.rg_b"}},
+		{id: subnet, want: []string{"azurerm_resource_group.rg_b", "azurerm_virtual_network.rg_b_vnet"}},
+		{id: subnet + "/networkSecurityGroupAssociation", want: []string{"azurerm_subnet.default", "azurerm_network_security_group.web"}},
+		{id: nic, want: []string{"azurerm_resource_group.rg_b", "azurerm_subnet.default"}},
+	}
+
+	for _, tt := range tests {
+		var resource discovery.Resource
+		for _, r := range resources {
+			if r.ID == tt.id {
+				resource = r
+			}
+		}
+
+		got, err := mapper.GetDependencies(resource, resources)
+		if err != nil {
+			t.Errorf("GetDependencies(%s) returned error: %v", tt.id, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetDependencies(%s) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,562 @@
+package mappers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// GCPMapper implements ResourceMapper for GCP resources
+type GCPMapper struct {
+	// names maps discovered resource IDs to unique Terraform resource names
+	names map[string]string
+
+	// addresses maps discovered resource IDs to Terraform addresses (type.name)
+	addresses map[string]string
+}
+
+// NewGCPMapper creates a new GCP resource mapper
+func NewGCPMapper() *GCPMapper {
+	return &GCPMapper{}
+}
+
+// MapResource maps a single discovered resource to an IaC resource (required by ResourceMapper interface)
+func (m *GCPMapper) MapResource(resource discovery.Resource) (*generation.MappedResource, error) {
+	switch resource.Type {
+	case "gcp_compute_network":
+		return m.mapNetwork(resource)
+	case "gcp_compute_subnetwork":
+		return m.mapSubnetwork(resource)
+	case "gcp_compute_firewall":
+		return m.mapFirewall(resource)
+	case "gcp_compute_instance":
+		return m.mapInstance(resource)
+	default:
+		return nil, fmt.Errorf("unsupported GCP resource type: %s", resource.Type)
+	}
+}
+
+// SetResources indexes the full resource set so that references between
+// resources resolve to the names they are generated under
+func (m *GCPMapper) SetResources(resources []discovery.Resource) {
+	m.names = make(map[string]string)
+	m.addresses = make(map[string]string)
+	used := make(map[string]bool)
+
+	for _, resource := range resources {
+		if resource.Provider != discovery.GCP {
+			continue
+		}
+
+		resourceType := m.terraformType(resource.Type)
+		name := m.sanitizeResourceName(resource.Name)
+
+		// Subnetworks and instances are only unique within a region or zone
+		if used[resourceType+"."+name] {
+			location := resource.Zone
+			if location == "" {
+				location = resource.Region
+			}
+			if location != "" {
+				name = m.sanitizeResourceName(location + "_" + resource.Name)
+			}
+		}
+		base := name
+		for i := 2; used[resourceType+"."+name]; i++ {
+			name = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[resourceType+"."+name] = true
+
+		m.names[resource.ID] = name
+		m.addresses[resource.ID] = resourceType + "." + name
+	}
+}
+
+// GetProviderConfig returns the provider configuration needed (required by ResourceMapper interface)
+func (m *GCPMapper) GetProviderConfig(resources []discovery.Resource) (*generation.ProviderConfig, error) {
+	config := make(map[string]interface{})
+	for _, resource := range resources {
+		if resource.Project != "" {
+			config["project"] = resource.Project
+		}
+		if resource.Region != "" {
+			config["region"] = resource.Region
+		}
+		if len(config) == 2 {
+			break
+		}
+	}
+
+	return &generation.ProviderConfig{
+		Name:     "google",
+		Source:   "hashicorp/google",
+		Version:  "~> 4.0",
+		Required: true,
+		Config:   config,
+	}, nil
+}
+
+// GetDependencies analyzes and returns resource dependencies (required by ResourceMapper interface)
+func (m *GCPMapper) GetDependencies(resource discovery.Resource, allResources []discovery.Resource) ([]string, error) {
+	var referencedIDs []string
+
+	switch resource.Type {
+	case "gcp_compute_subnetwork", "gcp_compute_firewall":
+		if network := m.getStringFromMetadata(resource.Metadata, "network", ""); network != "" {
+			referencedIDs = append(referencedIDs, m.networkID(resource.Project, network))
+		}
+	case "gcp_compute_instance":
+		for _, nic := range m.getMapSliceFromMetadata(resource.Metadata, "network_interfaces") {
+			if network := m.getStringFromMetadata(nic, "network", ""); network != "" {
+				referencedIDs = append(referencedIDs, m.networkID(resource.Project, network))
+			}
+			if subnetwork := m.getStringFromMetadata(nic, "subnetwork", ""); subnetwork != "" {
+				region := m.getStringFromMetadata(nic, "subnetwork_region", resource.Region)
+				referencedIDs = append(referencedIDs, m.subnetworkID(resource.Project, region, subnetwork))
+			}
+		}
+	}
+
+	var dependencies []string
+	for _, id := range referencedIDs {
+		for _, res := range allResources {
+			if res.ID == id {
+				dependencies = append(dependencies, fmt.Sprintf("%s.%s", m.terraformType(res.Type), m.sanitizeResourceName(res.Name)))
+				break
+			}
+		}
+	}
+
+	return dependencies, nil
+}
+
+// ValidateMapping validates that the mapping is correct (required by ResourceMapper interface)
+func (m *GCPMapper) ValidateMapping(original discovery.Resource, mapped generation.MappedResource) error {
+	if mapped.ResourceType == "" {
+		return fmt.Errorf("mapped resource type cannot be empty")
+	}
+	if mapped.ResourceName == "" {
+		return fmt.Errorf("mapped resource name cannot be empty")
+	}
+	if mapped.Configuration == nil {
+		return fmt.Errorf("mapped resource configuration cannot be nil")
+	}
+
+	// Validate GCP-specific requirements
+	if !strings.HasPrefix(mapped.ResourceType, "google_") {
+		return fmt.Errorf("GCP resource type must start with 'google_', got: %s", mapped.ResourceType)
+	}
+
+	return nil
+}
+
+// GetSupportedTypes returns the resource types this mapper supports (required by ResourceMapper interface)
+func (m *GCPMapper) GetSupportedTypes() []string {
+	return []string{
+		"gcp_compute_network",
+		"gcp_compute_subnetwork",
+		"gcp_compute_firewall",
+		"gcp_compute_instance",
+	}
+}
+
+// Provider returns the cloud provider this mapper supports (required by ResourceMapper interface)
+func (m *GCPMapper) Provider() discovery.CloudProvider {
+	return discovery.GCP
+}
+
+// MapResources maps GCP discovery resources to IaC representations
+func (m *GCPMapper) MapResources(resources []discovery.Resource, opts generation.GenerationOptions) ([]generation.MappedResource, error) {
+	var mapped []generation.MappedResource
+
+	for _, resource := range resources {
+		mappedResource, err := m.MapResource(resource)
+		if err != nil {
+			continue
+		}
+		if mappedResource != nil {
+			mapped = append(mapped, *mappedResource)
+		}
+	}
+
+	return mapped, nil
+}
+
+// mapNetwork maps a GCP VPC network to Terraform resource
+func (m *GCPMapper) mapNetwork(resource discovery.Resource) (*generation.MappedResource, error) {
+	config := map[string]interface{}{
+		"name":                    resource.Name,
+		"project":                 resource.Project,
+		"auto_create_subnetworks": m.getBoolFromMetadata(resource.Metadata, "auto_create_subnetworks", true),
+	}
+
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	if routingMode := m.getStringFromMetadata(resource.Metadata, "routing_mode", ""); routingMode != "" {
+		config["routing_mode"] = routingMode
+	}
+
+	return m.newMappedResource(resource, config, []string{}), nil
+}
+
+// mapSubnetwork maps a GCP subnetwork to Terraform resource
+func (m *GCPMapper) mapSubnetwork(resource discovery.Resource) (*generation.MappedResource, error) {
+	cidrRange := m.getStringFromMetadata(resource.Metadata, "ip_cidr_range", "")
+	if cidrRange == "" {
+		return nil, fmt.Errorf("subnetwork %s has no IP CIDR range", resource.Name)
+	}
+
+	config := map[string]interface{}{
+		"name":                     resource.Name,
+		"project":                  resource.Project,
+		"region":                   resource.Region,
+		"ip_cidr_range":            cidrRange,
+		"private_ip_google_access": m.getBoolFromMetadata(resource.Metadata, "private_ip_google_access", false),
+	}
+
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+
+	var dependencies []string
+	network := m.getStringFromMetadata(resource.Metadata, "network", "")
+	config["network"], dependencies = m.generateNetworkReference(resource.Project, network, dependencies)
+
+	var secondaryRanges []map[string]interface{}
+	for _, secondary := range m.getMapSliceFromMetadata(resource.Metadata, "secondary_ip_ranges") {
+		secondaryRanges = append(secondaryRanges, map[string]interface{}{
+			"range_name":    m.getStringFromMetadata(secondary, "range_name", ""),
+			"ip_cidr_range": m.getStringFromMetadata(secondary, "ip_cidr_range", ""),
+		})
+	}
+	if len(secondaryRanges) > 0 {
+		config["secondary_ip_range"] = secondaryRanges
+	}
+
+	return m.newMappedResource(resource, config, dependencies), nil
+}
+
+// mapFirewall maps a GCP firewall rule to Terraform resource
+func (m *GCPMapper) mapFirewall(resource discovery.Resource) (*generation.MappedResource, error) {
+	config := map[string]interface{}{
+		"name":      resource.Name,
+		"project":   resource.Project,
+		"direction": m.getStringFromMetadata(resource.Metadata, "direction", "INGRESS"),
+		"priority":  m.getIntFromMetadata(resource.Metadata, "priority", 1000),
+	}
+
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "disabled", false) {
+		config["disabled"] = true
+	}
+
+	var dependencies []string
+	network := m.getStringFromMetadata(resource.Metadata, "network", "")
+	config["network"], dependencies = m.generateNetworkReference(resource.Project, network, dependencies)
+
+	for _, key := range []string{"source_ranges", "destination_ranges", "source_tags", "target_tags", "source_service_accounts", "target_service_accounts"} {
+		if values := m.getStringSliceFromMetadata(resource.Metadata, key); len(values) > 0 {
+			config[key] = values
+		}
+	}
+
+	allow := m.buildFirewallRules(m.getMapSliceFromMetadata(resource.Metadata, "allowed"))
+	deny := m.buildFirewallRules(m.getMapSliceFromMetadata(resource.Metadata, "denied"))
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil, fmt.Errorf("firewall %s has no allow or deny rules in discovery metadata", resource.Name)
+	}
+	if len(allow) > 0 {
+		config["allow"] = allow
+	}
+	if len(deny) > 0 {
+		config["deny"] = deny
+	}
+
+	return m.newMappedResource(resource, config, dependencies), nil
+}
+
+// mapInstance maps a GCP compute instance to Terraform resource
+func (m *GCPMapper) mapInstance(resource discovery.Resource) (*generation.MappedResource, error) {
+	bootDisk := m.getStringFromMetadata(resource.Metadata, "boot_disk_source", "")
+	if bootDisk == "" {
+		return nil, fmt.Errorf("instance %s has no boot disk in discovery metadata", resource.Name)
+	}
+
+	config := map[string]interface{}{
+		"name":         resource.Name,
+		"project":      resource.Project,
+		"zone":         resource.Zone,
+		"machine_type": m.getStringFromMetadata(resource.Metadata, "machine_type", "e2-medium"),
+		"labels":       m.convertLabels(resource.Tags),
+		"boot_disk": []map[string]interface{}{
+			{
+				"source":      bootDisk,
+				"auto_delete": m.getBoolFromMetadata(resource.Metadata, "boot_disk_auto_delete", true),
+			},
+		},
+	}
+
+	if description := m.getStringFromMetadata(resource.Metadata, "description", ""); description != "" {
+		config["description"] = description
+	}
+	if networkTags := m.getStringSliceFromMetadata(resource.Metadata, "network_tags"); len(networkTags) > 0 {
+		config["tags"] = networkTags
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "can_ip_forward", false) {
+		config["can_ip_forward"] = true
+	}
+	if m.getBoolFromMetadata(resource.Metadata, "deletion_protection", false) {
+		config["deletion_protection"] = true
+	}
+
+	var dependencies []string
+	var networkInterfaces []map[string]interface{}
+	for _, nic := range m.getMapSliceFromMetadata(resource.Metadata, "network_interfaces") {
+		block := make(map[string]interface{})
+
+		if network := m.getStringFromMetadata(nic, "network", ""); network != "" {
+			block["network"], dependencies = m.generateNetworkReference(resource.Project, network, dependencies)
+		}
+		if subnetwork := m.getStringFromMetadata(nic, "subnetwork", ""); subnetwork != "" {
+			region := m.getStringFromMetadata(nic, "subnetwork_region", resource.Region)
+			subnetworkId := m.subnetworkID(resource.Project, region, subnetwork)
+			block["subnetwork"] = subnetworkId
+			if address, exists := m.addresses[subnetworkId]; exists {
+				block["subnetwork"] = generation.Expression(address + ".self_link")
+				dependencies = append(dependencies, address)
+			}
+		}
+		if networkIP := m.getStringFromMetadata(nic, "network_ip", ""); networkIP != "" {
+			block["network_ip"] = networkIP
+		}
+		if m.getBoolFromMetadata(nic, "has_external_ip", false) {
+			// An empty access_config requests an ephemeral external IP
+			block["access_config"] = []map[string]interface{}{{}}
+		}
+
+		networkInterfaces = append(networkInterfaces, block)
+	}
+	if len(networkInterfaces) == 0 {
+		return nil, fmt.Errorf("instance %s has no network interfaces", resource.Name)
+	}
+	config["network_interface"] = networkInterfaces
+
+	if email := m.getStringFromMetadata(resource.Metadata, "service_account_email", ""); email != "" {
+		config["service_account"] = []map[string]interface{}{
+			{
+				"email":  email,
+				"scopes": m.getStringSliceFromMetadata(resource.Metadata, "service_account_scopes"),
+			},
+		}
+	}
+
+	if scheduling := m.getMapFromMetadata(resource.Metadata, "scheduling"); len(scheduling) > 0 {
+		block := make(map[string]interface{})
+		for _, key := range []string{"preemptible", "automatic_restart"} {
+			if _, exists := scheduling[key]; exists {
+				block[key] = m.getBoolFromMetadata(scheduling, key, false)
+			}
+		}
+		if onHostMaintenance := m.getStringFromMetadata(scheduling, "on_host_maintenance", ""); onHostMaintenance != "" {
+			block["on_host_maintenance"] = onHostMaintenance
+		}
+		config["scheduling"] = []map[string]interface{}{block}
+	}
+
+	return m.newMappedResource(resource, config, dependencies), nil
+}
+
+// Helper methods
+
+// newMappedResource assembles a MappedResource with the standard self_link output
+func (m *GCPMapper) newMappedResource(resource discovery.Resource, config map[string]interface{}, dependencies []string) *generation.MappedResource {
+	if dependencies == nil {
+		dependencies = []string{}
+	}
+
+	resourceType := m.terraformType(resource.Type)
+	resourceName := m.generateResourceName(resource)
+
+	return &generation.MappedResource{
+		OriginalResource: resource,
+		ResourceType:     resourceType,
+		ResourceName:     resourceName,
+		Configuration:    config,
+		Dependencies:     dependencies,
+		Variables:        make(map[string]generation.Variable),
+		ImportID:         resource.ID,
+		Outputs: map[string]generation.Output{
+			"self_link": {
+				Name:        fmt.Sprintf("%s_self_link", resourceName),
+				Value:       fmt.Sprintf("%s.%s.self_link", resourceType, resourceName),
+				Description: fmt.Sprintf("Self link of the %s", strings.ReplaceAll(strings.TrimPrefix(resourceType, "google_compute_"), "_", " ")),
+			},
+		},
+	}
+}
+
+// buildFirewallRules converts discovered allow or deny entries into blocks
+func (m *GCPMapper) buildFirewallRules(rules []map[string]interface{}) []map[string]interface{} {
+	var blocks []map[string]interface{}
+	for _, rule := range rules {
+		block := map[string]interface{}{
+			"protocol": m.getStringFromMetadata(rule, "protocol", "all"),
+		}
+		if ports := m.getStringSliceFromMetadata(rule, "ports"); len(ports) > 0 {
+			block["ports"] = ports
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// generateNetworkReference references a discovered network by self link, or
+// falls back to the network's relative resource path
+func (m *GCPMapper) generateNetworkReference(project, network string, dependencies []string) (interface{}, []string) {
+	networkId := m.networkID(project, network)
+	if address, exists := m.addresses[networkId]; exists {
+		return generation.Expression(address + ".self_link"), append(dependencies, address)
+	}
+	return networkId, dependencies
+}
+
+// networkID builds the discovery ID of a global network
+func (m *GCPMapper) networkID(project, network string) string {
+	return fmt.Sprintf("projects/%s/global/networks/%s", project, network)
+}
+
+// subnetworkID builds the discovery ID of a regional subnetwork
+func (m *GCPMapper) subnetworkID(project, region, subnetwork string) string {
+	return fmt.Sprintf("projects/%s/regions/%s/subnetworks/%s", project, region, subnetwork)
+}
+
+// terraformType returns the Terraform resource type for a discovered type
+func (m *GCPMapper) terraformType(resourceType string) string {
+	return "google_" + strings.TrimPrefix(resourceType, "gcp_")
+}
+
+// generateResourceName returns the Terraform name for a resource, preferring
+// the unique name assigned by SetResources
+func (m *GCPMapper) generateResourceName(resource discovery.Resource) string {
+	if name, exists := m.names[resource.ID]; exists {
+		return name
+	}
+	return m.sanitizeResourceName(resource.Name)
+}
+
+// sanitizeResourceName sanitizes a string for use as Terraform resource name
+func (m *GCPMapper) sanitizeResourceName(name string) string {
+	var result strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_':
+			result.WriteRune(r)
+		case r == '-' || r == ' ' || r == '.':
+			result.WriteRune('_')
+		}
+	}
+
+	cleaned := result.String()
+
+	// Ensure it starts with a letter or underscore
+	if len(cleaned) > 0 && cleaned[0] >= '0' && cleaned[0] <= '9' {
+		cleaned = "resource_" + cleaned
+	}
+
+	if cleaned == "" {
+		cleaned = "resource"
+	}
+
+	return cleaned
+}
+
+// convertLabels converts discovery labels to Terraform format; GCP label keys
+// must be lower case, so the management label differs from other providers
+func (m *GCPMapper) convertLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result["managed_by"] = "chimera"
+
+	return result
+}
+
+// Metadata helper methods
+func (m *GCPMapper) getStringFromMetadata(metadata map[string]interface{}, key, defaultValue string) string {
+	if value, exists := metadata[key]; exists {
+		if str, ok := value.(string); ok {
+			return str
+		}
+	}
+	return defaultValue
+}
+
+func (m *GCPMapper) getBoolFromMetadata(metadata map[string]interface{}, key string, defaultValue bool) bool {
+	if value, exists := metadata[key]; exists {
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func (m *GCPMapper) getIntFromMetadata(metadata map[string]interface{}, key string, defaultValue int) int {
+	if value, exists := metadata[key]; exists {
+		switch v := value.(type) {
+		case int:
+			return v
+		case int32:
+			return int(v)
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+	}
+	return defaultValue
+}
+
+func (m *GCPMapper) getStringSliceFromMetadata(metadata map[string]interface{}, key string) []string {
+	switch value := metadata[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func (m *GCPMapper) getMapSliceFromMetadata(metadata map[string]interface{}, key string) []map[string]interface{} {
+	switch value := metadata[key].(type) {
+	case []map[string]interface{}:
+		return value
+	case []interface{}:
+		result := make([]map[string]interface{}, 0, len(value))
+		for _, item := range value {
+			if entry, ok := item.(map[string]interface{}); ok {
+				result = append(result, entry)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func (m *GCPMapper) getMapFromMetadata(metadata map[string]interface{}, key string) map[string]interface{} {
+	if value, ok := metadata[key].(map[string]interface{}); ok {
+		return value
+	}
+	return map[string]interface{}{}
+}
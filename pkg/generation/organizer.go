@@ -0,0 +1,216 @@
+package generation
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// PatternSeparator joins organization patterns into a combined pattern, such
+// as by_provider+by_region+by_service
+const PatternSeparator = "+"
+
+// CombinePatterns combines organization patterns; each pattern adds one level
+// to the file path, the last naming the file
+func CombinePatterns(patterns ...OrganizationPattern) OrganizationPattern {
+	parts := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		parts[i] = string(pattern)
+	}
+	return OrganizationPattern(strings.Join(parts, PatternSeparator))
+}
+
+// SplitPattern splits a combined organization pattern into its patterns
+func SplitPattern(pattern OrganizationPattern) []OrganizationPattern {
+	if pattern == "" {
+		return []OrganizationPattern{OrganizeFlat}
+	}
+
+	var patterns []OrganizationPattern
+	for _, part := range strings.Split(string(pattern), PatternSeparator) {
+		patterns = append(patterns, OrganizationPattern(strings.TrimSpace(part)))
+	}
+	return patterns
+}
+
+// ResolvePattern returns the organization pattern of the generation options,
+// derived from the OrganizeByType, OrganizeByRegion and SingleFile options
+// when no pattern is set; it is empty when none of them are set
+func ResolvePattern(opts GenerationOptions) OrganizationPattern {
+	switch {
+	case opts.Organization != "":
+		return opts.Organization
+	case opts.SingleFile:
+		return OrganizeFlat
+	case opts.OrganizeByRegion && opts.OrganizeByType:
+		return CombinePatterns(OrganizeByRegion, OrganizeByResourceType)
+	case opts.OrganizeByRegion:
+		return OrganizeByRegion
+	case opts.OrganizeByType:
+		return OrganizeByResourceType
+	default:
+		return ""
+	}
+}
+
+// serviceKeywords maps resource type keywords to the service file a resource
+// is organized into by OrganizeByService, checked in order
+var serviceKeywords = []struct {
+	keyword string
+	service string
+}{
+	{"security_group", "security"},
+	{"firewall", "security"},
+	{"network_acl", "security"},
+	{"key_pair", "security"},
+	{"kms", "security"},
+	{"s3", "storage"},
+	{"iam", "iam"},
+	{"service_account", "iam"},
+	{"role", "iam"},
+	{"policy", "iam"},
+	{"lambda", "serverless"},
+	{"function", "serverless"},
+	{"api_gateway", "serverless"},
+	{"apigatewayv2", "serverless"},
+	{"cloudwatch_event", "serverless"},
+	{"db_", "database"},
+	{"rds", "database"},
+	{"sql", "database"},
+	{"dynamodb", "database"},
+	{"cosmosdb", "database"},
+	{"storage", "storage"},
+	{"efs", "storage"},
+	{"ebs", "storage"},
+	{"managed_disk", "storage"},
+	{"volume", "storage"},
+	{"instance", "compute"},
+	{"virtual_machine", "compute"},
+	{"launch_template", "compute"},
+	{"autoscaling", "compute"},
+	{"vpc", "network"},
+	{"subnet", "network"},
+	{"network", "network"},
+	{"route", "network"},
+	{"gateway", "network"},
+	{"eip", "network"},
+	{"public_ip", "network"},
+	{"lb", "network"},
+	{"resource_group", "management"},
+}
+
+// unsafePathChars matches characters not allowed in generated path segments
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// PatternOrganizer organizes Terraform resources into files for every
+// organization pattern and for combined patterns. Each pattern of a combined
+// pattern adds a directory level, so by_provider+by_region+by_service writes
+// aws/us-east-1/network.tf.
+type PatternOrganizer struct{}
+
+// NewPatternOrganizer creates a new pattern organizer
+func NewPatternOrganizer() *PatternOrganizer {
+	return &PatternOrganizer{}
+}
+
+// OrganizeFiles groups resources by file path, keeping their order
+func (o *PatternOrganizer) OrganizeFiles(resources []TerraformResource, pattern OrganizationPattern) (map[string][]TerraformResource, error) {
+	if err := o.ValidateOrganization(pattern, resources); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]TerraformResource)
+	for _, resource := range resources {
+		filePath, err := o.GetFilePath(resource, pattern)
+		if err != nil {
+			return nil, err
+		}
+		files[filePath] = append(files[filePath], resource)
+	}
+	return files, nil
+}
+
+// GetFilePath returns the file path for a resource
+func (o *PatternOrganizer) GetFilePath(resource TerraformResource, pattern OrganizationPattern) (string, error) {
+	patterns := SplitPattern(pattern)
+	if len(patterns) == 1 && patterns[0] == OrganizeFlat {
+		return "main.tf", nil
+	}
+
+	segments := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		segment, err := patternSegment(resource, p)
+		if err != nil {
+			return "", err
+		}
+		segments = append(segments, segment)
+	}
+	return path.Join(segments...) + ".tf", nil
+}
+
+// ValidateOrganization validates the organization pattern
+func (o *PatternOrganizer) ValidateOrganization(pattern OrganizationPattern, resources []TerraformResource) error {
+	patterns := SplitPattern(pattern)
+	seen := make(map[OrganizationPattern]bool, len(patterns))
+	for _, p := range patterns {
+		switch p {
+		case OrganizeByProvider, OrganizeByService, OrganizeByRegion, OrganizeByResourceType:
+		case OrganizeFlat:
+			if len(patterns) > 1 {
+				return fmt.Errorf("organization pattern %s cannot be combined with other patterns", OrganizeFlat)
+			}
+		default:
+			return fmt.Errorf("unknown organization pattern: %q", p)
+		}
+		if seen[p] {
+			return fmt.Errorf("organization pattern %s is repeated in %s", p, pattern)
+		}
+		seen[p] = true
+	}
+	return nil
+}
+
+// patternSegment returns the path segment a pattern assigns to a resource
+func patternSegment(resource TerraformResource, pattern OrganizationPattern) (string, error) {
+	var segment string
+	switch pattern {
+	case OrganizeByProvider:
+		segment = string(resource.SourceInfo.OriginalProvider)
+		if segment == "" {
+			segment = string(resource.Provider)
+		}
+	case OrganizeByRegion:
+		segment = resource.SourceInfo.OriginalRegion
+		if segment == "" {
+			segment = "global"
+		}
+	case OrganizeByService:
+		segment = serviceOf(resource.Type)
+	case OrganizeByResourceType:
+		segment = resource.Type
+	default:
+		return "", fmt.Errorf("organization pattern %s cannot be combined with other patterns", pattern)
+	}
+
+	segment = strings.Trim(unsafePathChars.ReplaceAllString(segment, "_"), "._")
+	if segment == "" {
+		segment = "other"
+	}
+	return segment, nil
+}
+
+// serviceOf returns the service a Terraform resource type belongs to
+func serviceOf(resourceType string) string {
+	name := resourceType
+	if i := strings.Index(name, "_"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	for _, entry := range serviceKeywords {
+		if strings.Contains(name, entry.keyword) {
+			return entry.service
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,252 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// Language is the language a Pulumi program is written in
+type Language string
+
+const (
+	TypeScript Language = "typescript"
+	Python     Language = "python"
+	Go         Language = "go"
+)
+
+// defaultProjectName is used when no project name can be derived from the output path
+const defaultProjectName = "chimera-import"
+
+// sdkVersions holds the SDK versions generated projects depend on
+var sdkVersions = map[string]string{
+	"pulumi": "3.113.0",
+	"aws":    "6.32.0",
+	"azure":  "5.73.0",
+	"gcp":    "7.20.0",
+}
+
+// projectNamePattern matches characters not allowed in a project name
+var projectNamePattern = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// Generator generates a Pulumi project in one language from mapped resources
+type Generator struct {
+	language Language
+}
+
+// NewGenerator creates a new Pulumi generator for the given language
+func NewGenerator(language Language) *Generator {
+	return &Generator{language: language}
+}
+
+// Capabilities returns the features of Pulumi programs
+func (g *Generator) Capabilities() generation.FormatCapabilities {
+	return generation.FormatCapabilities{
+		SupportedProviders: []discovery.CloudProvider{discovery.AWS, discovery.Azure, discovery.GCP},
+		SupportsVariables:  true,
+		SupportsOutputs:    true,
+		SupportsImports:    true,
+	}
+}
+
+// Generate generates the project file, dependency manifest and program.
+// Every resource is declared with the import resource option so the first
+// pulumi up adopts the existing infrastructure.
+func (g *Generator) Generate(resources []generation.MappedResource, opts generation.GenerationOptions) ([]generation.GeneratedFile, error) {
+	prog, unsupported, err := buildProgram(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	project := projectName(opts.OutputPath)
+	regions := providerRegions(resources)
+
+	var files []generation.GeneratedFile
+	switch g.language {
+	case TypeScript:
+		files = []generation.GeneratedFile{
+			g.file("Pulumi.yaml", projectFile(project, "nodejs", regions), generation.FileTypeProject, 0),
+			g.file("package.json", packageJSON(project, prog.packages), generation.FileTypeVersions, 0),
+			g.file("tsconfig.json", tsconfigJSON, generation.FileTypeProject, 0),
+			g.file("index.ts", renderTypeScript(prog, unsupported), generation.FileTypeMain, len(prog.resources)),
+		}
+	case Python:
+		files = []generation.GeneratedFile{
+			g.file("Pulumi.yaml", projectFile(project, "python", regions), generation.FileTypeProject, 0),
+			g.file("requirements.txt", requirementsTxt(prog.packages), generation.FileTypeVersions, 0),
+			g.file("__main__.py", renderPython(prog, unsupported), generation.FileTypeMain, len(prog.resources)),
+		}
+	case Go:
+		program, err := renderGo(prog, unsupported)
+		if err != nil {
+			return nil, err
+		}
+		files = []generation.GeneratedFile{
+			g.file("Pulumi.yaml", projectFile(project, "go", regions), generation.FileTypeProject, 0),
+			g.file("go.mod", goMod(project, prog.packages), generation.FileTypeVersions, 0),
+			g.file("main.go", program, generation.FileTypeMain, len(prog.resources)),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Pulumi language: %s", g.language)
+	}
+
+	return files, nil
+}
+
+// format returns the output format for the generator's language
+func (g *Generator) format() generation.IaCFormat {
+	switch g.language {
+	case Python:
+		return generation.PulumiPython
+	case Go:
+		return generation.PulumiGo
+	default:
+		return generation.PulumiTypeScript
+	}
+}
+
+// file builds a generated file
+func (g *Generator) file(path, content string, fileType generation.FileType, resourceCount int) generation.GeneratedFile {
+	return generation.GeneratedFile{
+		Path:          path,
+		Content:       content,
+		Type:          fileType,
+		Format:        g.format(),
+		Size:          int64(len(content)),
+		ResourceCount: resourceCount,
+	}
+}
+
+// projectName derives a project name from the output directory
+func projectName(outputPath string) string {
+	name := filepath.Base(filepath.Clean(outputPath))
+	name = strings.Trim(projectNamePattern.ReplaceAllString(name, "-"), "-.")
+	if name == "" {
+		return defaultProjectName
+	}
+	return name
+}
+
+// providerRegions returns the AWS regions of the mapped resources, which
+// must be set as the aws:region stack configuration
+func providerRegions(resources []generation.MappedResource) []string {
+	seen := make(map[string]bool)
+	var regions []string
+	for _, resource := range resources {
+		region := resource.OriginalResource.Region
+		if resource.OriginalResource.Provider != discovery.AWS || region == "" || seen[region] {
+			continue
+		}
+		seen[region] = true
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// projectFile renders Pulumi.yaml
+func projectFile(project, runtime string, regions []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: %s\n", project)
+	switch runtime {
+	case "python":
+		b.WriteString("runtime:\n  name: python\n  options:\n    virtualenv: venv\n")
+	default:
+		fmt.Fprintf(&b, "runtime: %s\n", runtime)
+	}
+	b.WriteString("description: Existing infrastructure imported by Chimera\n")
+
+	if len(regions) > 0 {
+		b.WriteString("# Set the AWS region before the first deployment:\n")
+		fmt.Fprintf(&b, "#   pulumi config set aws:region %s\n", regions[0])
+		if len(regions) > 1 {
+			fmt.Fprintf(&b, "# Resources were discovered in several regions (%s); deploy\n", strings.Join(regions, ", "))
+			b.WriteString("# one stack per region or add explicit providers.\n")
+		}
+	}
+
+	return b.String()
+}
+
+// packageJSON renders the Node.js dependency manifest
+func packageJSON(project string, packages []string) string {
+	dependencies := map[string]string{"@pulumi/pulumi": "^" + sdkVersions["pulumi"]}
+	for _, pkg := range packages {
+		dependencies["@pulumi/"+pkg] = "^" + sdkVersions[pkg]
+	}
+
+	manifest := map[string]interface{}{
+		"name":         project,
+		"main":         "index.ts",
+		"dependencies": dependencies,
+		"devDependencies": map[string]string{
+			"@types/node": "^18.0.0",
+			"typescript":  "^5.0.0",
+		},
+	}
+
+	content, _ := json.MarshalIndent(manifest, "", "  ")
+	return string(content) + "\n"
+}
+
+// tsconfigJSON is the TypeScript compiler configuration of Pulumi's templates
+const tsconfigJSON = `{
+  "compilerOptions": {
+    "strict": true,
+    "outDir": "bin",
+    "target": "es2020",
+    "module": "commonjs",
+    "moduleResolution": "node",
+    "sourceMap": true,
+    "experimentalDecorators": true,
+    "pretty": true,
+    "noFallthroughCasesInSwitch": true,
+    "noImplicitReturns": true,
+    "forceConsistentCasingInFileNames": true
+  },
+  "files": [
+    "index.ts"
+  ]
+}
+`
+
+// requirementsTxt renders the Python dependency manifest
+func requirementsTxt(packages []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pulumi>=%s,<4.0.0\n", sdkVersions["pulumi"])
+	for _, pkg := range packages {
+		fmt.Fprintf(&b, "pulumi-%s>=%s,<%s\n", pkg, sdkVersions[pkg], nextMajor(sdkVersions[pkg]))
+	}
+	return b.String()
+}
+
+// goMod renders the Go module file
+func goMod(project string, packages []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "module %s\n\ngo 1.21\n\nrequire (\n", project)
+	fmt.Fprintf(&b, "\tgithub.com/pulumi/pulumi/sdk/v3 v%s\n", sdkVersions["pulumi"])
+	for _, pkg := range packages {
+		fmt.Fprintf(&b, "\t%s v%s\n", goSDKPath(pkg), sdkVersions[pkg])
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+// goSDKPath returns the Go module path of a provider SDK
+func goSDKPath(pkg string) string {
+	major := strings.SplitN(sdkVersions[pkg], ".", 2)[0]
+	return fmt.Sprintf("github.com/pulumi/pulumi-%s/sdk/v%s", pkg, major)
+}
+
+// nextMajor returns the next major version of a semantic version
+func nextMajor(version string) string {
+	var major int
+	fmt.Sscanf(version, "%d", &major)
+	return fmt.Sprintf("%d.0.0", major+1)
+}
@@ -0,0 +1,285 @@
+package pulumi
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// renderGo renders main.go, formatted with gofmt
+func renderGo(prog *program, unsupported []string) (string, error) {
+	aliases := goPackageAliases(prog.modules)
+
+	w := &sourceWriter{unit: "\t"}
+	w.line("// Generated by Chimera")
+	for _, address := range unsupported {
+		w.line("// Not generated, no Pulumi resource mapping: " + address)
+	}
+	w.line("package main")
+	w.line("")
+	w.line("import (")
+	for _, module := range prog.modules {
+		alias := aliases[module]
+		if alias == module.module {
+			alias = ""
+		} else {
+			alias += " "
+		}
+		w.line(fmt.Sprintf("\t%s%q", alias, fmt.Sprintf("%s/go/%s/%s", goSDKPath(module.pkg), module.pkg, module.module)))
+	}
+	w.line(`	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"`)
+	if len(prog.config) > 0 {
+		w.line(`	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"`)
+	}
+	w.line(")")
+	w.line("")
+	w.line("func main() {")
+	w.line("pulumi.Run(func(ctx *pulumi.Context) error {")
+
+	if len(prog.config) > 0 {
+		w.line(`cfg := config.New(ctx, "")`)
+		for _, value := range prog.config {
+			goConfigRead(w, value)
+		}
+		w.line("")
+	}
+
+	g := &goRenderer{aliases: aliases}
+	errDeclared := false
+	for _, r := range prog.resources {
+		module := aliases[resourceType{pkg: r.typ.pkg, module: r.typ.module}]
+		g.current = r
+		g.module = module
+
+		assign := "_, err = "
+		switch {
+		case r.used:
+			assign = camelCase(r.ident) + ", err := "
+			errDeclared = true
+		case !errDeclared:
+			assign = "_, err := "
+			errDeclared = true
+		}
+
+		w.line(fmt.Sprintf("%s%s.New%s(ctx, %s, &%s.%sArgs%s, pulumi.Import(pulumi.ID(%s)))",
+			assign, module, r.typ.class, strconv.Quote(r.logicalName), module, r.typ.class,
+			g.fields(r.args), strconv.Quote(r.importID)))
+		w.line("if err != nil {")
+		w.line("return err")
+		w.line("}")
+		w.line("")
+	}
+
+	for _, e := range prog.exports {
+		w.line(fmt.Sprintf("ctx.Export(%q, %s)", e.name, g.value(e.value)))
+	}
+
+	w.line("return nil")
+	w.line("})")
+	w.line("}")
+
+	source, err := format.Source([]byte(w.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format Go program: %w", err)
+	}
+	return string(source), nil
+}
+
+// goPackageAliases returns the package name used for each imported module,
+// qualifying module names shared by several providers with the provider name
+func goPackageAliases(modules []resourceType) map[resourceType]string {
+	counts := make(map[string]int)
+	for _, module := range modules {
+		counts[module.module]++
+	}
+
+	aliases := make(map[resourceType]string, len(modules))
+	for _, module := range modules {
+		if counts[module.module] > 1 {
+			aliases[module] = module.pkg + module.module
+		} else {
+			aliases[module] = module.module
+		}
+	}
+	return aliases
+}
+
+// goConfigRead writes the statements reading a configuration value
+func goConfigRead(w *sourceWriter, value *configValue) {
+	ident := camelCase(value.ident)
+	key := strconv.Quote(value.key)
+	if value.secret {
+		w.line(fmt.Sprintf("%s := cfg.RequireSecret(%s)", ident, key))
+		return
+	}
+
+	suffix := ""
+	switch value.kind {
+	case "number":
+		suffix = "Int"
+	case "bool":
+		suffix = "Bool"
+	}
+
+	if value.defaultValue == nil {
+		w.line(fmt.Sprintf("%s := cfg.Require%s(%s)", ident, suffix, key))
+		return
+	}
+
+	defaultValue := fmt.Sprint(value.defaultValue)
+	if value.kind == "string" {
+		defaultValue = strconv.Quote(defaultValue)
+	}
+	w.line(fmt.Sprintf("%s := %s", ident, defaultValue))
+	w.line(fmt.Sprintf("if v, err := cfg.Try%s(%s); err == nil {", suffix, key))
+	w.line(fmt.Sprintf("%s = v", ident))
+	w.line("}")
+}
+
+// goRenderer renders argument values as typed Pulumi Go inputs for the
+// resource being declared
+type goRenderer struct {
+	aliases map[resourceType]string
+	current *programResource
+	module  string
+}
+
+// fields renders the fields of an object as a composite literal body
+func (g *goRenderer) fields(o *object) string {
+	if len(o.fields) == 0 {
+		return "{}"
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range o.sortedFields() {
+		b.WriteString(pascalCase(key) + ": " + g.value(o.fields[key]) + ",\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// typeName returns the name of the nested argument type for a block path
+func (g *goRenderer) typeName(path []string) string {
+	name := g.module + "." + g.current.typ.class
+	for _, segment := range path {
+		name += pascalCase(segment)
+	}
+	return name
+}
+
+// value renders a value as a Pulumi input
+func (g *goRenderer) value(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("pulumi.String(%s)", strconv.Quote(v))
+	case bool:
+		return fmt.Sprintf("pulumi.Bool(%t)", v)
+	case int, int32, int64:
+		return fmt.Sprintf("pulumi.Int(%d)", v)
+	case float32, float64:
+		return fmt.Sprintf("pulumi.Float64(%v)", v)
+	case resourceRef:
+		if v.attribute == "id" {
+			return camelCase(v.resource.ident) + ".ID()"
+		}
+		return camelCase(v.resource.ident) + "." + pascalCase(v.attribute)
+	case configRef:
+		ident := camelCase(v.value.ident)
+		switch {
+		case v.value.secret:
+			return ident
+		case v.value.kind == "number":
+			return fmt.Sprintf("pulumi.Int(%s)", ident)
+		case v.value.kind == "bool":
+			return fmt.Sprintf("pulumi.Bool(%s)", ident)
+		default:
+			return fmt.Sprintf("pulumi.String(%s)", ident)
+		}
+	case stringMap:
+		var b strings.Builder
+		b.WriteString("pulumi.StringMap{\n")
+		for _, key := range sortedStringMapKeys(v) {
+			b.WriteString(fmt.Sprintf("%s: pulumi.String(%s),\n", strconv.Quote(key), strconv.Quote(v[key])))
+		}
+		b.WriteString("}")
+		return b.String()
+	case valueMap:
+		var b strings.Builder
+		b.WriteString(g.mapType(v) + "{\n")
+		for _, key := range sortedKeys(v) {
+			b.WriteString(fmt.Sprintf("%s: %s,\n", strconv.Quote(key), g.value(v[key])))
+		}
+		b.WriteString("}")
+		return b.String()
+	case fileArchive:
+		// The path is a literal or a plain string read from configuration
+		path := strconv.Quote(fmt.Sprint(v.path))
+		if ref, ok := v.path.(configRef); ok && !ref.value.secret {
+			path = camelCase(ref.value.ident)
+		}
+		return fmt.Sprintf("pulumi.NewFileArchive(%s)", path)
+	case *object:
+		return "&" + g.typeName(v.path) + "Args" + g.fields(v)
+	case *list:
+		var b strings.Builder
+		b.WriteString(g.arrayType(v) + "{\n")
+		for _, item := range v.items {
+			if o, ok := item.(*object); ok {
+				b.WriteString(g.typeName(o.path) + "Args" + g.fields(o) + ",\n")
+			} else {
+				b.WriteString(g.value(item) + ",\n")
+			}
+		}
+		b.WriteString("}")
+		return b.String()
+	default:
+		return fmt.Sprintf("pulumi.String(%s)", strconv.Quote(fmt.Sprint(v)))
+	}
+}
+
+// arrayType returns the array input type for a list, taken from its first
+// literal element; lists of references are string arrays
+func (g *goRenderer) arrayType(l *list) string {
+	for _, item := range l.items {
+		switch v := item.(type) {
+		case *object:
+			return g.typeName(v.path) + "Array"
+		case bool:
+			return "pulumi.BoolArray"
+		case int, int32, int64:
+			return "pulumi.IntArray"
+		case float32, float64:
+			return "pulumi.Float64Array"
+		case string:
+			return "pulumi.StringArray"
+		}
+	}
+	return "pulumi.StringArray"
+}
+
+// mapType returns the map input type for a map, taken from its first literal
+// value like arrayType; maps of references are string maps
+func (g *goRenderer) mapType(m valueMap) string {
+	for _, key := range sortedKeys(m) {
+		switch m[key].(type) {
+		case bool:
+			return "pulumi.BoolMap"
+		case int, int32, int64:
+			return "pulumi.IntMap"
+		case string:
+			return "pulumi.StringMap"
+		}
+	}
+	return "pulumi.StringMap"
+}
+
+// sortedStringMapKeys returns the keys of a string map in sorted order
+func sortedStringMapKeys(m stringMap) []string {
+	fields := make(map[string]interface{}, len(m))
+	for key := range m {
+		fields[key] = nil
+	}
+	return sortedKeys(fields)
+}
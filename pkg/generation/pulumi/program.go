@@ -0,0 +1,768 @@
+package pulumi
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// resourceType identifies a Pulumi resource class within a provider package
+type resourceType struct {
+	pkg    string
+	module string
+	class  string
+}
+
+// resourceTypes maps Terraform resource types to their Pulumi classes. The
+// aws, azure and gcp Pulumi providers are bridged from the Terraform
+// providers, so arguments map one to one apart from the renames below.
+var resourceTypes = map[string]resourceType{
+	"aws_vpc":                             {"aws", "ec2", "Vpc"},
+	"aws_subnet":                          {"aws", "ec2", "Subnet"},
+	"aws_security_group":                  {"aws", "ec2", "SecurityGroup"},
+	"aws_vpc_security_group_ingress_rule": {"aws", "vpc", "SecurityGroupIngressRule"},
+	"aws_vpc_security_group_egress_rule":  {"aws", "vpc", "SecurityGroupEgressRule"},
+	"aws_instance":                        {"aws", "ec2", "Instance"},
+	"aws_internet_gateway":                {"aws", "ec2", "InternetGateway"},
+	"aws_egress_only_internet_gateway":    {"aws", "ec2", "EgressOnlyInternetGateway"},
+	"aws_nat_gateway":                     {"aws", "ec2", "NatGateway"},
+	"aws_route_table":                     {"aws", "ec2", "RouteTable"},
+	"aws_route_table_association":         {"aws", "ec2", "RouteTableAssociation"},
+	"aws_key_pair":                        {"aws", "ec2", "KeyPair"},
+	"aws_ebs_volume":                      {"aws", "ebs", "Volume"},
+	"aws_volume_attachment":               {"aws", "ec2", "VolumeAttachment"},
+	"aws_eip":                             {"aws", "ec2", "Eip"},
+
+	"aws_s3_bucket":            {"aws", "s3", "BucketV2"},
+	"aws_s3_bucket_versioning": {"aws", "s3", "BucketVersioningV2"},
+	"aws_s3_bucket_server_side_encryption_configuration": {"aws", "s3", "BucketServerSideEncryptionConfigurationV2"},
+	"aws_s3_bucket_lifecycle_configuration":              {"aws", "s3", "BucketLifecycleConfigurationV2"},
+	"aws_s3_bucket_public_access_block":                  {"aws", "s3", "BucketPublicAccessBlock"},
+	"aws_s3_bucket_policy":                               {"aws", "s3", "BucketPolicy"},
+	"aws_s3_bucket_logging":                              {"aws", "s3", "BucketLoggingV2"},
+	"aws_s3_bucket_replication_configuration":            {"aws", "s3", "BucketReplicationConfig"},
+
+	"aws_iam_role":                    {"aws", "iam", "Role"},
+	"aws_iam_service_linked_role":     {"aws", "iam", "ServiceLinkedRole"},
+	"aws_iam_policy":                  {"aws", "iam", "Policy"},
+	"aws_iam_user":                    {"aws", "iam", "User"},
+	"aws_iam_group":                   {"aws", "iam", "Group"},
+	"aws_iam_instance_profile":        {"aws", "iam", "InstanceProfile"},
+	"aws_iam_role_policy":             {"aws", "iam", "RolePolicy"},
+	"aws_iam_user_policy":             {"aws", "iam", "UserPolicy"},
+	"aws_iam_group_policy":            {"aws", "iam", "GroupPolicy"},
+	"aws_iam_role_policy_attachment":  {"aws", "iam", "RolePolicyAttachment"},
+	"aws_iam_user_policy_attachment":  {"aws", "iam", "UserPolicyAttachment"},
+	"aws_iam_group_policy_attachment": {"aws", "iam", "GroupPolicyAttachment"},
+	"aws_iam_user_group_membership":   {"aws", "iam", "UserGroupMembership"},
+
+	"aws_db_instance":                 {"aws", "rds", "Instance"},
+	"aws_rds_cluster":                 {"aws", "rds", "Cluster"},
+	"aws_rds_cluster_instance":        {"aws", "rds", "ClusterInstance"},
+	"aws_db_subnet_group":             {"aws", "rds", "SubnetGroup"},
+	"aws_db_parameter_group":          {"aws", "rds", "ParameterGroup"},
+	"aws_rds_cluster_parameter_group": {"aws", "rds", "ClusterParameterGroup"},
+	"aws_db_option_group":             {"aws", "rds", "OptionGroup"},
+
+	"aws_lambda_function":             {"aws", "lambda", "Function"},
+	"aws_lambda_event_source_mapping": {"aws", "lambda", "EventSourceMapping"},
+	"aws_api_gateway_rest_api":        {"aws", "apigateway", "RestApi"},
+	"aws_api_gateway_resource":        {"aws", "apigateway", "Resource"},
+	"aws_api_gateway_method":          {"aws", "apigateway", "Method"},
+	"aws_api_gateway_integration":     {"aws", "apigateway", "Integration"},
+	"aws_api_gateway_stage":           {"aws", "apigateway", "Stage"},
+	"aws_apigatewayv2_api":            {"aws", "apigatewayv2", "Api"},
+	"aws_apigatewayv2_integration":    {"aws", "apigatewayv2", "Integration"},
+	"aws_apigatewayv2_route":          {"aws", "apigatewayv2", "Route"},
+	"aws_apigatewayv2_stage":          {"aws", "apigatewayv2", "Stage"},
+	"aws_cloudwatch_event_rule":       {"aws", "cloudwatch", "EventRule"},
+	"aws_cloudwatch_event_target":     {"aws", "cloudwatch", "EventTarget"},
+
+	"azurerm_resource_group":                            {"azure", "core", "ResourceGroup"},
+	"azurerm_virtual_network":                           {"azure", "network", "VirtualNetwork"},
+	"azurerm_subnet":                                    {"azure", "network", "Subnet"},
+	"azurerm_network_security_group":                    {"azure", "network", "NetworkSecurityGroup"},
+	"azurerm_subnet_network_security_group_association": {"azure", "network", "SubnetNetworkSecurityGroupAssociation"},
+	"azurerm_network_interface":                         {"azure", "network", "NetworkInterface"},
+	"azurerm_linux_virtual_machine":                     {"azure", "compute", "LinuxVirtualMachine"},
+	"azurerm_windows_virtual_machine":                   {"azure", "compute", "WindowsVirtualMachine"},
+
+	"google_compute_network":    {"gcp", "compute", "Network"},
+	"google_compute_subnetwork": {"gcp", "compute", "Subnetwork"},
+	"google_compute_firewall":   {"gcp", "compute", "Firewall"},
+	"google_compute_instance":   {"gcp", "compute", "Instance"},
+}
+
+// argumentRenames holds arguments Pulumi names differently from Terraform,
+// keyed by resource type and block path. Repeated blocks are pluralized.
+var argumentRenames = map[string]map[string]string{
+	"aws_instance":                    {"ebs_block_device": "ebs_block_devices"},
+	"aws_route_table":                 {"route": "routes"},
+	"azurerm_network_security_group":  {"security_rule": "security_rules"},
+	"azurerm_network_interface":       {"ip_configuration": "ip_configurations"},
+	"azurerm_linux_virtual_machine":   {"admin_ssh_key": "admin_ssh_keys"},
+	"azurerm_windows_virtual_machine": {"additional_unattend_content": "additional_unattend_contents"},
+	"google_compute_subnetwork":       {"secondary_ip_range": "secondary_ip_ranges"},
+	"google_compute_firewall":         {"allow": "allows", "deny": "denies"},
+	"google_compute_instance": {
+		"network_interface":               "network_interfaces",
+		"network_interface.access_config": "access_configs",
+	},
+
+	"aws_s3_bucket_server_side_encryption_configuration": {"rule": "rules"},
+	"aws_s3_bucket_replication_configuration":            {"rule": "rules"},
+	"aws_s3_bucket_lifecycle_configuration": {
+		"rule":                               "rules",
+		"rule.transition":                    "transitions",
+		"rule.noncurrent_version_transition": "noncurrent_version_transitions",
+	},
+
+	"aws_db_parameter_group":          {"parameter": "parameters"},
+	"aws_rds_cluster_parameter_group": {"parameter": "parameters"},
+	"aws_db_option_group":             {"option": "options"},
+
+	"aws_lambda_function": {"function_name": "name", "filename": "code"},
+}
+
+// archiveArguments holds arguments that are paths to local archives, which
+// Pulumi takes as assets under the renamed argument
+var archiveArguments = map[string]map[string]bool{
+	"aws_lambda_function": {"filename": true},
+}
+
+// singleBlocks holds blocks limited to one element, which Pulumi takes as an
+// object rather than a list
+var singleBlocks = map[string]map[string]bool{
+	"aws_instance":                    {"root_block_device": true, "metadata_options": true},
+	"azurerm_linux_virtual_machine":   {"os_disk": true, "source_image_reference": true},
+	"azurerm_windows_virtual_machine": {"os_disk": true, "source_image_reference": true},
+	"google_compute_instance":         {"boot_disk": true, "service_account": true, "scheduling": true},
+}
+
+// singleValues holds list arguments limited to one element, which Pulumi
+// takes as a single value
+var singleValues = map[string]map[string]bool{
+	"aws_api_gateway_rest_api": {"endpoint_configuration.types": true},
+}
+
+// referencePattern matches a resource attribute or variable reference
+var referencePattern = regexp.MustCompile(`^([a-zA-Z0-9_]+)\.([a-zA-Z0-9_-]+)(?:\.([a-zA-Z0-9_]+))?$`)
+
+// program is a language-independent Pulumi program
+type program struct {
+	resources []*programResource
+	config    []*configValue
+	exports   []export
+	packages  []string
+	modules   []resourceType
+}
+
+// programResource is a resource declaration
+type programResource struct {
+	ident        string
+	address      string
+	logicalName  string
+	importID     string
+	typ          resourceType
+	args         *object
+	dependencies map[*programResource]bool
+	used         bool
+}
+
+// configValue is a value read from stack configuration
+type configValue struct {
+	ident        string
+	key          string
+	description  string
+	kind         string
+	secret       bool
+	defaultValue interface{}
+}
+
+// export is a stack output
+type export struct {
+	name  string
+	value interface{}
+}
+
+// object is a set of arguments or a nested block. path records the
+// Terraform block names leading to it, from which Go type names are derived.
+type object struct {
+	path   []string
+	fields map[string]interface{}
+}
+
+// list is a list of values or of nested blocks
+type list struct {
+	path  []string
+	items []interface{}
+}
+
+// stringMap is a map of strings such as tags or labels
+type stringMap map[string]string
+
+// valueMap is a map whose values may be references, such as the environment
+// variables of a Lambda function
+type valueMap map[string]interface{}
+
+// fileArchive is an archive read from a local path, such as the deployment
+// package of a Lambda function
+type fileArchive struct {
+	path interface{}
+}
+
+// resourceRef references an attribute of another resource
+type resourceRef struct {
+	resource  *programResource
+	attribute string
+}
+
+// configRef references a configuration value
+type configRef struct {
+	value *configValue
+}
+
+// programBuilder converts mapped resources into a program
+type programBuilder struct {
+	program   *program
+	addresses map[string]*programResource
+	variables map[string]generation.Variable
+	config    map[string]*configValue
+	idents    map[string]bool
+	current   *programResource
+	// deferred holds, per resource, the references that break a reference
+	// cycle and are read from configuration instead
+	deferred map[*programResource]map[*programResource]bool
+}
+
+// buildProgram converts mapped resources into a program. Resource types
+// without a Pulumi class are returned as unsupported.
+func buildProgram(resources []generation.MappedResource) (*program, []string, error) {
+	b := &programBuilder{
+		program:   &program{},
+		addresses: make(map[string]*programResource),
+		variables: make(map[string]generation.Variable),
+		config:    make(map[string]*configValue),
+		idents:    make(map[string]bool),
+		deferred:  make(map[*programResource]map[*programResource]bool),
+	}
+
+	sorted := make([]generation.MappedResource, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ResourceType+"."+sorted[i].ResourceName < sorted[j].ResourceType+"."+sorted[j].ResourceName
+	})
+
+	var supported []generation.MappedResource
+	var unsupported []string
+	packages := make(map[string]bool)
+	modules := make(map[resourceType]bool)
+	for _, resource := range sorted {
+		typ, exists := resourceTypes[resource.ResourceType]
+		if !exists {
+			unsupported = append(unsupported, resource.ResourceType+"."+resource.ResourceName)
+			continue
+		}
+		supported = append(supported, resource)
+		packages[typ.pkg] = true
+		modules[resourceType{pkg: typ.pkg, module: typ.module}] = true
+		for name, variable := range resource.Variables {
+			b.variables[name] = variable
+		}
+	}
+	if len(supported) == 0 {
+		return nil, unsupported, fmt.Errorf("no resources supported by Pulumi generation")
+	}
+
+	for pkg := range packages {
+		b.program.packages = append(b.program.packages, pkg)
+		b.idents[pkg] = true
+	}
+	sort.Strings(b.program.packages)
+	for module := range modules {
+		b.program.modules = append(b.program.modules, module)
+		b.idents[module.module] = true
+		b.idents[module.pkg+module.module] = true
+	}
+	sort.Slice(b.program.modules, func(i, j int) bool {
+		return b.program.modules[i].pkg+"/"+b.program.modules[i].module < b.program.modules[j].pkg+"/"+b.program.modules[j].module
+	})
+
+	// Declare every resource first so references resolve regardless of order
+	for _, resource := range supported {
+		typ := resourceTypes[resource.ResourceType]
+		r := &programResource{
+			ident:        b.uniqueIdent(resource.ResourceName, typ.class),
+			address:      resource.ResourceType + "." + resource.ResourceName,
+			logicalName:  resource.ResourceName,
+			importID:     resource.ImportID,
+			typ:          typ,
+			dependencies: make(map[*programResource]bool),
+		}
+		b.addresses[r.address] = r
+		b.program.resources = append(b.program.resources, r)
+	}
+
+	for i, resource := range supported {
+		r := b.program.resources[i]
+		b.current = r
+		args, err := b.convertObject(resource.ResourceType, nil, resource.Configuration)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert %s.%s: %w", resource.ResourceType, resource.ResourceName, err)
+		}
+		r.args = args
+		b.current = nil
+
+		for _, key := range sortedOutputKeys(resource.Outputs) {
+			output := resource.Outputs[key]
+			name := output.Name
+			if name == "" {
+				name = resource.ResourceName + "_" + key
+			}
+			b.program.exports = append(b.program.exports, export{
+				name:  camelCase(b.uniqueIdent(name, "output")),
+				value: b.reference(output.Value),
+			})
+		}
+	}
+
+	if err := b.orderResources(); err != nil {
+		return nil, nil, err
+	}
+
+	for _, r := range b.program.resources {
+		b.resolveReferences(r.args, r)
+	}
+	for i := range b.program.exports {
+		b.program.exports[i].value = b.resolveValue(b.program.exports[i].value, nil)
+	}
+
+	sort.Slice(b.program.config, func(i, j int) bool {
+		return b.program.config[i].key < b.program.config[j].key
+	})
+
+	return b.program, unsupported, nil
+}
+
+// orderResources sorts resources so that every resource is declared after
+// the resources it references. Reference cycles, as found by the dependency
+// analyzer, are broken at a member of the cycle whose references to the
+// other members not yet declared are deferred to configuration values.
+func (b *programBuilder) orderResources() error {
+	graph := make(map[string][]string, len(b.program.resources))
+	for _, r := range b.program.resources {
+		graph[r.address] = []string{}
+		for dependency := range r.dependencies {
+			graph[r.address] = append(graph[r.address], dependency.address)
+		}
+	}
+
+	cycleOf := make(map[*programResource]int)
+	if err := generation.NewMetadataDependencyAnalyzer().ValidateDependencies(graph); err != nil {
+		var cycleErr *generation.DependencyCycleError
+		if !errors.As(err, &cycleErr) {
+			return fmt.Errorf("failed to order resources: %w", err)
+		}
+		for i, cycle := range cycleErr.Cycles {
+			for _, address := range cycle {
+				cycleOf[b.addresses[address]] = i + 1
+			}
+		}
+	}
+
+	var ordered []*programResource
+	placed := make(map[*programResource]bool)
+
+	for len(ordered) < len(b.program.resources) {
+		progress := false
+		for _, r := range b.program.resources {
+			if placed[r] || !dependenciesPlaced(r, placed) {
+				continue
+			}
+			ordered = append(ordered, r)
+			placed[r] = true
+			progress = true
+		}
+		if progress {
+			continue
+		}
+
+		r := cycleBreak(b.program.resources, placed, cycleOf)
+		if r == nil {
+			return fmt.Errorf("failed to order resources: no reference cycle to break among the %d remaining", len(b.program.resources)-len(ordered))
+		}
+		b.deferred[r] = make(map[*programResource]bool)
+		for dependency := range r.dependencies {
+			if dependency != r && !placed[dependency] {
+				b.deferred[r][dependency] = true
+			}
+		}
+		ordered = append(ordered, r)
+		placed[r] = true
+	}
+
+	b.program.resources = ordered
+	return nil
+}
+
+// cycleBreak returns the first resource not yet placed that is in a cycle and
+// whose unplaced dependencies all belong to that cycle, or nil if there is none
+func cycleBreak(resources []*programResource, placed map[*programResource]bool, cycleOf map[*programResource]int) *programResource {
+	for _, r := range resources {
+		if placed[r] || cycleOf[r] == 0 {
+			continue
+		}
+		inCycle := true
+		for dependency := range r.dependencies {
+			if dependency != r && !placed[dependency] && cycleOf[dependency] != cycleOf[r] {
+				inCycle = false
+				break
+			}
+		}
+		if inCycle {
+			return r
+		}
+	}
+	return nil
+}
+
+// dependenciesPlaced reports whether every resource r references has been placed
+func dependenciesPlaced(r *programResource, placed map[*programResource]bool) bool {
+	for dependency := range r.dependencies {
+		if dependency != r && !placed[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveReferences resolves the references in an object's fields, which
+// belong to resource from
+func (b *programBuilder) resolveReferences(o *object, from *programResource) {
+	for key, value := range o.fields {
+		o.fields[key] = b.resolveValue(value, from)
+	}
+}
+
+// resolveValue marks referenced resources as used. References deferred to
+// break a reference cycle are replaced by configuration values.
+func (b *programBuilder) resolveValue(value interface{}, from *programResource) interface{} {
+	switch v := value.(type) {
+	case resourceRef:
+		if b.deferred[from][v.resource] {
+			expr := v.resource.address + "." + v.attribute
+			return b.configValue(strings.ReplaceAll(expr, ".", "_"), generation.Variable{
+				Description: fmt.Sprintf("Value of %s, which is referenced before it is declared to break a reference cycle", expr),
+			})
+		}
+		v.resource.used = true
+		return v
+	case *object:
+		b.resolveReferences(v, from)
+	case valueMap:
+		for key, item := range v {
+			v[key] = b.resolveValue(item, from)
+		}
+	case fileArchive:
+		return fileArchive{path: b.resolveValue(v.path, from)}
+	case *list:
+		for i, item := range v.items {
+			v.items[i] = b.resolveValue(item, from)
+		}
+	}
+	return value
+}
+
+// convertObject converts a configuration map to an object
+func (b *programBuilder) convertObject(resourceType string, path []string, config map[string]interface{}) (*object, error) {
+	result := &object{path: path, fields: make(map[string]interface{})}
+
+	for key, value := range config {
+		if value == nil {
+			continue
+		}
+
+		blockPath := append(append([]string{}, path...), key)
+		name := key
+		if renamed, exists := argumentRenames[resourceType][strings.Join(blockPath, ".")]; exists {
+			name = renamed
+		}
+
+		converted, err := b.convertValue(resourceType, blockPath, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if archiveArguments[resourceType][strings.Join(blockPath, ".")] {
+			converted = fileArchive{path: converted}
+		}
+		if converted != nil {
+			result.fields[name] = converted
+		}
+	}
+
+	return result, nil
+}
+
+// convertValue converts a configuration value
+func (b *programBuilder) convertValue(resourceType string, path []string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case generation.Expression:
+		if constant, ok := generation.EvaluateString(v); ok {
+			return constant, nil
+		}
+		return b.reference(string(v)), nil
+	case string, bool, int, int32, int64, float32, float64:
+		return v, nil
+	case map[string]string:
+		return stringMap(v), nil
+	case generation.Map:
+		items := make(valueMap, len(v))
+		for key, item := range v {
+			converted, err := b.convertValue(resourceType, path, item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			items[key] = converted
+		}
+		return items, nil
+	case []string:
+		if singleValues[resourceType][strings.Join(path, ".")] {
+			if len(v) == 0 {
+				return nil, nil
+			}
+			return v[0], nil
+		}
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return &list{path: path, items: items}, nil
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted, err := b.convertValue(resourceType, path, item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, converted)
+		}
+		return &list{path: path, items: items}, nil
+	case map[string]interface{}:
+		return b.convertObject(resourceType, path, v)
+	case []map[string]interface{}:
+		if singleBlocks[resourceType][strings.Join(path, ".")] {
+			if len(v) == 0 {
+				return nil, nil
+			}
+			return b.convertObject(resourceType, path, v[0])
+		}
+		items := make([]interface{}, 0, len(v))
+		for _, block := range v {
+			converted, err := b.convertObject(resourceType, path, block)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, converted)
+		}
+		return &list{path: path, items: items}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// reference resolves a Terraform expression. Variables become configuration
+// values; references to resources that are not generated become required
+// configuration values so the program still compiles.
+func (b *programBuilder) reference(expr string) interface{} {
+	match := referencePattern.FindStringSubmatch(expr)
+	if match == nil {
+		return b.configValue(expr, generation.Variable{Description: fmt.Sprintf("Value of %s", expr)})
+	}
+
+	if match[1] == "var" {
+		variable, exists := b.variables[match[2]]
+		if !exists {
+			variable = generation.Variable{Name: match[2]}
+		}
+		return b.configValue(match[2], variable)
+	}
+
+	if r, exists := b.addresses[match[1]+"."+match[2]]; exists && match[3] != "" {
+		if b.current != nil {
+			b.current.dependencies[r] = true
+		}
+		return resourceRef{resource: r, attribute: match[3]}
+	}
+
+	return b.configValue(strings.ReplaceAll(expr, ".", "_"), generation.Variable{
+		Description: fmt.Sprintf("Value of %s, which is not part of this program", expr),
+	})
+}
+
+// configValue returns a reference to a configuration value, declaring it on first use
+func (b *programBuilder) configValue(name string, variable generation.Variable) configRef {
+	if value, exists := b.config[name]; exists {
+		return configRef{value: value}
+	}
+
+	value := &configValue{
+		ident:        b.uniqueIdent(name, "config"),
+		key:          camelCase(name),
+		description:  variable.Description,
+		kind:         configKind(variable.Type),
+		secret:       variable.Sensitive,
+		defaultValue: variable.Default,
+	}
+	b.config[name] = value
+	b.program.config = append(b.program.config, value)
+	return configRef{value: value}
+}
+
+// configKind returns the kind of a configuration value from a variable type
+func configKind(variableType string) string {
+	switch variableType {
+	case "number", "bool":
+		return variableType
+	default:
+		return "string"
+	}
+}
+
+// uniqueIdent returns a snake_case identifier for name that is not a keyword
+// in any target language and not already taken, qualifying it with suffix if needed
+func (b *programBuilder) uniqueIdent(name, suffix string) string {
+	ident := snakeCase(name)
+	if ident == "" || (ident[0] >= '0' && ident[0] <= '9') {
+		ident = "resource_" + ident
+	}
+	if b.idents[ident] || b.idents[camelCase(ident)] || reservedWords[ident] || reservedWords[camelCase(ident)] {
+		ident = ident + "_" + snakeCase(suffix)
+	}
+	base := ident
+	for i := 2; b.idents[ident] || b.idents[camelCase(ident)]; i++ {
+		ident = fmt.Sprintf("%s_%d", base, i)
+	}
+	b.idents[ident] = true
+	b.idents[camelCase(ident)] = true
+	return ident
+}
+
+// reservedWords holds keywords and predeclared names of TypeScript, Python
+// and Go, and names used by the generated programs
+var reservedWords = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true, "break": true,
+	"case": true, "catch": true, "chan": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "def": true, "default": true, "defer": true, "del": true, "delete": true,
+	"do": true, "elif": true, "else": true, "enum": true, "except": true, "export": true,
+	"extends": true, "fallthrough": true, "false": true, "finally": true, "for": true,
+	"from": true, "func": true, "function": true, "global": true, "go": true, "goto": true,
+	"if": true, "implements": true, "import": true, "in": true, "instanceof": true,
+	"interface": true, "is": true, "lambda": true, "let": true, "map": true, "new": true,
+	"nil": true, "nonlocal": true, "not": true, "null": true, "or": true, "package": true,
+	"pass": true, "private": true, "protected": true, "public": true, "raise": true,
+	"range": true, "return": true, "select": true, "static": true, "struct": true,
+	"super": true, "switch": true, "this": true, "throw": true, "true": true, "try": true,
+	"type": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"yield": true, "string": true, "bool": true, "int": true, "error": true, "len": true,
+	"append": true, "copy": true, "make": true, "print": true, "object": true, "id": true,
+	"pulumi": true, "config": true, "cfg": true, "ctx": true, "err": true, "opts": true,
+}
+
+// sortedOutputKeys returns the keys of an output map in sorted order
+func sortedOutputKeys(outputs map[string]generation.Output) []string {
+	keys := make([]string, 0, len(outputs))
+	for key := range outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedFields returns the field names of an object in sorted order
+func (o *object) sortedFields() []string {
+	return sortedKeys(o.fields)
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// snakeCase converts a name to lower snake_case
+func snakeCase(name string) string {
+	var result strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if i > 0 && result.Len() > 0 && !strings.HasSuffix(result.String(), "_") {
+				result.WriteRune('_')
+			}
+			result.WriteRune(r + ('a' - 'A'))
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			result.WriteRune(r)
+		default:
+			if result.Len() > 0 && !strings.HasSuffix(result.String(), "_") {
+				result.WriteRune('_')
+			}
+		}
+	}
+	return strings.TrimSuffix(result.String(), "_")
+}
+
+// camelCase converts a snake_case name to camelCase
+func camelCase(name string) string {
+	pascal := pascalCase(name)
+	if pascal == "" {
+		return ""
+	}
+	return strings.ToLower(pascal[:1]) + pascal[1:]
+}
+
+// pascalCase converts a snake_case name to PascalCase
+func pascalCase(name string) string {
+	var result strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == '.' || r == ' ':
+			upper = true
+		case upper && r >= 'a' && r <= 'z':
+			result.WriteRune(r - ('a' - 'A'))
+			upper = false
+		default:
+			result.WriteRune(r)
+			upper = false
+		}
+	}
+	return result.String()
+}
+
+// sourceWriter writes indented program source
+type sourceWriter struct {
+	buf    strings.Builder
+	indent int
+	unit   string
+}
+
+// line writes a single indented line
+func (w *sourceWriter) line(s string) {
+	if s != "" {
+		w.buf.WriteString(strings.Repeat(w.unit, w.indent))
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\n")
+}
+
+// String returns the written source
+func (w *sourceWriter) String() string {
+	return w.buf.String()
+}
@@ -0,0 +1,149 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// pyIndent is the indentation unit of generated Python
+const pyIndent = "    "
+
+// renderPython renders __main__.py
+func renderPython(prog *program, unsupported []string) string {
+	w := &sourceWriter{unit: pyIndent}
+	w.line(`"""Generated by Chimera"""`)
+	for _, address := range unsupported {
+		w.line("# Not generated, no Pulumi resource mapping: " + address)
+	}
+	w.line("")
+	w.line("import pulumi")
+	for _, pkg := range prog.packages {
+		w.line(fmt.Sprintf("import pulumi_%s as %s", pkg, pkg))
+	}
+
+	if len(prog.config) > 0 {
+		w.line("")
+		w.line("config = pulumi.Config()")
+		for _, value := range prog.config {
+			w.line(fmt.Sprintf("%s = %s", value.ident, pyConfigRead(value)))
+		}
+	}
+
+	for _, r := range prog.resources {
+		w.line("")
+		w.line(fmt.Sprintf("%s = %s.%s.%s(", r.ident, r.typ.pkg, pyModule(r.typ.module), r.typ.class))
+		w.indent++
+		w.line(pyString(r.logicalName) + ",")
+		for _, key := range r.args.sortedFields() {
+			w.line(fmt.Sprintf("%s=%s,", key, pyValue(r.args.fields[key], 1)))
+		}
+		w.line(fmt.Sprintf("opts=pulumi.ResourceOptions(import_=%s),", pyString(r.importID)))
+		w.indent--
+		w.line(")")
+	}
+
+	if len(prog.exports) > 0 {
+		w.line("")
+		for _, e := range prog.exports {
+			w.line(fmt.Sprintf("pulumi.export(%s, %s)", pyString(e.name), pyValue(e.value, 0)))
+		}
+	}
+
+	return w.String()
+}
+
+// pyConfigRead returns the expression reading a configuration value
+func pyConfigRead(value *configValue) string {
+	key := pyString(value.key)
+	if value.secret {
+		return fmt.Sprintf("config.require_secret(%s)", key)
+	}
+
+	getter, requirer := "get", "require"
+	switch value.kind {
+	case "number":
+		getter, requirer = "get_int", "require_int"
+	case "bool":
+		getter, requirer = "get_bool", "require_bool"
+	}
+
+	if value.defaultValue == nil {
+		return fmt.Sprintf("config.%s(%s)", requirer, key)
+	}
+	return fmt.Sprintf("config.%s(%s, %s)", getter, key, pyValue(value.defaultValue, 0))
+}
+
+// pyValue renders a value at the given indentation level
+func pyValue(value interface{}, indent int) string {
+	switch v := value.(type) {
+	case string:
+		return pyString(v)
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case resourceRef:
+		return v.resource.ident + "." + v.attribute
+	case configRef:
+		return v.value.ident
+	case stringMap:
+		fields := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			fields[key] = item
+		}
+		return pyDict(fields, sortedKeys(fields), indent)
+	case valueMap:
+		return pyDict(v, sortedKeys(v), indent)
+	case fileArchive:
+		return fmt.Sprintf("pulumi.FileArchive(%s)", pyValue(v.path, indent))
+	case *object:
+		return pyDict(v.fields, v.sortedFields(), indent)
+	case *list:
+		if len(v.items) == 0 {
+			return "[]"
+		}
+		inner := strings.Repeat(pyIndent, indent+1)
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, item := range v.items {
+			b.WriteString(inner + pyValue(item, indent+1) + ",\n")
+		}
+		b.WriteString(strings.Repeat(pyIndent, indent) + "]")
+		return b.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// pyDict renders a dictionary literal; nested argument names stay snake_case
+func pyDict(fields map[string]interface{}, keys []string, indent int) string {
+	if len(keys) == 0 {
+		return "{}"
+	}
+
+	inner := strings.Repeat(pyIndent, indent+1)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range keys {
+		b.WriteString(inner + pyString(key) + ": " + pyValue(fields[key], indent+1) + ",\n")
+	}
+	b.WriteString(strings.Repeat(pyIndent, indent) + "}")
+	return b.String()
+}
+
+// pyString returns a double-quoted string literal
+func pyString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// pyModule returns the name of a provider module in the Python SDK, where
+// modules named after Python keywords take a trailing underscore
+func pyModule(module string) string {
+	if module == "lambda" {
+		return module + "_"
+	}
+	return module
+}
@@ -0,0 +1,143 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tsIdentifier matches object keys that can be written unquoted
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsIndent is the indentation unit of generated TypeScript
+const tsIndent = "    "
+
+// renderTypeScript renders index.ts
+func renderTypeScript(prog *program, unsupported []string) string {
+	w := &sourceWriter{unit: tsIndent}
+	w.line("// Generated by Chimera")
+	for _, address := range unsupported {
+		w.line("// Not generated, no Pulumi resource mapping: " + address)
+	}
+	w.line(`import * as pulumi from "@pulumi/pulumi";`)
+	for _, pkg := range prog.packages {
+		w.line(fmt.Sprintf(`import * as %s from "@pulumi/%s";`, pkg, pkg))
+	}
+
+	if len(prog.config) > 0 {
+		w.line("")
+		w.line("const config = new pulumi.Config();")
+		for _, value := range prog.config {
+			w.line(fmt.Sprintf("const %s = %s;", camelCase(value.ident), tsConfigRead(value)))
+		}
+	}
+
+	for _, r := range prog.resources {
+		w.line("")
+		w.line(fmt.Sprintf("const %s = new %s.%s.%s(%s, %s, { import: %s });",
+			camelCase(r.ident), r.typ.pkg, r.typ.module, r.typ.class,
+			tsString(r.logicalName), tsValue(r.args, 0), tsString(r.importID)))
+	}
+
+	if len(prog.exports) > 0 {
+		w.line("")
+		for _, e := range prog.exports {
+			w.line(fmt.Sprintf("export const %s = %s;", e.name, tsValue(e.value, 0)))
+		}
+	}
+
+	return w.String()
+}
+
+// tsConfigRead returns the expression reading a configuration value
+func tsConfigRead(value *configValue) string {
+	key := tsString(value.key)
+	if value.secret {
+		return fmt.Sprintf("config.requireSecret(%s)", key)
+	}
+
+	getter, requirer := "get", "require"
+	switch value.kind {
+	case "number":
+		getter, requirer = "getNumber", "requireNumber"
+	case "bool":
+		getter, requirer = "getBoolean", "requireBoolean"
+	}
+
+	if value.defaultValue == nil {
+		return fmt.Sprintf("config.%s(%s)", requirer, key)
+	}
+	return fmt.Sprintf("config.%s(%s) ?? %s", getter, key, tsValue(value.defaultValue, 0))
+}
+
+// tsValue renders a value at the given indentation level
+func tsValue(value interface{}, indent int) string {
+	switch v := value.(type) {
+	case string:
+		return tsString(v)
+	case resourceRef:
+		if v.attribute == "id" {
+			return camelCase(v.resource.ident) + ".id"
+		}
+		return camelCase(v.resource.ident) + "." + camelCase(v.attribute)
+	case configRef:
+		return camelCase(v.value.ident)
+	case stringMap:
+		fields := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			fields[key] = item
+		}
+		return tsObject(fields, sortedKeys(fields), false, indent)
+	case valueMap:
+		return tsObject(v, sortedKeys(v), false, indent)
+	case fileArchive:
+		return fmt.Sprintf("new pulumi.asset.FileArchive(%s)", tsValue(v.path, indent))
+	case *object:
+		return tsObject(v.fields, v.sortedFields(), true, indent)
+	case *list:
+		if len(v.items) == 0 {
+			return "[]"
+		}
+		inner := strings.Repeat(tsIndent, indent+1)
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, item := range v.items {
+			b.WriteString(inner + tsValue(item, indent+1) + ",\n")
+		}
+		b.WriteString(strings.Repeat(tsIndent, indent) + "]")
+		return b.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// tsObject renders an object literal. Argument names are converted to
+// camelCase; map keys such as tag names are kept as they are.
+func tsObject(fields map[string]interface{}, keys []string, arguments bool, indent int) string {
+	if len(keys) == 0 {
+		return "{}"
+	}
+
+	inner := strings.Repeat(tsIndent, indent+1)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range keys {
+		name := key
+		if arguments {
+			name = camelCase(key)
+		}
+		if !tsIdentifier.MatchString(name) {
+			name = tsString(name)
+		}
+		b.WriteString(inner + name + ": " + tsValue(fields[key], indent+1) + ",\n")
+	}
+	b.WriteString(strings.Repeat(tsIndent, indent) + "}")
+	return b.String()
+}
+
+// tsString returns a double-quoted string literal
+func tsString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
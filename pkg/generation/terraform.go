@@ -0,0 +1,466 @@
+package generation
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TerraformFormatGenerator lays out a Terraform configuration, rendering each
+// file with a TerraformGenerator: versions and provider files, the organized
+// resource files, imports, state, variables and outputs. When organization
+// places resources in several directories, each directory is a separate root
+// configuration and references between directories are replaced by the
+// discovered values.
+type TerraformFormatGenerator struct {
+	generator TerraformGenerator
+	organizer FileOrganizer
+}
+
+// NewTerraformFormatGenerator creates a format generator rendering files with
+// the given Terraform generator, organizing files with a PatternOrganizer
+func NewTerraformFormatGenerator(generator TerraformGenerator) *TerraformFormatGenerator {
+	return &TerraformFormatGenerator{
+		generator: generator,
+		organizer: NewPatternOrganizer(),
+	}
+}
+
+// SetOrganizer sets the file organizer; without one every resource is
+// written to main.tf
+func (g *TerraformFormatGenerator) SetOrganizer(organizer FileOrganizer) {
+	g.organizer = organizer
+}
+
+// Capabilities returns the features of Terraform configurations
+func (g *TerraformFormatGenerator) Capabilities() FormatCapabilities {
+	return FormatCapabilities{
+		SupportsModules:    true,
+		SupportsState:      true,
+		SupportsVariables:  true,
+		SupportsOutputs:    true,
+		SupportsValidation: true,
+		SupportsImports:    true,
+		OrganizationPatterns: []OrganizationPattern{
+			OrganizeByProvider,
+			OrganizeByService,
+			OrganizeByRegion,
+			OrganizeByResourceType,
+			OrganizeFlat,
+		},
+	}
+}
+
+// Generate generates the Terraform configuration files
+func (g *TerraformFormatGenerator) Generate(resources []MappedResource, opts GenerationOptions) ([]GeneratedFile, error) {
+	files, _, err := g.GenerateWithWarnings(resources, opts)
+	return files, err
+}
+
+// GenerateWithWarnings generates the Terraform configuration files, reporting
+// the resources that could not be recorded in state
+func (g *TerraformFormatGenerator) GenerateWithWarnings(resources []MappedResource, opts GenerationOptions) ([]GeneratedFile, []GenerationWarning, error) {
+	organizedFiles, err := g.organizeResources(resources, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to organize resources: %w", err)
+	}
+
+	// Group files by the directory of their root configuration
+	directories := make(map[string]map[string][]MappedResource)
+	directoryOf := make(map[string]string)
+	for filePath, fileResources := range organizedFiles {
+		dir := path.Dir(filePath)
+		if directories[dir] == nil {
+			directories[dir] = make(map[string][]MappedResource)
+		}
+		directories[dir][filePath] = fileResources
+		for _, resource := range fileResources {
+			directoryOf[resource.ResourceType+"."+resource.ResourceName] = dir
+		}
+	}
+
+	dirs := make([]string, 0, len(directories))
+	for dir := range directories {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var files []GeneratedFile
+	var warnings []GenerationWarning
+	for _, dir := range dirs {
+		dirFiles := directories[dir]
+		if len(directories) > 1 {
+			dirFiles = localizeReferences(dirFiles, dir, directoryOf, resources)
+		}
+
+		generated, dirWarnings, err := g.generateDirectory(dir, dirFiles, opts)
+		if err != nil {
+			if dir != "." {
+				return nil, nil, fmt.Errorf("%s: %w", dir, err)
+			}
+			return nil, nil, err
+		}
+		files = append(files, generated...)
+		warnings = append(warnings, dirWarnings...)
+	}
+
+	// Terraform JSON configuration files carry a .tf.json extension
+	if opts.Format == TerraformJSON {
+		for i := range files {
+			if strings.HasSuffix(files[i].Path, ".tf") {
+				files[i].Path += ".json"
+			}
+		}
+	}
+
+	return files, warnings, nil
+}
+
+// generateDirectory generates the files of the root configuration in dir
+func (g *TerraformFormatGenerator) generateDirectory(dir string, organizedFiles map[string][]MappedResource, opts GenerationOptions) ([]GeneratedFile, []GenerationWarning, error) {
+	var files []GeneratedFile
+	var warnings []GenerationWarning
+	file := func(name, content string, fileType FileType, resourceCount int) {
+		files = append(files, GeneratedFile{
+			Path:          path.Join(dir, name),
+			Content:       content,
+			Type:          fileType,
+			Format:        opts.Format,
+			Size:          int64(len(content)),
+			ResourceCount: resourceCount,
+		})
+	}
+
+	filePaths := make([]string, 0, len(organizedFiles))
+	for filePath := range organizedFiles {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	var resources []MappedResource
+	for _, filePath := range filePaths {
+		resources = append(resources, organizedFiles[filePath]...)
+	}
+	providers := providersFor(resources, opts.Providers)
+
+	// Generate provider configuration files
+	if opts.IncludeProvider && len(providers) > 0 {
+		versionsContent, err := g.generator.GenerateVersions(providers)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate versions.tf: %w", err)
+		}
+		file("versions.tf", versionsContent, FileTypeVersions, 0)
+
+		for _, config := range providers {
+			providerContent, err := g.generator.GenerateProvider(config)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to generate provider %s: %w", config.Name, err)
+			}
+			name := config.Name
+			if config.Alias != "" {
+				name += "_" + config.Alias
+			}
+			file(fmt.Sprintf("provider_%s.tf", name), providerContent, FileTypeProvider, 0)
+		}
+	}
+
+	// Generate resource files
+	for _, filePath := range filePaths {
+		content, err := g.generateResourceFile(organizedFiles[filePath])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate file %s: %w", filePath, err)
+		}
+		file(path.Base(filePath), content, FileTypeMain, len(organizedFiles[filePath]))
+	}
+
+	// Generate import blocks if requested
+	if opts.GenerateImports {
+		importsContent, err := g.generator.GenerateImports(resources)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate imports.tf: %w", err)
+		}
+		file("imports.tf", importsContent, FileTypeImports, 0)
+	}
+
+	// Generate legacy import script if requested
+	if opts.ImportScript {
+		scriptContent, err := g.generator.GenerateImportScript(resources)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate import.sh: %w", err)
+		}
+		file("import.sh", scriptContent, FileTypeScript, 0)
+	}
+
+	// Generate terraform.tfstate if requested
+	if opts.IncludeState {
+		stateContent, stateWarnings, err := g.generator.GenerateState(resources, providers)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate terraform.tfstate: %w", err)
+		}
+		for i := range stateWarnings {
+			stateWarnings[i].File = path.Join(dir, "terraform.tfstate")
+		}
+		warnings = append(warnings, stateWarnings...)
+		file("terraform.tfstate", stateContent, FileTypeState, len(resources)-len(stateWarnings))
+	}
+
+	// Generate variables.tf if needed
+	if variables := collectVariables(resources); len(variables) > 0 {
+		variablesContent, err := g.generator.GenerateVariables(variables)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate variables.tf: %w", err)
+		}
+		file("variables.tf", variablesContent, FileTypeVariables, 0)
+	}
+
+	// Generate outputs.tf if needed
+	if outputs := collectOutputs(resources); len(outputs) > 0 {
+		outputsContent, err := g.generator.GenerateOutputs(outputs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate outputs.tf: %w", err)
+		}
+		file("outputs.tf", outputsContent, FileTypeOutputs, 0)
+	}
+
+	return files, warnings, nil
+}
+
+// organizeResources organizes resources into file structure
+func (g *TerraformFormatGenerator) organizeResources(resources []MappedResource, opts GenerationOptions) (map[string][]MappedResource, error) {
+	if g.organizer == nil {
+		// Default flat organization
+		return map[string][]MappedResource{
+			"main.tf": resources,
+		}, nil
+	}
+
+	// Convert MappedResource to TerraformResource for organizer interface
+	terraformResources := make([]TerraformResource, len(resources))
+	for i, mapped := range resources {
+		terraformResources[i] = TerraformResource{
+			Type:         mapped.ResourceType,
+			Name:         mapped.ResourceName,
+			Provider:     mapped.OriginalResource.Provider,
+			Config:       mapped.Configuration,
+			Dependencies: mapped.Dependencies,
+			Outputs:      make(map[string]string),
+			Variables:    mapped.Variables,
+			SourceInfo: SourceInfo{
+				OriginalID:       mapped.OriginalResource.ID,
+				OriginalType:     mapped.OriginalResource.Type,
+				OriginalProvider: mapped.OriginalResource.Provider,
+				OriginalRegion:   mapped.OriginalResource.Region,
+				DiscoveredAt:     time.Now(),
+				Metadata:         mapped.OriginalResource.Metadata,
+				Tags:             mapped.OriginalResource.Tags,
+			},
+		}
+		for name, output := range mapped.Outputs {
+			terraformResources[i].Outputs[name] = output.Value
+		}
+	}
+
+	organizedTerraform, err := g.organizer.OrganizeFiles(terraformResources, ResolvePattern(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert back to MappedResource
+	result := make(map[string][]MappedResource)
+	for path, tfResources := range organizedTerraform {
+		mappedResources := make([]MappedResource, len(tfResources))
+		for i, tfRes := range tfResources {
+			for _, orig := range resources {
+				if orig.ResourceName == tfRes.Name && orig.ResourceType == tfRes.Type {
+					mappedResources[i] = orig
+					break
+				}
+			}
+		}
+		result[path] = mappedResources
+	}
+
+	return result, nil
+}
+
+// generateResourceFile generates content for a single resource file
+func (g *TerraformFormatGenerator) generateResourceFile(resources []MappedResource) (string, error) {
+	if fileGenerator, ok := g.generator.(ResourceFileGenerator); ok {
+		return fileGenerator.GenerateResourceFile(resources)
+	}
+
+	var content strings.Builder
+
+	content.WriteString("# Generated by Chimera\n")
+	content.WriteString(fmt.Sprintf("# Generated at: %s\n\n", time.Now().Format(time.RFC3339)))
+
+	for _, resource := range resources {
+		resourceContent, err := g.generator.GenerateResourceHCL(resource)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate resource %s: %w", resource.ResourceName, err)
+		}
+
+		content.WriteString(resourceContent)
+		content.WriteString("\n")
+	}
+
+	return content.String(), nil
+}
+
+// providersFor returns the provider configurations used by resources; a
+// Terraform resource type is prefixed with the name of its provider, and an
+// aliased configuration is only used by the resources that select it
+func providersFor(resources []MappedResource, providers []ProviderConfig) []ProviderConfig {
+	var used []ProviderConfig
+	for _, provider := range providers {
+		for _, resource := range resources {
+			if !strings.HasPrefix(resource.ResourceType, provider.Name+"_") {
+				continue
+			}
+			if provider.Alias == "" || resource.Provider == provider.Name+"."+provider.Alias {
+				used = append(used, provider)
+				break
+			}
+		}
+	}
+	return used
+}
+
+// localizeReferences returns the resources of the files in dir, with
+// references to resources generated in other directories replaced by the
+// discovered values and dependencies on them removed
+func localizeReferences(organizedFiles map[string][]MappedResource, dir string, directoryOf map[string]string, all []MappedResource) map[string][]MappedResource {
+	byAddress := make(map[string]MappedResource, len(all))
+	for _, resource := range all {
+		byAddress[resource.ResourceType+"."+resource.ResourceName] = resource
+	}
+
+	var localize func(value interface{}) interface{}
+	localize = func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case Expression:
+			match := terraformReference.FindStringSubmatch(string(v))
+			if match == nil {
+				return v
+			}
+			address := match[1]
+			if other, generated := directoryOf[address]; !generated || other == dir {
+				return v
+			}
+			if resolved, ok := externalValue(byAddress[address], match[2]); ok {
+				return resolved
+			}
+			return v
+		case map[string]interface{}:
+			localized := make(map[string]interface{}, len(v))
+			for key, item := range v {
+				localized[key] = localize(item)
+			}
+			return localized
+		case []map[string]interface{}:
+			localized := make([]map[string]interface{}, len(v))
+			for i, item := range v {
+				localized[i] = localize(item).(map[string]interface{})
+			}
+			return localized
+		case []interface{}:
+			localized := make([]interface{}, len(v))
+			for i, item := range v {
+				localized[i] = localize(item)
+			}
+			return localized
+		default:
+			return v
+		}
+	}
+
+	result := make(map[string][]MappedResource, len(organizedFiles))
+	for filePath, resources := range organizedFiles {
+		localized := make([]MappedResource, len(resources))
+		for i, resource := range resources {
+			resource.Configuration = localize(resource.Configuration).(map[string]interface{})
+
+			var dependencies []string
+			for _, dependency := range resource.Dependencies {
+				if other, generated := directoryOf[dependency]; !generated || other == dir {
+					dependencies = append(dependencies, dependency)
+				}
+			}
+			resource.Dependencies = dependencies
+
+			localized[i] = resource
+		}
+		result[filePath] = localized
+	}
+	return result
+}
+
+// terraformReference matches a reference to a resource attribute, capturing
+// the resource address and attribute
+var terraformReference = regexp.MustCompile(`^([a-z][a-z0-9_]*\.[A-Za-z_][A-Za-z0-9_-]*)\.([a-z_]+)$`)
+
+// externalValue returns the discovered value of a resource attribute
+func externalValue(resource MappedResource, attribute string) (interface{}, bool) {
+	id := resource.ImportID
+	if id == "" {
+		id = resource.OriginalResource.ID
+	}
+
+	switch attribute {
+	case "id":
+		return id, true
+	case "self_link":
+		if strings.HasPrefix(id, "https://") {
+			return id, true
+		}
+		return "https://www.googleapis.com/compute/v1/" + id, true
+	case "arn":
+		if arn, ok := resource.OriginalResource.Metadata["arn"].(string); ok && arn != "" {
+			return arn, true
+		}
+	default:
+		if value, ok := resource.Configuration[attribute].(string); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// collectVariables collects all variables from resources
+func collectVariables(resources []MappedResource) map[string]Variable {
+	variables := make(map[string]Variable)
+
+	for _, resource := range resources {
+		for name, variable := range resource.Variables {
+			if existing, exists := variables[name]; exists {
+				// Merge variable definitions, preferring required ones
+				if variable.Required && !existing.Required {
+					variables[name] = variable
+				}
+			} else {
+				variables[name] = variable
+			}
+		}
+	}
+
+	return variables
+}
+
+// collectOutputs collects all outputs from resources
+func collectOutputs(resources []MappedResource) map[string]Output {
+	outputs := make(map[string]Output)
+
+	for _, resource := range resources {
+		for name, output := range resource.Outputs {
+			if output.Name != "" {
+				name = output.Name
+			}
+			outputs[name] = output
+		}
+	}
+
+	return outputs
+}
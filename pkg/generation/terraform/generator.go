@@ -7,6 +7,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/BigChiefRick/chimera/pkg/generation"
 )
 
@@ -88,27 +91,31 @@ func (g *Generator) generateProvider(resources []generation.MappedResource, opts
 	// Get required providers
 	providers := g.getRequiredProviders(resources)
 
-	// Generate terraform block with required providers
-	content.WriteString("terraform {\n")
-	content.WriteString("  required_providers {\n")
-
-	for name, version := range providers {
-		content.WriteString(fmt.Sprintf("    %s = {\n", name))
-		content.WriteString(fmt.Sprintf("      source  = \"hashicorp/%s\"\n", name))
-		content.WriteString(fmt.Sprintf("      version = \"%s\"\n", version))
-		content.WriteString("    }\n")
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	content.WriteString("  }\n")
-	content.WriteString("}\n\n")
+	// Generate terraform block with required providers
+	var required []generation.ProviderConfig
+	for _, name := range names {
+		required = append(required, generation.ProviderConfig{
+			Name:    name,
+			Source:  fmt.Sprintf("hashicorp/%s", name),
+			Version: providers[name],
+		})
+	}
+	content.WriteString(renderRequiredProviders(required))
+	content.WriteString("\n")
 
 	// Generate provider configurations
-	for name := range providers {
+	for _, name := range names {
 		switch name {
 		case "aws":
 			content.WriteString("provider \"aws\" {\n")
 			if opts.ProviderVersion != "" {
-				content.WriteString(fmt.Sprintf("  # version = \"%s\"\n", opts.ProviderVersion))
+				content.WriteString(fmt.Sprintf("  # version = %q\n", opts.ProviderVersion))
 			}
 			content.WriteString("  # Configuration options\n")
 			content.WriteString("  # region = var.aws_region\n")
@@ -240,29 +247,11 @@ func (g *Generator) generateVariables(resources []generation.MappedResource, opt
 		}
 	}
 
-	// Sort variable names for consistent output
-	varNames := make([]string, 0, len(variables))
-	for name := range variables {
-		varNames = append(varNames, name)
-	}
-	sort.Strings(varNames)
-
-	for _, name := range varNames {
-		variable := variables[name]
-		content.WriteString(fmt.Sprintf("variable \"%s\" {\n", name))
-		content.WriteString(fmt.Sprintf("  description = \"%s\"\n", variable.Description))
-		content.WriteString(fmt.Sprintf("  type        = %s\n", variable.Type))
-		
-		if variable.Default != nil {
-			content.WriteString(fmt.Sprintf("  default     = %v\n", variable.Default))
-		}
-		
-		if variable.Sensitive {
-			content.WriteString("  sensitive   = true\n")
-		}
-
-		content.WriteString("}\n\n")
+	variablesHCL, err := renderVariables(variables)
+	if err != nil {
+		return generation.GeneratedFile{}, err
 	}
+	content.WriteString(variablesHCL)
 
 	filePath := filepath.Join(opts.OutputPath, "variables.tf")
 
@@ -294,25 +283,11 @@ func (g *Generator) generateOutputs(resources []generation.MappedResource, opts
 		}
 	}
 
-	// Sort output names for consistent output
-	outputNames := make([]string, 0, len(outputs))
-	for name := range outputs {
-		outputNames = append(outputNames, name)
-	}
-	sort.Strings(outputNames)
-
-	for _, name := range outputNames {
-		output := outputs[name]
-		content.WriteString(fmt.Sprintf("output \"%s\" {\n", name))
-		content.WriteString(fmt.Sprintf("  description = \"%s\"\n", output.Description))
-		content.WriteString(fmt.Sprintf("  value       = %s\n", output.Value))
-		
-		if output.Sensitive {
-			content.WriteString("  sensitive   = true\n")
-		}
-
-		content.WriteString("}\n\n")
+	outputsHCL, err := renderOutputs(outputs)
+	if err != nil {
+		return generation.GeneratedFile{}, err
 	}
+	content.WriteString(outputsHCL)
 
 	filePath := filepath.Join(opts.OutputPath, "outputs.tf")
 
@@ -391,92 +366,36 @@ func (g *Generator) GenerateModule(config generation.ModuleConfig) (map[string]s
 	mainContent.WriteString(fmt.Sprintf("# Generated by Chimera on %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
 	
 	// Add module resources if provided - NOTE: config.Resources is []TerraformResource, not []MappedResource
-	if len(config.Resources) > 0 {
-		for _, resource := range config.Resources {
-			resourceHCL, err := g.GenerateResource(resource)
-			if err != nil {
-				return nil, fmt.Errorf("failed to generate HCL for resource %s: %w", resource.Name, err)
-			}
-			mainContent.WriteString(resourceHCL)
+	for i, resource := range config.Resources {
+		resourceHCL, err := g.GenerateResource(resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate HCL for resource %s: %w", resource.Name, err)
+		}
+		if i > 0 {
 			mainContent.WriteString("\n")
 		}
+		mainContent.WriteString(resourceHCL)
 	}
 	
 	files["main.tf"] = mainContent.String()
 	
 	// Generate variables.tf from config.Variables
-	var variablesContent strings.Builder
-	variablesContent.WriteString("# Module Variables\n\n")
-	
-	// Sort variable names for consistent output
-	varNames := make([]string, 0, len(config.Variables))
-	for name := range config.Variables {
-		varNames = append(varNames, name)
-	}
-	sort.Strings(varNames)
-	
-	for _, name := range varNames {
-		variable := config.Variables[name]
-		variablesContent.WriteString(fmt.Sprintf("variable \"%s\" {\n", name))
-		variablesContent.WriteString(fmt.Sprintf("  description = \"%s\"\n", variable.Description))
-		variablesContent.WriteString(fmt.Sprintf("  type        = %s\n", variable.Type))
-		
-		if variable.Default != nil {
-			variablesContent.WriteString(fmt.Sprintf("  default     = %v\n", variable.Default))
-		}
-		
-		if variable.Sensitive {
-			variablesContent.WriteString("  sensitive   = true\n")
-		}
-		
-		variablesContent.WriteString("}\n\n")
+	variablesHCL, err := renderVariables(config.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate module variables: %w", err)
 	}
-	
-	files["variables.tf"] = variablesContent.String()
+	files["variables.tf"] = "# Module Variables\n\n" + variablesHCL
 	
 	// Generate outputs.tf from config.Outputs
-	var outputsContent strings.Builder
-	outputsContent.WriteString("# Module Outputs\n\n")
-	
-	// Sort output names for consistent output
-	outputNames := make([]string, 0, len(config.Outputs))
-	for name := range config.Outputs {
-		outputNames = append(outputNames, name)
-	}
-	sort.Strings(outputNames)
-	
-	for _, name := range outputNames {
-		output := config.Outputs[name]
-		outputsContent.WriteString(fmt.Sprintf("output \"%s\" {\n", name))
-		outputsContent.WriteString(fmt.Sprintf("  description = \"%s\"\n", output.Description))
-		outputsContent.WriteString(fmt.Sprintf("  value       = %s\n", output.Value))
-		
-		if output.Sensitive {
-			outputsContent.WriteString("  sensitive   = true\n")
-		}
-		
-		outputsContent.WriteString("}\n\n")
+	outputsHCL, err := renderOutputs(config.Outputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate module outputs: %w", err)
 	}
-	
-	files["outputs.tf"] = outputsContent.String()
+	files["outputs.tf"] = "# Module Outputs\n\n" + outputsHCL
 	
 	// Generate versions.tf from config.Providers
 	if len(config.Providers) > 0 {
-		var versionsContent strings.Builder
-		versionsContent.WriteString("terraform {\n")
-		versionsContent.WriteString("  required_providers {\n")
-		
-		for _, provider := range config.Providers {
-			versionsContent.WriteString(fmt.Sprintf("    %s = {\n", provider.Name))
-			versionsContent.WriteString(fmt.Sprintf("      source  = \"%s\"\n", provider.Source))
-			versionsContent.WriteString(fmt.Sprintf("      version = \"%s\"\n", provider.Version))
-			versionsContent.WriteString("    }\n")
-		}
-		
-		versionsContent.WriteString("  }\n")
-		versionsContent.WriteString("}\n")
-		
-		files["versions.tf"] = versionsContent.String()
+		files["versions.tf"] = renderRequiredProviders(config.Providers)
 	}
 	
 	return files, nil
@@ -484,105 +403,68 @@ func (g *Generator) GenerateModule(config generation.ModuleConfig) (map[string]s
 
 // GenerateResource generates Terraform HCL for a TerraformResource
 func (g *Generator) GenerateResource(resource generation.TerraformResource) (string, error) {
-	var content strings.Builder
-	
-	content.WriteString(fmt.Sprintf("resource \"%s\" \"%s\" {\n", resource.Type, resource.Name))
-	
-	// Add configuration attributes
-	for key, value := range resource.Config {
-		switch v := value.(type) {
-		case string:
-			// Check if it's a reference to another resource or variable
-			if strings.Contains(v, ".") && (strings.HasPrefix(v, "var.") || strings.HasPrefix(v, "aws_") || strings.HasPrefix(v, "azurerm_") || strings.HasPrefix(v, "google_")) {
-				content.WriteString(fmt.Sprintf("  %s = %s\n", key, v))
-			} else {
-				content.WriteString(fmt.Sprintf("  %s = \"%s\"\n", key, v))
-			}
-		case int, int64:
-			content.WriteString(fmt.Sprintf("  %s = %v\n", key, v))
-		case bool:
-			content.WriteString(fmt.Sprintf("  %s = %t\n", key, v))
-		case []interface{}:
-			content.WriteString(fmt.Sprintf("  %s = %s\n", key, formatList(v)))
-		case map[string]interface{}:
-			content.WriteString(fmt.Sprintf("  %s = {\n", key))
-			for subKey, subValue := range v {
-				switch sv := subValue.(type) {
-				case string:
-					content.WriteString(fmt.Sprintf("    %s = \"%s\"\n", subKey, sv))
-				default:
-					content.WriteString(fmt.Sprintf("    %s = %v\n", subKey, sv))
-				}
-			}
-			content.WriteString("  }\n")
-		default:
-			content.WriteString(fmt.Sprintf("  %s = \"%v\"\n", key, v))
-		}
-	}
-	
-	// Add dependencies if any
-	if len(resource.Dependencies) > 0 {
-		content.WriteString("  depends_on = [\n")
-		for _, dep := range resource.Dependencies {
-			content.WriteString(fmt.Sprintf("    %s,\n", dep))
-		}
-		content.WriteString("  ]\n")
-	}
-	
-	content.WriteString("}\n")
-	
-	return content.String(), nil
+	return renderResource(resource.Type, resource.Name, "", resource.Config, resource.Dependencies)
 }
 
 // GenerateResourceHCL generates HCL for a MappedResource (interface requirement)
 func (g *Generator) GenerateResourceHCL(resource generation.MappedResource) (string, error) {
-	var content strings.Builder
-	
-	content.WriteString(fmt.Sprintf("resource \"%s\" \"%s\" {\n", resource.ResourceType, resource.ResourceName))
-	
-	// Add configuration attributes
-	for key, value := range resource.Configuration {
-		switch v := value.(type) {
-		case string:
-			// Check if it's a reference to another resource or variable
-			if strings.Contains(v, ".") && (strings.HasPrefix(v, "var.") || strings.HasPrefix(v, "aws_") || strings.HasPrefix(v, "azurerm_") || strings.HasPrefix(v, "google_")) {
-				content.WriteString(fmt.Sprintf("  %s = %s\n", key, v))
-			} else {
-				content.WriteString(fmt.Sprintf("  %s = \"%s\"\n", key, v))
-			}
-		case int, int64:
-			content.WriteString(fmt.Sprintf("  %s = %v\n", key, v))
-		case bool:
-			content.WriteString(fmt.Sprintf("  %s = %t\n", key, v))
-		case []interface{}:
-			content.WriteString(fmt.Sprintf("  %s = %s\n", key, formatList(v)))
-		case map[string]interface{}:
-			content.WriteString(fmt.Sprintf("  %s = {\n", key))
-			for subKey, subValue := range v {
-				switch sv := subValue.(type) {
-				case string:
-					content.WriteString(fmt.Sprintf("    %s = \"%s\"\n", subKey, sv))
-				default:
-					content.WriteString(fmt.Sprintf("    %s = %v\n", subKey, sv))
-				}
+	return renderResource(resource.ResourceType, resource.ResourceName, resource.Provider, resource.Configuration, resource.Dependencies)
+}
+
+// GenerateImports generates Terraform 1.5+ import blocks (interface requirement)
+func (g *Generator) GenerateImports(resources []generation.MappedResource) (string, error) {
+	file := hclwrite.NewEmptyFile()
+
+	for _, resource := range sortByAddress(resources) {
+		if resource.ImportID == "" {
+			continue
+		}
+
+		to, err := expressionTokens(fmt.Sprintf("%s.%s", resource.ResourceType, resource.ResourceName))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate import for %s: %w", resource.ResourceName, err)
+		}
+
+		if len(file.Body().Blocks()) > 0 {
+			file.Body().AppendNewline()
+		}
+		body := file.Body().AppendNewBlock("import", nil).Body()
+		body.SetAttributeRaw("to", to)
+		body.SetAttributeValue("id", cty.StringVal(resource.ImportID))
+		if resource.Provider != "" {
+			provider, err := expressionTokens(resource.Provider)
+			if err != nil {
+				return "", fmt.Errorf("failed to generate import for %s: %w", resource.ResourceName, err)
 			}
-			content.WriteString("  }\n")
-		default:
-			content.WriteString(fmt.Sprintf("  %s = \"%v\"\n", key, v))
+			body.SetAttributeRaw("provider", provider)
 		}
 	}
-	
-	// Add dependencies if any
-	if len(resource.Dependencies) > 0 {
-		content.WriteString("  depends_on = [\n")
-		for _, dep := range resource.Dependencies {
-			content.WriteString(fmt.Sprintf("    %s,\n", dep))
+
+	var content strings.Builder
+	content.WriteString("# Import blocks for adopting existing resources (Terraform 1.5+)\n")
+	content.WriteString("# Generated by Chimera\n\n")
+	content.WriteString(formatFile(file))
+
+	return content.String(), nil
+}
+
+// GenerateImportScript generates a legacy terraform import shell script (interface requirement)
+func (g *Generator) GenerateImportScript(resources []generation.MappedResource) (string, error) {
+	var content strings.Builder
+
+	content.WriteString("#!/usr/bin/env bash\n")
+	content.WriteString("# Imports existing resources into Terraform state\n")
+	content.WriteString("# Generated by Chimera\n")
+	content.WriteString("set -euo pipefail\n\n")
+
+	for _, resource := range sortByAddress(resources) {
+		if resource.ImportID == "" {
+			continue
 		}
-		content.WriteString("  ]\n")
+		address := fmt.Sprintf("%s.%s", resource.ResourceType, resource.ResourceName)
+		content.WriteString(fmt.Sprintf("terraform import %s %s\n", shellQuote(address), shellQuote(resource.ImportID)))
 	}
-	
-	content.WriteString("}\n")
-	
+
 	return content.String(), nil
 }
 
@@ -593,130 +475,58 @@ func (g *Generator) ValidateSyntax(content string) error {
 
 // GenerateProvider generates provider configuration block (interface requirement)
 func (g *Generator) GenerateProvider(config generation.ProviderConfig) (string, error) {
-	var content strings.Builder
-	
-	content.WriteString(fmt.Sprintf("provider \"%s\" {\n", config.Name))
-	
-	for key, value := range config.Config {
-		switch v := value.(type) {
-		case string:
-			content.WriteString(fmt.Sprintf("  %s = \"%s\"\n", key, v))
-		case int, int64:
-			content.WriteString(fmt.Sprintf("  %s = %v\n", key, v))
-		case bool:
-			content.WriteString(fmt.Sprintf("  %s = %t\n", key, v))
-		default:
-			content.WriteString(fmt.Sprintf("  %s = \"%v\"\n", key, v))
-		}
+	file := hclwrite.NewEmptyFile()
+	body := file.Body().AppendNewBlock("provider", []string{config.Name}).Body()
+
+	if config.Alias != "" {
+		body.SetAttributeValue("alias", cty.StringVal(config.Alias))
 	}
-	
-	content.WriteString("}\n")
-	
-	return content.String(), nil
+	if err := writeBody(body, config.Config); err != nil {
+		return "", fmt.Errorf("failed to generate provider %s: %w", config.Name, err)
+	}
+
+	return formatFile(file), nil
 }
 
 // GenerateVariables generates variables.tf content (interface requirement)
 func (g *Generator) GenerateVariables(variables map[string]generation.Variable) (string, error) {
-	var content strings.Builder
-	
-	content.WriteString("# Variables\n\n")
-	
-	// Sort variable names for consistent output
-	varNames := make([]string, 0, len(variables))
-	for name := range variables {
-		varNames = append(varNames, name)
-	}
-	sort.Strings(varNames)
-	
-	for _, name := range varNames {
-		variable := variables[name]
-		content.WriteString(fmt.Sprintf("variable \"%s\" {\n", name))
-		content.WriteString(fmt.Sprintf("  description = \"%s\"\n", variable.Description))
-		content.WriteString(fmt.Sprintf("  type        = %s\n", variable.Type))
-		
-		if variable.Default != nil {
-			content.WriteString(fmt.Sprintf("  default     = %v\n", variable.Default))
-		}
-		
-		if variable.Sensitive {
-			content.WriteString("  sensitive   = true\n")
-		}
-		
-		content.WriteString("}\n\n")
+	variablesHCL, err := renderVariables(variables)
+	if err != nil {
+		return "", err
 	}
-	
-	return content.String(), nil
+	return "# Variables\n\n" + variablesHCL, nil
 }
 
 // GenerateOutputs generates outputs.tf content (interface requirement)
 func (g *Generator) GenerateOutputs(outputs map[string]generation.Output) (string, error) {
-	var content strings.Builder
-	
-	content.WriteString("# Outputs\n\n")
-	
-	// Sort output names for consistent output
-	outputNames := make([]string, 0, len(outputs))
-	for name := range outputs {
-		outputNames = append(outputNames, name)
-	}
-	sort.Strings(outputNames)
-	
-	for _, name := range outputNames {
-		output := outputs[name]
-		content.WriteString(fmt.Sprintf("output \"%s\" {\n", name))
-		content.WriteString(fmt.Sprintf("  description = \"%s\"\n", output.Description))
-		content.WriteString(fmt.Sprintf("  value       = %s\n", output.Value))
-		
-		if output.Sensitive {
-			content.WriteString("  sensitive   = true\n")
-		}
-		
-		content.WriteString("}\n\n")
+	outputsHCL, err := renderOutputs(outputs)
+	if err != nil {
+		return "", err
 	}
-	
-	return content.String(), nil
+	return "# Outputs\n\n" + outputsHCL, nil
 }
 
 // GenerateVersions generates versions.tf content (interface requirement)
 func (g *Generator) GenerateVersions(providers []generation.ProviderConfig) (string, error) {
-	var content strings.Builder
-	
-	content.WriteString("terraform {\n")
-	content.WriteString("  required_providers {\n")
-	
-	for _, provider := range providers {
-		content.WriteString(fmt.Sprintf("    %s = {\n", provider.Name))
-		content.WriteString(fmt.Sprintf("      source  = \"%s\"\n", provider.Source))
-		content.WriteString(fmt.Sprintf("      version = \"%s\"\n", provider.Version))
-		content.WriteString("    }\n")
-	}
-	
-	content.WriteString("  }\n")
-	content.WriteString("}\n")
-	
-	return content.String(), nil
+	return renderRequiredProviders(providers), nil
 }
 
-// formatList formats a list for Terraform HCL
-func formatList(list []interface{}) string {
-	var result strings.Builder
-	result.WriteString("[")
-	
-	for i, item := range list {
-		if i > 0 {
-			result.WriteString(", ")
-		}
-		
-		switch v := item.(type) {
-		case string:
-			result.WriteString(fmt.Sprintf("\"%s\"", v))
-		default:
-			result.WriteString(fmt.Sprintf("%v", v))
+// sortByAddress returns a copy of resources sorted by Terraform address
+func sortByAddress(resources []generation.MappedResource) []generation.MappedResource {
+	sorted := make([]generation.MappedResource, len(resources))
+	copy(sorted, resources)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ResourceType != sorted[j].ResourceType {
+			return sorted[i].ResourceType < sorted[j].ResourceType
 		}
-	}
-	
-	result.WriteString("]")
-	return result.String()
+		return sorted[i].ResourceName < sorted[j].ResourceName
+	})
+	return sorted
+}
+
+// shellQuote quotes a value for safe use as a single shell word
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
 }
 
 // groupResourcesForModules groups resources based on module structure
@@ -815,10 +625,12 @@ func (g *Generator) sortResourcesByDependencies(resources []generation.MappedRes
 		"aws_subnet":           3,
 		"aws_route_table":      4,
 		"aws_security_group":   5,
-		"aws_key_pair":         6,
-		"aws_instance":         7,
-		"aws_ebs_volume":       8,
-		"aws_eip":              9,
+		"aws_vpc_security_group_ingress_rule": 6,
+		"aws_vpc_security_group_egress_rule":  6,
+		"aws_key_pair":         7,
+		"aws_instance":         8,
+		"aws_ebs_volume":       9,
+		"aws_eip":              10,
 	}
 
 	sort.Slice(resources, func(i, j int) bool {
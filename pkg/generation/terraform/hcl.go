@@ -0,0 +1,362 @@
+package terraform
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// writeBody writes configuration into an HCL body. Attributes are written
+// first in sorted order, followed by nested blocks: a map[string]interface{}
+// value becomes a single block and a []map[string]interface{} value becomes
+// one block per element. map[string]string values such as tags and labels,
+// and generation.Map values, are written as object attributes.
+func writeBody(body *hclwrite.Body, config map[string]interface{}) error {
+	var blockKeys []string
+
+	for _, key := range sortedKeys(config) {
+		switch v := config[key].(type) {
+		case nil:
+			continue
+		case map[string]interface{}, []map[string]interface{}:
+			blockKeys = append(blockKeys, key)
+		default:
+			tokens, err := valueTokens(v)
+			if err != nil {
+				return fmt.Errorf("attribute %s: %w", key, err)
+			}
+			body.SetAttributeRaw(key, tokens)
+		}
+	}
+
+	for _, key := range blockKeys {
+		var blocks []map[string]interface{}
+		switch v := config[key].(type) {
+		case map[string]interface{}:
+			blocks = []map[string]interface{}{v}
+		case []map[string]interface{}:
+			blocks = v
+		}
+
+		for _, block := range blocks {
+			if err := writeBody(body.AppendNewBlock(key, nil).Body(), block); err != nil {
+				return fmt.Errorf("block %s: %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// valueTokens converts a configuration value to HCL tokens. Strings are
+// always literals, with quotes and template sequences escaped; references
+// must be passed as generation.Expression to be written unquoted.
+func valueTokens(value interface{}) (hclwrite.Tokens, error) {
+	switch v := value.(type) {
+	case generation.Expression:
+		return expressionTokens(string(v))
+	case string:
+		return hclwrite.TokensForValue(cty.StringVal(v)), nil
+	case bool:
+		return hclwrite.TokensForValue(cty.BoolVal(v)), nil
+	case int:
+		return hclwrite.TokensForValue(cty.NumberIntVal(int64(v))), nil
+	case int32:
+		return hclwrite.TokensForValue(cty.NumberIntVal(int64(v))), nil
+	case int64:
+		return hclwrite.TokensForValue(cty.NumberIntVal(v)), nil
+	case float32:
+		return hclwrite.TokensForValue(cty.NumberFloatVal(float64(v))), nil
+	case float64:
+		return hclwrite.TokensForValue(cty.NumberFloatVal(v)), nil
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return valueTokens(items)
+	case []interface{}:
+		items := make([]hclwrite.Tokens, 0, len(v))
+		for _, item := range v {
+			tokens, err := valueTokens(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, tokens)
+		}
+		return hclwrite.TokensForTuple(items), nil
+	case map[string]string:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[key] = item
+		}
+		return objectTokens(object)
+	case map[string]interface{}:
+		return objectTokens(v)
+	case generation.Map:
+		return objectTokens(v)
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// objectTokens converts a map to an HCL object expression with sorted keys
+func objectTokens(object map[string]interface{}) (hclwrite.Tokens, error) {
+	attrs := make([]hclwrite.ObjectAttrTokens, 0, len(object))
+	for _, key := range sortedKeys(object) {
+		tokens, err := valueTokens(object[key])
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", key, err)
+		}
+		name := hclwrite.TokensForValue(cty.StringVal(key))
+		if hclsyntax.ValidIdentifier(key) {
+			name = hclwrite.TokensForIdentifier(key)
+		}
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{Name: name, Value: tokens})
+	}
+	return hclwrite.TokensForObject(attrs), nil
+}
+
+// expressionTokens parses a raw HCL expression, such as a traversal like
+// aws_vpc.main.id or a type constraint like list(string), into tokens
+func expressionTokens(expr string) (hclwrite.Tokens, error) {
+	file, diags := hclwrite.ParseConfig([]byte("value = "+expr+"\n"), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid expression %q: %s", expr, diags.Error())
+	}
+
+	attr := file.Body().GetAttribute("value")
+	if attr == nil {
+		return nil, fmt.Errorf("invalid expression %q", expr)
+	}
+	return attr.Expr().BuildTokens(nil), nil
+}
+
+// referenceListTokens builds a tuple of references, as used by depends_on
+func referenceListTokens(references []string) (hclwrite.Tokens, error) {
+	items := make([]hclwrite.Tokens, 0, len(references))
+	seen := make(map[string]bool)
+	for _, reference := range references {
+		if seen[reference] {
+			continue
+		}
+		seen[reference] = true
+
+		tokens, err := expressionTokens(reference)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, tokens)
+	}
+	return hclwrite.TokensForTuple(items), nil
+}
+
+// explicitDependencies returns the dependencies that no expression in the
+// configuration references. A reference already orders the resources, so
+// only the remaining dependencies need to be listed in depends_on.
+func explicitDependencies(config map[string]interface{}, dependencies []string) []string {
+	referenced := make(map[string]bool)
+	collectReferences(config, referenced)
+
+	var result []string
+	for _, dependency := range dependencies {
+		if !referenced[dependency] {
+			result = append(result, dependency)
+		}
+	}
+	return result
+}
+
+// collectReferences records the addresses of the resources and data sources
+// referenced by the expressions in a configuration value
+func collectReferences(value interface{}, referenced map[string]bool) {
+	switch v := value.(type) {
+	case generation.Expression:
+		expr, diags := hclsyntax.ParseExpression([]byte(v), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			return
+		}
+		for _, traversal := range expr.Variables() {
+			address := traversal.RootName()
+			parts := 1
+			if address == "data" {
+				parts = 2
+			}
+			for _, step := range traversal[1:] {
+				if parts == 0 {
+					break
+				}
+				attr, ok := step.(hcl.TraverseAttr)
+				if !ok {
+					break
+				}
+				address += "." + attr.Name
+				parts--
+			}
+			if parts == 0 {
+				referenced[address] = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectReferences(item, referenced)
+		}
+	case []map[string]interface{}:
+		for _, item := range v {
+			collectReferences(item, referenced)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			collectReferences(item, referenced)
+		}
+	case generation.Map:
+		for _, item := range v {
+			collectReferences(item, referenced)
+		}
+	}
+}
+
+// renderResource renders a resource block in canonical format. An aliased
+// provider configuration is selected with the provider meta-argument, which
+// is written first.
+func renderResource(resourceType, name, provider string, config map[string]interface{}, dependencies []string) (string, error) {
+	file := hclwrite.NewEmptyFile()
+	body := file.Body().AppendNewBlock("resource", []string{resourceType, name}).Body()
+
+	if provider != "" {
+		tokens, err := expressionTokens(provider)
+		if err != nil {
+			return "", fmt.Errorf("provider: %w", err)
+		}
+		body.SetAttributeRaw("provider", tokens)
+		body.AppendNewline()
+	}
+
+	if err := writeBody(body, config); err != nil {
+		return "", err
+	}
+
+	if dependencies = explicitDependencies(config, dependencies); len(dependencies) > 0 {
+		tokens, err := referenceListTokens(dependencies)
+		if err != nil {
+			return "", fmt.Errorf("depends_on: %w", err)
+		}
+		body.SetAttributeRaw("depends_on", tokens)
+	}
+
+	return formatFile(file), nil
+}
+
+// renderVariables renders variable blocks sorted by name
+func renderVariables(variables map[string]generation.Variable) (string, error) {
+	file := hclwrite.NewEmptyFile()
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		variable := variables[name]
+		if i > 0 {
+			file.Body().AppendNewline()
+		}
+		body := file.Body().AppendNewBlock("variable", []string{name}).Body()
+
+		body.SetAttributeValue("description", cty.StringVal(variable.Description))
+
+		variableType := variable.Type
+		if variableType == "" {
+			variableType = "string"
+		}
+		typeTokens, err := expressionTokens(variableType)
+		if err != nil {
+			return "", fmt.Errorf("variable %s type: %w", name, err)
+		}
+		body.SetAttributeRaw("type", typeTokens)
+
+		if variable.Default != nil {
+			defaultTokens, err := valueTokens(variable.Default)
+			if err != nil {
+				return "", fmt.Errorf("variable %s default: %w", name, err)
+			}
+			body.SetAttributeRaw("default", defaultTokens)
+		}
+
+		if variable.Sensitive {
+			body.SetAttributeValue("sensitive", cty.True)
+		}
+	}
+
+	return formatFile(file), nil
+}
+
+// renderOutputs renders output blocks sorted by name
+func renderOutputs(outputs map[string]generation.Output) (string, error) {
+	file := hclwrite.NewEmptyFile()
+
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		output := outputs[name]
+		if i > 0 {
+			file.Body().AppendNewline()
+		}
+		body := file.Body().AppendNewBlock("output", []string{name}).Body()
+
+		body.SetAttributeValue("description", cty.StringVal(output.Description))
+
+		valueExpr, err := expressionTokens(output.Value)
+		if err != nil {
+			return "", fmt.Errorf("output %s value: %w", name, err)
+		}
+		body.SetAttributeRaw("value", valueExpr)
+
+		if output.Sensitive {
+			body.SetAttributeValue("sensitive", cty.True)
+		}
+	}
+
+	return formatFile(file), nil
+}
+
+// renderRequiredProviders renders a terraform block declaring providers
+func renderRequiredProviders(providers []generation.ProviderConfig) string {
+	file := hclwrite.NewEmptyFile()
+	required := file.Body().AppendNewBlock("terraform", nil).Body().AppendNewBlock("required_providers", nil).Body()
+
+	for _, provider := range providers {
+		required.SetAttributeValue(provider.Name, cty.ObjectVal(map[string]cty.Value{
+			"source":  cty.StringVal(provider.Source),
+			"version": cty.StringVal(provider.Version),
+		}))
+	}
+
+	return formatFile(file)
+}
+
+// formatFile returns the file content formatted like terraform fmt
+func formatFile(file *hclwrite.File) string {
+	return string(hclwrite.Format(file.Bytes()))
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,297 @@
+package terraform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// renderBody writes a configuration into an empty body and returns it formatted
+func renderBody(config map[string]interface{}) (string, error) {
+	file := hclwrite.NewEmptyFile()
+	if err := writeBody(file.Body(), config); err != nil {
+		return "", err
+	}
+	return formatFile(file), nil
+}
+
+func TestValueTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "string", value: "web", want: `value = "web"`},
+		{name: "quotes and newlines", value: "say \"hi\"\nbye", want: `value = "say \"hi\"\nbye"`},
+		{name: "backslash", value: `C:\data`, want: `value = "C:\\data"`},
+		{name: "interpolation", value: "${aws_vpc.main.id}", want: `value = "$${aws_vpc.main.id}"`},
+		{name: "template directive", value: "%{if true}", want: `value = "%%{if true}"`},
+		{name: "reference", value: generation.Expression("aws_vpc.main.id"), want: `value = aws_vpc.main.id`},
+		{name: "function call", value: generation.Expression(`jsonencode({ a = 1 })`), want: `value = jsonencode({ a = 1 })`},
+		{name: "bool", value: true, want: `value = true`},
+		{name: "int", value: 8, want: `value = 8`},
+		{name: "int64", value: int64(3306), want: `value = 3306`},
+		{name: "float", value: 0.5, want: `value = 0.5`},
+		{name: "string list", value: []string{"a", "${b}"}, want: `value = ["a", "$${b}"]`},
+		{name: "mixed list", value: []interface{}{"a", generation.Expression("var.b"), 1}, want: `value = ["a", var.b, 1]`},
+		{name: "empty list", value: []string{}, want: `value = []`},
+		{
+			name:  "string map",
+			value: map[string]string{"Name": "web", "kubernetes.io/role": "elb"},
+			want:  "value = {\n  Name                 = \"web\"\n  \"kubernetes.io/role\" = \"elb\"\n}",
+		},
+		{
+			name:  "expression map",
+			value: generation.Map{"DB_HOST": generation.Expression("aws_db_instance.main.address"), "MODE": "prod"},
+			want:  "value = {\n  DB_HOST = aws_db_instance.main.address\n  MODE    = \"prod\"\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderBody(map[string]interface{}{"value": tt.value})
+			if err != nil {
+				t.Fatalf("writeBody returned error: %v", err)
+			}
+			if got = strings.TrimSpace(got); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueTokensRejectsUnsupportedTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "struct", value: struct{}{}},
+		{name: "pointer", value: new(string)},
+		{name: "uint", value: uint(1)},
+		{name: "nested in list", value: []interface{}{"a", struct{}{}}},
+		{name: "nested in map", value: generation.Map{"a": []int{1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := renderBody(map[string]interface{}{"value": tt.value}); err == nil {
+				t.Errorf("writeBody accepted %T", tt.value)
+			}
+		})
+	}
+}
+
+func TestExpressionTokensRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "aws_vpc.", "(a", `"unterminated`} {
+		if _, err := expressionTokens(expr); err == nil {
+			t.Errorf("expressionTokens(%q) returned no error", expr)
+		}
+	}
+}
+
+func TestWriteBodyBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{
+			name: "map is a block",
+			config: map[string]interface{}{
+				"root_block_device": map[string]interface{}{"volume_size": 8},
+				"ami":               "ami-1",
+			},
+			want: `ami = "ami-1"
+root_block_device {
+  volume_size = 8
+}`,
+		},
+		{
+			name: "list of maps is repeated blocks",
+			config: map[string]interface{}{
+				"ingress": []map[string]interface{}{
+					{"from_port": 22, "cidr_blocks": []string{"10.0.0.0/8"}},
+					{"from_port": 443},
+				},
+			},
+			want: `ingress {
+  cidr_blocks = ["10.0.0.0/8"]
+  from_port   = 22
+}
+ingress {
+  from_port = 443
+}`,
+		},
+		{
+			name: "nested blocks",
+			config: map[string]interface{}{
+				"environment": map[string]interface{}{
+					"variables": generation.Map{"A": "1"},
+					"config":    map[string]interface{}{"b": generation.Expression("var.b")},
+				},
+			},
+			want: `environment {
+  variables = {
+    A = "1"
+  }
+  config {
+    b = var.b
+  }
+}`,
+		},
+		{
+			name:   "nil is skipped",
+			config: map[string]interface{}{"key_name": nil, "ami": "ami-1"},
+			want:   `ami = "ami-1"`,
+		},
+		{
+			name:   "attributes are sorted",
+			config: map[string]interface{}{"b": 2, "a": 1, "c": 3},
+			want:   "a = 1\nb = 2\nc = 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderBody(tt.config)
+			if err != nil {
+				t.Fatalf("writeBody returned error: %v", err)
+			}
+			if got = strings.TrimSpace(got); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderResource(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     string
+		config       map[string]interface{}
+		dependencies []string
+		want         string
+	}{
+		{
+			name:   "plain",
+			config: map[string]interface{}{"cidr_block": "10.0.0.0/16"},
+			want: `resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}`,
+		},
+		{
+			name:     "aliased provider first",
+			provider: "aws.eu-west-1",
+			config:   map[string]interface{}{"cidr_block": "10.0.0.0/16"},
+			want: `resource "aws_vpc" "main" {
+  provider = aws.eu-west-1
+
+  cidr_block = "10.0.0.0/16"
+}`,
+		},
+		{
+			name:         "unreferenced dependencies",
+			config:       map[string]interface{}{"cidr_block": "10.0.0.0/16"},
+			dependencies: []string{"aws_internet_gateway.main", "aws_iam_role.flow", "aws_internet_gateway.main"},
+			want: `resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+  depends_on = [aws_internet_gateway.main, aws_iam_role.flow]
+}`,
+		},
+		{
+			name:         "referenced dependencies are left out",
+			config:       map[string]interface{}{"vpc_id": generation.Expression("aws_vpc.main.id")},
+			dependencies: []string{"aws_vpc.main", "aws_iam_role.flow"},
+			want: `resource "aws_vpc" "main" {
+  vpc_id     = aws_vpc.main.id
+  depends_on = [aws_iam_role.flow]
+}`,
+		},
+		{
+			name:         "all dependencies referenced",
+			config:       map[string]interface{}{"vpc_id": generation.Expression("aws_vpc.main.id")},
+			dependencies: []string{"aws_vpc.main"},
+			want: `resource "aws_vpc" "main" {
+  vpc_id = aws_vpc.main.id
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderResource("aws_vpc", "main", tt.provider, tt.config, tt.dependencies)
+			if err != nil {
+				t.Fatalf("renderResource returned error: %v", err)
+			}
+			if got = strings.TrimSpace(got); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplicitDependencies(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       map[string]interface{}
+		dependencies []string
+		want         []string
+	}{
+		{
+			name:         "no references",
+			config:       map[string]interface{}{"name": "aws_vpc.main"},
+			dependencies: []string{"aws_vpc.main"},
+			want:         []string{"aws_vpc.main"},
+		},
+		{
+			name:         "attribute reference",
+			config:       map[string]interface{}{"vpc_id": generation.Expression("aws_vpc.main.id")},
+			dependencies: []string{"aws_vpc.main", "aws_subnet.a"},
+			want:         []string{"aws_subnet.a"},
+		},
+		{
+			name:         "reference in a template",
+			config:       map[string]interface{}{"target": generation.Expression(`"integrations/${aws_apigatewayv2_integration.api.id}"`)},
+			dependencies: []string{"aws_apigatewayv2_integration.api"},
+		},
+		{
+			name: "references in nested blocks, lists and maps",
+			config: map[string]interface{}{
+				"network_interface": []map[string]interface{}{
+					{"subnet_id": generation.Expression("aws_subnet.a.id")},
+				},
+				"vpc_security_group_ids": []interface{}{generation.Expression("aws_security_group.web.id")},
+				"environment": map[string]interface{}{
+					"variables": generation.Map{"DB": generation.Expression("aws_db_instance.main.address")},
+				},
+			},
+			dependencies: []string{"aws_subnet.a", "aws_security_group.web", "aws_db_instance.main", "aws_iam_role.exec"},
+			want:         []string{"aws_iam_role.exec"},
+		},
+		{
+			name:         "data source reference",
+			config:       map[string]interface{}{"ami": generation.Expression("data.aws_ami.ubuntu.id")},
+			dependencies: []string{"data.aws_ami.ubuntu", "data.aws_ami"},
+			want:         []string{"data.aws_ami"},
+		},
+		{
+			name:         "invalid expression",
+			config:       map[string]interface{}{"vpc_id": generation.Expression("aws_vpc.main.(")},
+			dependencies: []string{"aws_vpc.main"},
+			want:         []string{"aws_vpc.main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := explicitDependencies(tt.config, tt.dependencies)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// generatedComment is written as the "//" property Terraform ignores in JSON configuration
+const generatedComment = "Generated by Chimera"
+
+// templateEscaper escapes template sequences in literal strings, since every
+// string in Terraform JSON configuration is interpreted as a template
+var templateEscaper = strings.NewReplacer("${", "$${", "%{", "%%{")
+
+// JSONGenerator generates Terraform configuration in JSON syntax (.tf.json)
+type JSONGenerator struct {
+	hcl *Generator
+}
+
+// NewJSONGenerator creates a new Terraform JSON generator
+func NewJSONGenerator() *JSONGenerator {
+	return &JSONGenerator{hcl: NewGenerator()}
+}
+
+// GenerateResource generates a .tf.json document for a TerraformResource
+func (g *JSONGenerator) GenerateResource(resource generation.TerraformResource) (string, error) {
+	resources := map[string]interface{}{}
+	if err := addJSONResource(resources, resource.Type, resource.Name, "", resource.Config, resource.Dependencies); err != nil {
+		return "", err
+	}
+	return marshalJSONDocument(map[string]interface{}{"resource": resources})
+}
+
+// GenerateResourceHCL generates a .tf.json document for a MappedResource (interface requirement)
+func (g *JSONGenerator) GenerateResourceHCL(resource generation.MappedResource) (string, error) {
+	return g.GenerateResourceFile([]generation.MappedResource{resource})
+}
+
+// GenerateResourceFile generates a single .tf.json document holding all resources
+func (g *JSONGenerator) GenerateResourceFile(resources []generation.MappedResource) (string, error) {
+	resourceBlocks := map[string]interface{}{}
+	for _, resource := range resources {
+		if err := addJSONResource(resourceBlocks, resource.ResourceType, resource.ResourceName, resource.Provider, resource.Configuration, resource.Dependencies); err != nil {
+			return "", err
+		}
+	}
+	return marshalJSONDocument(map[string]interface{}{"resource": resourceBlocks})
+}
+
+// GenerateProvider generates a provider configuration document (interface requirement)
+func (g *JSONGenerator) GenerateProvider(config generation.ProviderConfig) (string, error) {
+	body, err := jsonValue(config.Config)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate provider %s: %w", config.Name, err)
+	}
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	if config.Alias != "" {
+		body.(map[string]interface{})["alias"] = config.Alias
+	}
+
+	return marshalJSONDocument(map[string]interface{}{
+		"provider": map[string]interface{}{config.Name: body},
+	})
+}
+
+// GenerateVariables generates a variables document (interface requirement)
+func (g *JSONGenerator) GenerateVariables(variables map[string]generation.Variable) (string, error) {
+	blocks := make(map[string]interface{}, len(variables))
+	for name, variable := range variables {
+		block := map[string]interface{}{
+			"description": variable.Description,
+			"type":        variable.Type,
+		}
+		if variable.Type == "" {
+			block["type"] = "string"
+		}
+		// Variable defaults are literal values, not templates
+		if variable.Default != nil {
+			block["default"] = variable.Default
+		}
+		if variable.Sensitive {
+			block["sensitive"] = true
+		}
+		blocks[name] = block
+	}
+
+	if len(blocks) == 0 {
+		return marshalJSONDocument(map[string]interface{}{})
+	}
+	return marshalJSONDocument(map[string]interface{}{"variable": blocks})
+}
+
+// GenerateOutputs generates an outputs document (interface requirement)
+func (g *JSONGenerator) GenerateOutputs(outputs map[string]generation.Output) (string, error) {
+	blocks := make(map[string]interface{}, len(outputs))
+	for name, output := range outputs {
+		block := map[string]interface{}{
+			"description": output.Description,
+			"value":       interpolate(output.Value),
+		}
+		if output.Sensitive {
+			block["sensitive"] = true
+		}
+		blocks[name] = block
+	}
+
+	if len(blocks) == 0 {
+		return marshalJSONDocument(map[string]interface{}{})
+	}
+	return marshalJSONDocument(map[string]interface{}{"output": blocks})
+}
+
+// GenerateVersions generates a required_providers document (interface requirement)
+func (g *JSONGenerator) GenerateVersions(providers []generation.ProviderConfig) (string, error) {
+	required := make(map[string]interface{}, len(providers))
+	for _, provider := range providers {
+		required[provider.Name] = map[string]interface{}{
+			"source":  provider.Source,
+			"version": provider.Version,
+		}
+	}
+
+	return marshalJSONDocument(map[string]interface{}{
+		"terraform": map[string]interface{}{"required_providers": required},
+	})
+}
+
+// GenerateModule generates a complete module in JSON syntax (interface requirement)
+func (g *JSONGenerator) GenerateModule(config generation.ModuleConfig) (map[string]string, error) {
+	files := make(map[string]string)
+
+	resourceBlocks := map[string]interface{}{}
+	for _, resource := range config.Resources {
+		if err := addJSONResource(resourceBlocks, resource.Type, resource.Name, "", resource.Config, resource.Dependencies); err != nil {
+			return nil, fmt.Errorf("failed to generate resource %s: %w", resource.Name, err)
+		}
+	}
+	mainContent, err := marshalJSONDocument(map[string]interface{}{"resource": resourceBlocks})
+	if err != nil {
+		return nil, err
+	}
+	files["main.tf.json"] = mainContent
+
+	variablesContent, err := g.GenerateVariables(config.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate module variables: %w", err)
+	}
+	files["variables.tf.json"] = variablesContent
+
+	outputsContent, err := g.GenerateOutputs(config.Outputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate module outputs: %w", err)
+	}
+	files["outputs.tf.json"] = outputsContent
+
+	if len(config.Providers) > 0 {
+		versionsContent, err := g.GenerateVersions(config.Providers)
+		if err != nil {
+			return nil, err
+		}
+		files["versions.tf.json"] = versionsContent
+	}
+
+	return files, nil
+}
+
+// GenerateImports generates import blocks in JSON syntax (interface requirement)
+func (g *JSONGenerator) GenerateImports(resources []generation.MappedResource) (string, error) {
+	var imports []interface{}
+	for _, resource := range sortByAddress(resources) {
+		if resource.ImportID == "" {
+			continue
+		}
+		block := map[string]interface{}{
+			"to": fmt.Sprintf("%s.%s", resource.ResourceType, resource.ResourceName),
+			"id": templateEscaper.Replace(resource.ImportID),
+		}
+		if resource.Provider != "" {
+			block["provider"] = resource.Provider
+		}
+		imports = append(imports, block)
+	}
+
+	if len(imports) == 0 {
+		return marshalJSONDocument(map[string]interface{}{})
+	}
+	return marshalJSONDocument(map[string]interface{}{"import": imports})
+}
+
+// GenerateImportScript generates a legacy terraform import shell script (interface requirement)
+func (g *JSONGenerator) GenerateImportScript(resources []generation.MappedResource) (string, error) {
+	return g.hcl.GenerateImportScript(resources)
+}
+
+// GenerateState generates a terraform.tfstate describing existing resources (interface requirement)
+func (g *JSONGenerator) GenerateState(resources []generation.MappedResource, providers []generation.ProviderConfig) (string, []generation.GenerationWarning, error) {
+	return g.hcl.GenerateState(resources, providers)
+}
+
+// ValidateSyntax validates that content is well-formed JSON (interface requirement)
+func (g *JSONGenerator) ValidateSyntax(content string) error {
+	if !json.Valid([]byte(content)) {
+		return fmt.Errorf("invalid JSON")
+	}
+	return nil
+}
+
+// addJSONResource adds a resource body under resource.<type>.<name>,
+// selecting an aliased provider configuration when provider is set
+func addJSONResource(resources map[string]interface{}, resourceType, name, provider string, config map[string]interface{}, dependencies []string) error {
+	value, err := jsonValue(config)
+	if err != nil {
+		return fmt.Errorf("failed to generate resource %s.%s: %w", resourceType, name, err)
+	}
+	body, _ := value.(map[string]interface{})
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	if provider != "" {
+		body["provider"] = provider
+	}
+
+	if dependencies = explicitDependencies(config, dependencies); len(dependencies) > 0 {
+		var dependsOn []interface{}
+		seen := make(map[string]bool)
+		for _, dependency := range dependencies {
+			if !seen[dependency] {
+				seen[dependency] = true
+				dependsOn = append(dependsOn, dependency)
+			}
+		}
+		body["depends_on"] = dependsOn
+	}
+
+	byName, _ := resources[resourceType].(map[string]interface{})
+	if byName == nil {
+		byName = map[string]interface{}{}
+		resources[resourceType] = byName
+	}
+	byName[name] = body
+
+	return nil
+}
+
+// jsonValue converts a configuration value to its Terraform JSON form.
+// References become "${...}" interpolations, template sequences in literal
+// strings are escaped, a map[string]interface{} is a single nested block and
+// a []map[string]interface{} is a list of repeated blocks.
+func jsonValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case generation.Expression:
+		return interpolate(string(v)), nil
+	case string:
+		return templateEscaper.Replace(v), nil
+	case bool, int, int32, int64, float32, float64:
+		return v, nil
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = templateEscaper.Replace(item)
+		}
+		return list, nil
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+	case map[string]string:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[key] = templateEscaper.Replace(item)
+		}
+		return object, nil
+	case generation.Map:
+		return jsonValue(map[string]interface{}(v))
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if item == nil {
+				continue
+			}
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			object[key] = converted
+		}
+		return object, nil
+	case []map[string]interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// interpolate wraps an expression in a template interpolation
+func interpolate(expr string) string {
+	return "${" + expr + "}"
+}
+
+// marshalJSONDocument marshals a top-level configuration object with stable
+// key order, two-space indentation and a generated-by comment
+func marshalJSONDocument(document map[string]interface{}) (string, error) {
+	document["//"] = generatedComment
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return "", fmt.Errorf("failed to marshal JSON configuration: %w", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,164 @@
+package terraform
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// decodeDocument decodes a generated .tf.json document
+func decodeDocument(t *testing.T, content string) map[string]interface{} {
+	t.Helper()
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &document); err != nil {
+		t.Fatalf("generated invalid JSON: %v\n%s", err, content)
+	}
+	if document["//"] != generatedComment {
+		t.Errorf("got comment %v, want %q", document["//"], generatedComment)
+	}
+	return document
+}
+
+func TestJSONValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{name: "nil", value: nil, want: nil},
+		{name: "string", value: "web", want: "web"},
+		{name: "template sequences", value: "${a} %{b}", want: "$${a} %%{b}"},
+		{name: "reference", value: generation.Expression("aws_vpc.main.id"), want: "${aws_vpc.main.id}"},
+		{name: "number", value: 8, want: 8},
+		{name: "bool", value: false, want: false},
+		{name: "string list", value: []string{"${a}"}, want: []interface{}{"$${a}"}},
+		{name: "mixed list", value: []interface{}{"a", generation.Expression("var.b")}, want: []interface{}{"a", "${var.b}"}},
+		{name: "string map", value: map[string]string{"Name": "${x}"}, want: map[string]interface{}{"Name": "$${x}"}},
+		{
+			name:  "expression map is an object",
+			value: generation.Map{"DB": generation.Expression("aws_db_instance.main.address")},
+			want:  map[string]interface{}{"DB": "${aws_db_instance.main.address}"},
+		},
+		{
+			name:  "block drops nil attributes",
+			value: map[string]interface{}{"volume_size": 8, "kms_key_id": nil},
+			want:  map[string]interface{}{"volume_size": 8},
+		},
+		{
+			name:  "repeated blocks are a list",
+			value: []map[string]interface{}{{"from_port": 22}, {"from_port": 443}},
+			want:  []interface{}{map[string]interface{}{"from_port": 22}, map[string]interface{}{"from_port": 443}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonValue(tt.value)
+			if err != nil {
+				t.Fatalf("jsonValue returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONValueRejectsUnsupportedTypes(t *testing.T) {
+	for _, value := range []interface{}{struct{}{}, uint(1), []interface{}{[]int{1}}, map[string]interface{}{"a": new(int)}} {
+		if _, err := jsonValue(value); err == nil {
+			t.Errorf("jsonValue accepted %T", value)
+		}
+	}
+}
+
+func TestJSONGeneratorResourceFile(t *testing.T) {
+	resources := []generation.MappedResource{
+		{
+			ResourceType:  "aws_vpc",
+			ResourceName:  "main",
+			Provider:      "aws.eu-west-1",
+			Configuration: map[string]interface{}{"cidr_block": "10.0.0.0/16"},
+		},
+		{
+			ResourceType: "aws_subnet",
+			ResourceName: "a",
+			Configuration: map[string]interface{}{
+				"vpc_id": generation.Expression("aws_vpc.main.id"),
+				"tags":   map[string]string{"Name": "a"},
+			},
+			Dependencies: []string{"aws_vpc.main", "aws_internet_gateway.main", "aws_internet_gateway.main"},
+		},
+	}
+
+	content, err := NewJSONGenerator().GenerateResourceFile(resources)
+	if err != nil {
+		t.Fatalf("GenerateResourceFile returned error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"aws_vpc": map[string]interface{}{
+			"main": map[string]interface{}{
+				"provider":   "aws.eu-west-1",
+				"cidr_block": "10.0.0.0/16",
+			},
+		},
+		"aws_subnet": map[string]interface{}{
+			"a": map[string]interface{}{
+				"vpc_id":     "${aws_vpc.main.id}",
+				"tags":       map[string]interface{}{"Name": "a"},
+				"depends_on": []interface{}{"aws_internet_gateway.main"},
+			},
+		},
+	}
+	if got := decodeDocument(t, content)["resource"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestJSONGeneratorProviderAndImports(t *testing.T) {
+	generator := NewJSONGenerator()
+
+	content, err := generator.GenerateProvider(generation.ProviderConfig{
+		Name:   "aws",
+		Alias:  "eu-west-1",
+		Config: map[string]interface{}{"region": "eu-west-1"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateProvider returned error: %v", err)
+	}
+	wantProvider := map[string]interface{}{
+		"aws": map[string]interface{}{"alias": "eu-west-1", "region": "eu-west-1"},
+	}
+	if got := decodeDocument(t, content)["provider"]; !reflect.DeepEqual(got, wantProvider) {
+		t.Errorf("got provider %v, want %v", got, wantProvider)
+	}
+
+	content, err = generator.GenerateImports([]generation.MappedResource{
+		{ResourceType: "aws_vpc", ResourceName: "main", ImportID: "vpc-1", Provider: "aws.eu-west-1"},
+		{ResourceType: "aws_vpc_security_group_ingress_rule", ResourceName: "web_ingress_0"},
+		{ResourceType: "aws_iam_policy", ResourceName: "p", ImportID: "arn:${x}"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateImports returned error: %v", err)
+	}
+	wantImports := []interface{}{
+		map[string]interface{}{"to": "aws_iam_policy.p", "id": "arn:$${x}"},
+		map[string]interface{}{"to": "aws_vpc.main", "id": "vpc-1", "provider": "aws.eu-west-1"},
+	}
+	if got := decodeDocument(t, content)["import"]; !reflect.DeepEqual(got, wantImports) {
+		t.Errorf("got imports %v, want %v", got, wantImports)
+	}
+}
+
+func TestJSONGeneratorValidateSyntax(t *testing.T) {
+	generator := NewJSONGenerator()
+	if err := generator.ValidateSyntax(`{"resource": {}}`); err != nil {
+		t.Errorf("ValidateSyntax rejected valid JSON: %v", err)
+	}
+	if err := generator.ValidateSyntax(`{"resource": `); err == nil {
+		t.Error("ValidateSyntax accepted invalid JSON")
+	}
+}
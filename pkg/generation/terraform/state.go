@@ -0,0 +1,449 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+const (
+	// StateFormatVersion is the Terraform state format version written by GenerateState
+	StateFormatVersion = 4
+
+	// StateTerraformVersion is recorded as the writer of generated state; it is kept
+	// low so that any Terraform release able to read version 4 state accepts it
+	StateTerraformVersion = "1.0.0"
+)
+
+// State is the version 4 Terraform state file layout
+type State struct {
+	Version          int                    `json:"version"`
+	TerraformVersion string                 `json:"terraform_version"`
+	Serial           int                    `json:"serial"`
+	Lineage          string                 `json:"lineage"`
+	Outputs          map[string]StateOutput `json:"outputs"`
+	Resources        []StateResource        `json:"resources"`
+	CheckResults     interface{}            `json:"check_results"`
+}
+
+// StateOutput is a root module output value recorded in state; Type is the
+// JSON encoding of the value's type, such as "string" or ["list","string"]
+type StateOutput struct {
+	Value     interface{} `json:"value"`
+	Type      interface{} `json:"type"`
+	Sensitive bool        `json:"sensitive,omitempty"`
+}
+
+// StateResource is a managed resource recorded in state
+type StateResource struct {
+	Mode      string          `json:"mode"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Provider  string          `json:"provider"`
+	Instances []StateInstance `json:"instances"`
+}
+
+// StateInstance is a single instance of a resource recorded in state
+type StateInstance struct {
+	SchemaVersion       int                    `json:"schema_version"`
+	Attributes          map[string]interface{} `json:"attributes"`
+	SensitiveAttributes []interface{}          `json:"sensitive_attributes"`
+	Dependencies        []string               `json:"dependencies,omitempty"`
+}
+
+// schemaVersions holds the provider schema version of each resource type;
+// types not listed use schema version 0
+var schemaVersions = map[string]int{
+	"aws_db_instance":         2,
+	"aws_instance":            1,
+	"aws_key_pair":            1,
+	"aws_security_group":      1,
+	"aws_security_group_rule": 2,
+	"aws_subnet":              1,
+	"aws_vpc":                 1,
+	"google_compute_firewall": 1,
+	"google_compute_instance": 6,
+}
+
+// referencePattern matches a simple reference expression such as aws_vpc.main.id or var.region
+var referencePattern = regexp.MustCompile(`^([a-zA-Z0-9_]+)\.([a-zA-Z0-9_-]+)(?:\.([a-zA-Z0-9_]+))?$`)
+
+// GenerateState synthesises a version 4 terraform.tfstate from mapped resources
+// so that existing infrastructure can be planned against without importing it
+// resource by resource. Resources without an ID to record, such as security
+// group rules discovered without rule IDs, are left out and reported as
+// warnings so that they can be imported by hand (interface requirement).
+func (g *Generator) GenerateState(resources []generation.MappedResource, providers []generation.ProviderConfig) (string, []generation.GenerationWarning, error) {
+	var sorted []generation.MappedResource
+	var warnings []generation.GenerationWarning
+	for _, resource := range sortByAddress(resources) {
+		if stateID(resource) == "" {
+			address := resource.ResourceType + "." + resource.ResourceName
+			warnings = append(warnings, generation.GenerationWarning{
+				ResourceID:   resource.OriginalResource.ID,
+				ResourceType: resource.OriginalResource.Type,
+				Provider:     resource.OriginalResource.Provider,
+				Message:      fmt.Sprintf("%s has no ID to record in state and was left out of it", address),
+				Type:         generation.WarningTypeManualAction,
+				Suggestion:   fmt.Sprintf("import %s by hand with terraform import once its ID is known", address),
+			})
+			continue
+		}
+		sorted = append(sorted, resource)
+	}
+
+	resolver := &stateResolver{
+		resources: make(map[string]*generation.MappedResource),
+		variables: make(map[string]generation.Variable),
+	}
+	for i := range sorted {
+		resource := &sorted[i]
+		resolver.resources[resource.ResourceType+"."+resource.ResourceName] = resource
+		for name, variable := range resource.Variables {
+			resolver.variables[name] = variable
+		}
+	}
+
+	state := State{
+		Version:          StateFormatVersion,
+		TerraformVersion: StateTerraformVersion,
+		Serial:           1,
+		Lineage:          uuid.New().String(),
+		Outputs:          make(map[string]StateOutput),
+		Resources:        make([]StateResource, 0, len(sorted)),
+	}
+
+	for _, resource := range sorted {
+		id := stateID(resource)
+
+		state.Resources = append(state.Resources, StateResource{
+			Mode:     "managed",
+			Type:     resource.ResourceType,
+			Name:     resource.ResourceName,
+			Provider: providerAddress(resource, providers),
+			Instances: []StateInstance{
+				{
+					SchemaVersion:       schemaVersions[resource.ResourceType],
+					Attributes:          resolver.attributes(resource, id),
+					SensitiveAttributes: []interface{}{},
+					Dependencies:        resolver.dependencies(resource.Dependencies),
+				},
+			},
+		})
+
+		for name, output := range resource.Outputs {
+			value, ok := resolver.resolve(generation.Expression(output.Value))
+			if !ok {
+				continue
+			}
+			if output.Name != "" {
+				name = output.Name
+			}
+			state.Outputs[name] = StateOutput{
+				Value:     value,
+				Type:      stateType(value),
+				Sensitive: output.Sensitive,
+			}
+		}
+	}
+
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	return string(content) + "\n", warnings, nil
+}
+
+// stateResolver turns configuration values into concrete state values by
+// following references to other mapped resources and variable defaults
+type stateResolver struct {
+	resources map[string]*generation.MappedResource
+	variables map[string]generation.Variable
+}
+
+// attributes builds the state attributes of a resource from what discovery
+// observed; tags added by the generator and defaults the mappers fill in for
+// missing metadata are left out for Terraform to refresh
+func (r *stateResolver) attributes(resource generation.MappedResource, id string) map[string]interface{} {
+	attributes := make(map[string]interface{})
+
+	for key, value := range resource.Configuration {
+		if observed, ok := r.observedValue(resource.OriginalResource, key, value); ok {
+			attributes[key] = observed
+		}
+	}
+
+	attributes["id"] = id
+
+	if tags, ok := attributes["tags"]; ok && strings.HasPrefix(resource.ResourceType, "aws_") {
+		attributes["tags_all"] = tags
+	}
+
+	if arn, ok := resource.OriginalResource.Metadata["arn"].(string); ok && arn != "" {
+		attributes["arn"] = arn
+	}
+
+	if strings.HasPrefix(resource.ResourceType, "google_") {
+		attributes["self_link"] = googleSelfLink(id)
+	}
+
+	return attributes
+}
+
+// observedValue returns the state value of a configuration attribute as
+// discovered. References and nested blocks are resolved from the
+// configuration and tags are the discovered tags. Other values are taken from
+// the metadata attribute of the same name, or kept when they match the
+// discovered name, location or a metadata string, such as an ami matching
+// image_id; anything else was made up by the mapper.
+func (r *stateResolver) observedValue(resource discovery.Resource, key string, value interface{}) (interface{}, bool) {
+	if _, ok := value.(map[string]string); ok && (key == "tags" || key == "labels") {
+		observed := make(map[string]string, len(resource.Tags))
+		for k, v := range resource.Tags {
+			observed[k] = v
+		}
+		return observed, true
+	}
+	if !isScalar(value) {
+		return r.resolveValue(value)
+	}
+
+	if observed, exists := resource.Metadata[key]; exists && isScalar(observed) {
+		return observed, true
+	}
+
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return nil, false
+	}
+	for _, field := range []string{resource.ID, resource.Name, resource.Region, resource.Zone, resource.Project, resource.ResourceGroup} {
+		if s == field {
+			return s, true
+		}
+	}
+	for _, observed := range resource.Metadata {
+		if str, ok := observed.(string); ok && str == s {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// isScalar reports whether a value is a plain string, bool or number
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, int32, int64, float32, float64:
+		return true
+	}
+	return false
+}
+
+// stateType returns the JSON encoding of the Terraform type of a state value
+func stateType(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bool:
+		return "bool"
+	case int, int32, int64, float32, float64:
+		return "number"
+	case []string:
+		return []interface{}{"list", "string"}
+	case map[string]string:
+		return []interface{}{"map", "string"}
+	case []interface{}:
+		elements := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			elements = append(elements, stateType(item))
+		}
+		return []interface{}{"tuple", elements}
+	case map[string]interface{}:
+		attributes := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			attributes[key] = stateType(item)
+		}
+		return []interface{}{"object", attributes}
+	}
+	return "string"
+}
+
+// resolveValue converts a configuration value to its state representation;
+// nested blocks become lists of objects as Terraform stores them
+func (r *stateResolver) resolveValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case generation.Expression:
+		if constant, ok := generation.EvaluateString(v); ok {
+			return constant, true
+		}
+		return r.resolve(v)
+	case []string:
+		return v, true
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			resolved, ok := r.resolveValue(item)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, resolved)
+		}
+		return list, true
+	case map[string]string:
+		return v, true
+	case map[string]interface{}:
+		return []interface{}{r.resolveObject(v)}, true
+	case generation.Map:
+		return r.resolveObject(v), true
+	case []map[string]interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			list = append(list, r.resolveObject(item))
+		}
+		return list, true
+	default:
+		return v, true
+	}
+}
+
+// resolveObject resolves every attribute of a nested block, dropping the ones
+// that have no concrete value
+func (r *stateResolver) resolveObject(object map[string]interface{}) map[string]interface{} {
+	resolved := make(map[string]interface{})
+	for key, value := range object {
+		if v, ok := r.resolveValue(value); ok {
+			resolved[key] = v
+		}
+	}
+	return resolved
+}
+
+// resolve resolves a reference expression such as type.name.attr or
+// var.name; expressions that cannot be known are reported as unresolved
+func (r *stateResolver) resolve(expr generation.Expression) (interface{}, bool) {
+	match := referencePattern.FindStringSubmatch(string(expr))
+	if match == nil {
+		return nil, false
+	}
+
+	if match[1] == "var" {
+		variable, ok := r.variables[match[2]]
+		if !ok || variable.Sensitive || variable.Default == nil {
+			return nil, false
+		}
+		return variable.Default, true
+	}
+
+	target, ok := r.resources[match[1]+"."+match[2]]
+	if !ok {
+		return nil, false
+	}
+
+	id := stateID(*target)
+
+	switch match[3] {
+	case "id":
+		return id, true
+	case "self_link":
+		return googleSelfLink(id), true
+	case "arn":
+		if arn, ok := target.OriginalResource.Metadata["arn"].(string); ok && arn != "" {
+			return arn, true
+		}
+	default:
+		if value, exists := target.Configuration[match[3]]; exists && isScalar(value) {
+			return r.observedValue(target.OriginalResource, match[3], value)
+		}
+	}
+
+	return nil, false
+}
+
+// dependencies returns the sorted, de-duplicated dependency addresses that
+// refer to resources present in the state
+func (r *stateResolver) dependencies(dependencies []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, dependency := range dependencies {
+		if _, exists := r.resources[dependency]; exists && !seen[dependency] {
+			seen[dependency] = true
+			result = append(result, dependency)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// stateID returns the ID Terraform records for a resource. It is usually
+// the import ID, but API Gateway parts and event targets are recorded under
+// IDs of their own, such as agm-<api>-<resource>-<method> for a method.
+// An empty ID means the resource cannot be recorded.
+func stateID(resource generation.MappedResource) string {
+	metadata := resource.OriginalResource.Metadata
+	value := func(key string) string {
+		s, _ := metadata[key].(string)
+		return s
+	}
+
+	switch resource.ResourceType {
+	case "aws_api_gateway_resource", "aws_apigatewayv2_integration", "aws_apigatewayv2_route":
+		return resource.OriginalResource.ID
+	case "aws_api_gateway_method":
+		return strings.Join([]string{"agm", value("rest_api_id"), value("resource_id"), value("http_method")}, "-")
+	case "aws_api_gateway_integration":
+		return strings.Join([]string{"agi", value("rest_api_id"), value("resource_id"), value("http_method")}, "-")
+	case "aws_api_gateway_stage":
+		return strings.Join([]string{"ags", value("rest_api_id"), value("stage_name")}, "-")
+	case "aws_apigatewayv2_stage":
+		return value("stage_name")
+	case "aws_vpc_security_group_ingress_rule", "aws_vpc_security_group_egress_rule":
+		// Rules split from a group discovered without rule IDs have none and are
+		// left out of the state
+		return resource.ImportID
+	case "aws_cloudwatch_event_target":
+		id := value("rule") + "-" + value("target_id")
+		if bus := value("event_bus_name"); bus != "" && bus != "default" {
+			id = bus + "-" + id
+		}
+		return id
+	}
+
+	if resource.ImportID != "" {
+		return resource.ImportID
+	}
+	return resource.OriginalResource.ID
+}
+
+// providerAddress returns the fully qualified address of the provider
+// configuration of a resource, suffixed with the alias of an aliased one
+func providerAddress(resource generation.MappedResource, providers []generation.ProviderConfig) string {
+	var alias string
+	if i := strings.Index(resource.Provider, "."); i >= 0 {
+		alias = "." + resource.Provider[i+1:]
+	}
+
+	for _, provider := range providers {
+		if strings.HasPrefix(resource.ResourceType, provider.Name+"_") && provider.Source != "" {
+			return fmt.Sprintf("provider[\"registry.terraform.io/%s\"]%s", provider.Source, alias)
+		}
+	}
+
+	name := resource.ResourceType
+	if idx := strings.Index(name, "_"); idx > 0 {
+		name = name[:idx]
+	}
+	return fmt.Sprintf("provider[\"registry.terraform.io/hashicorp/%s\"]%s", name, alias)
+}
+
+// googleSelfLink expands a relative GCP resource path to its self link
+func googleSelfLink(id string) string {
+	if strings.HasPrefix(id, "https://") {
+		return id
+	}
+	return "https://www.googleapis.com/compute/v1/" + id
+}
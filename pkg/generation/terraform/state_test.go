@@ -0,0 +1,299 @@
+package terraform
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/BigChiefRick/chimera/pkg/discovery"
+	"github.com/BigChiefRick/chimera/pkg/generation"
+)
+
+// generateState generates and decodes a state
+func generateState(t *testing.T, resources []generation.MappedResource, providers []generation.ProviderConfig) (State, []generation.GenerationWarning) {
+	t.Helper()
+	content, warnings, err := NewGenerator().GenerateState(resources, providers)
+	if err != nil {
+		t.Fatalf("GenerateState returned error: %v", err)
+	}
+	var state State
+	if err := json.Unmarshal([]byte(content), &state); err != nil {
+		t.Fatalf("GenerateState returned invalid JSON: %v", err)
+	}
+	return state, warnings
+}
+
+// stateResource returns the resource recorded under an address
+func stateResource(t *testing.T, state State, address string) StateResource {
+	t.Helper()
+	for _, resource := range state.Resources {
+		if resource.Type+"."+resource.Name == address {
+			return resource
+		}
+	}
+	t.Fatalf("state has no resource %s", address)
+	return StateResource{}
+}
+
+func TestProviderAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		resource  generation.MappedResource
+		providers []generation.ProviderConfig
+		want      string
+	}{
+		{
+			name:     "default",
+			resource: generation.MappedResource{ResourceType: "aws_vpc"},
+			want:     `provider["registry.terraform.io/hashicorp/aws"]`,
+		},
+		{
+			name:     "aliased",
+			resource: generation.MappedResource{ResourceType: "aws_vpc", Provider: "aws.eu-west-1"},
+			want:     `provider["registry.terraform.io/hashicorp/aws"].eu-west-1`,
+		},
+		{
+			name:      "configured source",
+			resource:  generation.MappedResource{ResourceType: "azurerm_subnet"},
+			providers: []generation.ProviderConfig{{Name: "aws", Source: "hashicorp/aws"}, {Name: "azurerm", Source: "example/azurerm"}},
+			want:      `provider["registry.terraform.io/example/azurerm"]`,
+		},
+		{
+			name:      "configured source, aliased",
+			resource:  generation.MappedResource{ResourceType: "aws_vpc", Provider: "aws.us-east-1"},
+			providers: []generation.ProviderConfig{{Name: "aws", Source: "hashicorp/aws", Alias: "us-east-1"}},
+			want:      `provider["registry.terraform.io/hashicorp/aws"].us-east-1`,
+		},
+		{
+			name:     "google",
+			resource: generation.MappedResource{ResourceType: "google_compute_network"},
+			want:     `provider["registry.terraform.io/hashicorp/google"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := providerAddress(tt.resource, tt.providers); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStateID(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource generation.MappedResource
+		want     string
+	}{
+		{
+			name:     "import ID",
+			resource: generation.MappedResource{ResourceType: "aws_vpc", ImportID: "vpc-1", OriginalResource: discovery.Resource{ID: "other"}},
+			want:     "vpc-1",
+		},
+		{
+			name:     "discovered ID",
+			resource: generation.MappedResource{ResourceType: "aws_vpc", OriginalResource: discovery.Resource{ID: "vpc-1"}},
+			want:     "vpc-1",
+		},
+		{
+			name: "api gateway method",
+			resource: generation.MappedResource{
+				ResourceType: "aws_api_gateway_method",
+				ImportID:     "api/res/GET",
+				OriginalResource: discovery.Resource{Metadata: map[string]interface{}{
+					"rest_api_id": "api", "resource_id": "res", "http_method": "GET",
+				}},
+			},
+			want: "agm-api-res-GET",
+		},
+		{
+			name: "api gateway stage",
+			resource: generation.MappedResource{
+				ResourceType:     "aws_api_gateway_stage",
+				OriginalResource: discovery.Resource{Metadata: map[string]interface{}{"rest_api_id": "api", "stage_name": "prod"}},
+			},
+			want: "ags-api-prod",
+		},
+		{
+			name: "event target on the default bus",
+			resource: generation.MappedResource{
+				ResourceType: "aws_cloudwatch_event_target",
+				OriginalResource: discovery.Resource{Metadata: map[string]interface{}{
+					"rule": "nightly", "target_id": "lambda", "event_bus_name": "default",
+				}},
+			},
+			want: "nightly-lambda",
+		},
+		{
+			name: "event target on a custom bus",
+			resource: generation.MappedResource{
+				ResourceType: "aws_cloudwatch_event_target",
+				OriginalResource: discovery.Resource{Metadata: map[string]interface{}{
+					"rule": "nightly", "target_id": "lambda", "event_bus_name": "orders",
+				}},
+			},
+			want: "orders-nightly-lambda",
+		},
+		{
+			name:     "rule with an ID",
+			resource: generation.MappedResource{ResourceType: "aws_vpc_security_group_ingress_rule", ImportID: "sgr-1", OriginalResource: discovery.Resource{ID: "sg-1"}},
+			want:     "sgr-1",
+		},
+		{
+			name:     "rule without an ID",
+			resource: generation.MappedResource{ResourceType: "aws_vpc_security_group_egress_rule", OriginalResource: discovery.Resource{ID: "sg-1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stateID(tt.resource); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateStateAttributes(t *testing.T) {
+	vpc := generation.MappedResource{
+		OriginalResource: discovery.Resource{
+			ID:       "vpc-1",
+			Region:   "eu-west-1",
+			Tags:     map[string]string{"Name": "main"},
+			Metadata: map[string]interface{}{"cidr_block": "10.0.0.0/16", "arn": "arn:aws:ec2:eu-west-1:1:vpc/vpc-1"},
+		},
+		ResourceType: "aws_vpc",
+		ResourceName: "main",
+		ImportID:     "vpc-1",
+		Provider:     "aws.eu-west-1",
+		Configuration: map[string]interface{}{
+			"cidr_block":           "10.0.0.0/16",
+			"enable_dns_hostnames": true,
+			"tags":                 map[string]string{"Name": "main", "ManagedBy": "Chimera"},
+		},
+		Outputs: map[string]generation.Output{"vpc_id": {Value: "aws_vpc.main.id"}},
+	}
+	subnet := generation.MappedResource{
+		OriginalResource: discovery.Resource{ID: "subnet-1", Metadata: map[string]interface{}{}},
+		ResourceType:     "aws_subnet",
+		ResourceName:     "a",
+		ImportID:         "subnet-1",
+		Configuration: map[string]interface{}{
+			"vpc_id":            generation.Expression("aws_vpc.main.id"),
+			"availability_zone": generation.Expression("var.zone"),
+			"cidr_block":        "10.0.1.0/24",
+		},
+		Variables:    map[string]generation.Variable{"zone": {Default: "eu-west-1a"}},
+		Dependencies: []string{"aws_vpc.main", "aws_vpc.main", "aws_internet_gateway.missing"},
+	}
+
+	state, warnings := generateState(t, []generation.MappedResource{subnet, vpc}, nil)
+	if len(warnings) != 0 {
+		t.Errorf("got warnings %v, want none", warnings)
+	}
+
+	if state.Version != StateFormatVersion || state.Serial != 1 || state.Lineage == "" {
+		t.Errorf("got version %d, serial %d, lineage %q", state.Version, state.Serial, state.Lineage)
+	}
+	if len(state.Resources) != 2 || state.Resources[0].Type != "aws_subnet" || state.Resources[1].Type != "aws_vpc" {
+		t.Fatalf("got resources %+v, want aws_subnet.a and aws_vpc.main in address order", state.Resources)
+	}
+
+	gotVPC := stateResource(t, state, "aws_vpc.main")
+	if want := `provider["registry.terraform.io/hashicorp/aws"].eu-west-1`; gotVPC.Provider != want {
+		t.Errorf("got provider %s, want %s", gotVPC.Provider, want)
+	}
+	if gotVPC.Instances[0].SchemaVersion != 1 {
+		t.Errorf("got schema version %d, want 1", gotVPC.Instances[0].SchemaVersion)
+	}
+	wantVPC := map[string]interface{}{
+		"id":         "vpc-1",
+		"arn":        "arn:aws:ec2:eu-west-1:1:vpc/vpc-1",
+		"cidr_block": "10.0.0.0/16",
+		"tags":       map[string]interface{}{"Name": "main"},
+		"tags_all":   map[string]interface{}{"Name": "main"},
+	}
+	if got := gotVPC.Instances[0].Attributes; !reflect.DeepEqual(got, wantVPC) {
+		t.Errorf("got vpc attributes %v, want %v", got, wantVPC)
+	}
+
+	gotSubnet := stateResource(t, state, "aws_subnet.a")
+	if want := `provider["registry.terraform.io/hashicorp/aws"]`; gotSubnet.Provider != want {
+		t.Errorf("got provider %s, want %s", gotSubnet.Provider, want)
+	}
+	wantSubnet := map[string]interface{}{
+		"id":                "subnet-1",
+		"vpc_id":            "vpc-1",
+		"availability_zone": "eu-west-1a",
+	}
+	if got := gotSubnet.Instances[0].Attributes; !reflect.DeepEqual(got, wantSubnet) {
+		t.Errorf("got subnet attributes %v, want %v", got, wantSubnet)
+	}
+	if got, want := gotSubnet.Instances[0].Dependencies, []string{"aws_vpc.main"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got dependencies %v, want %v", got, want)
+	}
+
+	if got, want := state.Outputs["vpc_id"], (StateOutput{Value: "vpc-1", Type: "string"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got output %+v, want %+v", got, want)
+	}
+}
+
+func TestGenerateStateWarnsAboutResourcesWithoutID(t *testing.T) {
+	group := generation.MappedResource{
+		OriginalResource: discovery.Resource{ID: "sg-1", Type: "aws_security_group", Provider: discovery.AWS},
+		ResourceType:     "aws_security_group",
+		ResourceName:     "web",
+		ImportID:         "sg-1",
+		Configuration:    map[string]interface{}{},
+	}
+	withID := generation.MappedResource{
+		OriginalResource: discovery.Resource{ID: "sg-1", Type: "aws_security_group", Provider: discovery.AWS},
+		ResourceType:     "aws_vpc_security_group_ingress_rule",
+		ResourceName:     "web_ingress_0",
+		ImportID:         "sgr-1",
+		Configuration:    map[string]interface{}{"security_group_id": generation.Expression("aws_security_group.web.id")},
+		Dependencies:     []string{"aws_security_group.web"},
+	}
+	withoutID := generation.MappedResource{
+		OriginalResource: discovery.Resource{ID: "sg-1", Type: "aws_security_group", Provider: discovery.AWS},
+		ResourceType:     "aws_vpc_security_group_ingress_rule",
+		ResourceName:     "web_ingress_1",
+		Configuration:    map[string]interface{}{"security_group_id": generation.Expression("aws_security_group.web.id")},
+		Dependencies:     []string{"aws_security_group.web"},
+	}
+	dependent := generation.MappedResource{
+		OriginalResource: discovery.Resource{ID: "i-1"},
+		ResourceType:     "aws_instance",
+		ResourceName:     "web",
+		ImportID:         "i-1",
+		Configuration:    map[string]interface{}{},
+		Dependencies:     []string{"aws_vpc_security_group_ingress_rule.web_ingress_1", "aws_security_group.web"},
+	}
+
+	state, warnings := generateState(t, []generation.MappedResource{group, withID, withoutID, dependent}, nil)
+
+	var addresses []string
+	for _, resource := range state.Resources {
+		addresses = append(addresses, resource.Type+"."+resource.Name)
+	}
+	wantAddresses := []string{"aws_instance.web", "aws_security_group.web", "aws_vpc_security_group_ingress_rule.web_ingress_0"}
+	if !reflect.DeepEqual(addresses, wantAddresses) {
+		t.Errorf("got resources %v, want %v", addresses, wantAddresses)
+	}
+
+	if got, want := stateResource(t, state, "aws_instance.web").Instances[0].Dependencies, []string{"aws_security_group.web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got dependencies %v, want %v", got, want)
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1", len(warnings))
+	}
+	warning := warnings[0]
+	if warning.ResourceID != "sg-1" || warning.Provider != discovery.AWS || warning.Type != generation.WarningTypeManualAction {
+		t.Errorf("got warning %+v", warning)
+	}
+	if want := "aws_vpc_security_group_ingress_rule.web_ingress_1 has no ID to record in state and was left out of it"; warning.Message != want {
+		t.Errorf("got message %q, want %q", warning.Message, want)
+	}
+}